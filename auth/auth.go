@@ -86,6 +86,18 @@ func (c simpleProvider) Cookies() []*http.Cookie {
 	return c.Cookie
 }
 
+// storedCreds is the serialised form of the credentials.
+type storedCreds struct {
+	simpleProvider
+	// OAuth is set if the token rotates.
+	OAuth *oauthCreds `json:",omitempty"`
+}
+
+// oauthProvider is the provider, which token may be refreshed.
+type oauthProvider interface {
+	oauth() *oauthCreds
+}
+
 // Load deserialises JSON data from reader and returns a ValueAuth, that can
 // be used to authenticate Slackdump.  It will return ErrNoToken or
 // ErrNoCookie if the authentication information is missing.
@@ -98,6 +110,25 @@ func Load(r io.Reader) (ValueAuth, error) {
 	return ValueAuth{s}, s.Validate()
 }
 
+// Restore is similar to [Load], but if the credentials were obtained with
+// the OAuth flow and the token rotates, it returns the [OAuthAuth] that
+// refreshes the token.
+func Restore(r io.Reader) (Provider, error) {
+	dec := json.NewDecoder(r)
+	var sc storedCreds
+	if err := dec.Decode(&sc); err != nil {
+		return nil, err
+	}
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+	if sc.OAuth == nil || sc.OAuth.RefreshToken == "" {
+		return ValueAuth{sc.simpleProvider}, nil
+	}
+	sc.OAuth.token = sc.Token
+	return OAuthAuth{simpleProvider: sc.simpleProvider, oc: sc.OAuth}, nil
+}
+
 // Save serialises authentication information to writer.  It will return
 // ErrNoToken or ErrNoCookie if provider fails validation.
 func Save(w io.Writer, p Provider) error {
@@ -105,10 +136,15 @@ func Save(w io.Writer, p Provider) error {
 		return err
 	}
 
-	s := simpleProvider{
-		Token:  p.SlackToken(),
-		Cookie: p.Cookies(),
+	var s storedCreds
+	if op, ok := p.(oauthProvider); ok && op.oauth() != nil {
+		// the token must not be refreshed here, as Save may be called on
+		// refresh.
+		s.Token, s.OAuth = op.oauth().stored()
+	} else {
+		s.Token = p.SlackToken()
 	}
+	s.Cookie = p.Cookies()
 
 	enc := json.NewEncoder(w)
 	if err := enc.Encode(s); err != nil {
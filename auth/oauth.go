@@ -0,0 +1,547 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rusq/slack"
+)
+
+// OAuth endpoints.
+const (
+	OAuthAuthorizeURL = "https://slack.com/oauth/v2/authorize"
+	OAuthTokenURL     = "https://slack.com/api/oauth.v2.access"
+)
+
+// DefOAuthRedirectURL is the default redirect URL of the OAuth flow.  It
+// must be listed in the "Redirect URLs" of the Slack app.
+const DefOAuthRedirectURL = "http://localhost:8338/oauth/callback"
+
+// DefOAuthScopes are the user token scopes requested by default.
+var DefOAuthScopes = []string{
+	"channels:history", "channels:read",
+	"groups:history", "groups:read",
+	"im:history", "im:read",
+	"mpim:history", "mpim:read",
+	"users:read", "users:read.email",
+	"files:read", "emoji:read", "team:read",
+	"search:read",
+}
+
+// refreshMargin is how long before the expiry the token is refreshed.
+const refreshMargin = 5 * time.Minute
+
+var (
+	ErrNoClientID = errors.New("oauth: client ID is required")
+	ErrOAuthState = errors.New("oauth: state mismatch")
+)
+
+var _ Provider = &OAuthAuth{}
+
+// OAuthAuth is the authentication provider that obtains the user token (xoxp)
+// with the OAuth v2 flow of the Slack app, using PKCE.  It does not automate
+// the browser:  the user is given the authorisation URL to open in any
+// browser, and the code is received by the local redirect listener.
+//
+// If the token rotation is enabled for the app, the token is refreshed
+// automatically when it is about to expire, see [OAuthAuth.OnRefresh].
+type OAuthAuth struct {
+	simpleProvider
+	oc *oauthCreds
+}
+
+// oauthCreds is the OAuth refresh information of the rotating token.  The
+// exported fields are saved with the credentials.
+type oauthCreds struct {
+	ClientID     string
+	ClientSecret string `json:",omitempty"`
+	RefreshToken string
+	ExpiresAt    time.Time
+	TokenURL     string `json:",omitempty"`
+
+	mu        sync.Mutex
+	token     string
+	onRefresh func(Provider)
+	hcl       *http.Client
+}
+
+type oauthOptions struct {
+	clientID     string
+	clientSecret string
+	scopes       []string
+	redirectURL  string
+	authorizeURL string
+	tokenURL     string
+	// urlFn is called with the authorisation URL that the user should open.
+	urlFn func(ctx context.Context, u string) error
+	// hcl is the HTTP client for OAuth API calls.
+	hcl *http.Client
+}
+
+// OAuthWithClient sets the client ID and the client secret of the Slack app.
+// The secret is optional with PKCE.
+func OAuthWithClient(id, secret string) Option {
+	return func(o *options) {
+		o.oauthOptions.clientID = id
+		o.oauthOptions.clientSecret = secret
+	}
+}
+
+// OAuthWithScopes sets the user token scopes.
+func OAuthWithScopes(scopes ...string) Option {
+	return func(o *options) {
+		if len(scopes) > 0 {
+			o.oauthOptions.scopes = scopes
+		}
+	}
+}
+
+// OAuthWithRedirectURL sets the redirect URL.  The listener is started on
+// the host and port of the URL, so it should point to the local machine.
+func OAuthWithRedirectURL(u string) Option {
+	return func(o *options) {
+		if u != "" {
+			o.oauthOptions.redirectURL = u
+		}
+	}
+}
+
+// OAuthWithEndpoints overrides the authorize and token endpoint URLs.
+func OAuthWithEndpoints(authorizeURL, tokenURL string) Option {
+	return func(o *options) {
+		if authorizeURL != "" {
+			o.oauthOptions.authorizeURL = authorizeURL
+		}
+		if tokenURL != "" {
+			o.oauthOptions.tokenURL = tokenURL
+		}
+	}
+}
+
+// OAuthWithURLFunc sets the function that presents the authorisation URL to
+// the user.  By default, it is printed to stderr.
+func OAuthWithURLFunc(fn func(ctx context.Context, u string) error) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.oauthOptions.urlFn = fn
+		}
+	}
+}
+
+// OAuthWithHTTPClient sets the HTTP client for OAuth API calls.
+func OAuthWithHTTPClient(cl *http.Client) Option {
+	return func(o *options) {
+		if cl != nil {
+			o.oauthOptions.hcl = cl
+		}
+	}
+}
+
+func printOAuthURL(_ context.Context, u string) error {
+	_, err := fmt.Fprintf(os.Stderr, "Open the following URL in the browser to authorise slackdump:\n\n\t%s\n\n", u)
+	return err
+}
+
+// NewOAuthAuth performs the OAuth v2 flow and returns the provider with the
+// obtained user token.
+func NewOAuthAuth(ctx context.Context, opts ...Option) (OAuthAuth, error) {
+	o := options{
+		playwrightOptions: playwrightOptions{loginTimeout: 5 * time.Minute},
+		oauthOptions: oauthOptions{
+			scopes:       DefOAuthScopes,
+			redirectURL:  DefOAuthRedirectURL,
+			authorizeURL: OAuthAuthorizeURL,
+			tokenURL:     OAuthTokenURL,
+			urlFn:        printOAuthURL,
+			hcl:          http.DefaultClient,
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	oo := o.oauthOptions
+	if oo.clientID == "" {
+		return OAuthAuth{}, ErrNoClientID
+	}
+	if o.loginTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.loginTimeout)
+		defer cancel()
+	}
+
+	redir, err := url.Parse(oo.redirectURL)
+	if err != nil {
+		return OAuthAuth{}, fmt.Errorf("oauth: invalid redirect URL: %w", err)
+	}
+	l, err := net.Listen("tcp", redir.Host)
+	if err != nil {
+		return OAuthAuth{}, fmt.Errorf("oauth: redirect listener: %w", err)
+	}
+	if redir.Port() == "0" {
+		// random port, for tests.
+		redir.Host = l.Addr().String()
+	}
+
+	verifier, challenge := pkcePair()
+	state := rand.Text()
+
+	codeC := make(chan callbackResult, 1)
+	srv := &http.Server{Handler: callbackHandler(redir.Path, state, codeC)}
+	go func() {
+		if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.DebugContext(ctx, "oauth: redirect listener", "error", err)
+		}
+	}()
+	defer srv.Close()
+
+	q := url.Values{
+		"client_id":             {oo.clientID},
+		"user_scope":            {strings.Join(oo.scopes, ",")},
+		"redirect_uri":          {redir.String()},
+		"state":                 {state},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+	if err := oo.urlFn(ctx, oo.authorizeURL+"?"+q.Encode()); err != nil {
+		return OAuthAuth{}, err
+	}
+
+	var cr callbackResult
+	select {
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return OAuthAuth{}, ErrCancelled
+		}
+		return OAuthAuth{}, fmt.Errorf("oauth: waiting for the authorisation: %w", ctx.Err())
+	case cr = <-codeC:
+	}
+	if cr.err != nil {
+		return OAuthAuth{}, cr.err
+	}
+
+	resp, err := oauthAccess(ctx, oo.hcl, oo.tokenURL, url.Values{
+		"client_id":     {oo.clientID},
+		"client_secret": {oo.clientSecret},
+		"code":          {cr.code},
+		"code_verifier": {verifier},
+		"redirect_uri":  {redir.String()},
+	})
+	if err != nil {
+		return OAuthAuth{}, err
+	}
+	tok := resp.token()
+	if tok.AccessToken == "" {
+		return OAuthAuth{}, fmt.Errorf("oauth: no user token in response, are the user scopes set?")
+	}
+	p := OAuthAuth{simpleProvider: simpleProvider{Token: tok.AccessToken}}
+	if tok.RefreshToken != "" {
+		p.oc = &oauthCreds{
+			ClientID:     oo.clientID,
+			ClientSecret: oo.clientSecret,
+			RefreshToken: tok.RefreshToken,
+			ExpiresAt:    tok.expiresAt(),
+			TokenURL:     oo.tokenURL,
+			token:        tok.AccessToken,
+			hcl:          oo.hcl,
+		}
+	}
+	return p, nil
+}
+
+// pkcePair returns the PKCE code verifier and its S256 challenge.
+func pkcePair() (verifier, challenge string) {
+	var b [32]byte
+	_, _ = rand.Read(b[:])
+	verifier = base64.RawURLEncoding.EncodeToString(b[:])
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// callbackHandler handles the redirect from Slack, it sends the code, or an
+// error, to codeC.
+func callbackHandler(path string, state string, codeC chan<- callbackResult) http.Handler {
+	if path == "" {
+		path = "/"
+	}
+	var once sync.Once
+	send := func(cr callbackResult) {
+		once.Do(func() { codeC <- cr })
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+path, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case q.Get("state") != state:
+			http.Error(w, ErrOAuthState.Error(), http.StatusBadRequest)
+			send(callbackResult{err: ErrOAuthState})
+		case q.Get("error") != "":
+			http.Error(w, "Authorisation failed: "+q.Get("error"), http.StatusForbidden)
+			send(callbackResult{err: fmt.Errorf("oauth: authorisation failed: %s", q.Get("error"))})
+		case q.Get("code") == "":
+			http.Error(w, "no code", http.StatusBadRequest)
+			send(callbackResult{err: errors.New("oauth: no code in the callback")})
+		default:
+			fmt.Fprintln(w, "Slackdump is authorised, you may close this window.")
+			send(callbackResult{code: q.Get("code")})
+		}
+	})
+	return mux
+}
+
+// oauthToken is the token information in the oauth.v2.access response.
+type oauthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+func (t oauthToken) expiresAt() time.Time {
+	if t.ExpiresIn <= 0 {
+		return time.Time{}
+	}
+	return timeFunc().Add(time.Duration(t.ExpiresIn) * time.Second)
+}
+
+// oauthResponse is the response of the oauth.v2.access API.  The user token
+// is returned in AuthedUser when exchanging the code, and at the top level
+// when refreshing it.
+type oauthResponse struct {
+	slack.SlackResponse
+	oauthToken
+	AuthedUser oauthToken `json:"authed_user"`
+}
+
+func (r oauthResponse) token() oauthToken {
+	if r.AuthedUser.AccessToken != "" {
+		return r.AuthedUser
+	}
+	return r.oauthToken
+}
+
+func oauthAccess(ctx context.Context, hcl *http.Client, tokenURL string, form url.Values) (*oauthResponse, error) {
+	if form.Get("client_secret") == "" {
+		form.Del("client_secret")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := hcl.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oauth: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oauth: unexpected status: %s", resp.Status)
+	}
+	var r oauthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("oauth: %w", err)
+	}
+	if err := r.Err(); err != nil {
+		return nil, &Error{Err: err, Msg: "oauth: " + err.Error()}
+	}
+	return &r, nil
+}
+
+// current returns the current access token, refreshing it, if it is about
+// to expire.
+func (oc *oauthCreds) current(ctx context.Context, p Provider) (string, error) {
+	oc.mu.Lock()
+	if oc.ExpiresAt.IsZero() || timeFunc().Add(refreshMargin).Before(oc.ExpiresAt) {
+		defer oc.mu.Unlock()
+		return oc.token, nil
+	}
+	tok, err := oc.refresh(ctx)
+	onRefresh := oc.onRefresh
+	oc.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	if onRefresh != nil {
+		onRefresh(p)
+	}
+	return tok, nil
+}
+
+// refresh refreshes the token, oc.mu must be held.
+func (oc *oauthCreds) refresh(ctx context.Context) (string, error) {
+	hcl := oc.hcl
+	if hcl == nil {
+		hcl = http.DefaultClient
+	}
+	tokenURL := oc.TokenURL
+	if tokenURL == "" {
+		tokenURL = OAuthTokenURL
+	}
+	resp, err := oauthAccess(ctx, hcl, tokenURL, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {oc.RefreshToken},
+		"client_id":     {oc.ClientID},
+		"client_secret": {oc.ClientSecret},
+	})
+	if err != nil {
+		return "", fmt.Errorf("refreshing the token: %w", err)
+	}
+	tok := resp.token()
+	if tok.AccessToken == "" {
+		return "", errors.New("oauth: no token in the refresh response")
+	}
+	oc.token = tok.AccessToken
+	if tok.RefreshToken != "" {
+		oc.RefreshToken = tok.RefreshToken
+	}
+	oc.ExpiresAt = tok.expiresAt()
+	slog.DebugContext(ctx, "oauth: token refreshed", "expires_at", oc.ExpiresAt)
+	return oc.token, nil
+}
+
+// stored returns the current token and a copy of the refresh information
+// for saving.
+func (oc *oauthCreds) stored() (string, *oauthCreds) {
+	oc.mu.Lock()
+	defer oc.mu.Unlock()
+	return oc.token, &oauthCreds{
+		ClientID:     oc.ClientID,
+		ClientSecret: oc.ClientSecret,
+		RefreshToken: oc.RefreshToken,
+		ExpiresAt:    oc.ExpiresAt,
+		TokenURL:     oc.TokenURL,
+	}
+}
+
+func (p OAuthAuth) oauth() *oauthCreds {
+	return p.oc
+}
+
+// OnRefresh sets the function that is called after the token is refreshed,
+// i.e. to save the new token.  It does nothing if the token does not rotate.
+func (p OAuthAuth) OnRefresh(fn func(Provider)) {
+	if p.oc == nil {
+		return
+	}
+	p.oc.mu.Lock()
+	p.oc.onRefresh = fn
+	p.oc.mu.Unlock()
+}
+
+// SlackToken returns the current token.
+func (p OAuthAuth) SlackToken() string {
+	if p.oc == nil {
+		return p.Token
+	}
+	tok, err := p.oc.current(context.Background(), p)
+	if err != nil {
+		slog.Warn("unable to refresh the token", "error", err)
+		tok, _ = p.oc.stored()
+	}
+	return tok
+}
+
+// HTTPClient returns the HTTP client that replaces the token in requests
+// with the refreshed one, when the token rotates.
+func (p OAuthAuth) HTTPClient() (*http.Client, error) {
+	cl, err := p.simpleProvider.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	if p.oc == nil {
+		return cl, nil
+	}
+	rt := cl.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	cl.Transport = &refreshTransport{p: p, rt: rt}
+	return cl, nil
+}
+
+// Test tests the credentials, refreshing the token if necessary.
+func (p OAuthAuth) Test(ctx context.Context) (*slack.AuthTestResponse, error) {
+	if p.oc == nil {
+		return p.simpleProvider.Test(ctx)
+	}
+	tok, err := p.oc.current(ctx, p)
+	if err != nil {
+		return nil, &Error{Err: err}
+	}
+	sp := p.simpleProvider
+	sp.Token = tok
+	return sp.Test(ctx)
+}
+
+// refreshTransport replaces the token in Slack API requests with the
+// current one, refreshing it if necessary.  The Slack client sends the
+// token either in the Authorization header, or in the "token" form field.
+type refreshTransport struct {
+	p  OAuthAuth
+	rt http.RoundTripper
+}
+
+func (t *refreshTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tok, err := t.p.oc.current(req.Context(), t.p)
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	if strings.HasPrefix(req.Header.Get("Authorization"), "Bearer ") {
+		req.Header.Set("Authorization", "Bearer "+tok)
+	}
+	if q := req.URL.Query(); q.Has("token") {
+		q.Set("token", tok)
+		req.URL.RawQuery = q.Encode()
+	}
+	if req.Body != nil && req.Method == http.MethodPost && strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if form, err := url.ParseQuery(string(body)); err == nil && form.Has("token") {
+			form.Set("token", tok)
+			body = []byte(form.Encode())
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+		req.ContentLength = int64(len(body))
+		req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	return t.rt.RoundTrip(req)
+}
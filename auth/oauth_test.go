@@ -0,0 +1,244 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOAuth is the fake Slack OAuth server.
+type fakeOAuth struct {
+	t         *testing.T
+	mu        sync.Mutex
+	challenge string
+	refreshes int
+	expiresIn int
+}
+
+func newFakeOAuth(t *testing.T, expiresIn int) (*fakeOAuth, *httptest.Server) {
+	f := &fakeOAuth{t: t, expiresIn: expiresIn}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /oauth/v2/authorize", f.authorize)
+	mux.HandleFunc("POST /api/oauth.v2.access", f.access)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeOAuth) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	assert.Equal(f.t, "CLIENT", q.Get("client_id"))
+	assert.Equal(f.t, "S256", q.Get("code_challenge_method"))
+	assert.Contains(f.t, q.Get("user_scope"), "channels:history")
+	f.mu.Lock()
+	f.challenge = q.Get("code_challenge")
+	f.mu.Unlock()
+	redir, err := url.Parse(q.Get("redirect_uri"))
+	require.NoError(f.t, err)
+	redir.RawQuery = url.Values{"code": {"CODE"}, "state": {q.Get("state")}}.Encode()
+	http.Redirect(w, r, redir.String(), http.StatusFound)
+}
+
+func (f *fakeOAuth) access(w http.ResponseWriter, r *http.Request) {
+	require.NoError(f.t, r.ParseForm())
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch r.Form.Get("grant_type") {
+	case "refresh_token":
+		if r.Form.Get("refresh_token") != fmt.Sprintf("xoxe-refresh-%d", f.refreshes) {
+			fmt.Fprint(w, `{"ok":false,"error":"invalid_refresh_token"}`)
+			return
+		}
+		f.refreshes++
+		json.NewEncoder(w).Encode(map[string]any{
+			"ok":            true,
+			"access_token":  fmt.Sprintf("xoxe.xoxp-%d", f.refreshes),
+			"refresh_token": fmt.Sprintf("xoxe-refresh-%d", f.refreshes),
+			"expires_in":    f.expiresIn,
+		})
+	default:
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if r.Form.Get("code") != "CODE" || base64.RawURLEncoding.EncodeToString(sum[:]) != f.challenge {
+			fmt.Fprint(w, `{"ok":false,"error":"invalid_code"}`)
+			return
+		}
+		authed := map[string]any{"id": "U1", "access_token": "xoxe.xoxp-0", "token_type": "user"}
+		if f.expiresIn > 0 {
+			authed["refresh_token"] = "xoxe-refresh-0"
+			authed["expires_in"] = f.expiresIn
+		}
+		json.NewEncoder(w).Encode(map[string]any{"ok": true, "authed_user": authed})
+	}
+}
+
+// oauthTestOpts returns the options to authenticate against the fake server.
+func oauthTestOpts(srv *httptest.Server) []Option {
+	return []Option{
+		OAuthWithClient("CLIENT", ""),
+		OAuthWithRedirectURL("http://127.0.0.1:0/cb"),
+		OAuthWithEndpoints(srv.URL+"/oauth/v2/authorize", srv.URL+"/api/oauth.v2.access"),
+		OAuthWithURLFunc(func(ctx context.Context, u string) error {
+			// plays the role of the user opening the URL in the browser.
+			go func() {
+				resp, err := http.Get(u)
+				if err == nil {
+					resp.Body.Close()
+				}
+			}()
+			return nil
+		}),
+	}
+}
+
+func TestNewOAuthAuth(t *testing.T) {
+	t.Run("without rotation", func(t *testing.T) {
+		_, srv := newFakeOAuth(t, 0)
+		p, err := NewOAuthAuth(t.Context(), oauthTestOpts(srv)...)
+		require.NoError(t, err)
+		assert.Equal(t, "xoxe.xoxp-0", p.SlackToken())
+		assert.Nil(t, p.oauth())
+
+		var buf bytes.Buffer
+		require.NoError(t, Save(&buf, p))
+		got, err := Restore(&buf)
+		require.NoError(t, err)
+		assert.IsType(t, ValueAuth{}, got)
+	})
+	t.Run("with rotation", func(t *testing.T) {
+		f, srv := newFakeOAuth(t, 3600)
+		p, err := NewOAuthAuth(t.Context(), oauthTestOpts(srv)...)
+		require.NoError(t, err)
+		assert.Equal(t, "xoxe.xoxp-0", p.SlackToken())
+		require.NotNil(t, p.oauth())
+		assert.Equal(t, "xoxe-refresh-0", p.oauth().RefreshToken)
+		assert.Zero(t, f.refreshes)
+	})
+	t.Run("no client ID", func(t *testing.T) {
+		_, err := NewOAuthAuth(t.Context())
+		assert.ErrorIs(t, err, ErrNoClientID)
+	})
+	t.Run("user denies", func(t *testing.T) {
+		_, srv := newFakeOAuth(t, 0)
+		opts := append(oauthTestOpts(srv), OAuthWithURLFunc(func(ctx context.Context, u string) error {
+			pu, _ := url.Parse(u)
+			redir, _ := url.Parse(pu.Query().Get("redirect_uri"))
+			redir.RawQuery = url.Values{"error": {"access_denied"}, "state": {pu.Query().Get("state")}}.Encode()
+			go func() {
+				if resp, err := http.Get(redir.String()); err == nil {
+					resp.Body.Close()
+				}
+			}()
+			return nil
+		}))
+		_, err := NewOAuthAuth(t.Context(), opts...)
+		assert.ErrorContains(t, err, "access_denied")
+	})
+	t.Run("state mismatch", func(t *testing.T) {
+		_, srv := newFakeOAuth(t, 0)
+		opts := append(oauthTestOpts(srv), OAuthWithURLFunc(func(ctx context.Context, u string) error {
+			pu, _ := url.Parse(u)
+			redir, _ := url.Parse(pu.Query().Get("redirect_uri"))
+			redir.RawQuery = url.Values{"code": {"CODE"}, "state": {"forged"}}.Encode()
+			go func() {
+				if resp, err := http.Get(redir.String()); err == nil {
+					resp.Body.Close()
+				}
+			}()
+			return nil
+		}))
+		_, err := NewOAuthAuth(t.Context(), opts...)
+		assert.ErrorIs(t, err, ErrOAuthState)
+	})
+}
+
+func TestOAuthAuth_refresh(t *testing.T) {
+	f, srv := newFakeOAuth(t, 3600)
+	p, err := NewOAuthAuth(t.Context(), oauthTestOpts(srv)...)
+	require.NoError(t, err)
+
+	// save and restore, as it would be done by the cache manager.
+	var buf bytes.Buffer
+	require.NoError(t, Save(&buf, p))
+	rp, err := Restore(&buf)
+	require.NoError(t, err)
+	require.IsType(t, OAuthAuth{}, rp)
+	restored := rp.(OAuthAuth)
+	assert.Equal(t, "xoxe.xoxp-0", restored.SlackToken())
+
+	var saved []string
+	restored.OnRefresh(func(p Provider) {
+		var buf bytes.Buffer
+		require.NoError(t, Save(&buf, p))
+		saved = append(saved, buf.String())
+	})
+
+	// the API server echoes the token it received.
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		fmt.Fprintf(w, "%s|%s", r.Form.Get("token"), r.Header.Get("Authorization"))
+	}))
+	defer api.Close()
+	cl, err := restored.HTTPClient()
+	require.NoError(t, err)
+	cl.Transport.(*refreshTransport).rt = http.DefaultTransport // no uTLS for the plain http server.
+	call := func() string {
+		resp, err := cl.PostForm(api.URL, url.Values{"token": {"stale"}, "channel": {"C1"}})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return string(b)
+	}
+
+	assert.Equal(t, "xoxe.xoxp-0|", call(), "token is fresh")
+	assert.Empty(t, saved)
+
+	// an hour later.
+	timeFunc = func() time.Time { return time.Now().Add(time.Hour) }
+	t.Cleanup(func() { timeFunc = time.Now })
+
+	assert.Equal(t, "xoxe.xoxp-1|", call(), "token is refreshed")
+	assert.Equal(t, 1, f.refreshes)
+	require.Len(t, saved, 1)
+	assert.Contains(t, saved[0], `"Token":"xoxe.xoxp-1"`)
+	assert.Contains(t, saved[0], `"RefreshToken":"xoxe-refresh-1"`)
+	assert.Equal(t, "xoxe.xoxp-1|", call(), "no second refresh")
+	assert.Equal(t, 1, f.refreshes)
+
+	req, _ := http.NewRequest(http.MethodGet, api.URL, nil)
+	req.Header.Set("Authorization", "Bearer stale")
+	resp, err := cl.Do(req)
+	require.NoError(t, err)
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.True(t, strings.HasSuffix(string(b), "Bearer xoxe.xoxp-1"))
+}
@@ -25,6 +25,7 @@ import (
 type options struct {
 	playwrightOptions
 	rodOpts
+	oauthOptions
 	workspace string
 }
 
@@ -19,6 +19,7 @@ import (
 	"context"
 	_ "embed"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
@@ -31,6 +32,7 @@ import (
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/bootstrap"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/golang/base"
+	"github.com/rusq/slackdump/v4/internal/cas"
 	"github.com/rusq/slackdump/v4/internal/chunk"
 	"github.com/rusq/slackdump/v4/internal/chunk/backend/dbase"
 	"github.com/rusq/slackdump/v4/internal/chunk/backend/dbase/repository"
@@ -38,6 +40,9 @@ import (
 	"github.com/rusq/slackdump/v4/internal/chunk/control"
 	"github.com/rusq/slackdump/v4/internal/client"
 	"github.com/rusq/slackdump/v4/internal/convert/transform/fileproc"
+	"github.com/rusq/slackdump/v4/internal/linkfetch"
+	"github.com/rusq/slackdump/v4/internal/s3"
+	"github.com/rusq/slackdump/v4/internal/seal"
 	"github.com/rusq/slackdump/v4/internal/structures"
 	"github.com/rusq/slackdump/v4/processor"
 	"github.com/rusq/slackdump/v4/source"
@@ -58,20 +63,42 @@ var CmdArchive = &base.Command{
 }
 
 func init() {
+	CmdArchive.Flag.BoolVar(&cfg.WithEmoji, "emoji", false, "record custom workspace emoji and download their images (placed in __emoji directory)")
+	CmdArchive.Flag.BoolVar(&cfg.WithCanvases, "canvases", false, "discover and archive all canvases you have access to, including standalone\ncanvases and canvases shared in DMs, with their comment threads")
+	CmdArchive.Flag.BoolVar(&cfg.EncryptArchive, "encrypt", false, "encrypt the database archive with the passphrase, see\n'slackdump help tools seal'")
+	CmdArchive.Flag.StringVar(&cfg.ArchiveKeyFile, "key-file", "", "use the contents of the key `file` instead of the passphrase for -encrypt")
+	CmdArchive.Flag.BoolVar(&cfg.WithThumbs, "files-thumbs", false, "download the Slack thumbnails of the image attachments, the viewer shows\nthem instead of generating the previews")
+	CmdArchive.Flag.BoolVar(&cfg.WithLinks, "links", false, "archive the publicly accessible targets of the link unfurls and external files,\nsuch as Google Drive or Dropbox documents (placed in __links directory)")
+	CmdArchive.Flag.Var(&cfg.LinkDomains, "links-allow", "comma-separated list of `domains` to archive the links to with -links,\nincluding their subdomains, by default links to any domain are archived")
+	CmdArchive.Flag.Int64Var(&cfg.LinkMaxSize, "links-max-size", cfg.LinkMaxSize, "size limit of the archived link in `bytes`, larger links are skipped")
+	CmdArchive.Flag.StringVar(&cfg.FileStore, "file-store", "", "keep the downloaded files in the content-addressed file store `directory`,\nshared between archives, so that each file is stored once")
+	cfg.SetPoolFlags(&CmdArchive.Flag)
 	CmdArchive.Wizard = archiveWizard
 }
 
-var errNoOutput = errors.New("output directory is required")
+var (
+	errNoOutput        = errors.New("output directory is required")
+	errEncryptNotDBase = errors.New("-encrypt is only supported for database archives")
+	errFileStoreS3     = errors.New("the shared file store can not be used with the S3 output")
+)
 
 func RunArchive(ctx context.Context, cmd *base.Command, args []string) error {
+	if cfg.FileStore != "" && s3.IsURL(cfg.Output) {
+		base.SetExitStatus(base.SInvalidParameters)
+		return errFileStoreS3
+	}
 	if cfg.UseChunkFiles {
+		if cfg.EncryptArchive {
+			base.SetExitStatus(base.SInvalidParameters)
+			return errEncryptNotDBase
+		}
 		return runChunkArchive(ctx, cmd, args)
 	} else {
 		return runDBArchive(ctx, cmd, args)
 	}
 }
 
-func runChunkArchive(ctx context.Context, _ *base.Command, args []string) error {
+func runChunkArchive(ctx context.Context, _ *base.Command, args []string) (err error) {
 	start := time.Now()
 	list, err := structures.NewEntityList(args)
 	if err != nil {
@@ -83,12 +110,20 @@ func runChunkArchive(ctx context.Context, _ *base.Command, args []string) error
 		base.SetExitStatus(base.SInitializationError)
 		return err
 	}
+	defer bootstrap.Finish(ctx, client)
+
+	output, finish, err := bootstrap.StageOutput(ctx, cfg.Output)
+	if err != nil {
+		base.SetExitStatus(base.SInitializationError)
+		return err
+	}
+	defer finish(&err)
 
-	if err := bootstrap.AskOverwrite(cfg.Output); err != nil {
+	if err := bootstrap.AskOverwrite(output); err != nil {
 		return err
 	}
 
-	cd, err := NewDirectory(cfg.Output)
+	cd, err := NewDirectory(output)
 	if err != nil {
 		base.SetExitStatus(base.SUserError)
 		return err
@@ -101,6 +136,11 @@ func runChunkArchive(ctx context.Context, _ *base.Command, args []string) error
 	}
 	defer ctrl.Close()
 	if err := ctrl.RunNoTransform(ctx, list); err != nil {
+		if errors.Is(err, control.ErrTokenInvalidated) {
+			base.SetExitStatus(base.SAuthError)
+			slog.WarnContext(ctx, "Slack no longer accepts the token, archiving is interrupted", "directory", cd.Name())
+			return fmt.Errorf("archive interrupted: %w", err)
+		}
 		base.SetExitStatus(base.SApplicationError)
 		return err
 	}
@@ -108,20 +148,42 @@ func runChunkArchive(ctx context.Context, _ *base.Command, args []string) error
 	return nil
 }
 
-func runDBArchive(ctx context.Context, cmd *base.Command, args []string) error {
+func runDBArchive(ctx context.Context, cmd *base.Command, args []string) (err error) {
 	start := time.Now()
 	list, err := structures.NewEntityList(args)
 	if err != nil {
 		base.SetExitStatus(base.SUserError)
 		return err
 	}
+	if cfg.EncryptArchive && cfg.FileStore != "" {
+		base.SetExitStatus(base.SUserError)
+		return errors.New("files in the shared file store can not be encrypted, use either -encrypt or -file-store")
+	}
+	if cfg.EncryptArchive {
+		// ask for the passphrase upfront, not after hours of archiving.
+		secret, err := bootstrap.NewArchiveSecret()
+		if err != nil {
+			base.SetExitStatus(base.SUserError)
+			return err
+		}
+		ctx = seal.WithSecret(ctx, secret)
+	}
 	client, err := bootstrap.Slack(ctx)
 	if err != nil {
 		base.SetExitStatus(base.SInitializationError)
 		return err
 	}
+	defer bootstrap.Finish(ctx, client)
 
-	dirname := cfg.StripZipExt(cfg.Output)
+	output, finish, err := bootstrap.StageOutput(ctx, cfg.Output)
+	if err != nil {
+		base.SetExitStatus(base.SInitializationError)
+		return err
+	}
+	// runs last, after the archive is closed and sealed.
+	defer finish(&err)
+
+	dirname := cfg.StripZipExt(output)
 	if err := os.MkdirAll(dirname, 0o755); err != nil {
 		return err
 	}
@@ -130,6 +192,22 @@ func runDBArchive(ctx context.Context, cmd *base.Command, args []string) error {
 	if err := bootstrap.AskOverwrite(dbfile); err != nil {
 		return err
 	}
+	if cfg.EncryptArchive {
+		// if the process is killed, the marker tells the readers that the
+		// archive must be sealed.
+		if err := seal.MarkPending(dirname); err != nil {
+			return err
+		}
+		// runs last, after the database is closed, and even if archiving
+		// fails, so that no plaintext is left behind.
+		defer func() {
+			if serr := bootstrap.SealArchive(ctx, dirname); serr != nil {
+				base.SetExitStatus(base.SApplicationError)
+				slog.ErrorContext(ctx, "unable to encrypt the archive", "error", serr)
+				err = errors.Join(err, serr) // the plaintext archive must not be uploaded
+			}
+		}()
+	}
 
 	conn, err := sqlx.Open(repository.Driver, dbfile)
 	if err != nil {
@@ -143,9 +221,19 @@ func runDBArchive(ctx context.Context, cmd *base.Command, args []string) error {
 		ChannelUsers:  cfg.OnlyChannelUsers,
 		IncludeLabels: cfg.IncludeCustomLabels,
 		ChannelTypes:  cfg.ChannelTypes,
+		Emojis:        cfg.WithEmoji,
+		Canvases:      cfg.WithCanvases,
 	}
 
-	ctrl, err := DBController(ctx, cmd.Name(), conn, client, dirname, flags, []stream.Option{})
+	var ctrlOpts []DBControllerOption
+	if cfg.EncryptArchive {
+		secret, err := seal.SecretFromContext(ctx)
+		if err != nil {
+			return err
+		}
+		ctrlOpts = append(ctrlOpts, WithSealedFiles(secret))
+	}
+	ctrl, err := DBController(ctx, cmd.Name(), conn, client, dirname, flags, []stream.Option{}, ctrlOpts...)
 	if err != nil {
 		return err
 	}
@@ -156,6 +244,17 @@ func runDBArchive(ctx context.Context, cmd *base.Command, args []string) error {
 	}()
 
 	if err := ctrl.RunNoTransform(ctx, list); err != nil {
+		if errors.Is(err, control.ErrTokenInvalidated) {
+			// checkpoint: finalise the session with the data received so
+			// far, so that the archive is usable and can be resumed.
+			base.SetExitStatus(base.SAuthError)
+			if err := ctrl.Finish(); err != nil {
+				slog.ErrorContext(ctx, "unable to finalise the session", "error", err)
+			}
+			slog.WarnContext(ctx, "Slack no longer accepts the token, archiving is interrupted and the data received so far is saved", "directory", dirname)
+			slog.WarnContext(ctx, "to continue, authenticate again with \"slackdump workspace new\", and run \"slackdump resume -refresh "+dirname+"\"")
+			return fmt.Errorf("archive interrupted: %w", err)
+		}
 		base.SetExitStatus(base.SApplicationError)
 		return err
 	}
@@ -186,6 +285,7 @@ func NewDirectory(name string) (*chunk.Directory, error) {
 type dbControllerOptions struct {
 	dbaseOptions    []dbase.Option
 	fileDeduplicate bool
+	secret          *seal.Secret
 }
 
 // DBControllerOption configures the database controller.
@@ -206,6 +306,15 @@ func WithFileDeduplication() DBControllerOption {
 	}
 }
 
+// WithSealedFiles seals the downloaded files, avatars, emoji and links with
+// the secret as they are written.  The files in the file store are not
+// sealed, as they are addressed by the hash of their contents.
+func WithSealedFiles(secret *seal.Secret) DBControllerOption {
+	return func(o *dbControllerOptions) {
+		o.secret = secret
+	}
+}
+
 // DBController returns a new database controller initialised with the given
 // parameters. sessionName is recorded in the database session only and must not
 // be used to select controller behaviour.
@@ -218,6 +327,9 @@ func DBController(ctx context.Context, sessionName string, conn *sqlx.DB, client
 		opt(&options)
 	}
 
+	if flags.Emojis {
+		options.dbaseOptions = append(options.dbaseOptions, dbase.WithEmojiFilenameFunc(fileproc.EmojiPath))
+	}
 	dbp, err := dbase.New(ctx, conn, bootstrap.SessionInfo(sessionName), options.dbaseOptions...)
 	if err != nil {
 		return nil, err
@@ -227,23 +339,46 @@ func DBController(ctx context.Context, sessionName string, conn *sqlx.DB, client
 		stream.OptOldest(time.Time(cfg.Oldest)),
 		stream.OptResultFn(resultLogger(lg)),
 		stream.OptFailOnNonCritError(cfg.FailOnNonCritical),
+		stream.OptAdaptive(bootstrap.Adaptive()),
 	}
 	sopts = append(sopts, streamOpts...)
 	// start attachment downloader
+	filefs, err := filesFS(dirname)
+	if err != nil {
+		return nil, err
+	}
+	var dirfs fsadapter.FS = fsadapter.NewDirectory(dirname)
+	if options.secret != nil {
+		dirfs = seal.Adapter(dirfs, options.secret)
+		if _, ok := filefs.(*cas.Writer); !ok {
+			filefs = seal.Adapter(filefs, options.secret)
+		}
+	}
 	dl := fileproc.NewDownloader(
 		ctx,
 		cfg.WithFiles,
 		client,
-		fsadapter.NewDirectory(dirname),
+		filefs,
 		lg,
+		fileproc.ResumableIn(dirname),
 	)
 	// start avatar downloader
 	avdl := fileproc.NewDownloader(
 		ctx,
 		cfg.WithAvatars,
 		client,
-		fsadapter.NewDirectory(dirname),
+		dirfs,
+		lg,
+		fileproc.ResumableIn(dirname),
+	)
+	// start emoji downloader
+	emdl := fileproc.NewDownloader(
+		ctx,
+		flags.Emojis,
+		client,
+		dirfs,
 		lg,
+		fileproc.ResumableIn(dirname),
 	)
 
 	filer := dbControllerFiler(dl, conn, lg, options)
@@ -252,9 +387,12 @@ func DBController(ctx context.Context, sessionName string, conn *sqlx.DB, client
 		ctx,
 		stream.New(client, cfg.Limits, sopts...),
 		dbp,
-		control.WithFiler(filer),
-		control.WithAvatarProcessor(fileproc.NewAvatarProc(avdl)),
-		control.WithFlags(flags),
+		append([]control.Option{
+			control.WithFiler(filer),
+			control.WithAvatarProcessor(fileproc.NewAvatarProc(avdl, fileproc.WithAvatarHistory(dirname))),
+			control.WithEmojiProcessor(fileproc.NewEmojiProc(emdl)),
+			control.WithFlags(flags),
+		}, linkOptions(ctx, dirfs)...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -262,8 +400,40 @@ func DBController(ctx context.Context, sessionName string, conn *sqlx.DB, client
 	return ctrl, nil
 }
 
+// filesFS returns the fs adapter for the file attachments of the archive in
+// the directory dir.  If the file store is requested, or the archive already
+// uses one, the files are placed into the store.
+func filesFS(dir string) (fsadapter.FS, error) {
+	fsa := fsadapter.NewDirectory(dir)
+	if cfg.FileStore == "" && !cas.Exists(dir) {
+		return fsa, nil
+	}
+	w, err := cas.NewWriter(fsa, dir, cfg.FileStore)
+	if err != nil {
+		base.SetExitStatus(base.SUserError)
+		return nil, fmt.Errorf("file store: %w", err)
+	}
+	return w, nil
+}
+
+// linkOptions returns the controller options to archive the external links
+// with the fs adapter fsa, if it is requested.
+func linkOptions(ctx context.Context, fsa fsadapter.FS) []control.Option {
+	if !cfg.WithLinks {
+		return nil
+	}
+	lf := linkfetch.New(
+		ctx,
+		fsa,
+		linkfetch.WithAllowedDomains(cfg.LinkDomains),
+		linkfetch.WithMaxSize(cfg.LinkMaxSize),
+		linkfetch.WithLogger(cfg.Log),
+	)
+	return []control.Option{control.WithLinkFetcher(lf)}
+}
+
 func dbControllerFiler(dl fileproc.Downloader, conn *sqlx.DB, lg *slog.Logger, options dbControllerOptions) processor.Filer {
-	filer := fileproc.New(dl)
+	filer := fileproc.New(dl, fileproc.WithThumbs(cfg.WithThumbs))
 	if options.fileDeduplicate {
 		return fileproc.NewDeduplicatingFileProcessor(filer, conn, lg)
 	}
@@ -292,16 +462,22 @@ func ArchiveController(ctx context.Context, cd *chunk.Directory, client client.S
 		stream.OptOldest(time.Time(cfg.Oldest)),
 		stream.OptResultFn(resultLogger(lg)),
 		stream.OptFailOnNonCritError(cfg.FailOnNonCritical),
+		stream.OptAdaptive(bootstrap.Adaptive()),
 	}
 	sopts = append(sopts, opts...)
 
 	// start attachment downloader
+	filefs, err := filesFS(cd.Name())
+	if err != nil {
+		return nil, err
+	}
 	dl := fileproc.NewDownloader(
 		ctx,
 		cfg.WithFiles,
 		client,
-		fsadapter.NewDirectory(cd.Name()),
+		filefs,
 		lg,
+		fileproc.ResumableIn(cd.Name()),
 	)
 	// start avatar downloader
 	avdl := fileproc.NewDownloader(
@@ -310,6 +486,16 @@ func ArchiveController(ctx context.Context, cd *chunk.Directory, client client.S
 		client,
 		fsadapter.NewDirectory(cd.Name()),
 		lg,
+		fileproc.ResumableIn(cd.Name()),
+	)
+	// start emoji downloader
+	emdl := fileproc.NewDownloader(
+		ctx,
+		cfg.WithEmoji,
+		client,
+		fsadapter.NewDirectory(cd.Name()),
+		lg,
+		fileproc.ResumableIn(cd.Name()),
 	)
 
 	erc := directory.NewERC(cd, lg)
@@ -320,16 +506,21 @@ func ArchiveController(ctx context.Context, cd *chunk.Directory, client client.S
 		ChannelUsers:  cfg.OnlyChannelUsers,
 		IncludeLabels: cfg.IncludeCustomLabels,
 		ChannelTypes:  cfg.ChannelTypes,
+		Emojis:        cfg.WithEmoji,
+		Canvases:      cfg.WithCanvases,
 	}
 
 	ctrl, err := control.New(
 		ctx,
 		stream.New(client, cfg.Limits, sopts...),
 		erc,
-		control.WithLogger(lg),
-		control.WithFlags(flags),
-		control.WithFiler(fileproc.New(dl)),
-		control.WithAvatarProcessor(fileproc.NewAvatarProc(avdl)),
+		append([]control.Option{
+			control.WithLogger(lg),
+			control.WithFlags(flags),
+			control.WithFiler(fileproc.New(dl, fileproc.WithThumbs(cfg.WithThumbs))),
+			control.WithAvatarProcessor(fileproc.NewAvatarProc(avdl, fileproc.WithAvatarHistory(cd.Name()))),
+			control.WithEmojiProcessor(fileproc.NewEmojiProc(emdl)),
+		}, linkOptions(ctx, fsadapter.NewDirectory(cd.Name()))...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -122,6 +122,7 @@ func runSearch(ctx context.Context, cmd *base.Command, args []string, typ contro
 		base.SetExitStatus(base.SInitializationError)
 		return err
 	}
+	defer bootstrap.Finish(ctx, client)
 
 	ctrl, stop, err := searchControllerv31(ctx, cfg.Output, client, args)
 	if err != nil {
@@ -185,6 +186,7 @@ func searchControllerv31(ctx context.Context, dir string, client client.Slack, t
 		client,
 		fsadapter.NewDirectory(cd.Name()),
 		lg,
+		fileproc.ResumableIn(cd.Name()),
 	)
 
 	erc, err := dbase.New(ctx, db, si)
@@ -204,6 +206,7 @@ func searchControllerv31(ctx context.Context, dir string, client client.Slack, t
 			return nil
 		}),
 		stream.OptFailOnNonCritError(cfg.FailOnNonCritical),
+		stream.OptAdaptive(bootstrap.Adaptive()),
 	}
 	if fastSearch {
 		sopts = append(sopts, stream.OptFastSearch())
@@ -0,0 +1,126 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package bootstrap
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/auth"
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/golang/base"
+	"github.com/rusq/slackdump/v4/internal/httptape"
+	"github.com/rusq/slackdump/v4/internal/network"
+)
+
+// tapeProvider is the auth provider, which HTTP clients record or replay the
+// HTTP traffic.
+type tapeProvider struct {
+	auth.Provider
+	httpClient func() (*http.Client, error)
+}
+
+func (p tapeProvider) HTTPClient() (*http.Client, error) {
+	return p.httpClient()
+}
+
+func (p tapeProvider) Test(ctx context.Context) (*slack.AuthTestResponse, error) {
+	cl, err := p.HTTPClient()
+	if err != nil {
+		return nil, &auth.Error{Err: err}
+	}
+	ai, err := slack.New(p.SlackToken(), slack.OptionHTTPClient(cl)).AuthTestContext(ctx)
+	if err != nil {
+		return ai, &auth.Error{Err: err}
+	}
+	return ai, nil
+}
+
+// RecordProviderCtx wraps the auth provider in the context, so that all HTTP
+// traffic of the Slack clients, including the edge API and file downloads, is
+// recorded to the [cfg.RecordHTTP] file.  The file is closed on exit.
+func RecordProviderCtx(ctx context.Context) (context.Context, error) {
+	prov, err := auth.FromContext(ctx)
+	if err != nil {
+		return ctx, err
+	}
+	rec, err := httptape.Create(cfg.RecordHTTP, httptape.Header{ClientToken: auth.IsClientToken(prov.SlackToken())})
+	if err != nil {
+		return ctx, err
+	}
+	base.AtExit(func() {
+		if err := rec.Close(); err != nil {
+			cfg.Log.Error("error closing the HTTP recording", "error", err)
+		}
+	})
+	tapeMode()
+	cfg.Log.InfoContext(ctx, "recording HTTP traffic", "file", cfg.RecordHTTP)
+
+	return auth.WithContext(ctx, tapeProvider{
+		Provider: prov,
+		httpClient: func() (*http.Client, error) {
+			cl, err := prov.HTTPClient()
+			if err != nil {
+				return nil, err
+			}
+			cl.Transport = rec.Transport(cl.Transport)
+			return cl, nil
+		},
+	}), nil
+}
+
+// ReplayProviderCtx returns the context with the auth provider, that replays
+// the HTTP traffic recorded in the [cfg.ReplayHTTP] file, no network requests
+// are made, and no credentials are required.
+func ReplayProviderCtx(ctx context.Context) (context.Context, error) {
+	p, err := httptape.Open(cfg.ReplayHTTP)
+	if err != nil {
+		return ctx, err
+	}
+	// the token value is irrelevant, but its type determines whether the
+	// edge client is used.
+	token, cookie := "xoxp-replay", ""
+	if p.Header().ClientToken {
+		token, cookie = "xoxc-0-0-0-"+strings.Repeat("0", 64), "replay"
+	}
+	prov, err := auth.NewValueAuth(token, cookie)
+	if err != nil {
+		return ctx, err
+	}
+	tapeMode()
+	// there's no point in waiting for the rate limiter on replay, but the
+	// request sizes must be the same as in the recording.
+	cfg.Limits.Tier2, cfg.Limits.Tier3, cfg.Limits.Tier4 = network.NoLimits.Tier2, network.NoLimits.Tier3, network.NoLimits.Tier4
+	cfg.NoAdaptiveLimits = true
+	cfg.Log.InfoContext(ctx, "replaying HTTP traffic", "file", cfg.ReplayHTTP)
+
+	return auth.WithContext(ctx, tapeProvider{
+		Provider: prov,
+		httpClient: func() (*http.Client, error) {
+			return &http.Client{Transport: p.Transport()}, nil
+		},
+	}), nil
+}
+
+// tapeMode disables the caches, that may change the set of the requests made
+// between the recording and the replay.
+func tapeMode() {
+	cfg.NoAPICache = true
+	cfg.NoUserCache = true
+}
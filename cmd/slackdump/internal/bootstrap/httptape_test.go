@@ -0,0 +1,65 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package bootstrap
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusq/slackdump/v4/auth"
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
+	"github.com/rusq/slackdump/v4/internal/httptape"
+)
+
+func TestReplayProviderCtx(t *testing.T) {
+	tape := filepath.Join(t.TempDir(), "tape.jsonl")
+	f, err := os.Create(tape)
+	require.NoError(t, err)
+	enc := json.NewEncoder(f)
+	require.NoError(t, enc.Encode(httptape.Header{Version: httptape.Version}))
+	require.NoError(t, enc.Encode(httptape.Entry{
+		Method:   http.MethodPost,
+		URL:      "https://slack.com/api/auth.test",
+		Status:   http.StatusOK,
+		Header:   http.Header{"Content-Type": {"application/json"}},
+		Response: []byte(`{"ok":true,"team_id":"T123","user_id":"U123"}`),
+	}))
+	require.NoError(t, f.Close())
+
+	oldReplay, oldLimits, oldNoAPI, oldNoUser, oldNoAdaptive := cfg.ReplayHTTP, cfg.Limits, cfg.NoAPICache, cfg.NoUserCache, cfg.NoAdaptiveLimits
+	t.Cleanup(func() {
+		cfg.ReplayHTTP, cfg.Limits, cfg.NoAPICache, cfg.NoUserCache, cfg.NoAdaptiveLimits = oldReplay, oldLimits, oldNoAPI, oldNoUser, oldNoAdaptive
+	})
+	cfg.ReplayHTTP = tape
+
+	ctx, err := ReplayProviderCtx(t.Context())
+	require.NoError(t, err)
+	prov, err := auth.FromContext(ctx)
+	require.NoError(t, err)
+
+	ai, err := prov.Test(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "T123", ai.TeamID)
+	assert.True(t, cfg.NoAPICache)
+	assert.True(t, cfg.NoAdaptiveLimits)
+	assert.Equal(t, oldLimits.Request, cfg.Limits.Request, "request sizes must not change")
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package bootstrap
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
+	"github.com/rusq/slackdump/v4/internal/cache"
+	"github.com/rusq/slackdump/v4/internal/client"
+	"github.com/rusq/slackdump/v4/internal/network"
+)
+
+// adaptive is the process-wide adaptive rate controller, shared by all
+// streams, it is initialised by [Slack].
+var adaptive struct {
+	mu     sync.Mutex
+	ctrl   *network.Adaptive
+	teamID string
+}
+
+// Adaptive returns the adaptive rate controller for the workspace of the
+// client returned by [Slack].  It returns nil, if the adaptive rate limiting
+// is disabled, or [Slack] was not called, which is a valid value for
+// [stream.OptAdaptive].
+func Adaptive() *network.Adaptive {
+	adaptive.mu.Lock()
+	defer adaptive.mu.Unlock()
+	return adaptive.ctrl
+}
+
+// initAdaptive initialises the adaptive rate controller with the limits
+// learned in the previous sessions for the workspace of the client.
+func initAdaptive(ctx context.Context, cl *client.Client) {
+	if cfg.NoAdaptiveLimits {
+		return
+	}
+	lg := cfg.Log.With("in", "initAdaptive")
+	wi, err := cl.AuthTestContext(ctx)
+	if err != nil {
+		lg.WarnContext(ctx, "unable to get workspace info, adaptive limits disabled", "error", err)
+		return
+	}
+	var learned []network.TierRate
+	if m, err := cacheManager(); err != nil {
+		lg.WarnContext(ctx, "unable to open cache", "error", err)
+	} else if learned, err = m.LoadLimits(wi.TeamID); err != nil {
+		lg.DebugContext(ctx, "no learned limits", "team_id", wi.TeamID, "error", err)
+	} else {
+		lg.DebugContext(ctx, "loaded learned limits", "team_id", wi.TeamID, "limits", learned)
+	}
+
+	adaptive.mu.Lock()
+	defer adaptive.mu.Unlock()
+	adaptive.ctrl = network.NewAdaptive(learned...)
+	adaptive.teamID = wi.TeamID
+}
+
+// cacheManager returns the cache manager for the configured cache directory.
+func cacheManager() (*cache.Manager, error) {
+	return cache.NewManager(cfg.CacheDir(), cache.WithMachineID(cfg.MachineIDOvr), cache.WithNoEncryption(cfg.NoEncryption), cache.WithCredStore(cfg.CredStore, cfg.CredCommand))
+}
+
+// saveAdaptive saves the limits learned by the adaptive rate controller to
+// the cache directory.
+func saveAdaptive(ctx context.Context) {
+	adaptive.mu.Lock()
+	defer adaptive.mu.Unlock()
+	if adaptive.ctrl == nil {
+		return
+	}
+	rates := adaptive.ctrl.Rates()
+	for _, r := range rates {
+		if r.Throttled > 0 {
+			cfg.Log.InfoContext(ctx, "learned rate limit", "tier", r.Tier, "factor", r.Factor, "throttled", r.Throttled)
+		}
+	}
+	m, err := cacheManager()
+	if err != nil {
+		cfg.Log.WarnContext(ctx, "unable to save learned limits", "error", err)
+		return
+	}
+	if err := m.CacheLimits(adaptive.teamID, rates); err != nil {
+		cfg.Log.WarnContext(ctx, "unable to save learned limits", "error", err)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/rusq/slackdump/v4/internal/s3"
+)
+
+// isHidden returns true for the hidden directories, i.e. partial downloads,
+// that are not transferred to or from the bucket.
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+// StageOutput returns the local directory to write the archive to.  If
+// output is the S3 URL ("s3://bucket/prefix"), it is the temporary
+// directory, otherwise output itself.
+//
+// The caller must defer finish with the pointer to the returned error.  If
+// the command succeeded, finish uploads the staged archive to the bucket and
+// removes the temporary directory, otherwise the staged archive is kept, so
+// that it can be resumed, and its location is logged.
+func StageOutput(ctx context.Context, output string) (dir string, finish func(*error), err error) {
+	if !s3.IsURL(output) {
+		return output, func(*error) {}, nil
+	}
+	sfs, err := s3.Open(ctx, output)
+	if err != nil {
+		return "", nil, err
+	}
+	dir, err = os.MkdirTemp("", "slackdump-s3-*")
+	if err != nil {
+		return "", nil, err
+	}
+	finish = func(errp *error) {
+		if *errp != nil {
+			slog.WarnContext(ctx, "archive was not uploaded to the bucket, it is kept locally", "directory", dir, "destination", output)
+			return
+		}
+		slog.InfoContext(ctx, "uploading the archive", "destination", output)
+		if err := s3.Upload(ctx, sfs, dir, isHidden); err != nil {
+			slog.WarnContext(ctx, "upload failed, the archive is kept locally", "directory", dir, "destination", output)
+			*errp = err
+			return
+		}
+		*errp = os.RemoveAll(dir)
+	}
+	return dir, finish, nil
+}
+
+// FetchInput returns the local copy of the input.  If src is the S3 URL, the
+// objects under the prefix are downloaded to the temporary directory, that
+// is removed by cleanup, otherwise src is returned as is.
+func FetchInput(ctx context.Context, src string) (local string, cleanup func() error, err error) {
+	if !s3.IsURL(src) {
+		return src, func() error { return nil }, nil
+	}
+	sfs, err := s3.Open(ctx, src)
+	if err != nil {
+		return "", nil, err
+	}
+	local, err = os.MkdirTemp("", "slackdump-s3-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup = func() error { return os.RemoveAll(local) }
+	slog.InfoContext(ctx, "downloading the archive", "source", src, "directory", local)
+	if err := s3.Download(ctx, local, sfs, isHidden); err != nil {
+		return "", nil, errors.Join(err, cleanup())
+	}
+	return local, cleanup, nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package bootstrap
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusq/slackdump/v4/internal/s3/s3test"
+)
+
+func TestStageOutput(t *testing.T) {
+	t.Run("local", func(t *testing.T) {
+		dir, finish, err := StageOutput(t.Context(), "local_dir")
+		require.NoError(t, err)
+		assert.Equal(t, "local_dir", dir)
+		var ferr error
+		finish(&ferr)
+		assert.NoError(t, ferr)
+	})
+	t.Run("uploads on success", func(t *testing.T) {
+		srv := s3test.NewServer(t)
+		srv.Setenv(t)
+		dir, finish, err := StageOutput(t.Context(), "s3://bucket/archive")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "slackdump.sqlite"), []byte("db"), 0o644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, ".partial"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".partial", "x.part"), []byte("x"), 0o644))
+
+		var ferr error
+		finish(&ferr)
+		require.NoError(t, ferr)
+		assert.Equal(t, []string{"archive/slackdump.sqlite"}, srv.Keys("bucket"))
+		assert.NoDirExists(t, dir)
+	})
+	t.Run("keeps the archive on failure", func(t *testing.T) {
+		srv := s3test.NewServer(t)
+		srv.Setenv(t)
+		dir, finish, err := StageOutput(t.Context(), "s3://bucket/archive")
+		require.NoError(t, err)
+		t.Cleanup(func() { os.RemoveAll(dir) })
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "slackdump.sqlite"), []byte("db"), 0o644))
+
+		ferr := errors.New("archive failed")
+		finish(&ferr)
+		assert.Empty(t, srv.Keys("bucket"))
+		assert.DirExists(t, dir)
+	})
+}
+
+func TestFetchInput(t *testing.T) {
+	srv := s3test.NewServer(t)
+	srv.Setenv(t)
+	dir, finish, err := StageOutput(t.Context(), "s3://bucket/archive")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "__uploads", "F1"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "__uploads", "F1", "a.txt"), []byte("a"), 0o644))
+	var ferr error
+	finish(&ferr)
+	require.NoError(t, ferr)
+
+	local, cleanup, err := FetchInput(t.Context(), "s3://bucket/archive")
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(local, "__uploads", "F1", "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(data))
+	require.NoError(t, cleanup())
+	assert.NoDirExists(t, local)
+}
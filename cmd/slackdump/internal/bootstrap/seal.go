@@ -0,0 +1,163 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"golang.org/x/term"
+
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
+	"github.com/rusq/slackdump/v4/internal/avatar"
+	"github.com/rusq/slackdump/v4/internal/chunk"
+	"github.com/rusq/slackdump/v4/internal/osext"
+	"github.com/rusq/slackdump/v4/internal/seal"
+	"github.com/rusq/slackdump/v4/source"
+)
+
+// Environment variables with the secret for the sealed archives.
+const (
+	envArchiveKeyFile    = "ARCHIVE_KEY_FILE"
+	envArchivePassphrase = "ARCHIVE_PASSPHRASE"
+)
+
+var errPassphraseMismatch = errors.New("passphrases do not match")
+
+// ArchiveSecret returns the secret for the sealed archives.  It reads the key
+// file set with the -key-file flag or the ARCHIVE_KEY_FILE environment
+// variable, then the ARCHIVE_PASSPHRASE environment variable, and if neither
+// is set, asks for the passphrase, if running in the terminal.
+func ArchiveSecret() (*seal.Secret, error) {
+	return archiveSecret(false)
+}
+
+// NewArchiveSecret is like [ArchiveSecret], but asks the user to confirm the
+// passphrase, as it is going to be used to seal the archive.
+func NewArchiveSecret() (*seal.Secret, error) {
+	return archiveSecret(true)
+}
+
+func archiveSecret(confirm bool) (*seal.Secret, error) {
+	keyFile := cfg.ArchiveKeyFile
+	if keyFile == "" {
+		keyFile = os.Getenv(envArchiveKeyFile)
+	}
+	if keyFile != "" {
+		return seal.ReadKeyFile(keyFile)
+	}
+	if pass := os.Getenv(envArchivePassphrase); pass != "" {
+		return seal.NewSecret([]byte(pass))
+	}
+	if !osext.IsInteractive() {
+		return nil, seal.ErrNoSecret
+	}
+	pass, err := readPassphrase("Archive passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if confirm {
+		again, err := readPassphrase("Repeat passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(pass, again) {
+			return nil, errPassphraseMismatch
+		}
+	}
+	return seal.NewSecret(pass)
+}
+
+func readPassphrase(prompt string) ([]byte, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	defer fmt.Fprintln(os.Stderr)
+	return term.ReadPassword(int(os.Stdin.Fd()))
+}
+
+// IsSealedArchive reports whether the database archive in dir is sealed.
+func IsSealedArchive(dir string) bool {
+	ok, err := seal.IsSealedFile(filepath.Join(dir, source.DefaultDBFile))
+	return err == nil && ok
+}
+
+// SealArchive seals all files of the database archive in dir with the secret
+// from the context, and removes the marker left by [UnsealArchive].
+func SealArchive(ctx context.Context, dir string) error {
+	secret, err := seal.SecretFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	n, err := seal.SealDir(dir, secret)
+	if err != nil {
+		return fmt.Errorf("error encrypting the archive: %w", err)
+	}
+	if err := seal.ClearPending(dir); err != nil {
+		return err
+	}
+	cfg.Log.InfoContext(ctx, "archive encrypted", "directory", dir, "files", n)
+	return nil
+}
+
+// keepSealed are the directories of the archive, that are left sealed by
+// [UnsealArchive].  The new files are sealed as they are written to them,
+// see archive.WithSealedFiles, and nothing reads the existing ones.
+var keepSealed = []string{
+	chunk.UploadsDir,
+	path.Join(chunk.AvatarsDir, avatar.StoreDir),
+	chunk.EmojiDir,
+	chunk.LinksDir,
+}
+
+// UnsealArchive decrypts the database and the other files of the sealed
+// database archive in dir, that are written to, in place, and returns the
+// function that seals them back.  The downloaded files are left sealed.  If
+// the archive is not sealed, it does nothing.  The marker is created in dir
+// before decrypting, so that if the process is interrupted, the next run
+// knows that the archive must be sealed again, see [RecoverArchive].
+func UnsealArchive(ctx context.Context, dir string) (reseal func() error, err error) {
+	if !IsSealedArchive(dir) {
+		return func() error { return nil }, nil
+	}
+	secret, err := seal.SecretFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := seal.MarkPending(dir); err != nil {
+		return nil, err
+	}
+	if _, err := seal.UnsealDir(dir, secret, keepSealed...); err != nil {
+		return nil, fmt.Errorf("error decrypting the archive: %w", err)
+	}
+	cfg.Log.WarnContext(ctx, "archive is decrypted for writing, it will be encrypted again when finished", "directory", dir)
+	return func() error {
+		return SealArchive(ctx, dir)
+	}, nil
+}
+
+// RecoverArchive seals the archive in dir again, if it was left in plaintext
+// by an interrupted run.  If the archive has no marker, it does nothing.
+func RecoverArchive(ctx context.Context, dir string) error {
+	if !seal.IsPending(dir) {
+		return nil
+	}
+	cfg.Log.WarnContext(ctx, "archive was left decrypted by an interrupted run, encrypting", "directory", dir)
+	return SealArchive(ctx, dir)
+}
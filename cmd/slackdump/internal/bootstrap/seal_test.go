@@ -0,0 +1,91 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package bootstrap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rusq/slackdump/v4/internal/seal"
+	"github.com/rusq/slackdump/v4/source"
+)
+
+func TestUnsealArchive(t *testing.T) {
+	secret, err := seal.NewSecret([]byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := seal.WithSecret(t.Context(), secret)
+	dir := t.TempDir()
+	files := []string{
+		source.DefaultDBFile,
+		"__avatars/U1/history.json",
+		"__avatars/_store/abc.png",
+		"__uploads/F1/hello.txt",
+		"__emoji/party.gif",
+		"__links/L1/page.html",
+	}
+	for _, name := range files {
+		fp := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fp), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fp, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := SealArchive(ctx, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	reseal, err := UnsealArchive(ctx, dir)
+	if err != nil {
+		t.Fatalf("UnsealArchive() error = %v", err)
+	}
+	if !seal.IsPending(dir) {
+		t.Error("UnsealArchive() did not leave the marker")
+	}
+	want := map[string]bool{
+		source.DefaultDBFile:        false,
+		"__avatars/U1/history.json": false,
+		"__avatars/_store/abc.png":  true,
+		"__uploads/F1/hello.txt":    true,
+		"__emoji/party.gif":         true,
+		"__links/L1/page.html":      true,
+	}
+	for name, sealed := range want {
+		got, err := seal.IsSealedFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != sealed {
+			t.Errorf("%s: sealed = %v, want %v", name, got, sealed)
+		}
+	}
+
+	if err := reseal(); err != nil {
+		t.Fatalf("reseal() error = %v", err)
+	}
+	if seal.IsPending(dir) {
+		t.Error("reseal() did not remove the marker")
+	}
+	for _, name := range files {
+		if ok, err := seal.IsSealedFile(filepath.Join(dir, filepath.FromSlash(name))); err != nil || !ok {
+			t.Errorf("%s: not sealed after reseal, err = %v", name, err)
+		}
+	}
+}
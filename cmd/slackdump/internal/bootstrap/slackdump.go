@@ -17,12 +17,17 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/rusq/slack"
 
 	"github.com/rusq/slackdump/v4"
 	"github.com/rusq/slackdump/v4/auth"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
 	"github.com/rusq/slackdump/v4/internal/client"
+	"github.com/rusq/slackdump/v4/internal/structures"
 )
 
 // SlackdumpSession returns the Slackdump Session initialised with the provider
@@ -39,6 +44,7 @@ func SlackdumpSession(ctx context.Context, opts ...slackdump.Option) (*slackdump
 		slackdump.WithLogger(cfg.Log),
 		slackdump.WithForceEnterprise(cfg.ForceEnterprise),
 		slackdump.WithLimits(cfg.Limits),
+		slackdump.WithClientOptions(clientOptions(ctx)...),
 	}
 
 	stdOpts = append(stdOpts, opts...)
@@ -51,15 +57,125 @@ func SlackdumpSession(ctx context.Context, opts ...slackdump.Option) (*slackdump
 
 // Slack returns the Slack client initialised with the provider from context
 // and a standard set of options initialised from the configuration.
+//
+// If the additional pool workspaces are configured in [cfg.PoolWorkspaces], it
+// returns the [client.Pool] of the clients for the current and the additional
+// workspaces, and scales [cfg.Limits] by the number of clients in the pool.
+//
+// Unless disabled with [cfg.NoAdaptiveLimits], it initialises the adaptive
+// rate controller, returned by [Adaptive].  Callers should defer [Finish].
 func Slack(ctx context.Context, opts ...client.Option) (client.Slack, error) {
 	prov, err := auth.FromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("authentication error: %w", err)
 	}
 	opts = append(opts, client.WithEnterprise(cfg.ForceEnterprise))
+	opts = append(opts, clientOptions(ctx)...)
 	client, err := client.New(ctx, prov, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("error creating new client: %w", err)
 	}
-	return client, nil
+	initAdaptive(ctx, client)
+	if len(cfg.PoolWorkspaces) == 0 {
+		return client, nil
+	}
+	pool, err := slackPool(ctx, client, opts...)
+	if err != nil {
+		return nil, errors.Join(err, client.Close())
+	}
+	return pool, nil
+}
+
+// clientOptions returns the client options initialised from the
+// configuration.
+func clientOptions(ctx context.Context) []client.Option {
+	var opts []client.Option
+	if !cfg.NoAPICache {
+		m, err := cacheManager()
+		if err != nil {
+			cfg.Log.WarnContext(ctx, "unable to open cache, API response cache disabled", "error", err)
+		} else {
+			opts = append(opts, client.WithTransport(m.APICache(cfg.Limits.APICache).Transport))
+		}
+	}
+	return opts
+}
+
+// slackPool creates the client pool with the primary client and the clients
+// for the pool workspaces.  All workspaces must belong to the same team.
+func slackPool(ctx context.Context, primary *client.Client, opts ...client.Option) (*client.Pool, error) {
+	wi, err := primary.AuthTestContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m, err := cacheManager()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		clients = []client.Slack{primary}
+		names   = []string{poolClientName(structures.NVL(cfg.Workspace, "current"), wi)}
+		added   []*client.Client
+	)
+	closeAdded := func(err error) error {
+		for _, cl := range added {
+			err = errors.Join(err, cl.Close())
+		}
+		return err
+	}
+	for _, wsp := range cfg.PoolWorkspaces {
+		wsp = strings.TrimSpace(wsp)
+		if wsp == "" {
+			continue
+		}
+		prov, err := m.LoadProvider(wsp)
+		if err != nil {
+			return nil, closeAdded(fmt.Errorf("pool workspace %q: %w", wsp, err))
+		}
+		cl, err := client.New(ctx, prov, opts...)
+		if err != nil {
+			return nil, closeAdded(fmt.Errorf("pool workspace %q: %w", wsp, err))
+		}
+		added = append(added, cl)
+		cwi, err := cl.AuthTestContext(ctx)
+		if err != nil {
+			return nil, closeAdded(fmt.Errorf("pool workspace %q: %w", wsp, err))
+		}
+		if cwi.TeamID != wi.TeamID {
+			return nil, closeAdded(fmt.Errorf("pool workspace %q belongs to team %s, expected %s (%s)", wsp, cwi.TeamID, wi.TeamID, wi.Team))
+		}
+		clients = append(clients, cl)
+		names = append(names, poolClientName(wsp, cwi))
+	}
+
+	pool, err := client.NewPoolWithOptions(clients, client.WithStrategy(client.Strategy(cfg.PoolStrategy)), client.WithNames(names...))
+	if err != nil {
+		return nil, closeAdded(err)
+	}
+	cfg.Limits = cfg.Limits.Scale(pool.Len())
+	cfg.Log.InfoContext(ctx, "using client pool", "clients", pool.Len(), "strategy", cfg.PoolStrategy)
+	return pool, nil
+}
+
+func poolClientName(wsp string, wi *slack.AuthTestResponse) string {
+	return wsp + " (" + wi.User + ")"
+}
+
+// Finish should be deferred by the callers of [Slack].  It saves the rate
+// limits learned by the adaptive rate controller, and logs the per-client
+// statistics, if cl is a client pool.
+func Finish(ctx context.Context, cl client.Slack) {
+	saveAdaptive(ctx)
+	pool, ok := cl.(*client.Pool)
+	if !ok {
+		return
+	}
+	for _, st := range pool.Stats() {
+		args := []any{"client", st.Name, "calls", st.Calls, "errors", st.Errors, "rate_limited", st.RateLimited}
+		if st.Evicted {
+			args = append(args, "evicted", st.EvictReason)
+		}
+		cfg.Log.InfoContext(ctx, "client pool stats", args...)
+	}
 }
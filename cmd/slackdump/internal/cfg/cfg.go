@@ -31,6 +31,8 @@ import (
 	"github.com/rusq/osenv/v2"
 
 	"github.com/rusq/slackdump/v4"
+	"github.com/rusq/slackdump/v4/internal/client"
+	"github.com/rusq/slackdump/v4/internal/linkfetch"
 	"github.com/rusq/slackdump/v4/internal/network"
 )
 
@@ -49,10 +51,21 @@ var (
 	Workspace  string
 
 	Limits = network.DefLimits
+	// NoAdaptiveLimits disables the adaptive rate limiting, see
+	// [network.Adaptive].
+	NoAdaptiveLimits bool
 
 	ForceEnterprise bool
+	RecordHTTP      string // file to record the HTTP traffic to.
+	ReplayHTTP      string // file to replay the HTTP traffic from.
 	MachineIDOvr    string // Machine ID override
 	NoEncryption    bool   // disable encryption
+	CredStore       string // credential store kind
+	CredCommand     string // credential helper command for the "command" store
+	EncryptArchive  bool   // seal the database archive with the passphrase
+	ArchiveKeyFile  string // key file for sealed archives
+	FileStore       string // content-addressed file store directory
+	ZipCache        bool   // keep the databases extracted from ZIP files
 
 	MemberOnly          bool
 	OnlyChannelUsers    bool
@@ -64,9 +77,25 @@ var (
 	// disabled.
 	FailOnNonCritical bool
 
-	WithFiles   bool
-	WithAvatars bool
-	RecordFiles bool // record file chunks in chunk files.
+	WithFiles    bool
+	WithAvatars  bool
+	WithEmoji    bool // record custom emoji, used by archive and export.
+	WithCanvases bool // archive all canvases the user can access, used by archive.
+	WithLinks    bool // archive the external links, used by archive.
+	WithThumbs   bool // download the Slack thumbnails of the images, used by archive.
+	RecordFiles  bool // record file chunks in chunk files.
+
+	// LinkDomains lists the domains of the external links that are archived,
+	// if empty, links to any domain are archived.
+	LinkDomains StringSlice
+	// LinkMaxSize is the size limit of the archived external link.
+	LinkMaxSize int64 = linkfetch.DefaultMaxSize
+
+	// PoolWorkspaces lists the additional saved workspaces for the same team,
+	// which credentials are used to build the client pool.
+	PoolWorkspaces StringSlice
+	// PoolStrategy is the client pool selection strategy.
+	PoolStrategy = string(client.StrategyRoundRobin)
 
 	// Oldest is the default timestamp of the oldest message to fetch, that is
 	// used by the dump and export commands.
@@ -79,6 +108,7 @@ var (
 	LocalCacheDir      string
 	UserCacheRetention time.Duration
 	NoUserCache        bool
+	NoAPICache         bool // disable the API response cache.
 	NoChunkCache       bool
 	UseChunkFiles      bool // Use chunk files for storage, instead of sqlite database.
 
@@ -225,6 +255,14 @@ const (
 		OmitChannelTypesFlag
 )
 
+// SetPoolFlags sets the client pool flags on the flagset.  It is used by the
+// commands that make a large number of API calls, i.e. archive, export and
+// resume.
+func SetPoolFlags(fs *flag.FlagSet) {
+	fs.Var(&PoolWorkspaces, "pool", "comma-separated list of additional saved `workspaces` for the same team,\nthe API calls are spread between the current and these workspaces' credentials")
+	fs.StringVar(&PoolStrategy, "pool-strategy", PoolStrategy, fmt.Sprintf("client pool `strategy`, one of: %v", client.Strategies))
+}
+
 // SetBaseFlags sets base flags
 func SetBaseFlags(fs *flag.FlagSet, mask FlagMask) {
 	setDevFlags(fs, mask) // no op if not in dev mode.
@@ -236,11 +274,15 @@ func SetBaseFlags(fs *flag.FlagSet, mask FlagMask) {
 	if mask&OmitAuthFlags == 0 {
 		fs.BoolVar(&ForceEnterprise, "enterprise", false, "enable Enterprise module, you need to specify this option if you're using Slack Enterprise Grid")
 		fs.BoolVar(&LoadSecrets, "load-env", false, "load secrets from the environment, .env, .env.txt or secrets.txt file")
+		fs.StringVar(&RecordHTTP, "record-http", "", "record all HTTP traffic with Slack to the `file`, for reproducing issues (contains workspace data)")
+		fs.StringVar(&ReplayHTTP, "replay-http", "", "replay the HTTP traffic recorded with -record-http from the `file`,\nno network requests are made and no authentication is required")
 	}
 	if mask&OmitAuthFlags == 0 || mask&OmitCacheDir == 0 {
 		// machine-id flag will be automatically enabled if auth flags or cache dir flags are enabled.
 		fs.StringVar(&MachineIDOvr, "machine-id", osenv.Secret("MACHINE_ID_OVERRIDE", ""), "override the machine ID for encryption")
 		fs.BoolVar(&NoEncryption, "no-encryption", osenv.Value("DISABLE_ENCRYPTION", false), "disable encryption for cache and credential files")
+		fs.StringVar(&CredStore, "cred-store", osenv.Value("CRED_STORE", "file"), "workspace credential `store`: file, keyring, pass, vault or command")
+		fs.StringVar(&CredCommand, "cred-command", osenv.Value("CRED_COMMAND", ""), "credential helper `command` for the \"command\" credential store, it is run\nwith \"get <name>\", \"put <name>\" or \"delete <name>\" arguments, quotes\nare interpreted as in the shell")
 	}
 	if mask&OmitWithFilesFlag == 0 {
 		fs.BoolVar(&WithFiles, "files", true, "enables file attachments download (to disable, specify: -files=false)")
@@ -253,6 +295,7 @@ func SetBaseFlags(fs *flag.FlagSet, mask FlagMask) {
 	}
 	if mask&OmitConfigFlag == 0 {
 		fs.StringVar(&ConfigFile, "api-config", "", "configuration `file` with Slack API limits overrides.\nYou can generate one with default values with 'slackdump config new`")
+		fs.BoolVar(&NoAdaptiveLimits, "no-adaptive-limits", osenv.Value("NO_ADAPTIVE_LIMITS", false), "disable adaptive rate limiting, that lowers the request rate when Slack\nthrottles requests, and remembers the learned rate for the workspace")
 	}
 	if mask&OmitOutputFlag == 0 {
 		base := fmt.Sprintf("slackdump_%s.zip", time.Now().Format(filenameLayout))
@@ -260,6 +303,7 @@ func SetBaseFlags(fs *flag.FlagSet, mask FlagMask) {
 	}
 	if mask&OmitCacheDir == 0 {
 		fs.StringVar(&LocalCacheDir, "cache-dir", osenv.Value("CACHE_DIR", CacheDir()), "cache `directory` location\n")
+		fs.BoolVar(&NoAPICache, "no-api-cache", osenv.Value("NO_API_CACHE", false), "disable the disk cache of read-only API responses (conversation and user info,\nconversation members), see 'slackdump help config new' for cache time-to-live settings")
 	} else {
 		// If the OmitCacheDir is specified, then the CacheDir will end up being
 		// the default value, which is "". Therefore, we need to init the
@@ -19,15 +19,24 @@ import (
 	"context"
 	_ "embed"
 	"errors"
+	"fmt"
+	"io"
 	"io/fs"
 	"os"
+	"path"
+	"path/filepath"
 	"time"
 
+	"github.com/rusq/fsadapter"
+
 	"github.com/rusq/slackdump/v4/source"
 
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/bootstrap"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/golang/base"
+	"github.com/rusq/slackdump/v4/internal/cas"
+	"github.com/rusq/slackdump/v4/internal/chunk"
+	"github.com/rusq/slackdump/v4/internal/nametmpl"
 	"github.com/rusq/slackdump/v4/internal/structures"
 )
 
@@ -51,6 +60,8 @@ var (
 	ErrSource = errors.New("unsupported source type")
 	// ErrStorage is returned when the storage type is not supported.
 	ErrStorage = errors.New("unsupported storage type")
+
+	errNoFileStore = errors.New("file store directory must be specified with -file-store for cas storage")
 )
 
 type convertFunc func(ctx context.Context, input, output string, cflg convertflags) error
@@ -67,23 +78,30 @@ type convertflags struct {
 	includeFiles   bool
 	includeAvatars bool
 	outStorageType source.StorageType
+	fileStore      string // file store directory for the STcas storage type
 	dmMode         structures.DMMode
-	sessionID      int64 // sessionID for database->chunk conversion
+	period         source.ExportPeriod // period of messages in each export file
+	dirTemplate    string              // channel directory naming template for export and html
+	sessionID      int64               // sessionID for database->chunk conversion
 	outputfmt      datafmt
 }
 
 var params = convertflags{
 	outStorageType: source.STmattermost,
 	dmMode:         structures.DMSingle,
+	period:         source.PeriodDay,
 	sessionID:      1,
 	outputfmt:      Fexport,
 }
 
 func init() {
 	CmdConvert.Flag.Var(&params.outStorageType, "storage", "storage type")
+	CmdConvert.Flag.StringVar(&params.fileStore, "file-store", "", "content-addressed file store `directory` for \"-storage cas\"")
 	CmdConvert.Flag.Var(&params.outputfmt, "format", "output `format`")
 	CmdConvert.Flag.Var(&params.outputfmt, "f", "shorthand for -format")
 	CmdConvert.Flag.Var(&params.dmMode, "dm-mode", "DM export mode: single or multi")
+	CmdConvert.Flag.StringVar(&params.dirTemplate, "dir-template", "", "channel directory naming `template` for the export and html formats, i.e. \"{{.Type}}/{{.Name}}-{{.ID}}\"")
+	CmdConvert.Flag.Var(&params.period, "period", "period of messages in each export file: day, week, month, year or single")
 	CmdConvert.Flag.Int64Var(&params.sessionID, "session", params.sessionID, "session `id` for database->chunk conversion")
 }
 
@@ -96,6 +114,14 @@ func runConvert(ctx context.Context, cmd *base.Command, args []string) error {
 		base.SetExitStatus(base.SInvalidParameters)
 		return errors.New("session id is required for database conversion")
 	}
+	if params.outStorageType == source.STcas && params.fileStore == "" {
+		base.SetExitStatus(base.SInvalidParameters)
+		return errNoFileStore
+	}
+	if _, err := params.channelNamer(); err != nil {
+		base.SetExitStatus(base.SInvalidParameters)
+		return fmt.Errorf("directory template: %w", err)
+	}
 	fn, exist := converters[params.outputfmt]
 	if !exist {
 		base.SetExitStatus(base.SInvalidParameters)
@@ -113,8 +139,16 @@ func runConvert(ctx context.Context, cmd *base.Command, args []string) error {
 	params.includeFiles = cfg.WithFiles
 	params.includeAvatars = cfg.WithAvatars
 
+	// converters need the local source, i.e. to open the chunk directory.
+	src, cleanup, err := bootstrap.FetchInput(ctx, args[0])
+	if err != nil {
+		base.SetExitStatus(base.SApplicationError)
+		return err
+	}
+	defer cleanup()
+
 	start := time.Now()
-	if err := fn(ctx, args[0], output, params); err != nil {
+	if err := fn(ctx, src, output, params); err != nil {
 		base.SetExitStatus(base.SApplicationError)
 		return err
 	}
@@ -132,6 +166,61 @@ func normalizeOutput(format datafmt, output string) string {
 	}
 }
 
+// channelNamer returns the channel namer for the directory template, or nil,
+// if the template is not set.
+func (cflg convertflags) channelNamer() (*nametmpl.ChannelNamer, error) {
+	if cflg.dirTemplate == "" {
+		return nil, nil
+	}
+	t, err := nametmpl.New(cflg.dirTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return nametmpl.NewChannelNamer(t), nil
+}
+
+// storeFS wraps the fs adapter fsa of the output directory dir, so that the
+// files are placed into the file store, if the output storage type is
+// [source.STcas].
+func storeFS(fsa fsadapter.FS, dir string, cflg convertflags) (fsadapter.FS, error) {
+	if cflg.outStorageType != source.STcas {
+		return fsa, nil
+	}
+	return cas.NewWriter(fsa, dir, cflg.fileStore)
+}
+
+// copyuploads copies the file attachments from fsys into the uploads
+// directory of the output directory trg, or into the file store, if the
+// output storage type is [source.STcas].
+func copyuploads(trg string, fsys fs.FS, cflg convertflags) error {
+	if cflg.outStorageType != source.STcas {
+		return copyfiles(filepath.Join(trg, chunk.UploadsDir), fsys)
+	}
+	w, err := cas.NewWriter(fsadapter.NewDirectory(trg), trg, cflg.fileStore)
+	if err != nil {
+		return err
+	}
+	return fs.WalkDir(fsys, ".", func(pth string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		src, err := fsys.Open(pth)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		dst, err := w.Create(path.Join(chunk.UploadsDir, pth))
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(dst, src); err != nil {
+			dst.Close()
+			return err
+		}
+		return dst.Close()
+	})
+}
+
 func copyfiles(trgdir string, fs fs.FS) error {
 	if err := os.MkdirAll(trgdir, 0o755); err != nil {
 		return err
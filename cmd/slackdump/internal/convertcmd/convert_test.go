@@ -16,12 +16,16 @@
 package convertcmd
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
+	"github.com/rusq/slackdump/v4/internal/cas"
 	"github.com/rusq/slackdump/v4/internal/fixtures"
+	"github.com/rusq/slackdump/v4/source"
 )
 
 func TestDatafmtSet_HTML(t *testing.T) {
@@ -140,3 +144,31 @@ func setConvertTestGlobals(t *testing.T, output string, format datafmt) {
 	cfg.WithFiles = false
 	cfg.WithAvatars = false
 }
+
+func TestCopyuploads_cas(t *testing.T) {
+	files := fstest.MapFS{
+		"F1/report.pdf": {Data: []byte("report")},
+		"F2/copy.pdf":   {Data: []byte("report")},
+	}
+	trg := t.TempDir()
+	cflg := convertflags{outStorageType: source.STcas, fileStore: t.TempDir()}
+	if err := copyuploads(trg, files, cflg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(trg, "__uploads")); !os.IsNotExist(err) {
+		t.Errorf("files must not be copied into the target, got: %v", err)
+	}
+	cfs, err := cas.Open(os.DirFS(trg), trg, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"__uploads/F1/report.pdf", "__uploads/F2/copy.pdf"} {
+		data, err := fs.ReadFile(cfs, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "report" {
+			t.Errorf("%s: got %q", name, data)
+		}
+	}
+}
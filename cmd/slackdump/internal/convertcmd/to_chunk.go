@@ -55,7 +55,7 @@ func toChunk(ctx context.Context, src, trg string, cflg convertflags) error {
 	}
 	if cflg.includeFiles && srcdb.Files().Type() != source.STnone {
 		slog.Info("Copying files...")
-		if err := copyfiles(filepath.Join(trg, chunk.UploadsDir), srcdb.Files().FS()); err != nil {
+		if err := copyuploads(trg, srcdb.Files().FS(), cflg); err != nil {
 			return err
 		}
 	}
@@ -52,13 +52,15 @@ func toDatabase(ctx context.Context, src, trg string, cflg convertflags) error {
 
 // dbConvertFast converts the chunk source to the database format.
 func dbConvertFast(ctx context.Context, src, trg string, cflg convertflags) error {
-	cd, err := chunk.OpenDir(src)
+	s, err := source.Load(ctx, src) // directory or ZIP file
 	if err != nil {
 		return err
 	}
-	defer cd.Close()
-	dsrc := source.OpenChunkDir(cd, true)
-	defer dsrc.Close()
+	defer s.Close()
+	dsrc, ok := s.(*source.ChunkDir)
+	if !ok {
+		return ErrSource
+	}
 
 	if err := chunk2db(ctx, dsrc, trg, cflg); err != nil {
 		return err
@@ -66,7 +68,7 @@ func dbConvertFast(ctx context.Context, src, trg string, cflg convertflags) erro
 
 	if cflg.includeFiles && dsrc.Files().Type() != source.STnone {
 		slog.Info("Copying files...")
-		if err := copyfiles(filepath.Join(trg, chunk.UploadsDir), dsrc.Files().FS()); err != nil {
+		if err := copyuploads(trg, dsrc.Files().FS(), cflg); err != nil {
 			return err
 		}
 	}
@@ -158,8 +160,12 @@ func dbConvert(ctx context.Context, src, dir string, cflg convertflags) error {
 			_ = os.RemoveAll(dir)
 		}
 	}()
-	fsa := fsadapter.NewDirectory(dir)
-	defer fsa.Close()
+	dirfsa := fsadapter.NewDirectory(dir)
+	defer dirfsa.Close()
+	fsa, err := storeFS(dirfsa, dir, cflg)
+	if err != nil {
+		return err
+	}
 
 	// create a new database
 	wconn, si, err := bootstrap.DatabaseWithSession(dir, "convert")
@@ -17,7 +17,10 @@ package convertcmd
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"path/filepath"
+	"strings"
 
 	"github.com/rusq/fsadapter"
 
@@ -39,14 +42,28 @@ func toExport(ctx context.Context, src, trg string, cflg convertflags) error {
 		return ErrMeaningless
 	}
 
-	fsa, err := fsadapter.New(trg)
+	if cflg.outStorageType == source.STcas && strings.EqualFold(filepath.Ext(trg), ".zip") {
+		return fmt.Errorf("%w: cas storage requires a directory output", ErrStorage)
+	}
+	trgfsa, err := fsadapter.New(trg)
+	if err != nil {
+		return err
+	}
+	defer trgfsa.Close()
+	fsa, err := storeFS(trgfsa, trg, cflg)
 	if err != nil {
 		return err
 	}
-	defer fsa.Close()
 
+	namer, err := cflg.channelNamer()
+	if err != nil {
+		return err
+	}
 	// output storage
 	sttFn, ok := cflg.outStorageType.Func()
+	if namer != nil {
+		sttFn, ok = cflg.outStorageType.FuncWithDir(namer.DirFunc(source.ExportChanName))
+	}
 	if !ok {
 		return ErrStorage
 	}
@@ -68,6 +85,8 @@ func toExport(ctx context.Context, src, trg string, cflg convertflags) error {
 		convert.WithIncludeFiles(includeFiles),
 		convert.WithIncludeAvatars(includeAvatars),
 		convert.WithDMMode(cflg.dmMode),
+		convert.WithExportPeriod(cflg.period),
+		convert.WithChannelNamer(namer),
 		convert.WithTrgFileLoc(sttFn),
 		convert.WithLogger(cfg.Log),
 	)
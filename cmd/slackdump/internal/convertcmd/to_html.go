@@ -25,7 +25,7 @@ import (
 	"github.com/rusq/slackdump/v4/source"
 )
 
-func toHTML(ctx context.Context, srcpath, trgdir string, _ convertflags) error {
+func toHTML(ctx context.Context, srcpath, trgdir string, cflg convertflags) error {
 	st, err := source.Type(srcpath)
 	if err != nil {
 		return err
@@ -34,13 +34,18 @@ func toHTML(ctx context.Context, srcpath, trgdir string, _ convertflags) error {
 		return ErrSource
 	}
 
+	namer, err := cflg.channelNamer()
+	if err != nil {
+		return err
+	}
+
 	src, err := source.Load(ctx, srcpath)
 	if err != nil {
 		return err
 	}
 	defer src.Close()
 
-	conv := convert.NewToHTML(src, fsadapter.NewDirectory(trgdir), convert.WithLogger(cfg.Log))
+	conv := convert.NewToHTML(src, fsadapter.NewDirectory(trgdir), convert.WithLogger(cfg.Log), convert.WithChannelNamer(namer))
 	if err := conv.Convert(ctx); err != nil {
 		return err
 	}
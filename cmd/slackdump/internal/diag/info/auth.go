@@ -21,11 +21,8 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 
-	"github.com/rusq/slackdump/v4/auth"
-	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/workspace"
 )
 
@@ -42,20 +39,11 @@ func CollectAuth(ctx context.Context, w io.Writer) error {
 	if err != nil {
 		return fmt.Errorf("cache error: %w", err)
 	}
-	fi, err := m.FileInfo(cur)
+	prov, err := m.LoadProvider(cur)
 	if err != nil {
 		return fmt.Errorf("cache error: %w", err)
 	}
-	f, err := m.Open(filepath.Join(cfg.CacheDir(), fi.Name()))
-	if err != nil {
-		return fmt.Errorf("cache error: %w", err)
-	}
-	defer f.Close()
-	prov, err := auth.Load(f)
-	if err != nil {
-		return fmt.Errorf("cache error: %w", err)
-	}
-	fmt.Fprintf(w, "TOKEN=%s\n", prov.Token)
+	fmt.Fprintf(w, "TOKEN=%s\n", prov.SlackToken())
 	if err := dumpCookiesMozilla(ctx, w, prov.Cookies()); err != nil {
 		return err
 	}
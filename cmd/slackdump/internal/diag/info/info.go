@@ -62,6 +62,7 @@ type SysInfo struct {
 	Slackdump  SlackdumpInfo `json:"slackdump"`
 	OS         OSInfo        `json:"os"`
 	Workspace  Workspace     `json:"workspace"`
+	RateLimits RateLimits    `json:"rate_limits"`
 	Playwright PwInfo        `json:"playwright"`
 	Rod        RodInfo       `json:"rod"`
 	EzLogin    EZLogin       `json:"ez_login"`
@@ -83,6 +84,7 @@ func collect(fn func(string) string) *SysInfo {
 	si := new(SysInfo)
 	collectors := []func(PathReplFunc){
 		si.Workspace.collect,
+		si.RateLimits.collect,
 		si.Playwright.collect,
 		si.Rod.collect,
 		si.EzLogin.collect,
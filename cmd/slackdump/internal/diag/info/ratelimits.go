@@ -0,0 +1,42 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package info
+
+import (
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/workspace"
+	"github.com/rusq/slackdump/v4/internal/network"
+)
+
+// RateLimits contains the rate limits learned by the adaptive limiter for
+// each team.
+type RateLimits struct {
+	Error string                        `json:"error,omitempty"`
+	Teams map[string][]network.TierRate `json:"teams,omitempty"`
+}
+
+func (inf *RateLimits) collect(PathReplFunc) {
+	m, err := workspace.CacheMgr()
+	if err != nil {
+		inf.Error = loser(err)
+		return
+	}
+	teams, err := m.AllLimits()
+	if err != nil {
+		inf.Error = loser(err)
+		return
+	}
+	inf.Teams = teams
+}
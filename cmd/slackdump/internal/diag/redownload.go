@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/bootstrap"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
@@ -32,11 +33,18 @@ var cmdRedownload = &base.Command{
 	Short:     "attempts to redownload missing files from the archive",
 	Long: `# File redownload tool
 Redownload tool scans the slackdump export, archive or dump directory,
-validating the files.
+validating the files.  Files are looked up in the file storage of the source,
+whatever its layout is:  standard or Mattermost export layout, dump layout,
+or the shared file store.
 
 If a file is missing or has zero length, it will be redownloaded from the Slack
 API. The tool will not overwrite existing files, so it is safe to run it
-multiple times.
+multiple times.  Use -since to check only the files of the recent messages.
+
+Files that can not be downloaded (deleted, external, hidden by the plan
+limits, or no longer available on Slack) are recorded in the
+"__unavailable.json" file in the archive directory, and are not retried by
+the subsequent runs.  Delete the file to retry all of them.
 
 **Please note:**
 
@@ -53,6 +61,7 @@ multiple times.
 
 type redownloadFlags struct {
 	dryRun bool
+	since  cfg.TimeValue
 }
 
 var redlFlags redownloadFlags
@@ -60,6 +69,7 @@ var redlFlags redownloadFlags
 func init() {
 	cmdRedownload.Flag.BoolVar(&redlFlags.dryRun, "dry", redlFlags.dryRun, "estimate amd print the size and count of files to be downloaded, do not download anything")
 	cmdRedownload.Flag.BoolVar(&redlFlags.dryRun, "estimate", redlFlags.dryRun, "alias for -dry")
+	cmdRedownload.Flag.Var(&redlFlags.since, "since", "check only files of the messages posted since this time (UTC timezone, `YYYY-MM-DDTHH:MM:SS`)")
 }
 
 func runRedownload(ctx context.Context, _ *base.Command, args []string) error {
@@ -69,7 +79,7 @@ func runRedownload(ctx context.Context, _ *base.Command, args []string) error {
 	}
 	dir := args[0]
 
-	rd, err := redownload.New(ctx, dir, redownload.WithLogger(cfg.Log))
+	rd, err := redownload.New(ctx, dir, redownload.WithLogger(cfg.Log), redownload.WithSince(time.Time(redlFlags.since)))
 	if err != nil {
 		return err
 	}
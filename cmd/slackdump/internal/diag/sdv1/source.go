@@ -27,6 +27,7 @@ import (
 
 	"github.com/rusq/slackdump/v4/internal/structures"
 	"github.com/rusq/slackdump/v4/source"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 type Source struct {
@@ -124,6 +125,19 @@ func (m Messages) ChannelInfo(ctx context.Context, channelID string) (*slack.Cha
 	return ci, nil
 }
 
+func (m Messages) ChannelMembership(ctx context.Context, channelID string) ([]types.MembershipEvent, error) {
+	if channelID != m.ChannelID {
+		return nil, source.ErrNotFound
+	}
+	var ee []types.MembershipEvent
+	for i := range m.Messages {
+		if ev, ok := types.MembershipFromMessage(&m.Messages[i]); ok {
+			ee = append(ee, ev)
+		}
+	}
+	return types.MergeMembership(ee, nil), nil
+}
+
 func (s Source) Files() source.Storage {
 	return s.fst
 }
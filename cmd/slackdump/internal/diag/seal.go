@@ -0,0 +1,163 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package diag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/bootstrap"
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/golang/base"
+	"github.com/rusq/slackdump/v4/internal/seal"
+	"github.com/rusq/slackdump/v4/source"
+)
+
+var cmdSeal = &base.Command{
+	UsageLine:  "slackdump tools seal [flags] <archive>",
+	Short:      "encrypt the database archive with a passphrase",
+	FlagMask:   cfg.OmitAll,
+	PrintFlags: true,
+	Long: `# Seal Command
+
+Seal encrypts all files of the database archive directory — the database and
+the downloaded files, avatars and emoji — in place, with the key derived from
+the passphrase, or from the contents of the key file.  Files are encrypted
+with AES-256-GCM, and the key is derived with scrypt.
+
+The sealed archive can be read by "view", "convert", "mcp" and "resume" as
+usual, they ask for the passphrase when opening the archive.  To run them
+unattended, set one of the environment variables:
+
+- ARCHIVE_PASSPHRASE — the passphrase;
+- ARCHIVE_KEY_FILE — the path to the key file.
+
+The database is decrypted as it is read, and is never written to disk in
+plaintext.  "resume" decrypts the database while it runs, and encrypts it again
+when it finishes, the downloaded files stay encrypted, and the new ones are
+encrypted as they are downloaded.  If it is interrupted, the database is left
+in plaintext with the ".slackdump-unsealed" marker file; other commands refuse
+to open such archive, and the next "resume" or "seal" encrypts it again.  To
+encrypt a new archive as it is created, run "archive" with the -encrypt flag.
+
+Only database archive directories can be sealed.  Chunk directories, exports,
+dumps and ZIP files are not supported, chunk directories, exports and dumps
+can be converted to a database archive with "slackdump convert -f database".
+
+There is no way to recover the archive if the passphrase or the key file is
+lost.
+
+Example:
+
+    slackdump tools seal slackdump_20260101_000000
+`,
+}
+
+var cmdUnseal = &base.Command{
+	UsageLine:  "slackdump tools unseal [flags] <archive>",
+	Short:      "decrypt the database archive encrypted with seal",
+	FlagMask:   cfg.OmitAll,
+	PrintFlags: true,
+	Long: `# Unseal Command
+
+Unseal decrypts all files of the database archive directory, that was
+encrypted with "slackdump tools seal", or "slackdump archive -encrypt", in
+place.
+
+Example:
+
+    slackdump tools unseal slackdump_20260101_000000
+`,
+}
+
+func init() {
+	cmdSeal.Run = runSeal
+	cmdSeal.Flag.StringVar(&cfg.ArchiveKeyFile, "key-file", "", "use the contents of the key `file` instead of the passphrase")
+	cmdUnseal.Run = runUnseal
+	cmdUnseal.Flag.StringVar(&cfg.ArchiveKeyFile, "key-file", "", "use the contents of the key `file` instead of the passphrase")
+}
+
+// sealArgs validates the arguments and returns the archive directory.
+func sealArgs(cmd *base.Command, args []string) (string, error) {
+	if err := cmd.Flag.Parse(args); err != nil {
+		base.SetExitStatus(base.SInvalidParameters)
+		return "", err
+	}
+	if cmd.Flag.NArg() != 1 {
+		base.SetExitStatus(base.SInvalidParameters)
+		return "", errors.New("archive directory is required")
+	}
+	dir := cmd.Flag.Arg(0)
+	st, err := source.Type(dir)
+	if err != nil {
+		base.SetExitStatus(base.SUserError)
+		return "", err
+	}
+	if !st.Has(source.FDatabase | source.FDirectory) {
+		base.SetExitStatus(base.SUserError)
+		return "", fmt.Errorf("%s: not a database archive directory", dir)
+	}
+	return dir, nil
+}
+
+func runSeal(ctx context.Context, cmd *base.Command, args []string) error {
+	dir, err := sealArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	if bootstrap.IsSealedArchive(dir) && !seal.IsPending(dir) {
+		base.SetExitStatus(base.SUserError)
+		return fmt.Errorf("%s: archive is already encrypted", dir)
+	}
+	secret, err := bootstrap.NewArchiveSecret()
+	if err != nil {
+		base.SetExitStatus(base.SUserError)
+		return err
+	}
+	if err := bootstrap.SealArchive(seal.WithSecret(ctx, secret), dir); err != nil {
+		base.SetExitStatus(base.SApplicationError)
+		return err
+	}
+	return nil
+}
+
+func runUnseal(ctx context.Context, cmd *base.Command, args []string) error {
+	dir, err := sealArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	if !bootstrap.IsSealedArchive(dir) {
+		base.SetExitStatus(base.SUserError)
+		return fmt.Errorf("%s: archive is not encrypted", dir)
+	}
+	secret, err := bootstrap.ArchiveSecret()
+	if err != nil {
+		base.SetExitStatus(base.SUserError)
+		return err
+	}
+	n, err := seal.UnsealDir(dir, secret)
+	if err != nil {
+		base.SetExitStatus(base.SApplicationError)
+		return err
+	}
+	if err := seal.ClearPending(dir); err != nil {
+		base.SetExitStatus(base.SApplicationError)
+		return err
+	}
+	cfg.Log.InfoContext(ctx, "archive decrypted", "directory", dir, "files", n)
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package diag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/golang/base"
+	"github.com/rusq/slackdump/v4/internal/mockslack"
+	"github.com/rusq/slackdump/v4/source"
+)
+
+var cmdServeMock = &base.Command{
+	UsageLine:  "slackdump tools serve-mock [flags] <source>",
+	Short:      "serve an archive as a fake Slack API for testing",
+	FlagMask:   cfg.OmitAll,
+	PrintFlags: true,
+	Long: `# Serve Mock Command
+
+Serve Mock starts a fake Slack Web API server, that serves the contents of any
+source supported by Slackdump (database, export or dump) as if it was a real
+workspace.  It is useful for integration testing of the tools built on
+Slackdump, without the need to have access to a real workspace.
+
+The following API methods are supported:
+- auth.test;
+- conversations.list, conversations.info, conversations.members;
+- conversations.history, conversations.replies;
+- users.list, users.info;
+- files.info.
+
+File download URLs in messages are rewritten to point to the mock server, and
+the file contents are served from the source, if it has them.
+
+The server can simulate the slow network with the -latency flag, and the
+rate limiting, by responding with HTTP 429 "Too Many Requests" to every n-th
+API request with the -rate-limit-every flag.  File downloads are not rate
+limited.
+
+Point the Slack client at the API URL printed on startup, i.e.:
+
+    slackdump tools serve-mock -listen 127.0.0.1:8081 slackdump_20260101_000000
+
+serves the API at http://127.0.0.1:8081/api/.
+`,
+}
+
+var serveMockFlags struct {
+	listen         string
+	latency        time.Duration
+	rateLimitEvery int
+	retryAfter     time.Duration
+}
+
+func init() {
+	cmdServeMock.Run = runServeMock
+	cmdServeMock.Flag.StringVar(&serveMockFlags.listen, "listen", "127.0.0.1:8081", "address to listen on")
+	cmdServeMock.Flag.DurationVar(&serveMockFlags.latency, "latency", 0, "delay every response by this `duration`")
+	cmdServeMock.Flag.IntVar(&serveMockFlags.rateLimitEvery, "rate-limit-every", 0, "respond with HTTP 429 to every `n`-th API request, 0 disables")
+	cmdServeMock.Flag.DurationVar(&serveMockFlags.retryAfter, "retry-after", time.Second, "Retry-After `duration` of the injected rate limit responses")
+}
+
+func runServeMock(ctx context.Context, cmd *base.Command, args []string) error {
+	if err := cmd.Flag.Parse(args); err != nil {
+		base.SetExitStatus(base.SInvalidParameters)
+		return err
+	}
+	if cmd.Flag.NArg() != 1 {
+		base.SetExitStatus(base.SInvalidParameters)
+		return errors.New("source is required")
+	}
+
+	src, err := source.Load(ctx, cmd.Flag.Arg(0))
+	if err != nil {
+		base.SetExitStatus(base.SUserError)
+		return err
+	}
+	defer src.Close()
+
+	lg := cfg.Log
+	srv := &http.Server{
+		Handler: mockslack.New(src,
+			mockslack.WithLatency(serveMockFlags.latency),
+			mockslack.WithRateLimit(serveMockFlags.rateLimitEvery, serveMockFlags.retryAfter),
+			mockslack.WithLogger(lg),
+		),
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	l, err := net.Listen("tcp", serveMockFlags.listen)
+	if err != nil {
+		base.SetExitStatus(base.SApplicationError)
+		return err
+	}
+	// sentinel
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	fmt.Printf("Serving %s at http://%s/api/\nPress Ctrl+C to stop.\n", src.Name(), l.Addr())
+	if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		base.SetExitStatus(base.SApplicationError)
+		return err
+	}
+	return nil
+}
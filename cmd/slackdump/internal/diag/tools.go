@@ -50,10 +50,14 @@ Tools command contains different tools, running which may be requested if you op
 		cmdObfuscate,
 		cmdRecord,
 		cmdRedownload,
+		cmdSeal,
 		// cmdSearch,
+		cmdServeMock,
 		cmdThread,
 		cmdUninstall,
+		cmdUnseal,
 		cmdUnzip,
 		cmdUpdate,
+		cmdUserHistory,
 	},
 }
@@ -0,0 +1,112 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package diag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/golang/base"
+	"github.com/rusq/slackdump/v4/source"
+	"github.com/rusq/slackdump/v4/types"
+)
+
+var cmdUserHistory = &base.Command{
+	UsageLine:  "slackdump tools user-history [flags] <archive> <user_id>",
+	Short:      "show the profile changes of the user across archive sessions",
+	FlagMask:   cfg.OmitAll,
+	PrintFlags: true,
+	Long: `# User History Command
+
+User history shows how the profile of the user, i.e. the username, real and
+display name, title, status, or deactivation, changed between the sessions
+that recorded the archive.  Each archive or resume run records the users anew,
+so the history is only available for database archives that were resumed, or
+archived more than once.
+
+Example:
+
+    slackdump tools user-history slackdump_20260101_000000 U12345678
+`,
+}
+
+var userHistoryFlags struct {
+	json bool
+}
+
+func init() {
+	cmdUserHistory.Run = runUserHistory
+	cmdUserHistory.Flag.BoolVar(&userHistoryFlags.json, "json", false, "output the changes as JSON")
+}
+
+func runUserHistory(ctx context.Context, cmd *base.Command, args []string) error {
+	if err := cmd.Flag.Parse(args); err != nil {
+		base.SetExitStatus(base.SInvalidParameters)
+		return err
+	}
+	if cmd.Flag.NArg() != 2 {
+		base.SetExitStatus(base.SInvalidParameters)
+		return errors.New("archive and user ID are required")
+	}
+	loc, userID := cmd.Flag.Arg(0), cmd.Flag.Arg(1)
+
+	src, err := source.Load(ctx, loc)
+	if err != nil {
+		base.SetExitStatus(base.SUserError)
+		return err
+	}
+	defer src.Close()
+
+	uh, ok := src.(source.UserHistorian)
+	if !ok {
+		base.SetExitStatus(base.SInvalidParameters)
+		return fmt.Errorf("source type %q does not keep the user history, use 'slackdump convert -f database' to convert it", src.Type())
+	}
+	changes, err := uh.UserHistory(ctx, userID)
+	if err != nil {
+		if errors.Is(err, source.ErrNotFound) {
+			base.SetExitStatus(base.SUserError)
+			return fmt.Errorf("user %s not found in %s", userID, loc)
+		}
+		base.SetExitStatus(base.SApplicationError)
+		return err
+	}
+	if userHistoryFlags.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(changes)
+	}
+	return printUserHistory(os.Stdout, changes)
+}
+
+func printUserHistory(w io.Writer, changes []types.UserChange) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "No profile changes recorded.")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Recorded\tSession\tField\tBefore\tAfter")
+	for _, c := range changes {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n", c.Recorded.Local().Format("2006-01-02 15:04:05"), c.SessionID, c.Field, c.Old, c.New)
+	}
+	return tw.Flush()
+}
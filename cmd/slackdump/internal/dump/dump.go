@@ -136,6 +136,7 @@ func RunDump(ctx context.Context, _ *base.Command, args []string) error {
 		base.SetExitStatus(base.SInitializationError)
 		return err
 	}
+	defer bootstrap.Finish(ctx, client)
 
 	p := dumpparams{
 		list:          list,
@@ -238,6 +239,7 @@ func dumpv3(ctx context.Context, sess client.Slack, fsa fsadapter.FS, p dumppara
 		stream.OptOldest(time.Time(cfg.Oldest)),
 		stream.OptLatest(time.Time(cfg.Latest)),
 		stream.OptFailOnNonCritError(cfg.FailOnNonCritical),
+		stream.OptAdaptive(bootstrap.Adaptive()),
 		stream.OptResultFn(func(sr stream.Result) error {
 			if sr.Err != nil {
 				return sr.Err
@@ -334,6 +336,7 @@ func dumpv31(ctx context.Context, client client.Slack, fsa fsadapter.FS, p dumpp
 		stream.OptOldest(time.Time(cfg.Oldest)),
 		stream.OptLatest(time.Time(cfg.Latest)),
 		stream.OptFailOnNonCritError(cfg.FailOnNonCritical),
+		stream.OptAdaptive(bootstrap.Adaptive()),
 		stream.OptResultFn(func(sr stream.Result) error {
 			if sr.Err != nil {
 				return sr.Err
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -32,7 +34,12 @@ import (
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/emoji/emojidl"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/golang/base"
+	"github.com/rusq/slackdump/v4/internal/chunk"
+	"github.com/rusq/slackdump/v4/internal/chunk/backend/dbase"
 	"github.com/rusq/slackdump/v4/internal/client"
+	"github.com/rusq/slackdump/v4/internal/convert/transform/fileproc"
+	"github.com/rusq/slackdump/v4/source"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 //go:embed assets/emoji.md
@@ -67,10 +74,34 @@ func init() {
 }
 
 func run(ctx context.Context, cmd *base.Command, args []string) error {
-	if err := bootstrap.AskOverwrite(cfg.Output); err != nil {
+	var (
+		dir = cfg.Output
+		dbp *dbase.DBP
+	)
+	if isDatabase(cfg.Output) {
+		// emoji are recorded into the archive database, and the images are
+		// downloaded into the archive directory.
+		conn, err := bootstrap.Database(cfg.Output)
+		if err != nil {
+			base.SetExitStatus(base.SInitializationError)
+			return err
+		}
+		defer conn.Close()
+		dbp, err = dbase.New(ctx, conn, bootstrap.SessionInfo(cmd.Name()))
+		if err != nil {
+			base.SetExitStatus(base.SInitializationError)
+			return err
+		}
+		defer dbp.Close()
+		if fi, err := os.Stat(cfg.Output); err == nil && !fi.IsDir() {
+			dir = filepath.Dir(cfg.Output)
+		}
+		cmdFlags.Recorder = &dbRecorder{dbp: dbp, withFiles: cfg.WithFiles}
+		cmdFlags.Dir = chunk.EmojiDir
+	} else if err := bootstrap.AskOverwrite(cfg.Output); err != nil {
 		return err
 	}
-	fsa, err := fsadapter.New(cfg.Output)
+	fsa, err := fsadapter.New(dir)
 	if err != nil {
 		return err
 	}
@@ -98,7 +129,42 @@ func run(ctx context.Context, cmd *base.Command, args []string) error {
 		return err
 	}
 
-	slog.InfoContext(ctx, "Emojis downloaded", "dir", cfg.Output, "took", time.Since(start).String())
+	if dbp != nil {
+		if err := dbp.Finish(); err != nil {
+			base.SetExitStatus(base.SApplicationError)
+			return err
+		}
+	}
+
+	slog.InfoContext(ctx, "Emojis downloaded", "dir", dir, "took", time.Since(start).String())
+	return nil
+}
+
+// isDatabase returns true if the output is an existing database archive.
+func isDatabase(output string) bool {
+	st, err := source.Type(output)
+	if err != nil {
+		return false
+	}
+	return st.Has(source.FDatabase)
+}
+
+// dbRecorder records the emoji into the archive database.
+type dbRecorder struct {
+	dbp       *dbase.DBP
+	withFiles bool
+}
+
+func (r *dbRecorder) RecordEmojis(ctx context.Context, emojis []types.Emoji) error {
+	filenameFn := func(*types.Emoji) string { return "" }
+	if r.withFiles {
+		filenameFn = fileproc.EmojiPath
+	}
+	n, err := r.dbp.InsertEmojis(ctx, emojis, filenameFn)
+	if err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "recorded emojis", "count", n)
 	return nil
 }
 
@@ -61,7 +61,7 @@ func DlEdgeFS(ctx context.Context, sess EdgeEmojiLister, fsa fsadapter.FS, opt *
 	defer cancel()
 
 	lg := cfg.Log
-	lg.DebugContext(ctx, "startup params", "dir", emojiDir, "numWorkers", numWorkers, "failFast", opt.FailFast)
+	lg.DebugContext(ctx, "startup params", "dir", opt.dir(), "numWorkers", numWorkers, "failFast", opt.FailFast)
 	if cb == nil {
 		cb = func(name string, total, count int) {}
 	}
@@ -115,7 +115,7 @@ func DlEdgeFS(ctx context.Context, sess EdgeEmojiLister, fsa fsadapter.FS, opt *
 	var wg sync.WaitGroup
 	for range numWorkers {
 		wg.Go(func() {
-			workerFn(ctx, fsa, emojiC, resultC)
+			workerFn(ctx, fsa, opt.dir(), emojiC, resultC)
 		})
 	}
 	// 3. Sentinel, closes the result channel once all workers are finished.
@@ -170,6 +170,13 @@ func DlEdgeFS(ctx context.Context, sess EdgeEmojiLister, fsa fsadapter.FS, opt *
 			cb(res.emoji.Name, total, count)
 		}
 	}
+	if opt.Recorder != nil {
+		ee := make([]edge.Emoji, 0, len(emojis))
+		for _, em := range emojis {
+			ee = append(ee, em)
+		}
+		return opt.Recorder.RecordEmojis(ctx, ee)
+	}
 	out, err := fsa.Create("index.json")
 	if err != nil {
 		return err
@@ -191,7 +198,7 @@ type result struct {
 // worker is the function that runs in a separate goroutine and downloads emoji
 // received from emojiC. The result of the operation is sent to resultC channel.
 // fn is called for each received emoji.
-func worker(ctx context.Context, fsa fsadapter.FS, emojiC <-chan edge.Emoji, resultC chan<- result) {
+func worker(ctx context.Context, fsa fsadapter.FS, dir string, emojiC <-chan edge.Emoji, resultC chan<- result) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -207,7 +214,7 @@ func worker(ctx context.Context, fsa fsadapter.FS, emojiC <-chan edge.Emoji, res
 				}
 				break
 			}
-			err := fetchFn(ctx, fsa, emojiDir, em.Name, em.URL)
+			err := fetchFn(ctx, fsa, dir, em.Name, em.URL)
 			if !sendResult(ctx, resultC, result{emoji: em, err: err}) {
 				return
 			}
@@ -215,7 +222,7 @@ func worker(ctx context.Context, fsa fsadapter.FS, emojiC <-chan edge.Emoji, res
 	}
 }
 
-func nofetchworker(ctx context.Context, _ fsadapter.FS, emojiC <-chan edge.Emoji, resultC chan<- result) {
+func nofetchworker(ctx context.Context, _ fsadapter.FS, _ string, emojiC <-chan edge.Emoji, resultC chan<- result) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -38,13 +38,12 @@ import (
 	"io"
 	"net/http"
 	"path"
-	"strings"
 	"sync"
 
 	"github.com/rusq/fsadapter"
 
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
-	"github.com/rusq/slackdump/v4/internal/edge"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 const (
@@ -62,6 +61,24 @@ type EmojiDumper interface {
 type Options struct {
 	FailFast  bool
 	WithFiles bool
+	// Recorder, if set, receives the emoji list once it is retrieved, and
+	// the index.json is not written.
+	Recorder Recorder
+	// Dir is the directory within the output filesystem, where the emoji
+	// images are saved.  If empty, "emojis" is used.
+	Dir string
+}
+
+func (o *Options) dir() string {
+	if o.Dir == "" {
+		return emojiDir
+	}
+	return o.Dir
+}
+
+// Recorder records the retrieved emoji list, i.e. into the database.
+type Recorder interface {
+	RecordEmojis(ctx context.Context, emojis []types.Emoji) error
 }
 
 // DlFS downloads all emojis from the workspace and saves them to the fsa.
@@ -74,16 +91,26 @@ func DlFS(ctx context.Context, sess EmojiDumper, fsa fsadapter.FS, opt *Options,
 		return fmt.Errorf("error during emoji dump: %w", err)
 	}
 
-	bIndex, err := json.Marshal(emojis)
-	if err != nil {
-		return fmt.Errorf("error marshalling emoji index: %w", err)
-	}
-	if err := fsa.WriteFile("index.json", bIndex, 0o644); err != nil {
-		return fmt.Errorf("failed writing emoji index: %w", err)
+	if opt.Recorder != nil {
+		ee := make([]types.Emoji, 0, len(emojis))
+		for name, uri := range emojis {
+			ee = append(ee, types.LegacyEmoji(name, uri))
+		}
+		if err := opt.Recorder.RecordEmojis(ctx, ee); err != nil {
+			return fmt.Errorf("failed recording emoji index: %w", err)
+		}
+	} else {
+		bIndex, err := json.Marshal(emojis)
+		if err != nil {
+			return fmt.Errorf("error marshalling emoji index: %w", err)
+		}
+		if err := fsa.WriteFile("index.json", bIndex, 0o644); err != nil {
+			return fmt.Errorf("failed writing emoji index: %w", err)
+		}
 	}
 
 	if opt.WithFiles {
-		if err := fetch(ctx, fsa, emojis, opt.FailFast, cb); err != nil {
+		if err := fetch(ctx, fsa, opt.dir(), emojis, opt.FailFast, cb); err != nil {
 			return fmt.Errorf("failed downloading emojis: %w", err)
 		}
 	} else {
@@ -92,33 +119,21 @@ func DlFS(ctx context.Context, sess EmojiDumper, fsa fsadapter.FS, opt *Options,
 	return nil
 }
 
-func ift[T any](cond bool, t, f T) T {
-	if cond {
-		return t
-	}
-	return f
-}
-
 // fetch downloads the emojis and saves them to the fsa. It spawns numWorker
 // goroutines for getting the files. It will call fetchFn for each emoji.
-func fetch(ctx context.Context, fsa fsadapter.FS, emojis map[string]string, failFast bool, cb StatusFunc) error {
+func fetch(ctx context.Context, fsa fsadapter.FS, dir string, emojis map[string]string, failFast bool, cb StatusFunc) error {
 	lg := cfg.Log
-	lg.DebugContext(ctx, "startup params", "dir", emojiDir, "numWorkers", numWorkers, "failFast", failFast)
+	lg.DebugContext(ctx, "startup params", "dir", dir, "numWorkers", numWorkers, "failFast", failFast)
 
 	if cb == nil {
 		cb = func(name string, total, count int) {}
 	}
 
 	var (
-		emojiC  = make(chan edge.Emoji)
+		emojiC  = make(chan types.Emoji)
 		resultC = make(chan result)
 	)
 
-	const (
-		aliasPrefix = "alias:"
-		aliasLen    = len(aliasPrefix)
-	)
-
 	// Async download pipeline.
 
 	// 1. generator, send emojis into the emojiC channel.
@@ -126,17 +141,10 @@ func fetch(ctx context.Context, fsa fsadapter.FS, emojis map[string]string, fail
 		defer close(emojiC)
 
 		for name, uri := range emojis {
-			isAlias := strings.HasPrefix(uri, aliasPrefix)
-			emoji := edge.Emoji{
-				Name:     name,
-				URL:      uri,
-				IsAlias:  ift(isAlias, 1, 0),
-				AliasFor: ift(isAlias, uri[aliasLen:], ""),
-			}
 			select {
 			case <-ctx.Done():
 				return
-			case emojiC <- emoji:
+			case emojiC <- types.LegacyEmoji(name, uri):
 			}
 		}
 	}()
@@ -145,7 +153,7 @@ func fetch(ctx context.Context, fsa fsadapter.FS, emojis map[string]string, fail
 	var wg sync.WaitGroup
 	for range numWorkers {
 		wg.Go(func() {
-			worker(ctx, fsa, emojiC, resultC)
+			worker(ctx, fsa, dir, emojiC, resultC)
 		})
 	}
 	// 3. Sentinel, closes the result channel once all workers are finished.
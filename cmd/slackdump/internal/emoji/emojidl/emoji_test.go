@@ -211,7 +211,7 @@ func Test_worker(t *testing.T) {
 
 			var wg sync.WaitGroup
 			wg.Go(func() {
-				worker(tt.args.ctx, fsa, tt.args.emojiC, resultC)
+				worker(tt.args.ctx, fsa, emojiDir, tt.args.emojiC, resultC)
 			})
 			go func() {
 				wg.Wait()
@@ -240,7 +240,7 @@ func Test_fetch(t *testing.T) {
 		return nil
 	})
 
-	err := fetch(t.Context(), fsa, emojis, true, nil)
+	err := fetch(t.Context(), fsa, emojiDir, emojis, true, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 	}
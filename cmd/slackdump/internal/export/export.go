@@ -32,7 +32,10 @@ import (
 
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/golang/base"
+	"github.com/rusq/slackdump/v4/internal/convert/transform/fileproc"
+	"github.com/rusq/slackdump/v4/internal/nametmpl"
 	"github.com/rusq/slackdump/v4/internal/structures"
+	"github.com/rusq/slackdump/v4/processor"
 )
 
 var CmdExport = &base.Command{
@@ -53,15 +56,22 @@ var mdExport string
 type exportFlags struct {
 	ExportStorageType source.StorageType
 	ExportToken       string
+	Period            source.ExportPeriod
+	DirTemplate       string // channel directory naming template
 }
 
 var options = exportFlags{
 	ExportStorageType: source.STmattermost,
+	Period:            source.PeriodDay,
 }
 
 func init() {
 	CmdExport.Flag.Var(&options.ExportStorageType, "type", "export file storage type")
 	CmdExport.Flag.StringVar(&options.ExportToken, "export-token", "", "file export token to append to each of the file URLs")
+	CmdExport.Flag.StringVar(&options.DirTemplate, "dir-template", "", "channel directory naming `template`, i.e. \"{{.Type}}/{{.Name}}-{{.ID}}\", see \"slackdump help export\"")
+	CmdExport.Flag.Var(&options.Period, "period", "period of messages in each channel file: day, week, month, year or single")
+	CmdExport.Flag.BoolVar(&cfg.WithEmoji, "emoji", false, "export custom workspace emoji into emoji.json and download their images (placed in __emoji directory)")
+	cfg.SetPoolFlags(&CmdExport.Flag)
 
 	CmdExport.Run = runExport
 	CmdExport.Wizard = wizExport
@@ -76,6 +86,10 @@ func runExport(ctx context.Context, cmd *base.Command, args []string) error {
 	if !cfg.WithFiles {
 		options.ExportStorageType = source.STnone
 	}
+	if _, err := options.channelNamer(); err != nil {
+		base.SetExitStatus(base.SInvalidParameters)
+		return fmt.Errorf("directory template: %w", err)
+	}
 	list, err := structures.NewEntityList(args)
 	if err != nil {
 		base.SetExitStatus(base.SUserError)
@@ -90,6 +104,7 @@ func runExport(ctx context.Context, cmd *base.Command, args []string) error {
 		base.SetExitStatus(base.SInitializationError)
 		return err
 	}
+	defer bootstrap.Finish(ctx, client)
 
 	fsa, err := fsadapter.New(cfg.Output)
 	if err != nil {
@@ -120,3 +135,25 @@ func runExport(ctx context.Context, cmd *base.Command, args []string) error {
 	lg.InfoContext(ctx, "export completed", "output", cfg.Output, "took", time.Since(start).String())
 	return nil
 }
+
+// channelNamer returns the channel namer for the directory template, or nil,
+// if the template is not set.
+func (fl *exportFlags) channelNamer() (*nametmpl.ChannelNamer, error) {
+	if fl.DirTemplate == "" {
+		return nil, nil
+	}
+	t, err := nametmpl.New(fl.DirTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return nametmpl.NewChannelNamer(t), nil
+}
+
+// fileproc returns the export file processor, that places the files into
+// the channel directories named by the namer, if it is set.
+func (fl *exportFlags) fileproc(dl fileproc.Downloader, namer *nametmpl.ChannelNamer) processor.Filer {
+	if namer == nil {
+		return fileproc.NewExport(fl.ExportStorageType, dl)
+	}
+	return fileproc.NewExportWithDir(fl.ExportStorageType, dl, namer.DirFunc(source.ExportChanName))
+}
@@ -68,16 +68,28 @@ func exportWithDB(ctx context.Context, sess client.Slack, fsa fsadapter.FS, list
 		defer func() { _ = os.RemoveAll(tmpdir) }()
 	}
 
-	conv := transform.NewExpConverter(src, fsa, transform.ExpWithMsgUpdateFunc(fileproc.ExportTokenUpdateFn(params.ExportToken)))
+	namer, err := params.channelNamer()
+	if err != nil {
+		return err
+	}
+	conv := transform.NewExpConverter(
+		src,
+		fsa,
+		transform.ExpWithMsgUpdateFunc(fileproc.ExportTokenUpdateFn(params.ExportToken)),
+		transform.ExpWithPeriod(params.Period),
+		transform.ExpWithChannelNamer(namer),
+	)
 	tf := transform.NewExportCoordinator(ctx, conv, transform.WithBufferSize(1000))
 	defer tf.Close()
 
 	// starting the downloader
 	dlEnabled := cfg.WithFiles && params.ExportStorageType != source.STnone
 	fdl := fileproc.NewDownloader(ctx, dlEnabled, sess, fsa, lg)
-	fp := fileproc.NewExport(params.ExportStorageType, fdl)
+	fp := params.fileproc(fdl, namer)
 	avdl := fileproc.NewDownloader(ctx, cfg.WithAvatars, sess, fsa, lg)
 	avp := fileproc.NewAvatarProc(avdl)
+	emdl := fileproc.NewDownloader(ctx, cfg.WithEmoji, sess, fsa, lg)
+	emp := fileproc.NewEmojiProc(emdl)
 
 	lg.InfoContext(ctx, "running export...")
 	pb := bootstrap.ProgressBar(ctx, lg, progressbar.OptionShowCount()) // progress bar
@@ -87,6 +99,7 @@ func exportWithDB(ctx context.Context, sess client.Slack, fsa fsadapter.FS, list
 		stream.OptOldest(time.Time(cfg.Oldest)),
 		stream.OptLatest(time.Time(cfg.Latest)),
 		stream.OptFailOnNonCritError(cfg.FailOnNonCritical),
+		stream.OptAdaptive(bootstrap.Adaptive()),
 		stream.OptResultFn(func(sr stream.Result) error {
 			lg.DebugContext(ctx, "conversations", "sr", sr.String())
 			pb.Describe(sr.String())
@@ -101,6 +114,7 @@ func exportWithDB(ctx context.Context, sess client.Slack, fsa fsadapter.FS, list
 		ChannelUsers:  cfg.OnlyChannelUsers,
 		ChannelTypes:  cfg.ChannelTypes,
 		IncludeLabels: cfg.IncludeCustomLabels,
+		Emojis:        cfg.WithEmoji,
 	}
 	ctr, err := control.New(
 		ctx,
@@ -111,6 +125,7 @@ func exportWithDB(ctx context.Context, sess client.Slack, fsa fsadapter.FS, list
 		control.WithFlags(flags),
 		control.WithCoordinator(tf),
 		control.WithAvatarProcessor(avp),
+		control.WithEmojiProcessor(emp),
 	)
 	if err != nil {
 		return fmt.Errorf("error creating db controller: %w", err)
@@ -160,16 +175,28 @@ func exportWithDir(ctx context.Context, sess client.Slack, fsa fsadapter.FS, lis
 		defer func() { _ = chunkdir.RemoveAll() }()
 	}
 	src := source.OpenChunkDir(chunkdir, true)
-	conv := transform.NewExpConverter(src, fsa, transform.ExpWithMsgUpdateFunc(fileproc.ExportTokenUpdateFn(params.ExportToken)))
+	namer, err := params.channelNamer()
+	if err != nil {
+		return err
+	}
+	conv := transform.NewExpConverter(
+		src,
+		fsa,
+		transform.ExpWithMsgUpdateFunc(fileproc.ExportTokenUpdateFn(params.ExportToken)),
+		transform.ExpWithPeriod(params.Period),
+		transform.ExpWithChannelNamer(namer),
+	)
 	tf := transform.NewExportCoordinator(ctx, conv, transform.WithBufferSize(1000))
 	defer tf.Close()
 
 	// starting the downloader
 	dlEnabled := cfg.WithFiles && params.ExportStorageType != source.STnone
 	fdl := fileproc.NewDownloader(ctx, dlEnabled, sess, fsa, lg)
-	fp := fileproc.NewExport(params.ExportStorageType, fdl)
+	fp := params.fileproc(fdl, namer)
 	avdl := fileproc.NewDownloader(ctx, cfg.WithAvatars, sess, fsa, lg)
 	avp := fileproc.NewAvatarProc(avdl)
+	emdl := fileproc.NewDownloader(ctx, cfg.WithEmoji, sess, fsa, lg)
+	emp := fileproc.NewEmojiProc(emdl)
 
 	lg.InfoContext(ctx, "running export...")
 	pb := bootstrap.ProgressBar(ctx, lg, progressbar.OptionShowCount()) // progress bar
@@ -178,6 +205,7 @@ func exportWithDir(ctx context.Context, sess client.Slack, fsa fsadapter.FS, lis
 		stream.OptOldest(time.Time(cfg.Oldest)),
 		stream.OptLatest(time.Time(cfg.Latest)),
 		stream.OptFailOnNonCritError(cfg.FailOnNonCritical),
+		stream.OptAdaptive(bootstrap.Adaptive()),
 		stream.OptResultFn(func(sr stream.Result) error {
 			lg.DebugContext(ctx, "conversations", "sr", sr.String())
 			pb.Describe(sr.String())
@@ -192,6 +220,7 @@ func exportWithDir(ctx context.Context, sess client.Slack, fsa fsadapter.FS, lis
 		ChannelUsers:  cfg.OnlyChannelUsers,
 		IncludeLabels: cfg.IncludeCustomLabels,
 		ChannelTypes:  cfg.ChannelTypes,
+		Emojis:        cfg.WithEmoji,
 	}
 	ctr := control.NewDir(
 		chunkdir,
@@ -201,6 +230,7 @@ func exportWithDir(ctx context.Context, sess client.Slack, fsa fsadapter.FS, lis
 		control.WithFlags(flags),
 		control.WithCoordinator(tf),
 		control.WithAvatarProcessor(avp),
+		control.WithEmojiProcessor(emp),
 	)
 	defer ctr.Close()
 
@@ -65,6 +65,21 @@ func (fl *exportFlags) configuration() cfgui.Configuration {
 							huh.NewOption("Disable", source.STnone),
 						)),
 				},
+				{
+					Name:        "Message File Period",
+					Value:       fl.Period.String(),
+					Description: "Period of messages in each channel file",
+					Inline:      false,
+					Updater: updaters.NewPicklist(&fl.Period, huh.NewSelect[source.ExportPeriod]().
+						Title("Choose the period of messages in each file").
+						Options(
+							huh.NewOption("Day (Slack default)", source.PeriodDay),
+							huh.NewOption("Week", source.PeriodWeek),
+							huh.NewOption("Month", source.PeriodMonth),
+							huh.NewOption("Year", source.PeriodYear),
+							huh.NewOption("Single file per channel", source.PeriodSingle),
+						)),
+				},
 				cfgui.MemberOnly(),
 				cfgui.OnlyChannelUsers(),
 				cfgui.IncludeCustomLabels(),
@@ -69,19 +69,16 @@ func runFormat(ctx context.Context, cmd *base.Command, args []string) error {
 
 	// determining the conversion type.
 	var convType format.Type
-	var formatter format.Formatter
 	if err := convType.Set(args[0]); err != nil {
 		base.SetExitStatus(base.SInvalidParameters)
 		return err
-	} else {
-		var ok bool
-		formatterInit, ok := convType.FormatFunc()
-		if !ok {
-			base.SetExitStatus(base.SInvalidParameters)
-			return errors.New("unknown converter type")
-		}
-		formatter = formatterInit()
 	}
+	formatterInit, ok := convType.FormatFunc()
+	if !ok {
+		base.SetExitStatus(base.SInvalidParameters)
+		return errors.New("unknown converter type")
+	}
+	formatter := formatterInit()
 
 	var input string
 	if len(args) > 1 {
@@ -123,6 +120,13 @@ func runFormat(ctx context.Context, cmd *base.Command, args []string) error {
 		}
 		defer fsa.Close()
 
+		names, err := source.NameResolver(ctx, src)
+		if err != nil {
+			base.SetExitStatus(base.SApplicationError)
+			return err
+		}
+		formatter = formatterInit(format.WithNameResolver(names))
+
 		if err := formatSrc(ctx, fsa, src, formatter, el); err != nil {
 			base.SetExitStatus(base.SApplicationError)
 		}
@@ -32,6 +32,7 @@ import (
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/workspace"
 	"github.com/rusq/slackdump/v4/internal/cache"
 	"github.com/rusq/slackdump/v4/internal/format"
+	"github.com/rusq/slackdump/v4/internal/structures"
 	"github.com/rusq/slackdump/v4/types"
 )
 
@@ -123,8 +124,12 @@ func list[T any](ctx context.Context, sess *slackdump.Session, l lister[T], file
 	}
 
 	data := l.Data()
+	opts := []format.Option{
+		format.WithBareFormat(commonFlags.bare),
+		format.WithNameResolver(structures.NewNameResolver(structures.NewUserIndex(l.Users()), sess.CurrentUserID(), nil)),
+	}
 	if !commonFlags.quiet {
-		if err := fmtPrint(ctx, os.Stdout, data, commonFlags.listType, l.Users(), commonFlags.bare); err != nil {
+		if err := fmtPrint(ctx, os.Stdout, data, commonFlags.listType, l.Users(), opts...); err != nil {
 			return err
 		}
 	}
@@ -136,7 +141,7 @@ func list[T any](ctx context.Context, sess *slackdump.Session, l lister[T], file
 		if err := bootstrap.AskOverwrite(filename); err != nil {
 			return err
 		}
-		if err := saveData(ctx, data, filename, commonFlags.listType, l.Users(), commonFlags.bare); err != nil {
+		if err := saveData(ctx, data, filename, commonFlags.listType, l.Users(), opts...); err != nil {
 			return err
 		}
 	}
@@ -157,14 +162,14 @@ func extForType(typ format.Type) string {
 }
 
 // saveData saves the given data to the given filename.
-func saveData(ctx context.Context, data any, filename string, typ format.Type, users []slack.User, bare bool) error {
+func saveData(ctx context.Context, data any, filename string, typ format.Type, users []slack.User, opts ...format.Option) error {
 	// save to a filesystem.
 	f, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
 	defer f.Close()
-	if err := fmtPrint(ctx, f, data, typ, users, bare); err != nil {
+	if err := fmtPrint(ctx, f, data, typ, users, opts...); err != nil {
 		return err
 	}
 	cfg.Log.InfoContext(ctx, "Data saved", "filename", filename)
@@ -172,16 +177,16 @@ func saveData(ctx context.Context, data any, filename string, typ format.Type, u
 	return nil
 }
 
-// fmtPrint prints the given data to the given writer, using the given format.
-// It should be supplied with prepopulated users, as it may need to look up
-// users by ID.
-func fmtPrint(ctx context.Context, w io.Writer, a any, typ format.Type, u []slack.User, bare bool) error {
+// fmtPrint prints the given data to the given writer, using the given format
+// and formatter options.  It should be supplied with prepopulated users, as it
+// may need to look up users by ID.
+func fmtPrint(ctx context.Context, w io.Writer, a any, typ format.Type, u []slack.User, opts ...format.Option) error {
 	// get the converter
 	initFn, ok := typ.FormatFunc()
 	if !ok {
 		return fmt.Errorf("unknown converter type: %s", typ)
 	}
-	cvt := initFn(format.WithBareFormat(bare))
+	cvt := initFn(opts...)
 
 	// currently there's no list function for conversations, because it
 	// requires additional options, and I don't want to clutter the flags -
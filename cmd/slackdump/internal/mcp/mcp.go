@@ -78,6 +78,8 @@ func init() {
 	CmdMCP.Flag.StringVar(&transport, "transport", "stdio", "MCP transport: \"stdio\" or \"http\"")
 	CmdMCP.Flag.StringVar(&listenAddr, "listen", "127.0.0.1:8483", "address to listen on when -transport=http")
 	CmdMCP.Flag.StringVar(&newProjectLayout, "new", "", fmt.Sprintf("creates new project layout for AI. Type may be one of: %v", projectLayouts))
+	CmdMCP.Flag.StringVar(&cfg.FileStore, "file-store", "", "content-addressed file store `directory`, if it was moved from the location\nrecorded in the archive")
+	CmdMCP.Flag.BoolVar(&cfg.ZipCache, "zip-cache", false, "keep the database extracted from the ZIP file in the cache directory, to\nopen the same ZIP file faster next time")
 }
 
 func runMCP(ctx context.Context, cmd *base.Command, args []string) error {
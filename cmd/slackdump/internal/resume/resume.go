@@ -36,6 +36,7 @@ import (
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/golang/base"
 	"github.com/rusq/slackdump/v4/internal/chunk/backend/dbase"
 	"github.com/rusq/slackdump/v4/internal/chunk/control"
+	"github.com/rusq/slackdump/v4/internal/seal"
 	"github.com/rusq/slackdump/v4/internal/structures"
 	"github.com/rusq/slackdump/v4/source"
 	"github.com/rusq/slackdump/v4/stream"
@@ -106,6 +107,8 @@ func init() {
 	CmdResume.Flag.Var(resumeFlags.SkipStaleThreads, "skip-stale-threads", "skip thread entities whose latest reply is older than this `duration` (default: disabled)")
 	CmdResume.Flag.Var(resumeFlags.SkipStaleChannels, "skip-stale-channels", "skip channel entities whose latest message is older than this `duration` (default: disabled; pair with a periodic full-sweep run)")
 	CmdResume.Flag.BoolVar(&resumeFlags.Dedupe, "dedupe", false, "run dedupe cleanup after successful resume finish")
+	CmdResume.Flag.StringVar(&cfg.ArchiveKeyFile, "key-file", "", "key `file` of the encrypted archive, if it was encrypted with the key file")
+	cfg.SetPoolFlags(&CmdResume.Flag)
 }
 
 var runDedupe = func(ctx context.Context, conn *sqlx.DB, opts dedupecmd.Options) (dedupecmd.Result, error) {
@@ -136,6 +139,13 @@ func runResume(ctx context.Context, cmd *base.Command, args []string) error {
 		return err
 	}
 
+	// the archive could be left in plaintext if the previous run was
+	// interrupted.
+	if err := bootstrap.RecoverArchive(ctx, dir); err != nil {
+		base.SetExitStatus(base.SApplicationError)
+		return err
+	}
+
 	src, err := source.Load(ctx, dir)
 	if err != nil {
 		base.SetExitStatus(base.SInvalidParameters)
@@ -169,6 +179,7 @@ func runResume(ctx context.Context, cmd *base.Command, args []string) error {
 		base.SetExitStatus(base.SInitializationError)
 		return fmt.Errorf("error creating slackdump session: %w", err)
 	}
+	defer bootstrap.Finish(ctx, client)
 	info, err := client.AuthTestContext(ctx)
 	if err != nil {
 		base.SetExitStatus(base.SInitializationError)
@@ -187,6 +198,29 @@ func runResume(ctx context.Context, cmd *base.Command, args []string) error {
 		return fmt.Errorf("error closing source: %w", err)
 	}
 
+	// sealed archive is decrypted for writing, and sealed back when done,
+	// the new files are sealed as they are downloaded.
+	var ctrlOpts []archive.DBControllerOption
+	if bootstrap.IsSealedArchive(dir) {
+		secret, err := seal.SecretFromContext(ctx)
+		if err != nil {
+			base.SetExitStatus(base.SInitializationError)
+			return err
+		}
+		ctrlOpts = append(ctrlOpts, archive.WithSealedFiles(secret))
+	}
+	reseal, err := bootstrap.UnsealArchive(ctx, dir)
+	if err != nil {
+		base.SetExitStatus(base.SInitializationError)
+		return err
+	}
+	defer func() {
+		if err := reseal(); err != nil {
+			base.SetExitStatus(base.SApplicationError)
+			cfg.Log.ErrorContext(ctx, "unable to encrypt the archive", "error", err)
+		}
+	}()
+
 	// connecting to the database in read-write mode.
 	wconn, err := bootstrap.Database(dir)
 	if err != nil {
@@ -216,10 +250,12 @@ func runResume(ctx context.Context, cmd *base.Command, args []string) error {
 		dir,
 		cf,
 		streamOpts,
-		archive.WithFileDeduplication(),
-		archive.WithDatabaseOptions(
-			dbase.WithOnlyNewOrChangedUsers(resumeFlags.RecordOnlyNewUsers),
-		),
+		append([]archive.DBControllerOption{
+			archive.WithFileDeduplication(),
+			archive.WithDatabaseOptions(
+				dbase.WithOnlyNewOrChangedUsers(resumeFlags.RecordOnlyNewUsers),
+			),
+		}, ctrlOpts...)...,
 	)
 	if err != nil {
 		base.SetExitStatus(base.SInitializationError)
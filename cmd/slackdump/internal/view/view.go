@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 
 	br "github.com/pkg/browser"
 
@@ -48,6 +49,8 @@ var listenAddr string
 
 func init() {
 	CmdView.Flag.StringVar(&listenAddr, "listen", "127.0.0.1:8080", "address to listen on")
+	CmdView.Flag.StringVar(&cfg.FileStore, "file-store", "", "content-addressed file store `directory`, if it was moved from the location\nrecorded in the archive")
+	CmdView.Flag.BoolVar(&cfg.ZipCache, "zip-cache", false, "keep the database extracted from the ZIP file in the cache directory, to\nopen the same ZIP file faster next time")
 }
 
 func runView(ctx context.Context, cmd *base.Command, args []string) error {
@@ -71,8 +74,14 @@ func runView(ctx context.Context, cmd *base.Command, args []string) error {
 	}
 	defer src.Close()
 
+	thumbDir := filepath.Join(cfg.CacheDir(), "thumbnails")
+	if s, ok := src.(source.Sealer); ok && s.Sealed() {
+		// the previews of the encrypted archive are not kept on disk.
+		thumbDir = ""
+	}
+
 	stoppb := bootstrap.TimedSpinner(ctx, os.Stdout, "Slackdump Viewer is loading files", -1, 0)
-	v, err := viewer.New(ctx, listenAddr, src)
+	v, err := viewer.New(ctx, listenAddr, src, viewer.WithThumbnailCache(thumbDir))
 	if err != nil {
 		base.SetExitStatus(base.SApplicationError)
 		return err
@@ -0,0 +1,230 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package workspace
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/golang/base"
+	"github.com/rusq/slackdump/v4/internal/cache"
+	"github.com/rusq/slackdump/v4/internal/structures"
+)
+
+//go:embed assets/check.md
+var checkMD string
+
+var cmdWspCheck = &base.Command{
+	UsageLine:  baseCommand + " check [flags] [workspace...]",
+	Short:      "checks if the saved credentials are still valid",
+	Long:       checkMD,
+	FlagMask:   flagmask,
+	PrintFlags: true,
+}
+
+var checkFlags struct {
+	json bool
+}
+
+func init() {
+	cmdWspCheck.Run = runWspCheck
+	cmdWspCheck.Flag.BoolVar(&checkFlags.json, "json", false, "output the status in JSON format")
+}
+
+// errInvalidCreds is returned when some of the workspaces need to be
+// re-authenticated.
+var errInvalidCreds = errors.New("some workspaces need to be re-authenticated")
+
+// Check statuses.
+const (
+	statusOK      = "ok"      // credentials are valid.
+	statusInvalid = "invalid" // Slack rejected the credentials.
+	statusError   = "error"   // credentials could not be checked.
+)
+
+// checkResult is the result of the workspace credentials check.
+type checkResult struct {
+	Workspace string `json:"workspace"`
+	Current   bool   `json:"current"`
+	Status    string `json:"status"`
+	// TokenType is the token prefix, i.e. "xoxc" for browser tokens.
+	TokenType string `json:"token_type,omitempty"`
+	// SavedAt is when the credentials were saved.
+	SavedAt time.Time `json:"saved_at,omitzero"`
+	// AgeSeconds is the age of the saved credentials.
+	AgeSeconds int64 `json:"age_seconds,omitempty"`
+	// LastSuccess is the last time the credentials were accepted by Slack.
+	LastSuccess time.Time `json:"last_success,omitzero"`
+	TeamID      string    `json:"team_id,omitempty"`
+	Team        string    `json:"team,omitempty"`
+	UserID      string    `json:"user_id,omitempty"`
+	User        string    `json:"user,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+func runWspCheck(ctx context.Context, cmd *base.Command, args []string) error {
+	m, err := CacheMgr()
+	if err != nil {
+		base.SetExitStatus(base.SCacheError)
+		return err
+	}
+	results, err := check(ctx, m, args, time.Now())
+	if err != nil {
+		return err
+	}
+	fmtFn := printCheck
+	if checkFlags.json {
+		fmtFn = printCheckJSON
+	}
+	if err := fmtFn(os.Stdout, results); err != nil {
+		base.SetExitStatus(base.SApplicationError)
+		return err
+	}
+	for _, r := range results {
+		if r.Status != statusOK {
+			base.SetExitStatus(base.SAuthError)
+			return errInvalidCreds
+		}
+	}
+	return nil
+}
+
+// check checks the credentials of workspaces wsps, or all workspaces, if wsps
+// is empty.
+func check(ctx context.Context, m manager, wsps []string, now time.Time) ([]checkResult, error) {
+	all, err := m.List()
+	if err != nil {
+		if errors.Is(err, cache.ErrNoWorkspaces) {
+			base.SetExitStatus(base.SUserError)
+			return nil, errors.New("no authenticated workspaces, please run \"" + baseCommand + " new\"")
+		}
+		base.SetExitStatus(base.SCacheError)
+		return nil, err
+	}
+	if len(wsps) == 0 {
+		wsps = all
+	}
+	for _, name := range wsps {
+		if !m.Exists(name) {
+			base.SetExitStatus(base.SUserError)
+			return nil, fmt.Errorf("workspace does not exist: %q", name)
+		}
+	}
+	current, _ := m.Current() // not critical
+
+	var (
+		wg      sync.WaitGroup
+		pool    = make(chan struct{}, 8)
+		results = make([]checkResult, len(wsps))
+	)
+	for i, name := range wsps {
+		wg.Go(func() {
+			pool <- struct{}{}
+			defer func() { <-pool }()
+			results[i] = checkWsp(ctx, m, name, now)
+			results[i].Current = name == current
+		})
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// checkWsp checks the credentials of the workspace name.  If the credentials
+// are valid, the time of the check is recorded as the last success.
+func checkWsp(ctx context.Context, m manager, name string, now time.Time) checkResult {
+	res := checkResult{Workspace: name, Status: statusError}
+	if fi, err := m.FileInfo(name); err == nil {
+		res.SavedAt = fi.ModTime().UTC()
+		res.AgeSeconds = int64(now.Sub(fi.ModTime()).Seconds())
+	}
+	if ts, err := m.LastAuthSuccess(name); err == nil {
+		res.LastSuccess = ts
+	}
+	prov, err := m.LoadProvider(name)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.TokenType = tokenType(prov.SlackToken())
+
+	info, err := prov.Test(ctx)
+	if err != nil {
+		if structures.IsAuthError(err) {
+			res.Status = statusInvalid
+		}
+		res.Error = err.Error()
+		return res
+	}
+	res.Status = statusOK
+	res.TeamID, res.Team = info.TeamID, info.Team
+	res.UserID, res.User = info.UserID, info.User
+	if err := m.RecordAuthSuccess(name, now); err != nil {
+		cfg.Log.WarnContext(ctx, "failed to record authentication status", "workspace", name, "error", err)
+	} else {
+		res.LastSuccess = now.UTC()
+	}
+	return res
+}
+
+// tokenType returns the type of the token, i.e. "xoxc" for the browser
+// tokens, or "xoxp" for user tokens.
+func tokenType(token string) string {
+	typ, _, found := strings.Cut(token, "-")
+	if !found || typ == "" {
+		return "unknown"
+	}
+	return typ
+}
+
+func printCheck(w io.Writer, results []checkResult) error {
+	ew := &errWriter{w: w}
+	tw := tabwriter.NewWriter(ew, 2, 8, 1, ' ', 0)
+	fmt.Fprintln(tw, makeHeader(checkHdrItems...))
+	for _, r := range results {
+		curr := ""
+		if r.Current {
+			curr = "*"
+		}
+		age, last := "-", "never"
+		if !r.SavedAt.IsZero() {
+			age = (time.Duration(r.AgeSeconds) * time.Second).Truncate(time.Minute).String()
+		}
+		if !r.LastSuccess.IsZero() {
+			last = r.LastSuccess.Local().Format(timeLayout)
+		}
+		fmt.Fprintln(tw, strings.Join([]string{curr, r.Workspace, r.TokenType, age, last, r.Team, r.Status, r.Error}, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	return ew.Err()
+}
+
+func printCheckJSON(w io.Writer, results []checkResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
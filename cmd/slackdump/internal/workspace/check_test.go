@@ -0,0 +1,137 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package workspace
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/rusq/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/rusq/slackdump/v4/auth"
+	"github.com/rusq/slackdump/v4/internal/mocks/mock_auth"
+)
+
+func Test_check(t *testing.T) {
+	var (
+		now     = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+		saved   = now.Add(-48 * time.Hour)
+		lastOK  = now.Add(-24 * time.Hour)
+		fsys    = fstest.MapFS{"acme.bin": {ModTime: saved}, "other.bin": {ModTime: saved}}
+		stat    = func(name string) os.FileInfo { fi, _ := fsys.Stat(name + ".bin"); return fi }
+		revoked = &auth.Error{Err: slack.SlackErrorResponse{Err: "token_revoked"}}
+	)
+	ctrl := gomock.NewController(t)
+	mm := NewMockmanager(ctrl)
+	good := mock_auth.NewMockProvider(ctrl)
+	bad := mock_auth.NewMockProvider(ctrl)
+
+	mm.EXPECT().List().Return([]string{"acme", "other"}, nil)
+	mm.EXPECT().Exists(gomock.Any()).Return(true).Times(2)
+	mm.EXPECT().Current().Return("acme", nil)
+	mm.EXPECT().FileInfo("acme").Return(stat("acme"), nil)
+	mm.EXPECT().FileInfo("other").Return(stat("other"), nil)
+	mm.EXPECT().LastAuthSuccess("acme").Return(lastOK, nil)
+	mm.EXPECT().LastAuthSuccess("other").Return(lastOK, nil)
+	mm.EXPECT().LoadProvider("acme").Return(good, nil)
+	mm.EXPECT().LoadProvider("other").Return(bad, nil)
+	good.EXPECT().SlackToken().Return("xoxp-1-2-3")
+	good.EXPECT().Test(gomock.Any()).Return(&slack.AuthTestResponse{TeamID: "T1", Team: "Acme", UserID: "U1", User: "bob"}, nil)
+	mm.EXPECT().RecordAuthSuccess("acme", now).Return(nil)
+	bad.EXPECT().SlackToken().Return("xoxc-1-2-3")
+	bad.EXPECT().Test(gomock.Any()).Return(nil, revoked)
+
+	got, err := check(t.Context(), mm, nil, now)
+	require.NoError(t, err)
+	want := []checkResult{
+		{
+			Workspace:   "acme",
+			Current:     true,
+			Status:      statusOK,
+			TokenType:   "xoxp",
+			SavedAt:     saved,
+			AgeSeconds:  48 * 3600,
+			LastSuccess: now,
+			TeamID:      "T1",
+			Team:        "Acme",
+			UserID:      "U1",
+			User:        "bob",
+		},
+		{
+			Workspace:   "other",
+			Status:      statusInvalid,
+			TokenType:   "xoxc",
+			SavedAt:     saved,
+			AgeSeconds:  48 * 3600,
+			LastSuccess: lastOK,
+			Error:       revoked.Error(),
+		},
+	}
+	assert.Equal(t, want, got)
+
+	var buf bytes.Buffer
+	require.NoError(t, printCheckJSON(&buf, got))
+	var decoded []checkResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, want, decoded)
+}
+
+func Test_check_unknownWorkspace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mm := NewMockmanager(ctrl)
+	mm.EXPECT().List().Return([]string{"acme"}, nil)
+	mm.EXPECT().Exists("nope").Return(false)
+
+	_, err := check(t.Context(), mm, []string{"nope"}, time.Now())
+	assert.Error(t, err)
+}
+
+func Test_checkWsp_loadError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mm := NewMockmanager(ctrl)
+	mm.EXPECT().FileInfo("acme").Return(nil, errors.New("no file"))
+	mm.EXPECT().LastAuthSuccess("acme").Return(time.Time{}, nil)
+	mm.EXPECT().LoadProvider("acme").Return(nil, errors.New("decryption failed"))
+
+	got := checkWsp(t.Context(), mm, "acme", time.Now())
+	assert.Equal(t, checkResult{Workspace: "acme", Status: statusError, Error: "decryption failed"}, got)
+}
+
+func Test_tokenType(t *testing.T) {
+	tests := []struct {
+		token string
+		want  string
+	}{
+		{"xoxc-123-456", "xoxc"},
+		{"xoxb-123", "xoxb"},
+		{"xoxe.xoxp-1-abc", "xoxe.xoxp"},
+		{"", "unknown"},
+		{"garbage", "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			assert.Equal(t, tt.want, tokenType(tt.token))
+		})
+	}
+}
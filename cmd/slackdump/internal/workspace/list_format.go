@@ -35,6 +35,17 @@ var hdrItems = []hdrItem{
 	{"error", 5},
 }
 
+var checkHdrItems = []hdrItem{
+	{"C", 1},
+	{"name", 7},
+	{"token", 5},
+	{"age", 3},
+	{"last success", 12},
+	{"team", 9},
+	{"status", 6},
+	{"error", 5},
+}
+
 type errWriter struct {
 	w   io.Writer
 	err error
@@ -0,0 +1,73 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package workspace
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/golang/base"
+	"github.com/rusq/slackdump/v4/internal/cache"
+)
+
+//go:embed assets/migrate.md
+var migrateMD string
+
+var cmdWspMigrate = &base.Command{
+	UsageLine:  baseCommand + " migrate [flags]",
+	Short:      "moves the saved credentials to another credential store",
+	Long:       migrateMD,
+	FlagMask:   flagmask,
+	PrintFlags: true,
+}
+
+var migrateFlags struct {
+	from        string
+	fromCommand string
+}
+
+func init() {
+	cmdWspMigrate.Run = runWspMigrate
+	cmdWspMigrate.Flag.StringVar(&migrateFlags.from, "from", cache.StoreFile, "credential `store` to migrate from")
+	cmdWspMigrate.Flag.StringVar(&migrateFlags.fromCommand, "from-command", "", "credential helper `command` of the source \"command\" store,\nif not set, the -cred-command value is used")
+}
+
+func runWspMigrate(ctx context.Context, cmd *base.Command, args []string) error {
+	m, err := CacheMgr()
+	if err != nil {
+		base.SetExitStatus(base.SCacheError)
+		return err
+	}
+	fromCmd := migrateFlags.fromCommand
+	if fromCmd == "" {
+		fromCmd = cfg.CredCommand
+	}
+	migrated, err := m.MigrateCreds(migrateFlags.from, fromCmd)
+	for _, name := range migrated {
+		cfg.Log.InfoContext(ctx, "migrated", "workspace", name, "from", migrateFlags.from, "to", cfg.CredStore)
+	}
+	if err != nil {
+		if errors.Is(err, cache.ErrNoWorkspaces) {
+			base.SetExitStatus(base.SUserError)
+		} else {
+			base.SetExitStatus(base.SCacheError)
+		}
+		return err
+	}
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	context "context"
 	os "os"
 	reflect "reflect"
+	time "time"
 
 	auth "github.com/rusq/slackdump/v4/auth"
 	cache "github.com/rusq/slackdump/v4/internal/cache"
@@ -116,6 +117,21 @@ func (mr *MockmanagerMockRecorder) FileInfo(name any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FileInfo", reflect.TypeOf((*Mockmanager)(nil).FileInfo), name)
 }
 
+// LastAuthSuccess mocks base method.
+func (m *Mockmanager) LastAuthSuccess(name string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastAuthSuccess", name)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastAuthSuccess indicates an expected call of LastAuthSuccess.
+func (mr *MockmanagerMockRecorder) LastAuthSuccess(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastAuthSuccess", reflect.TypeOf((*Mockmanager)(nil).LastAuthSuccess), name)
+}
+
 // List mocks base method.
 func (m *Mockmanager) List() ([]string, error) {
 	m.ctrl.T.Helper()
@@ -146,6 +162,20 @@ func (mr *MockmanagerMockRecorder) LoadProvider(name any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadProvider", reflect.TypeOf((*Mockmanager)(nil).LoadProvider), name)
 }
 
+// RecordAuthSuccess mocks base method.
+func (m *Mockmanager) RecordAuthSuccess(name string, t time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAuthSuccess", name, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAuthSuccess indicates an expected call of RecordAuthSuccess.
+func (mr *MockmanagerMockRecorder) RecordAuthSuccess(name, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAuthSuccess", reflect.TypeOf((*Mockmanager)(nil).RecordAuthSuccess), name, t)
+}
+
 // Select mocks base method.
 func (m *Mockmanager) Select(name string) error {
 	m.ctrl.T.Helper()
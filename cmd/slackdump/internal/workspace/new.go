@@ -55,6 +55,7 @@ func runWspNew(ctx context.Context, cmd *base.Command, args []string) error {
 		auth.BrowserWithBrowser(wspcfg.Browser),
 		auth.BrowserWithTimeout(wspcfg.LoginTimeout),
 	}, wspcfg.RodAuthOptions()...)
+	authOpts = append(authOpts, wspcfg.OAuthAuthOptions()...)
 
 	m, err := CacheMgr(
 		cache.WithAuthOpts(authOpts...))
@@ -95,6 +96,9 @@ func createWsp(ctx context.Context, m manager, wsp string, confirm bool) error {
 		Token:         wspcfg.SlackToken,
 		Cookie:        wspcfg.SlackCookie,
 		UsePlaywright: wspcfg.LegacyBrowser,
+
+		OAuthClientID:     wspcfg.OAuthClientID,
+		OAuthClientSecret: wspcfg.OAuthClientSecret,
 	}
 	prov, err := m.Auth(ctx, wsp, ad)
 	if err != nil {
@@ -23,6 +23,7 @@ import (
 	"os"
 	"runtime/trace"
 	"strings"
+	"time"
 
 	"github.com/rusq/slackdump/v4/auth"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
@@ -50,8 +51,9 @@ due to some other reason).
 
 **Workspace** command allows to add a **new** Slack Workspace, **list** already
 authenticated workspaces, **select** a workspace that you have previously
-logged in to, **del**ete an existing workspace, or **import** credentials from
-an environment file.
+logged in to, **del**ete an existing workspace, **import** credentials from
+an environment file, **migrate** credentials to another credential store, or
+**check** whether the saved credentials are still valid.
 
 To learn more about different login options, run:
 
@@ -69,6 +71,8 @@ automatically detected to be:
 		cmdWspList,
 		cmdWspSelect,
 		cmdWspDel,
+		cmdWspMigrate,
+		cmdWspCheck,
 		cmdWspWiz,
 	},
 }
@@ -85,6 +89,8 @@ type manager interface {
 	LoadProvider(name string) (auth.Provider, error)
 	Select(name string) error
 	Current() (string, error)
+	LastAuthSuccess(name string) (time.Time, error)
+	RecordAuthSuccess(name string, t time.Time) error
 }
 
 // argsWorkspace checks if the current workspace override is set, and returns it
@@ -173,7 +179,7 @@ func authWsp(ctx context.Context, cacheDir string, wsp string, usePlaywright boo
 }
 
 func mgrOpts() []cache.Option {
-	return []cache.Option{cache.WithMachineID(cfg.MachineIDOvr), cache.WithNoEncryption(cfg.NoEncryption)}
+	return []cache.Option{cache.WithMachineID(cfg.MachineIDOvr), cache.WithNoEncryption(cfg.NoEncryption), cache.WithCredStore(cfg.CredStore, cfg.CredCommand)}
 }
 
 func CacheMgr(opts ...cache.Option) (*cache.Manager, error) {
@@ -170,7 +170,7 @@ LOOP:
 		if !ok {
 			return errors.New("internal error:  unhandled login option")
 		}
-		mgr, err := cache.NewManager(cfg.CacheDir(), cache.WithMachineID(cfg.MachineIDOvr), cache.WithNoEncryption(cfg.NoEncryption)) // avoiding import cycle
+		mgr, err := cache.NewManager(cfg.CacheDir(), cache.WithMachineID(cfg.MachineIDOvr), cache.WithNoEncryption(cfg.NoEncryption), cache.WithCredStore(cfg.CredStore, cfg.CredCommand)) // avoiding import cycle
 		if err != nil {
 			return err
 		}
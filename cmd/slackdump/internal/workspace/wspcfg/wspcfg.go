@@ -18,6 +18,7 @@ package wspcfg
 
 import (
 	"flag"
+	"strings"
 	"time"
 
 	"github.com/rusq/osenv/v2"
@@ -36,6 +37,11 @@ var (
 	// playwright stuff
 	Browser       browser.Browser
 	LegacyBrowser bool
+	// OAuth app flow
+	OAuthClientID     string
+	OAuthClientSecret string
+	OAuthRedirectURL  string = auth.DefOAuthRedirectURL
+	OAuthScopes       string = strings.Join(auth.DefOAuthScopes, ",")
 )
 
 var (
@@ -54,6 +60,24 @@ func SetWspFlags(fs *flag.FlagSet) {
 	fs.BoolVar(&LegacyBrowser, "legacy-browser", false, "use legacy browser automation (playwright) for EZ-Login 3000")
 	fs.StringVar(&RODUserAgent, "user-agent", "", "override the user agent string for EZ-Login 3000")
 	fs.BoolVar(&BundledBrowser, "bundled-browser", false, "force the launcher-managed bundled Chromium for interactive login (disables system browser auto-detection)")
+	fs.StringVar(&OAuthClientID, "oauth-client-id", osenv.Value("SLACK_CLIENT_ID", ""), "client `ID` of the Slack app, enables the OAuth login\n(environment: SLACK_CLIENT_ID)")
+	fs.StringVar(&OAuthClientSecret, "oauth-client-secret", osenv.Secret("SLACK_CLIENT_SECRET", ""), "client `secret` of the Slack app, optional\n(environment: SLACK_CLIENT_SECRET)")
+	fs.StringVar(&OAuthRedirectURL, "oauth-redirect-url", OAuthRedirectURL, "OAuth redirect `URL`, must be registered in the Slack app")
+	fs.StringVar(&OAuthScopes, "oauth-scopes", OAuthScopes, "comma-separated OAuth user token `scopes`")
+}
+
+// OAuthAuthOptions returns auth options for the OAuth app login flow.
+func OAuthAuthOptions() []auth.Option {
+	var scopes []string
+	for s := range strings.SplitSeq(OAuthScopes, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	return []auth.Option{
+		auth.OAuthWithRedirectURL(OAuthRedirectURL),
+		auth.OAuthWithScopes(scopes...),
+	}
 }
 
 // RodAuthOptions returns auth options shared by all ROD-based workspace login
@@ -24,6 +24,7 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime/trace"
 	"strings"
 	_ "time/tzdata" // load the timezone data
@@ -36,6 +37,7 @@ import (
 
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/apiconfig"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/archive"
+	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/bootstrap"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/cfg"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/convertcmd"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/diag"
@@ -54,6 +56,8 @@ import (
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/wizard"
 	"github.com/rusq/slackdump/v4/cmd/slackdump/internal/workspace"
 	"github.com/rusq/slackdump/v4/internal/osext"
+	"github.com/rusq/slackdump/v4/internal/seal"
+	"github.com/rusq/slackdump/v4/source"
 )
 
 func init() {
@@ -218,16 +222,43 @@ func invoke(cmd *base.Command, args []string) error {
 	if cmd.RequireAuth {
 		trace.Logf(ctx, "invoke", "command %s requires auth", cmd.Name())
 		var err error
-		ctx, err = workspace.CurrentOrNewProviderCtx(ctx)
+		ctx, err = authProviderCtx(ctx)
 		if err != nil {
 			base.SetExitStatus(base.SAuthError)
 			return fmt.Errorf("auth error: %w", err)
 		}
 	}
+	// sealed archives ask for the passphrase only when opened.
+	ctx = seal.WithSecretFunc(ctx, bootstrap.ArchiveSecret)
+	if cfg.ZipCache {
+		// databases opened from ZIP files are extracted once and reused.
+		ctx = source.WithZipCache(ctx, filepath.Join(cfg.CacheDir(), "zipdb"))
+	}
+	if cfg.FileStore != "" {
+		// overrides the file store recorded in the archive manifest.
+		ctx = source.WithFileStore(ctx, cfg.FileStore)
+	}
 	trace.Log(ctx, "command", fmt.Sprint("Running ", cmd.Name(), " command"))
 	return cmd.Run(ctx, cmd, args)
 }
 
+// authProviderCtx returns the context with the auth provider for the current
+// workspace.  If the HTTP replay is requested, the provider replays the
+// recorded traffic instead, and no authentication is performed.
+func authProviderCtx(ctx context.Context) (context.Context, error) {
+	if cfg.ReplayHTTP != "" {
+		if cfg.RecordHTTP != "" {
+			return ctx, errors.New("-record-http and -replay-http are mutually exclusive")
+		}
+		return bootstrap.ReplayProviderCtx(ctx)
+	}
+	ctx, err := workspace.CurrentOrNewProviderCtx(ctx)
+	if err != nil || cfg.RecordHTTP == "" {
+		return ctx, err
+	}
+	return bootstrap.RecordProviderCtx(ctx)
+}
+
 func parseFlags(cmd *base.Command, args []string) ([]string, error) {
 	cfg.SetBaseFlags(&cmd.Flag, cmd.FlagMask)
 	cmd.Flag.Usage = func() { cmd.Usage() }
@@ -20,15 +20,17 @@ package slackdump
 import (
 	"time"
 
+	"github.com/rusq/slackdump/v4/internal/client"
 	"github.com/rusq/slackdump/v4/internal/network"
 )
 
 // Config is the option set for the Session.
 type config struct {
 	limits          network.Limits
-	dumpFiles       bool          // will we save the conversation files?
-	cacheRetention  time.Duration // how long to keep the cache (user, etc.)
-	forceEnterprise bool          // force enterprise workspace
+	dumpFiles       bool            // will we save the conversation files?
+	cacheRetention  time.Duration   // how long to keep the cache (user, etc.)
+	forceEnterprise bool            // force enterprise workspace
+	clientOpts      []client.Option // additional client options
 }
 
 // DefOptions is the default options used when initialising slackdump instance.
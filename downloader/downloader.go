@@ -76,11 +76,13 @@ type Client struct {
 type Option func(*options)
 
 type options struct {
-	limiter   *rate.Limiter
-	retries   int
-	workers   int
-	lg        *slog.Logger
-	chanBufSz int
+	limiter    *rate.Limiter
+	retries    int
+	workers    int
+	lg         *slog.Logger
+	chanBufSz  int
+	partialDir string
+	errFn      func(fullpath, url string, err error)
 }
 
 // FilenameFunc is the file naming function that should return the output
@@ -130,6 +132,25 @@ func WithLogger(l *slog.Logger) Option {
 	}
 }
 
+// WithPartialDir sets the directory where the partially downloaded files
+// and their download state are kept.  If set, and the client implements
+// [RangeOpener], the interrupted downloads are continued by the subsequent
+// runs, otherwise the partial files are discarded.
+func WithPartialDir(dir string) Option {
+	return func(c *options) {
+		c.partialDir = dir
+	}
+}
+
+// WithErrorFunc sets the function that is called for each file that failed
+// to download, after all retries.  It is called from the download workers,
+// so it must be safe for concurrent use.
+func WithErrorFunc(fn func(fullpath, url string, err error)) Option {
+	return func(c *options) {
+		c.errFn = fn
+	}
+}
+
 // New initialises new file downloader.
 func New(sc GetFiler, fs fsadapter.FS, opts ...Option) *Client {
 	if sc == nil {
@@ -252,6 +273,9 @@ func (c *Client) worker(ctx context.Context, reqC <-chan Request) {
 				lg.DebugContext(ctx, "download cancelled")
 			} else {
 				lg.ErrorContext(ctx, "error saving file", "error", err)
+				if c.errFn != nil {
+					c.errFn(req.Fullpath, req.URL, err)
+				}
 			}
 		} else {
 			lg.DebugContext(ctx, "file saved", "bytes_written", n)
@@ -266,6 +290,13 @@ func (c *Client) download(ctx context.Context, fullpath string, url string) (int
 		return 0, ErrNoFS
 	}
 
+	if ro, ok := c.sc.(RangeOpener); ok {
+		n, err := c.downloadRanged(ctx, ro, fullpath, url)
+		if !errors.Is(err, errors.ErrUnsupported) {
+			return n, err
+		}
+	}
+
 	tf, err := os.CreateTemp("", "")
 	if err != nil {
 		return 0, err
@@ -302,13 +333,18 @@ func (c *Client) download(ctx context.Context, fullpath string, url string) (int
 		return 0, err
 	}
 
+	return c.copyTo(fullpath, tf)
+}
+
+// copyTo copies the contents of r into the file fullpath on the fs adapter.
+func (c *Client) copyTo(fullpath string, r io.Reader) (int64, error) {
 	fsf, err := c.fsa.Create(fullpath)
 	if err != nil {
 		return 0, err
 	}
 	defer fsf.Close()
 
-	n, err := io.Copy(fsf, tf)
+	n, err := io.Copy(fsf, r)
 	if err != nil {
 		return 0, err
 	}
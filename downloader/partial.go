@@ -0,0 +1,279 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/trace"
+	"strconv"
+	"time"
+
+	"github.com/rusq/slackdump/v4/internal/network"
+)
+
+// ErrCorrupt is returned if the downloaded file does not match the size or
+// the digest reported by the server.
+var ErrCorrupt = errors.New("downloaded file is corrupt")
+
+// errInterrupted is returned by the download callback, if the transfer was
+// interrupted after receiving some data.
+var errInterrupted = errors.New("transfer interrupted")
+
+const (
+	partialExt = ".part"
+	stateExt   = ".json"
+)
+
+// partialState is the state of the partially downloaded file, it is saved
+// alongside the partial file, so that the download can be continued by the
+// subsequent run.
+type partialState struct {
+	URL      string    `json:"url"`
+	Fullpath string    `json:"fullpath"`
+	Size     int64     `json:"size"`
+	ETag     string    `json:"etag,omitempty"`
+	Digest   string    `json:"digest,omitempty"`
+	Updated  time.Time `json:"updated"`
+}
+
+// partial is the partially downloaded file.
+type partial struct {
+	*os.File
+	state     partialState
+	statePath string // empty if the state is not persisted
+}
+
+// openPartial opens the partial file for the fullpath.  If the partial dir
+// is not set, a temporary file is created, and the state is not persisted.
+// If the saved state does not match the url, the partial file is truncated.
+func (c *Client) openPartial(fullpath string, url string) (*partial, error) {
+	p := &partial{state: partialState{URL: url, Fullpath: fullpath, Size: -1}}
+	if c.partialDir == "" {
+		f, err := os.CreateTemp("", "")
+		if err != nil {
+			return nil, err
+		}
+		p.File = f
+		return p, nil
+	}
+	if err := os.MkdirAll(c.partialDir, 0o755); err != nil {
+		return nil, err
+	}
+	name := filepath.Join(c.partialDir, strconv.FormatUint(hash(fullpath), 16))
+	f, err := os.OpenFile(name+partialExt, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	p.File = f
+	p.statePath = name + stateExt
+	var st partialState
+	if data, err := os.ReadFile(p.statePath); err == nil && json.Unmarshal(data, &st) == nil && st.URL == url && st.Fullpath == fullpath {
+		p.state = st
+	} else if err := p.reset(); err != nil {
+		p.remove()
+		return nil, err
+	}
+	return p, nil
+}
+
+// reset truncates the partial file and resets the state.
+func (p *partial) reset() error {
+	if err := p.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := p.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	p.state = partialState{URL: p.state.URL, Fullpath: p.state.Fullpath, Size: -1}
+	return p.save()
+}
+
+// save persists the state, if the partial file is persistent.
+func (p *partial) save() error {
+	if p.statePath == "" {
+		return nil
+	}
+	p.state.Updated = time.Now()
+	data, err := json.Marshal(p.state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p.statePath, data, 0o644)
+}
+
+// discard closes the partial file after the failed download.  The file is
+// kept for the next attempt if it is persistent and not empty, otherwise it
+// is removed.
+func (p *partial) discard() error {
+	fi, err := p.Stat()
+	if err != nil || fi.Size() == 0 || p.statePath == "" {
+		return p.remove()
+	}
+	return p.File.Close()
+}
+
+// remove closes and removes the partial file and its state.
+func (p *partial) remove() error {
+	var errs error
+	if err := p.File.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		errs = errors.Join(errs, err)
+	}
+	if err := os.Remove(p.Name()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		errs = errors.Join(errs, err)
+	}
+	if p.statePath != "" {
+		if err := os.Remove(p.statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// fetch requests the remainder of the file and appends it to the partial
+// file.
+func (p *partial) fetch(ctx context.Context, ro RangeOpener) error {
+	region := trace.StartRegion(ctx, "GetFileRange")
+	defer region.End()
+
+	off, err := p.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if off > 0 && off == p.state.Size {
+		return nil // already complete
+	}
+	rr, err := ro.OpenFileRange(ctx, p.state.URL, off, p.state.ETag)
+	if err != nil {
+		if errors.Is(err, ErrRangeNotSatisfiable) {
+			// the file has changed or the partial file is damaged, start
+			// over.
+			if err := p.reset(); err != nil {
+				return err
+			}
+			return network.ErrRetryPlease
+		}
+		return err
+	}
+	defer rr.Body.Close()
+
+	if rr.Offset != off {
+		// server ignored the range request, or the file has changed,
+		// starting from scratch.
+		if err := p.reset(); err != nil {
+			return err
+		}
+		if rr.Offset != 0 {
+			return network.ErrRetryPlease
+		}
+	}
+	if rr.Offset == 0 || (rr.ETag != "" && rr.ETag != p.state.ETag) {
+		p.state.ETag = rr.ETag
+		p.state.Digest = rr.Digest
+	} else if p.state.Digest == "" {
+		p.state.Digest = rr.Digest
+	}
+	p.state.Size = rr.Size
+	if err := p.save(); err != nil {
+		return err
+	}
+
+	n, err := io.Copy(p, rr.Body)
+	if err != nil {
+		if n > 0 && ctx.Err() == nil {
+			// the cause is not wrapped deliberately, so that the retry
+			// function returns immediately, and the download continues from
+			// the new offset without the delay.
+			return fmt.Errorf("%w at offset %d: %v", errInterrupted, rr.Offset+n, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// verify checks the size and the digest of the downloaded file.
+func (p *partial) verify() error {
+	fi, err := p.Stat()
+	if err != nil {
+		return err
+	}
+	if p.state.Size >= 0 && fi.Size() != p.state.Size {
+		return fmt.Errorf("%w: size mismatch: want %d, got %d", ErrCorrupt, p.state.Size, fi.Size())
+	}
+	if p.state.Digest == "" {
+		return nil
+	}
+	if _, err := p.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return verifyDigest(p, p.state.Digest)
+}
+
+// downloadRanged downloads the file, continuing the partial download, if
+// there is one.  Every time the transfer is interrupted after receiving some
+// data, the retry attempts are reset.
+func (c *Client) downloadRanged(ctx context.Context, ro RangeOpener, fullpath string, url string) (int64, error) {
+	p, err := c.openPartial(fullpath, url)
+	if err != nil {
+		return 0, err
+	}
+	if off, _ := p.Seek(0, io.SeekEnd); off > 0 {
+		c.lg.InfoContext(ctx, "resuming download", "destination", fullpath, "offset", off)
+	}
+	var last int64 // offset after the last interruption
+	for {
+		err := network.WithRetry(ctx, c.limiter, c.retries, func(ctx context.Context) error {
+			return p.fetch(ctx, ro)
+		})
+		if err == nil {
+			break
+		}
+		if errors.Is(err, errInterrupted) {
+			// continue only while there is progress, otherwise the server
+			// that ignores ranges would keep us here forever.
+			if off, serr := p.Seek(0, io.SeekEnd); serr == nil && off > last {
+				last = off
+				c.lg.DebugContext(ctx, "download interrupted, resuming", "destination", fullpath, "error", err)
+				continue
+			}
+		}
+		if cerr := p.discard(); cerr != nil {
+			c.lg.WarnContext(ctx, "closing partial file", "error", cerr)
+		}
+		return 0, fmt.Errorf("download to %q failed, [src=%s]: %w", fullpath, url, err)
+	}
+
+	if err := p.verify(); err != nil {
+		if rerr := p.remove(); rerr != nil {
+			c.lg.WarnContext(ctx, "removing partial file", "error", rerr)
+		}
+		return 0, fmt.Errorf("download to %q failed, [src=%s]: %w", fullpath, url, err)
+	}
+	defer func() {
+		if err := p.remove(); err != nil {
+			c.lg.WarnContext(ctx, "removing partial file", "error", err)
+		}
+	}()
+	if _, err := p.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return c.copyTo(fullpath, p)
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rusq/fsadapter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+// rangeClient is the test client that implements [RangeOpener].
+type rangeClient struct {
+	hcl *http.Client
+}
+
+func (c rangeClient) GetFileContext(ctx context.Context, downloadURL string, w io.Writer) error {
+	rr, err := c.OpenFileRange(ctx, downloadURL, 0, "")
+	if err != nil {
+		return err
+	}
+	defer rr.Body.Close()
+	_, err = io.Copy(w, rr.Body)
+	return err
+}
+
+func (c rangeClient) OpenFileRange(ctx context.Context, downloadURL string, offset int64, ifRange string) (*RangeResponse, error) {
+	return OpenRange(ctx, c.hcl, "", downloadURL, offset, ifRange)
+}
+
+// unsupportedClient returns [errors.ErrUnsupported] from OpenFileRange.
+type unsupportedClient struct {
+	rangeClient
+}
+
+func (unsupportedClient) OpenFileRange(context.Context, string, int64, string) (*RangeResponse, error) {
+	return nil, errors.ErrUnsupported
+}
+
+// flakyServer serves data, dropping the connection after sending chunk bytes
+// for the first drops requests.  It records the Range headers of the
+// requests.
+type flakyServer struct {
+	data   []byte
+	digest string
+	chunk  int
+	drops  atomic.Int32
+	ranges []string
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.ranges = append(s.ranges, r.Header.Get("Range"))
+	w.Header().Set("ETag", `"v1"`)
+	if s.digest != "" {
+		w.Header().Set("Repr-Digest", s.digest)
+	}
+	if s.drops.Add(-1) >= 0 {
+		start := 0
+		if rng := r.Header.Get("Range"); rng != "" {
+			start, _ = strconv.Atoi(rng[len("bytes=") : len(rng)-1])
+			w.Header().Set("Content-Range", "bytes "+strconv.Itoa(start)+"-"+strconv.Itoa(len(s.data)-1)+"/"+strconv.Itoa(len(s.data)))
+			w.Header().Set("Content-Length", strconv.Itoa(len(s.data)-start))
+			w.WriteHeader(http.StatusPartialContent)
+		} else {
+			w.Header().Set("Content-Length", strconv.Itoa(len(s.data)))
+			w.WriteHeader(http.StatusOK)
+		}
+		w.Write(s.data[start:min(start+s.chunk, len(s.data))])
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+	http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(s.data))
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+}
+
+func newTestClient(t *testing.T, sc GetFiler, opts ...Option) (*Client, string) {
+	t.Helper()
+	dir := t.TempDir()
+	opts = append([]Option{Limiter(rate.NewLimiter(rate.Inf, 1)), WithLogger(slog.Default())}, opts...)
+	return New(sc, fsadapter.NewDirectory(dir), opts...), dir
+}
+
+func TestClient_download_resume(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100)
+
+	t.Run("continues after the interruption", func(t *testing.T) {
+		fs := &flakyServer{data: data, digest: sha256Digest(data), chunk: 300}
+		fs.drops.Store(2)
+		srv := httptest.NewServer(fs)
+		defer srv.Close()
+
+		c, dir := newTestClient(t, rangeClient{srv.Client()})
+		n, err := c.download(t.Context(), "files/video.mp4", srv.URL)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), n)
+		assert.Equal(t, []string{"", "bytes=300-", "bytes=600-"}, fs.ranges)
+
+		got, err := os.ReadFile(filepath.Join(dir, "files", "video.mp4"))
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+	t.Run("continues the partial file of the previous run", func(t *testing.T) {
+		fs := &flakyServer{data: data, chunk: 400}
+		fs.drops.Store(1)
+		srv := httptest.NewServer(fs)
+		defer srv.Close()
+
+		partialDir := t.TempDir()
+		c, dir := newTestClient(t, rangeClient{srv.Client()}, WithPartialDir(partialDir), Retries(1))
+		_, err := c.download(t.Context(), "files/video.mp4", srv.URL)
+		require.NoError(t, err, "interruption must not count as a failed attempt")
+
+		// simulate the interrupted run: restore the partial file.
+		p, err := c.openPartial("files/doc.pdf", srv.URL)
+		require.NoError(t, err)
+		_, err = p.Write(data[:500])
+		require.NoError(t, err)
+		p.state.Size = int64(len(data))
+		p.state.ETag = `"v1"`
+		require.NoError(t, p.save())
+		require.NoError(t, p.File.Close())
+
+		fs.ranges = nil
+		n, err := c.download(t.Context(), "files/doc.pdf", srv.URL)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), n)
+		assert.Equal(t, []string{"bytes=500-"}, fs.ranges)
+
+		got, err := os.ReadFile(filepath.Join(dir, "files", "doc.pdf"))
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+
+		left, err := os.ReadDir(partialDir)
+		require.NoError(t, err)
+		assert.Empty(t, left, "partial files must be removed")
+	})
+	t.Run("partial file for another url is discarded", func(t *testing.T) {
+		fs := &flakyServer{data: data}
+		srv := httptest.NewServer(fs)
+		defer srv.Close()
+
+		c, dir := newTestClient(t, rangeClient{srv.Client()}, WithPartialDir(t.TempDir()))
+		p, err := c.openPartial("files/doc.pdf", srv.URL+"/old")
+		require.NoError(t, err)
+		_, err = p.Write([]byte("stale"))
+		require.NoError(t, err)
+		require.NoError(t, p.save())
+		require.NoError(t, p.File.Close())
+
+		_, err = c.download(t.Context(), "files/doc.pdf", srv.URL)
+		require.NoError(t, err)
+		assert.Equal(t, []string{""}, fs.ranges)
+		got, err := os.ReadFile(filepath.Join(dir, "files", "doc.pdf"))
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+	t.Run("digest mismatch", func(t *testing.T) {
+		fs := &flakyServer{data: data, digest: sha256Digest([]byte("other"))}
+		srv := httptest.NewServer(fs)
+		defer srv.Close()
+
+		partialDir := t.TempDir()
+		c, dir := newTestClient(t, rangeClient{srv.Client()}, WithPartialDir(partialDir))
+		_, err := c.download(t.Context(), "files/video.mp4", srv.URL)
+		assert.ErrorIs(t, err, ErrCorrupt)
+		assert.NoFileExists(t, filepath.Join(dir, "files", "video.mp4"))
+		left, err := os.ReadDir(partialDir)
+		require.NoError(t, err)
+		assert.Empty(t, left, "corrupt partial file must be removed")
+	})
+	t.Run("falls back if ranges are unsupported", func(t *testing.T) {
+		fs := &flakyServer{data: data}
+		srv := httptest.NewServer(fs)
+		defer srv.Close()
+
+		partialDir := t.TempDir()
+		c, dir := newTestClient(t, unsupportedClient{rangeClient{srv.Client()}}, WithPartialDir(partialDir))
+		n, err := c.download(t.Context(), "files/video.mp4", srv.URL)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), n)
+		assert.FileExists(t, filepath.Join(dir, "files", "video.mp4"))
+		left, err := os.ReadDir(partialDir)
+		require.NoError(t, err)
+		assert.Empty(t, left)
+	})
+}
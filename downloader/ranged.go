@@ -0,0 +1,205 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	gohash "hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rusq/slack"
+)
+
+// ErrRangeNotSatisfiable is returned by [RangeOpener], if the offset is
+// beyond the end of the file.
+var ErrRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// RangeOpener is implemented by the clients that are able to download the
+// file starting from the offset.  If the client passed to [New] implements
+// it, interrupted downloads continue from where they stopped, instead of
+// starting over.
+type RangeOpener interface {
+	// OpenFileRange should request the file starting at the offset.  If
+	// ifRange is not empty, the range should be honoured only if the file
+	// ETag matches it.
+	OpenFileRange(ctx context.Context, downloadURL string, offset int64, ifRange string) (*RangeResponse, error)
+}
+
+// RangeResponse is the response to the ranged file request.
+type RangeResponse struct {
+	Body io.ReadCloser
+	// Offset is the offset of the first byte of Body in the file.  It is
+	// zero if the server sent the whole file.
+	Offset int64
+	// Size is the total size of the file, or -1 if unknown.
+	Size int64
+	// ETag is the entity tag of the file.
+	ETag string
+	// Digest is the digest of the whole file in "algorithm=base64" form, if
+	// the server provided it, i.e. "sha-256=X48E9q...".
+	Digest string
+}
+
+// OpenRange requests the file at url starting at the offset, using the HTTP
+// client hcl and the token.  It can be used to implement [RangeOpener].
+func OpenRange(ctx context.Context, hcl *http.Client, token string, url string, offset int64, ifRange string) (*RangeResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+		if ifRange != "" {
+			req.Header.Set("If-Range", ifRange)
+		}
+	}
+	resp, err := hcl.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	rr := &RangeResponse{
+		Body:   resp.Body,
+		Size:   -1,
+		ETag:   resp.Header.Get("ETag"),
+		Digest: reprDigest(resp.Header, resp.StatusCode == http.StatusOK),
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		rr.Size = resp.ContentLength
+	case http.StatusPartialContent:
+		start, size, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		rr.Offset, rr.Size = start, size
+	case http.StatusRequestedRangeNotSatisfiable:
+		resp.Body.Close()
+		return nil, ErrRangeNotSatisfiable
+	default:
+		resp.Body.Close()
+		return nil, slack.StatusCodeError{Code: resp.StatusCode, Status: resp.Status}
+	}
+	return rr, nil
+}
+
+// parseContentRange parses the "bytes start-end/size" header value.  Size
+// is -1 if it is unknown ("*").
+func parseContentRange(s string) (start, size int64, err error) {
+	rng, ok := strings.CutPrefix(s, "bytes ")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", s)
+	}
+	span, total, ok := strings.Cut(rng, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", s)
+	}
+	first, _, ok := strings.Cut(span, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q", s)
+	}
+	if start, err = strconv.ParseInt(first, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q: %w", s, err)
+	}
+	if total == "*" {
+		return start, -1, nil
+	}
+	if size, err = strconv.ParseInt(total, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range: %q: %w", s, err)
+	}
+	return start, size, nil
+}
+
+// digestAlgs are the supported digest algorithms, in the order of
+// preference.
+var digestAlgs = []string{"sha-512", "sha-256", "md5"}
+
+func newDigestHash(alg string) gohash.Hash {
+	switch alg {
+	case "sha-512":
+		return sha512.New()
+	case "sha-256":
+		return sha256.New()
+	case "md5":
+		return md5.New()
+	}
+	return nil
+}
+
+// reprDigest returns the digest of the whole file from the response headers:
+// Repr-Digest (RFC 9530), Digest (RFC 3230), or Content-MD5, which is the
+// digest of the whole file only if the response is not partial.
+func reprDigest(h http.Header, full bool) string {
+	found := make(map[string]string)
+	// Repr-Digest: sha-256=:base64:, sha-512=:base64:
+	for v := range strings.SplitSeq(h.Get("Repr-Digest"), ",") {
+		if alg, val, ok := strings.Cut(strings.TrimSpace(v), "="); ok {
+			found[strings.ToLower(alg)] = strings.Trim(val, ":")
+		}
+	}
+	// Digest: SHA-256=base64,MD5=base64
+	for v := range strings.SplitSeq(h.Get("Digest"), ",") {
+		if alg, val, ok := strings.Cut(strings.TrimSpace(v), "="); ok {
+			if _, ok := found[strings.ToLower(alg)]; !ok {
+				found[strings.ToLower(alg)] = val
+			}
+		}
+	}
+	if md5sum := h.Get("Content-MD5"); full && md5sum != "" {
+		if _, ok := found["md5"]; !ok {
+			found["md5"] = md5sum
+		}
+	}
+	for _, alg := range digestAlgs {
+		if v := found[alg]; v != "" {
+			return alg + "=" + v
+		}
+	}
+	return ""
+}
+
+// verifyDigest verifies that the contents of r match the digest in the
+// "algorithm=base64" form.
+func verifyDigest(r io.Reader, digest string) error {
+	alg, val, _ := strings.Cut(digest, "=")
+	h := newDigestHash(alg)
+	if h == nil {
+		return fmt.Errorf("unsupported digest algorithm: %q", alg)
+	}
+	want, err := base64.StdEncoding.DecodeString(val)
+	if err != nil {
+		return fmt.Errorf("invalid digest: %w", err)
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return err
+	}
+	if got := h.Sum(nil); string(got) != string(want) {
+		return fmt.Errorf("%w: %s mismatch", ErrCorrupt, alg)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseContentRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         string
+		wantStart int64
+		wantSize  int64
+		wantErr   bool
+	}{
+		{"full", "bytes 0-99/100", 0, 100, false},
+		{"partial", "bytes 42-99/100", 42, 100, false},
+		{"unknown size", "bytes 42-99/*", 42, -1, false},
+		{"no unit", "42-99/100", 0, 0, true},
+		{"no size", "bytes 42-99", 0, 0, true},
+		{"garbage", "bytes x-99/100", 0, 0, true},
+		{"empty", "", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStart, gotSize, err := parseContentRange(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseContentRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantStart, gotStart)
+			assert.Equal(t, tt.wantSize, gotSize)
+		})
+	}
+}
+
+func Test_reprDigest(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		full   bool
+		want   string
+	}{
+		{"none", http.Header{}, true, ""},
+		{"repr-digest", http.Header{"Repr-Digest": {"sha-256=:abc=:"}}, false, "sha-256=abc="},
+		{"prefers sha-512", http.Header{"Repr-Digest": {"sha-256=:abc=:, sha-512=:def=:"}}, false, "sha-512=def="},
+		{"digest", http.Header{"Digest": {"SHA-256=abc="}}, false, "sha-256=abc="},
+		{"content-md5 full", http.Header{"Content-Md5": {"xyz="}}, true, "md5=xyz="},
+		{"content-md5 partial", http.Header{"Content-Md5": {"xyz="}}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, reprDigest(tt.header, tt.full))
+		})
+	}
+}
+
+func Test_verifyDigest(t *testing.T) {
+	data := []byte("hello, world")
+	sum := sha256.Sum256(data)
+	good := "sha-256=" + base64.StdEncoding.EncodeToString(sum[:])
+
+	assert.NoError(t, verifyDigest(bytes.NewReader(data), good))
+	assert.ErrorIs(t, verifyDigest(strings.NewReader("tampered"), good), ErrCorrupt)
+	assert.Error(t, verifyDigest(bytes.NewReader(data), "crc32=AAAA"))
+	assert.Error(t, verifyDigest(bytes.NewReader(data), "sha-256=!!!"))
+}
+
+func TestOpenRange(t *testing.T) {
+	data := []byte("0123456789abcdefghij")
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		if r.URL.Path == "/404" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "file", time.Time{}, bytes.NewReader(data))
+	}))
+	defer srv.Close()
+
+	t.Run("from the start", func(t *testing.T) {
+		rr, err := OpenRange(t.Context(), srv.Client(), "xoxb-token", srv.URL, 0, "")
+		require.NoError(t, err)
+		defer rr.Body.Close()
+		got, _ := io.ReadAll(rr.Body)
+		assert.Equal(t, data, got)
+		assert.Equal(t, int64(0), rr.Offset)
+		assert.Equal(t, int64(len(data)), rr.Size)
+		assert.Equal(t, `"v1"`, rr.ETag)
+		assert.Equal(t, "Bearer xoxb-token", gotAuth)
+	})
+	t.Run("from the offset", func(t *testing.T) {
+		rr, err := OpenRange(t.Context(), srv.Client(), "", srv.URL, 10, `"v1"`)
+		require.NoError(t, err)
+		defer rr.Body.Close()
+		got, _ := io.ReadAll(rr.Body)
+		assert.Equal(t, data[10:], got)
+		assert.Equal(t, int64(10), rr.Offset)
+		assert.Equal(t, int64(len(data)), rr.Size)
+		assert.Empty(t, gotAuth)
+	})
+	t.Run("etag changed", func(t *testing.T) {
+		rr, err := OpenRange(t.Context(), srv.Client(), "", srv.URL, 10, `"v0"`)
+		require.NoError(t, err)
+		defer rr.Body.Close()
+		got, _ := io.ReadAll(rr.Body)
+		assert.Equal(t, data, got)
+		assert.Equal(t, int64(0), rr.Offset)
+	})
+	t.Run("beyond the end", func(t *testing.T) {
+		_, err := OpenRange(t.Context(), srv.Client(), "", srv.URL, 100, "")
+		assert.ErrorIs(t, err, ErrRangeNotSatisfiable)
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, err := OpenRange(t.Context(), srv.Client(), "", srv.URL+"/404", 0, "")
+		assert.Error(t, err)
+	})
+}
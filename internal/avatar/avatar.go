@@ -0,0 +1,114 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package avatar defines the content-addressed layout of the user avatar
+// storage, and the avatar history of each user.
+//
+// Layout of the avatar directory:
+//
+//	__avatars/
+//	  +-- _store/<key>.<ext>      avatar images, see [Key]
+//	  +-- <user_id>/history.json  avatar history of the user
+//	  +-- <user_id>/<filename>    avatars stored by the previous versions
+//
+// The image is stored once, no matter how many users have it, and it is not
+// downloaded again, if it is already in the store.
+package avatar
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"time"
+)
+
+const (
+	// StoreDir is the directory within the avatar directory where the
+	// images are stored.
+	StoreDir = "_store"
+	// HistoryFile is the name of the avatar history file within the user
+	// directory.
+	HistoryFile = "history.json"
+)
+
+// Entry is the avatar history entry.
+type Entry struct {
+	// Recorded is the time when the avatar was first seen.
+	Recorded time.Time `json:"recorded"`
+	// URL is the image_original URL of the avatar.
+	URL string `json:"image_original"`
+	// Path is the path of the image within the avatar directory.
+	Path string `json:"path"`
+}
+
+// Key returns the key of the avatar with the image_original URL u.
+func Key(u string) string {
+	sum := sha256.Sum256([]byte(u))
+	return hex.EncodeToString(sum[:16])
+}
+
+// StorePath returns the path of the avatar with the image_original URL u
+// within the avatar directory.
+func StorePath(u string) string {
+	return path.Join(StoreDir, Key(u)+path.Ext(u))
+}
+
+// HistoryPath returns the path of the avatar history of the user within the
+// avatar directory.
+func HistoryPath(userID string) string {
+	return path.Join(userID, HistoryFile)
+}
+
+// ReadHistory reads the avatar history of the user from fsys, that should be
+// the avatar directory.
+func ReadHistory(fsys fs.FS, userID string) ([]Entry, error) {
+	data, err := fs.ReadFile(fsys, HistoryPath(userID))
+	if err != nil {
+		return nil, err
+	}
+	var hh []Entry
+	if err := json.Unmarshal(data, &hh); err != nil {
+		return nil, err
+	}
+	return hh, nil
+}
+
+// Append appends the entry e to the history hh, if the avatar has changed
+// since the last entry, and reports whether it was appended.
+func Append(hh []Entry, e Entry) ([]Entry, bool) {
+	if len(hh) > 0 && hh[len(hh)-1].URL == e.URL {
+		return hh, false
+	}
+	return append(hh, e), true
+}
+
+// At returns the entry of the avatar that the user had at the time t, that
+// is the last entry recorded not later than t.  The avatars before the first
+// entry are unknown, so the first entry is returned for them.
+func At(hh []Entry, t time.Time) (Entry, bool) {
+	if len(hh) == 0 {
+		return Entry{}, false
+	}
+	e := hh[0]
+	for _, h := range hh[1:] {
+		if h.Recorded.After(t) {
+			break
+		}
+		e = h
+	}
+	return e, true
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package avatar
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestStorePath(t *testing.T) {
+	const u = "https://avatars.slack-edge.com/2024-01-01/123_original.png"
+	got := StorePath(u)
+	if want := "_store/" + Key(u) + ".png"; got != want {
+		t.Errorf("StorePath() = %v, want %v", got, want)
+	}
+	if StorePath(u) == StorePath(u+"?v=2") {
+		t.Error("StorePath() must differ for different URLs")
+	}
+}
+
+func TestAppend(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	hh, ok := Append(nil, Entry{Recorded: t0, URL: "a"})
+	if !ok || len(hh) != 1 {
+		t.Fatalf("Append() to empty = %v, %v", hh, ok)
+	}
+	hh, ok = Append(hh, Entry{Recorded: t0.Add(time.Hour), URL: "a"})
+	if ok || len(hh) != 1 {
+		t.Errorf("Append() of unchanged avatar = %v, %v", hh, ok)
+	}
+	hh, ok = Append(hh, Entry{Recorded: t0.Add(time.Hour), URL: "b"})
+	if !ok || len(hh) != 2 {
+		t.Errorf("Append() of changed avatar = %v, %v", hh, ok)
+	}
+}
+
+func TestAt(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	hh := []Entry{
+		{Recorded: t0, URL: "a"},
+		{Recorded: t0.Add(24 * time.Hour), URL: "b"},
+		{Recorded: t0.Add(48 * time.Hour), URL: "c"},
+	}
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		{"before the first entry", t0.Add(-time.Hour), "a"},
+		{"exactly at the entry", t0.Add(24 * time.Hour), "b"},
+		{"between the entries", t0.Add(36 * time.Hour), "b"},
+		{"after the last entry", t0.Add(100 * time.Hour), "c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := At(hh, tt.t)
+			if !ok || got.URL != tt.want {
+				t.Errorf("At() = %v, %v, want %v", got.URL, ok, tt.want)
+			}
+		})
+	}
+	if _, ok := At(nil, t0); ok {
+		t.Error("At() on empty history must return false")
+	}
+}
+
+func TestReadHistory(t *testing.T) {
+	fsys := fstest.MapFS{
+		"U1/history.json": &fstest.MapFile{Data: []byte(`[{"recorded":"2024-01-01T00:00:00Z","image_original":"a","path":"_store/x.png"}]`)},
+	}
+	hh, err := ReadHistory(fsys, "U1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hh) != 1 || hh[0].URL != "a" || hh[0].Path != "_store/x.png" {
+		t.Errorf("ReadHistory() = %v", hh)
+	}
+	if _, err := ReadHistory(fsys, "U2"); err == nil {
+		t.Error("ReadHistory() for missing user must fail")
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rusq/slackdump/v4/internal/network"
+)
+
+// apiCacheDir is the directory in the cache directory, where the API
+// responses are stored.
+const apiCacheDir = "api"
+
+// APICache is the disk-backed cache of the responses of the read-only Slack
+// API calls.  Responses are keyed by the team and user ID, the API method and
+// its parameters, excluding the token, so that the responses are never shared
+// between the users, as they may see different data.
+type APICache struct {
+	dir string
+	ttl map[string]time.Duration
+	co  createOpener
+}
+
+// APICache returns the API response cache, that stores the responses in the
+// cache directory for the durations defined by ttl.
+func (m *Manager) APICache(ttl network.CacheLimit) *APICache {
+	return &APICache{
+		dir: filepath.Join(m.dir, apiCacheDir),
+		ttl: map[string]time.Duration{
+			"conversations.info":        ttl.Conversations,
+			"conversations.genericInfo": ttl.Conversations,
+			"conversations.members":     ttl.Members,
+			"users.info":                ttl.Users,
+		},
+		co: m.createOpener(),
+	}
+}
+
+// Transport returns the [http.RoundTripper] that serves the cached responses
+// for the user userID of the team teamID, and caches the successful responses
+// of rt.  If rt implements [io.Closer], the returned RoundTripper implements
+// it too.
+func (c *APICache) Transport(teamID, userID string, rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &apiTransport{c: c, teamID: teamID, userID: userID, next: rt}
+}
+
+type apiTransport struct {
+	c      *APICache
+	teamID string
+	userID string
+	next   http.RoundTripper
+}
+
+func (t *apiTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := path.Base(req.URL.Path)
+	ttl := t.c.ttl[method]
+	if ttl <= 0 {
+		return t.next.RoundTrip(req)
+	}
+	key, err := requestKey(req, method)
+	if err != nil {
+		return nil, err
+	}
+	filename := filepath.Join(t.c.dir, t.teamID, t.userID, method, key)
+	if body, err := t.c.load(filename, ttl); err == nil {
+		return cachedResponse(req, body), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if isOK(body) {
+		if err := t.c.save(filename, body); err != nil {
+			slog.Debug("unable to cache API response", "method", method, "error", err)
+		}
+	}
+	return resp, nil
+}
+
+// Close closes the underlying transport, if it implements [io.Closer].
+func (t *apiTransport) Close() error {
+	if c, ok := t.next.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// requestKey returns the cache key for the request.  It reads the form body
+// of the request, if any, and restores it, so that the request can be sent.
+func requestKey(req *http.Request, method string) (string, error) {
+	params := req.URL.Query()
+	var raw []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if ct, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); ct == "application/x-www-form-urlencoded" {
+			form, err := url.ParseQuery(string(body))
+			if err != nil {
+				return "", err
+			}
+			for k, v := range form {
+				params[k] = append(params[k], v...)
+			}
+		} else {
+			raw = body
+		}
+	}
+	for k := range params {
+		// token and web client fields do not affect the response.
+		if k == "token" || strings.HasPrefix(k, "_x_") {
+			params.Del(k)
+		}
+	}
+	h := sha256.New()
+	h.Write([]byte(method + "?" + params.Encode() + "\n"))
+	h.Write(raw)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// isOK returns true if body is the successful Slack API response.
+func isOK(body []byte) bool {
+	var r struct {
+		Ok bool `json:"ok"`
+	}
+	return json.Unmarshal(body, &r) == nil && r.Ok
+}
+
+func cachedResponse(req *http.Request, body []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func (c *APICache) load(filename string, maxAge time.Duration) ([]byte, error) {
+	if err := checkCacheFile(filename, maxAge); err != nil {
+		return nil, err
+	}
+	f, err := c.co.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// save saves the response body to the file.  The file is written to a
+// temporary file first, so that the concurrent readers never see a partial
+// response.
+func (c *APICache) save(filename string, body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0o700); err != nil {
+		return err
+	}
+	tf, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := tf.Name()
+	tf.Close()
+	f, err := c.co.Create(tmp)
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if _, err := f.Write(body); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusq/slackdump/v4/internal/network"
+)
+
+func TestAPICache_Transport(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Form.Get("channel") == "CERR" {
+			io.WriteString(w, `{"ok":false,"error":"channel_not_found"}`)
+			return
+		}
+		io.WriteString(w, `{"ok":true,"channel":{"id":"`+r.Form.Get("channel")+`"}}`)
+	}))
+	defer srv.Close()
+
+	m, err := NewManager(t.TempDir(), WithNoEncryption(true))
+	require.NoError(t, err)
+	cl := &http.Client{
+		Transport: m.APICache(network.CacheLimit{Conversations: time.Hour}).Transport("T123", "U1", http.DefaultTransport),
+	}
+	post := func(method string, form url.Values) string {
+		t.Helper()
+		resp, err := cl.PostForm(srv.URL+"/api/"+method, form)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	// first call is a cache miss
+	body := post("conversations.info", url.Values{"token": {"xoxc-1"}, "channel": {"C1"}})
+	assert.Equal(t, `{"ok":true,"channel":{"id":"C1"}}`, body)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// same parameters with a different token and web client fields is a hit
+	body = post("conversations.info", url.Values{"token": {"xoxc-2"}, "channel": {"C1"}, "_x_reason": {"test"}})
+	assert.Equal(t, `{"ok":true,"channel":{"id":"C1"}}`, body)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// different parameters is a miss
+	post("conversations.info", url.Values{"channel": {"C2"}})
+	assert.Equal(t, int32(2), calls.Load())
+
+	// error responses are not cached
+	post("conversations.info", url.Values{"channel": {"CERR"}})
+	post("conversations.info", url.Values{"channel": {"CERR"}})
+	assert.Equal(t, int32(4), calls.Load())
+
+	// methods with zero ttl are not cached
+	post("users.info", url.Values{"user": {"U1"}})
+	post("users.info", url.Values{"user": {"U1"}})
+	assert.Equal(t, int32(6), calls.Load())
+
+	// other teams don't share the cache
+	cl.Transport = m.APICache(network.CacheLimit{Conversations: time.Hour}).Transport("T456", "U1", http.DefaultTransport)
+	post("conversations.info", url.Values{"channel": {"C1"}})
+	assert.Equal(t, int32(7), calls.Load())
+
+	// neither do other users of the same team
+	cl.Transport = m.APICache(network.CacheLimit{Conversations: time.Hour}).Transport("T123", "U2", http.DefaultTransport)
+	post("conversations.info", url.Values{"channel": {"C1"}})
+	assert.Equal(t, int32(8), calls.Load())
+}
+
+func Test_requestKey(t *testing.T) {
+	newReq := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "https://slack.com/api/users.info", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+	req := newReq("user=U1&token=xoxb-1")
+	k1, err := requestKey(req, "users.info")
+	require.NoError(t, err)
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "user=U1&token=xoxb-1", string(body), "body must be restored")
+
+	k2, err := requestKey(newReq("token=xoxb-2&user=U1"), "users.info")
+	require.NoError(t, err)
+	assert.Equal(t, k1, k2)
+
+	k3, err := requestKey(newReq("user=U2"), "users.info")
+	require.NoError(t, err)
+	assert.NotEqual(t, k1, k3)
+}
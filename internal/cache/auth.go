@@ -45,6 +45,10 @@ type AuthData struct {
 	Token         string
 	Cookie        string
 	UsePlaywright bool
+	// OAuthClientID is the client ID of the Slack app, if set, and the token
+	// is empty, the OAuth flow is used.
+	OAuthClientID     string
+	OAuthClientSecret string
 }
 
 var (
@@ -60,6 +64,7 @@ const (
 	ATCookieFile
 	ATRod
 	ATPlaywright
+	ATOAuth
 )
 
 // Type returns the authentication type that should be used for the current
@@ -79,6 +84,10 @@ func (c AuthData) Type(context.Context) (AuthType, error) {
 		}
 		return ATValue, nil
 	}
+	if c.OAuthClientID != "" {
+		// the user opens the URL in any browser, so it works everywhere.
+		return ATOAuth, nil
+	}
 
 	if !ezLoginSupported() {
 		return ATInvalid, ErrUnsupported
@@ -113,6 +122,8 @@ func (c AuthData) AuthProvider(ctx context.Context, workspace string, opts ...au
 		return auth.NewRODAuth(ctx, opts...)
 	case ATPlaywright:
 		return auth.NewPlaywrightAuth(ctx, opts...)
+	case ATOAuth:
+		return auth.NewOAuthAuth(ctx, append(opts, auth.OAuthWithClient(c.OAuthClientID, c.OAuthClientSecret))...)
 	}
 	return nil, errors.New("internal error: unsupported auth type")
 }
@@ -160,6 +171,12 @@ func withMachineIDOverride(id string) authOption {
 	}
 }
 
+func withCredStore(cs CredStore) authOption {
+	return func(a *authenticator) {
+		a.ct = cs
+	}
+}
+
 func newAuthenticator(cacheDir string, opt ...authOption) authenticator {
 	a := authenticator{
 		dir: cacheDir,
@@ -226,6 +243,7 @@ func (a authenticator) initProvider(ctx context.Context, filename string, worksp
 	if err := saveCreds(a.ct, credsFile, provider); err != nil {
 		trace.Logf(ctx, "error", "failed to save credentials to: %s", credsFile)
 	}
+	saveOnRefresh(a.ct, credsFile, provider)
 
 	return provider, nil
 }
@@ -250,27 +268,51 @@ var ErrFailed = errors.New("failed to load stored credentials")
 func loadCreds(ct createOpener, filename string) (auth.Provider, error) {
 	f, err := ct.Open(filename)
 	if err != nil {
+		slog.Debug("failed to open credentials", "err", err)
 		return nil, ErrFailed
 	}
 	defer f.Close()
 
-	p, err := auth.Load(f)
+	p, err := auth.Restore(f)
 	if err != nil {
 		slog.Debug("failed to load credentials, possibly mismatched machine ID", "err", err)
 		return nil, ErrFailed
 	}
+	saveOnRefresh(ct, filename, p)
 	return p, nil
 }
 
+// refresher is the provider that refreshes the token, see [auth.OAuthAuth].
+type refresher interface {
+	OnRefresh(func(auth.Provider))
+}
+
+// saveOnRefresh saves the credentials each time the token of the provider p
+// is refreshed.
+func saveOnRefresh(ct createOpener, filename string, p auth.Provider) {
+	r, ok := p.(refresher)
+	if !ok {
+		return
+	}
+	r.OnRefresh(func(p auth.Provider) {
+		if err := saveCreds(ct, filename, p); err != nil {
+			slog.Warn("failed to save the refreshed credentials", "filename", filename, "error", err)
+		}
+	})
+}
+
 // saveCreds encrypts and saves the credentials.
 func saveCreds(ct createOpener, filename string, p auth.Provider) error {
 	f, err := ct.Create(filename)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-
-	return auth.Save(f, p)
+	if err := auth.Save(f, p); err != nil {
+		f.Close()
+		return err
+	}
+	// external stores put the credentials on Close.
+	return f.Close()
 }
 
 // AuthReset removes the cached credentials.
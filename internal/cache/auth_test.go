@@ -19,7 +19,10 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -31,6 +34,7 @@ import (
 	"github.com/rusq/slackdump/v4/internal/mocks/mock_cache"
 	"github.com/rusq/slackdump/v4/internal/mocks/mock_io"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -70,6 +74,7 @@ func TestAuthData_Type(t *testing.T) {
 		Token         string
 		Cookie        string
 		UsePlaywright bool
+		OAuthClientID string
 	}
 	type args struct {
 		ctx context.Context
@@ -84,6 +89,8 @@ func TestAuthData_Type(t *testing.T) {
 	tests := []test{
 		{"value", fields{Token: "t", Cookie: "c"}, args{t.Context()}, ATValue, false},
 		{"cookie file", fields{Token: "t", Cookie: testFile}, args{t.Context()}, ATCookieFile, false},
+		{"oauth", fields{OAuthClientID: "123.456"}, args{t.Context()}, ATOAuth, false},
+		{"token takes precedence over oauth", fields{Token: "t", Cookie: "c", OAuthClientID: "123.456"}, args{t.Context()}, ATValue, false},
 	}
 	if !isWSL {
 		tests = append(tests, test{"rod", fields{Token: "", Cookie: ""}, args{t.Context()}, ATRod, false})
@@ -95,6 +102,7 @@ func TestAuthData_Type(t *testing.T) {
 				Token:         tt.fields.Token,
 				Cookie:        tt.fields.Cookie,
 				UsePlaywright: tt.fields.UsePlaywright,
+				OAuthClientID: tt.fields.OAuthClientID,
 			}
 			got, err := c.Type(tt.args.ctx)
 			if (err != nil) != tt.wantErr {
@@ -645,3 +653,29 @@ func Test_withMachineIDOverride(t *testing.T) {
 		}
 	})
 }
+
+func TestManager_LoadProvider_oauthRefresh(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.Form.Get("grant_type"))
+		assert.Equal(t, "xoxe-1", r.Form.Get("refresh_token"))
+		fmt.Fprint(w, `{"ok":true,"access_token":"xoxe.xoxp-new","refresh_token":"xoxe-2","expires_in":43200}`)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	m, err := NewManager(dir, WithNoEncryption(true))
+	require.NoError(t, err)
+	stored := fmt.Sprintf(`{"Token":"xoxe.xoxp-old","Cookie":null,"OAuth":{"ClientID":"123.456","RefreshToken":"xoxe-1","ExpiresAt":"2020-01-01T00:00:00Z","TokenURL":%q}}`, srv.URL)
+	require.NoError(t, os.WriteFile(m.filepath("acme"), []byte(stored), 0o600))
+
+	prov, err := m.LoadProvider("acme")
+	require.NoError(t, err)
+	assert.Equal(t, "xoxe.xoxp-new", prov.SlackToken())
+
+	// the refreshed token is saved.
+	data, err := os.ReadFile(m.filepath("acme"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Token":"xoxe.xoxp-new"`)
+	assert.Contains(t, string(data), `"RefreshToken":"xoxe-2"`)
+}
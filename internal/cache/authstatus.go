@@ -0,0 +1,64 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// authStatusMaxAge is the maximum age of the authentication status record.
+// The record is never considered expired.
+const authStatusMaxAge = time.Duration(math.MaxInt64)
+
+// authStatus is the authentication status of the workspace.
+type authStatus struct {
+	LastSuccess time.Time `json:"last_success"`
+}
+
+// RecordAuthSuccess records the time t as the last time the credentials of
+// the workspace "name" were accepted by Slack.
+func (m *Manager) RecordAuthSuccess(name string, t time.Time) error {
+	return save(m.dir, m.authFile, wspName(m.filename(name)), []authStatus{{LastSuccess: t.UTC()}}, m.createOpener())
+}
+
+// LastAuthSuccess returns the last time the credentials of the workspace
+// "name" were accepted by Slack.  It returns a zero time, if it was never
+// recorded.
+func (m *Manager) LastAuthSuccess(name string) (time.Time, error) {
+	st, err := load[authStatus](m.dir, m.authFile, wspName(m.filename(name)), authStatusMaxAge, m.createOpener())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	if len(st) == 0 {
+		return time.Time{}, fmt.Errorf("%s: %w", name, ErrEmpty)
+	}
+	return st[len(st)-1].LastSuccess, nil
+}
+
+// removeAuthStatus removes the authentication status record of the workspace.
+func (m *Manager) removeAuthStatus(name string) error {
+	if err := os.Remove(makeCacheFilename(m.dir, m.authFile, wspName(m.filename(name)))); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
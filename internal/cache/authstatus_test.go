@@ -0,0 +1,53 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cache
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_LastAuthSuccess(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewManager(dir, WithNoEncryption(true))
+	require.NoError(t, err)
+
+	got, err := m.LastAuthSuccess("acme")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero(), "never recorded")
+
+	ts := time.Date(2026, 10, 1, 2, 3, 4, 0, time.UTC)
+	require.NoError(t, m.RecordAuthSuccess("acme", ts))
+	require.NoError(t, m.RecordAuthSuccess("", ts.Add(time.Hour)))
+
+	got, err = m.LastAuthSuccess("acme")
+	require.NoError(t, err)
+	assert.Equal(t, ts, got)
+	got, err = m.LastAuthSuccess("default")
+	require.NoError(t, err)
+	assert.Equal(t, ts.Add(time.Hour), got, "empty name is the default workspace")
+
+	// deleting the workspace removes the status.
+	require.NoError(t, os.WriteFile(m.filepath("acme"), []byte("x"), 0o600))
+	require.NoError(t, m.Delete("acme"))
+	got, err = m.LastAuthSuccess("acme")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+}
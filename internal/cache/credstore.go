@@ -0,0 +1,381 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cache
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/rusq/slackdump/v4/auth"
+)
+
+// CredStore is the storage for the workspace credentials.  The workspace file
+// ("*.bin") is always created in the cache directory, as it is used to list
+// the workspaces.  For the file store it contains the credentials themselves,
+// other stores keep the credentials elsewhere, and the workspace file only
+// contains the name of the store.
+type CredStore interface {
+	createOpener
+	// Remove should remove the credentials for the workspace file filename
+	// from the store.
+	Remove(filename string) error
+}
+
+// Credential store kinds.
+const (
+	StoreFile    = "file"    // encrypted (or plain) file in the cache directory
+	StoreKeyring = "keyring" // OS keyring, Secret Service on Linux, Keychain on macOS
+	StorePass    = "pass"    // the standard unix password manager
+	StoreVault   = "vault"   // HashiCorp Vault KV v2 secrets engine, "secret" mount
+	StoreCommand = "command" // user-provided command
+)
+
+// CredStores is the list of supported credential store kinds.
+var CredStores = []string{StoreFile, StoreKeyring, StorePass, StoreVault, StoreCommand}
+
+var (
+	ErrUnknownStore = errors.New("unknown credential store")
+	ErrNoCommand    = errors.New("credential store command is not set")
+)
+
+// secretKeyPrefix is the prefix of the key under which the credentials are
+// stored in the external stores.
+const secretKeyPrefix = "slackdump/"
+
+// credStoreFor returns the credential store of the given kind.  The command is
+// only used by the StoreCommand kind.
+func (m *Manager) credStoreFor(kind string, command string) (CredStore, error) {
+	switch kind {
+	case "", StoreFile:
+		return fileStore{m.createOpener()}, nil
+	case StoreKeyring:
+		return keyringStore()
+	case StorePass:
+		return &commandStore{
+			kind: kind,
+			get:  []string{"pass", "show", secretKeyPrefix + "{name}"},
+			put:  []string{"pass", "insert", "--multiline", "--force", secretKeyPrefix + "{name}"},
+			del:  []string{"pass", "rm", "--force", secretKeyPrefix + "{name}"},
+		}, nil
+	case StoreVault:
+		return &commandStore{
+			kind: kind,
+			get:  []string{"vault", "kv", "get", "-mount=secret", "-field=credentials", secretKeyPrefix + "{name}"},
+			put:  []string{"vault", "kv", "put", "-mount=secret", secretKeyPrefix + "{name}", "credentials=-"},
+			del:  []string{"vault", "kv", "metadata", "delete", "-mount=secret", secretKeyPrefix + "{name}"},
+		}, nil
+	case StoreCommand:
+		cmd, err := splitArgs(command)
+		if err != nil {
+			return nil, fmt.Errorf("credential store command: %w", err)
+		}
+		if len(cmd) == 0 {
+			return nil, ErrNoCommand
+		}
+		with := func(op string) []string {
+			return append(slices.Clone(cmd), op, "{name}")
+		}
+		return &commandStore{kind: kind, get: with("get"), put: with("put"), del: with("delete")}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q, must be one of: %s", ErrUnknownStore, kind, strings.Join(CredStores, ", "))
+	}
+}
+
+// storeKind returns the normalised store kind.
+func storeKind(kind string) string {
+	if kind == "" {
+		return StoreFile
+	}
+	return kind
+}
+
+// keyringStore returns the store that keeps credentials in the OS keyring.
+func keyringStore() (CredStore, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return &commandStore{
+			kind: StoreKeyring,
+			get:  []string{"security", "find-generic-password", "-s", "slackdump", "-a", "{name}", "-w"},
+			// security prompts for the password on the terminal, if it is not
+			// given, so the command is passed to the interactive mode on the
+			// standard input instead, where it does not show up in the
+			// process list.
+			put: []string{"security", "-i"},
+			input: func(name, secret string) string {
+				return "add-generic-password -U -s slackdump -a " + shellQuote(name) + " -w " + shellQuote(secret) + "\n"
+			},
+			del: []string{"security", "delete-generic-password", "-s", "slackdump", "-a", "{name}"},
+		}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return &commandStore{
+			kind: StoreKeyring,
+			get:  []string{"secret-tool", "lookup", "service", "slackdump", "workspace", "{name}"},
+			put:  []string{"secret-tool", "store", "--label=Slackdump workspace {name}", "service", "slackdump", "workspace", "{name}"},
+			del:  []string{"secret-tool", "clear", "service", "slackdump", "workspace", "{name}"},
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: keyring is not supported on %s, use %q store", ErrUnknownStore, runtime.GOOS, StoreCommand)
+	}
+}
+
+// fileStore keeps the credentials in the workspace file.
+type fileStore struct {
+	createOpener
+}
+
+func (fileStore) Remove(filename string) error {
+	return os.Remove(filename)
+}
+
+// commandStore keeps the credentials in the external store, that is operated
+// by running commands.  Command arguments may contain the placeholders
+// "{name}", which is replaced with the workspace name, and "{secret}", which
+// is replaced with the credentials.  If the put command does not have the
+// "{secret}" placeholder, credentials are passed on the standard input.  The
+// get command should print the credentials to the standard output.  Prefer
+// the standard input, as the arguments are visible to other users of the
+// system.  If input is set, the put command gets its output on the standard
+// input instead.
+//
+// Credentials are base64-encoded to survive the round trip through stores
+// that treat the secrets as text.
+type commandStore struct {
+	kind string
+	get  []string
+	put  []string
+	del  []string
+	// input returns the standard input of the put command for the workspace
+	// name and the secret.
+	input func(name, secret string) string
+}
+
+// refHeader is the header of the workspace file of the external stores.
+const refHeader = "slackdump credentials reference, store: "
+
+func (s *commandStore) Open(filename string) (io.ReadCloser, error) {
+	if _, err := os.Stat(filename); err != nil {
+		return nil, err
+	}
+	out, err := s.run(s.get, filename, "")
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+	if err != nil {
+		return nil, fmt.Errorf("%s store: invalid credentials: %w", s.kind, err)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *commandStore) Create(filename string) (io.WriteCloser, error) {
+	return &secretWriter{s: s, filename: filename}, nil
+}
+
+func (s *commandStore) Remove(filename string) error {
+	_, err := s.run(s.del, filename, "")
+	return err
+}
+
+// run runs the command template tmpl for the workspace file filename.
+func (s *commandStore) run(tmpl []string, filename string, secret string) ([]byte, error) {
+	var (
+		name     = wspName(filename)
+		args     = make([]string, len(tmpl))
+		useStdin = secret != ""
+	)
+	for i, a := range tmpl {
+		if strings.Contains(a, "{secret}") {
+			useStdin = false
+		}
+		args[i] = strings.NewReplacer("{name}", name, "{secret}", secret).Replace(a)
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	if useStdin {
+		input := secret + "\n"
+		if s.input != nil {
+			input = s.input(name, secret)
+		}
+		cmd.Stdin = strings.NewReader(input)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s store: %s: %w: %s", s.kind, tmpl[0], err, msg)
+		}
+		return nil, fmt.Errorf("%s store: %s: %w", s.kind, tmpl[0], err)
+	}
+	return out, nil
+}
+
+// shellQuote quotes the string s for the shell-like command line parsers.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789@%_+=:,./-") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// splitArgs splits the command line s into arguments, following the shell
+// quoting rules:  arguments are separated by spaces, the single quotes
+// preserve the literal value of the characters, and within the double quotes
+// and outside of quotes the backslash escapes the next character.
+func splitArgs(s string) ([]string, error) {
+	var (
+		args  []string
+		arg   strings.Builder
+		inArg bool
+		quote rune
+		esc   bool
+	)
+	for _, r := range s {
+		switch {
+		case esc:
+			if quote == '"' && !strings.ContainsRune(`"\$`+"`", r) {
+				arg.WriteRune('\\')
+			}
+			arg.WriteRune(r)
+			esc = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\\':
+			esc, inArg = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || esc {
+		return nil, fmt.Errorf("unterminated quote or escape in %q", s)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// secretWriter buffers the credentials, and puts them to the store on Close.
+type secretWriter struct {
+	s        *commandStore
+	filename string
+	buf      bytes.Buffer
+}
+
+func (w *secretWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *secretWriter) Close() error {
+	secret := base64.StdEncoding.EncodeToString(w.buf.Bytes())
+	if _, err := w.s.run(w.s.put, w.filename, secret); err != nil {
+		return err
+	}
+	return os.WriteFile(w.filename, []byte(refHeader+w.s.kind+"\n"), 0o600)
+}
+
+// MigrateCreds moves the credentials of all workspaces from the store of the
+// kind "from" to the current credential store of the manager.  It returns the
+// list of migrated workspaces.  The workspace that fails to migrate is left in
+// the original store, and the migration continues with the next one.
+func (m *Manager) MigrateCreds(from string, command string) ([]string, error) {
+	src, err := m.credStoreFor(from, command)
+	if err != nil {
+		return nil, err
+	}
+	dst := m.credStore()
+	if storeKind(from) == storeKind(m.credKind) {
+		return nil, fmt.Errorf("credentials are already in the %q store", storeKind(from))
+	}
+	workspaces, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		migrated []string
+		errs     error
+	)
+	for _, name := range workspaces {
+		filename := m.filepath(name)
+		p, err := loadCreds(src, filename)
+		if err != nil {
+			errs = errors.Join(errs, &ErrWorkspace{Workspace: name, Message: "failed to load credentials", Err: err})
+			continue
+		}
+		// the credentials are removed from the source store only after they
+		// are saved to the destination and read back successfully.
+		if err := migrateOne(dst, filename, p); err != nil {
+			// the workspace file is shared by the stores, put it back.
+			if rerr := saveCreds(src, filename, p); rerr != nil {
+				err = errors.Join(err, fmt.Errorf("restore failed: %w", rerr))
+			}
+			errs = errors.Join(errs, &ErrWorkspace{Workspace: name, Message: "failed to save credentials", Err: err})
+			continue
+		}
+		migrated = append(migrated, name)
+		if _, ok := src.(fileStore); ok {
+			// the workspace file now holds the destination store data.
+			continue
+		}
+		if err := src.Remove(filename); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = errors.Join(errs, &ErrWorkspace{Workspace: name, Message: "credentials migrated, but failed to remove them from the source store", Err: err})
+		}
+	}
+	return migrated, errs
+}
+
+// migrateOne saves the credentials p to the store dst, and verifies that they
+// can be loaded back.
+func migrateOne(dst CredStore, filename string, p auth.Provider) error {
+	if err := saveCreds(dst, filename, p); err != nil {
+		return err
+	}
+	got, err := loadCreds(dst, filename)
+	if err != nil {
+		return fmt.Errorf("verification failed: %w", err)
+	}
+	if got.SlackToken() != p.SlackToken() {
+		return errors.New("verification failed: credentials mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusq/slackdump/v4/auth"
+)
+
+// fakeHelper creates the credential helper script, that keeps secrets in the
+// directory, and returns the command to run it.
+func fakeHelper(t *testing.T) (string, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	// the space in the path checks that the command is split with regard to
+	// the quotes.
+	dir := filepath.Join(t.TempDir(), "cred helper")
+	require.NoError(t, os.Mkdir(dir, 0o700))
+	script := filepath.Join(dir, "helper.sh")
+	body := "#!/bin/sh\n" +
+		"case \"$1\" in\n" +
+		"get) cat \"" + dir + "/$2\" ;;\n" +
+		"put) cat > \"" + dir + "/$2\" ;;\n" +
+		"delete) rm \"" + dir + "/$2\" ;;\n" +
+		"esac\n"
+	require.NoError(t, os.WriteFile(script, []byte(body), 0o700))
+	return "sh " + shellQuote(script), dir
+}
+
+func testProvider(t *testing.T) auth.Provider {
+	t.Helper()
+	prov, err := auth.NewValueAuth("xoxc-123-456-789-abc", "xoxd-cookie")
+	require.NoError(t, err)
+	return prov
+}
+
+func TestManager_credStoreFor(t *testing.T) {
+	m := &Manager{}
+	for _, kind := range []string{"", StoreFile, StorePass, StoreVault} {
+		_, err := m.credStoreFor(kind, "")
+		assert.NoError(t, err, kind)
+	}
+	_, err := m.credStoreFor("bogus", "")
+	assert.ErrorIs(t, err, ErrUnknownStore)
+	_, err = m.credStoreFor(StoreCommand, " ")
+	assert.ErrorIs(t, err, ErrNoCommand)
+}
+
+func TestCommandStore(t *testing.T) {
+	helper, secrets := fakeHelper(t)
+	dir := t.TempDir()
+	m, err := NewManager(dir, WithCredStore(StoreCommand, helper))
+	require.NoError(t, err)
+
+	prov := testProvider(t)
+	require.NoError(t, m.saveProvider("acme", prov))
+
+	// the workspace file is a reference, the credentials are in the helper.
+	ref, err := os.ReadFile(filepath.Join(dir, "acme.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, refHeader+StoreCommand+"\n", string(ref))
+	assert.FileExists(t, filepath.Join(secrets, "acme"))
+	assert.NotContains(t, string(ref), "xoxc")
+
+	ws, err := m.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme"}, ws)
+
+	got, err := m.LoadProvider("acme")
+	require.NoError(t, err)
+	assert.Equal(t, prov.SlackToken(), got.SlackToken())
+
+	require.NoError(t, m.Delete("acme"))
+	assert.NoFileExists(t, filepath.Join(secrets, "acme"))
+	assert.NoFileExists(t, filepath.Join(dir, "acme.bin"))
+}
+
+func TestManager_MigrateCreds(t *testing.T) {
+	helper, secrets := fakeHelper(t)
+	dir := t.TempDir()
+	prov := testProvider(t)
+
+	fm, err := NewManager(dir, WithNoEncryption(true))
+	require.NoError(t, err)
+	require.NoError(t, fm.saveProvider("acme", prov))
+
+	// file -> command
+	cm, err := NewManager(dir, WithNoEncryption(true), WithCredStore(StoreCommand, helper))
+	require.NoError(t, err)
+	migrated, err := cm.MigrateCreds(StoreFile, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme"}, migrated)
+	ref, err := os.ReadFile(filepath.Join(dir, "acme.bin"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(ref), refHeader))
+	got, err := cm.LoadProvider("acme")
+	require.NoError(t, err)
+	assert.Equal(t, prov.SlackToken(), got.SlackToken())
+
+	// command -> file
+	migrated, err = fm.MigrateCreds(StoreCommand, helper)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme"}, migrated)
+	assert.NoFileExists(t, filepath.Join(secrets, "acme"))
+	got, err = fm.LoadProvider("acme")
+	require.NoError(t, err)
+	assert.Equal(t, prov.SlackToken(), got.SlackToken())
+
+	_, err = fm.MigrateCreds("", "")
+	assert.Error(t, err, "same store")
+}
+
+func TestManager_MigrateCreds_saveFails(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	dir := t.TempDir()
+	prov := testProvider(t)
+
+	fm, err := NewManager(dir, WithNoEncryption(true))
+	require.NoError(t, err)
+	require.NoError(t, fm.saveProvider("acme", prov))
+
+	// the helper fails to store the secret, the credentials must stay in
+	// the source store.
+	cm, err := NewManager(dir, WithNoEncryption(true), WithCredStore(StoreCommand, "false"))
+	require.NoError(t, err)
+	migrated, err := cm.MigrateCreds(StoreFile, "")
+	assert.Error(t, err)
+	assert.Empty(t, migrated)
+
+	got, err := fm.LoadProvider("acme")
+	require.NoError(t, err)
+	assert.Equal(t, prov.SlackToken(), got.SlackToken())
+}
+
+func TestCommandStore_input(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "input")
+	s := &commandStore{
+		kind: StoreKeyring,
+		put:  []string{"sh", "-c", "cat > " + out},
+		input: func(name, secret string) string {
+			return "add -a " + shellQuote(name) + " -w " + shellQuote(secret) + "\n"
+		},
+	}
+	_, err := s.run(s.put, filepath.Join(dir, "o'brien.bin"), "c2VjcmV0+/==")
+	require.NoError(t, err)
+	got, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, `add -a 'o'"'"'brien' -w c2VjcmV0+/==`+"\n", string(got))
+}
+
+func Test_splitArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    []string
+		wantErr bool
+	}{
+		{"empty", " ", nil, false},
+		{"plain", "pass-helper  --flag x", []string{"pass-helper", "--flag", "x"}, false},
+		{"double quotes", `vault kv get -field="token" "secret/my app"`, []string{"vault", "kv", "get", "-field=token", "secret/my app"}, false},
+		{"single quotes", `helper 'a "b" \c'`, []string{"helper", `a "b" \c`}, false},
+		{"escapes", `helper a\ b "c\"d" "e\f"`, []string{"helper", "a b", `c"d`, `e\f`}, false},
+		{"empty argument", `helper ""`, []string{"helper", ""}, false},
+		{"unterminated quote", `helper "a`, nil, true},
+		{"trailing escape", `helper a\`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitArgs(tt.s)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cache
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rusq/slackdump/v4/internal/network"
+)
+
+// limitsMaxAge is the maximum age of the learned rate limits.  Slack may
+// change the limits, or the workspace conditions may change, so the old
+// values are discarded.
+const limitsMaxAge = 7 * 24 * time.Hour
+
+// LoadLimits loads the rate limits learned by the adaptive limiter for teamID.
+func (m *Manager) LoadLimits(teamID string) ([]network.TierRate, error) {
+	return load[network.TierRate](m.dir, m.limitsFile, teamID, limitsMaxAge, m.createOpener())
+}
+
+// CacheLimits saves the rate limits learned by the adaptive limiter for
+// teamID.
+func (m *Manager) CacheLimits(teamID string, rates []network.TierRate) error {
+	return save(m.dir, m.limitsFile, teamID, rates, m.createOpener())
+}
+
+// AllLimits returns the learned rate limits for all teams that have them in
+// the cache directory, the key is the team ID.  Expired limits are not
+// returned.
+func (m *Manager) AllLimits() (map[string][]network.TierRate, error) {
+	ne := filenameSplit(m.limitsFile)
+	matches, err := filepath.Glob(filepath.Join(m.dir, ne[0]+"-*"+ne[1]))
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[string][]network.TierRate, len(matches))
+	for _, match := range matches {
+		teamID := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(match), ne[0]+"-"), ne[1])
+		rates, err := m.LoadLimits(teamID)
+		if err != nil {
+			if errors.Is(err, ErrExpired) || errors.Is(err, ErrEmpty) {
+				continue
+			}
+			return nil, err
+		}
+		all[teamID] = rates
+	}
+	return all, nil
+}
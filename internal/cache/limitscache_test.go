@@ -0,0 +1,52 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusq/slackdump/v4/internal/network"
+)
+
+func TestManager_CacheLimits(t *testing.T) {
+	m, err := NewManager(t.TempDir(), WithNoEncryption(true))
+	require.NoError(t, err)
+
+	rates := []network.TierRate{
+		{Tier: network.Tier2, Factor: 0.5, Throttled: 3, Updated: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{Tier: network.Tier3, Factor: 1},
+	}
+	require.NoError(t, m.CacheLimits("T123", rates))
+	require.NoError(t, m.CacheLimits("T456", rates[1:]))
+
+	got, err := m.LoadLimits("T123")
+	require.NoError(t, err)
+	assert.Equal(t, rates, got)
+
+	_, err = m.LoadLimits("T789")
+	assert.Error(t, err)
+
+	all, err := m.AllLimits()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]network.TierRate{
+		"T123": rates,
+		"T456": rates[1:],
+	}, all)
+}
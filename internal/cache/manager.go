@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"slices"
@@ -47,16 +48,24 @@ import (
 //   - "*.bin" - other workspaces, the filename is the name of the workspace.
 //   - "workspace.txt" - a pointer to the current workspace, it contains the
 //     current workspace name.
-//   - "*.cache" - cache files, they contain the cache for users and channels.
+//   - "*.cache" - cache files, they contain the cache for users and channels,
+//     the rate limits learned by the adaptive limiter, and the time of the
+//     last successful authentication in each workspace.
 type Manager struct {
 	dir         string
 	authOptions []auth.Option
 
 	userFile    string
 	channelFile string
+	limitsFile  string
+	authFile    string
 	// machineID is the machine ID override for encryption/decryption.
 	machineID    string
 	noEncryption bool
+	// credKind is the kind of the credential store, see [CredStores].
+	credKind    string
+	credCommand string
+	creds       CredStore
 }
 
 const (
@@ -98,6 +107,16 @@ func WithNoEncryption(b bool) Option {
 	}
 }
 
+// WithCredStore sets the credential store kind, one of [CredStores].  The
+// command is the credential helper command for the [StoreCommand] kind, and is
+// ignored by other kinds.
+func WithCredStore(kind string, command string) Option {
+	return func(m *Manager) {
+		m.credKind = kind
+		m.credCommand = command
+	}
+}
+
 // WithChannelCacheBase allows to change the default cache file name for
 // channels cache.
 func WithChannelCacheBase(filename string) Option {
@@ -141,10 +160,17 @@ func NewManager(dir string, opts ...Option) (*Manager, error) {
 		dir:         dir,
 		userFile:    "users.cache",
 		channelFile: "channels.cache",
+		limitsFile:  "limits.cache",
+		authFile:    "auth.cache",
 	}
 	for _, opt := range opts {
 		opt(m)
 	}
+	cs, err := m.credStoreFor(m.credKind, m.credCommand)
+	if err != nil {
+		return nil, err
+	}
+	m.creds = cs
 	if m.dir != "" {
 		if err := os.MkdirAll(dir, 0o700); err != nil {
 			return nil, err
@@ -161,22 +187,24 @@ func NewManager(dir string, opts ...Option) (*Manager, error) {
 // If the creds is empty, it attempts to load the stored credentials.  If it
 // finds them, it returns an initialised credentials provider.  If not - it
 // returns the auth provider according to the type of credentials determined
-// by creds.AuthProvider, and saves them to an AES-256-CFB encrypted storage.
+// by creds.AuthProvider, and saves them to the credential store, which, by
+// default, is an AES-256-CFB encrypted file (see [WithCredStore]).
 //
-// The storage is encrypted using the hash of the unique machine-ID, supplied by
+// The file storage is encrypted using the hash of the unique machine-ID, supplied by
 // the operating system (see package encio), it makes it impossible use the
 // stored credentials on another machine (including virtual), even another
 // operating system on the same machine, unless it's a clone of the source
 // operating system on which the credentials storage was created.
 func (m *Manager) Auth(ctx context.Context, name string, c Credentials) (auth.Provider, error) {
-	var opts []authOption
-	if m.noEncryption {
-		opts = append(opts, withNoEncryption())
-	} else {
-		opts = append(opts, withMachineIDOverride(m.machineID))
+	a := newAuthenticator(m.dir, withCredStore(m.credStore()))
+	prov, err := a.initProvider(ctx, m.filename(name), name, c, m.authOptions...)
+	if err != nil {
+		return nil, err
 	}
-	a := newAuthenticator(m.dir, opts...)
-	return a.initProvider(ctx, m.filename(name), name, c, m.authOptions...)
+	if err := m.RecordAuthSuccess(name, time.Now()); err != nil {
+		slog.DebugContext(ctx, "failed to record authentication status", "workspace", name, "error", err)
+	}
+	return prov, nil
 }
 
 func (m *Manager) Open(name string) (io.ReadCloser, error) {
@@ -190,14 +218,23 @@ func (m *Manager) createOpener() createOpener {
 	return encryptedFile{machineID: m.machineID}
 }
 
-// LoadProvider loads the file from disk without any logical validation.
+// credStore returns the credential store of the manager.
+func (m *Manager) credStore() CredStore {
+	if m.creds == nil {
+		return fileStore{m.createOpener()}
+	}
+	return m.creds
+}
+
+// LoadProvider loads the credentials from the credential store without any
+// logical validation.
 func (m *Manager) LoadProvider(name string) (auth.Provider, error) {
-	return loadCreds(m.createOpener(), m.filepath(name))
+	return loadCreds(m.credStore(), m.filepath(name))
 }
 
-// saveProvider saves the provider to the file, no questions asked.
+// saveProvider saves the provider to the credential store, no questions asked.
 func (m *Manager) saveProvider(name string, p auth.Provider) error {
-	return saveCreds(m.createOpener(), m.filepath(name), p)
+	return saveCreds(m.credStore(), m.filepath(name), p)
 }
 
 // ErrWorkspace is the error returned by the workspace manager, it contains the
@@ -224,14 +261,20 @@ func newErrNoWorkspace(name string) *ErrWorkspace {
 	return &ErrWorkspace{Workspace: name, Message: "no such workspace"}
 }
 
-// Delete deletes the workspace file.
+// Delete deletes the workspace file and the credentials.
 func (m *Manager) Delete(name string) error {
 	if !m.Exists(name) {
 		return newErrNoWorkspace(name)
 	}
-	if err := os.Remove(m.filepath(name)); err != nil {
+	if err := m.credStore().Remove(m.filepath(name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return &ErrWorkspace{Workspace: name, Message: "failed to delete credentials", Err: err}
+	}
+	if err := os.Remove(m.filepath(name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return &ErrWorkspace{Workspace: name, Message: "failed to delete", Err: err}
 	}
+	if err := m.removeAuthStatus(name); err != nil {
+		return &ErrWorkspace{Workspace: name, Message: "failed to delete authentication status", Err: err}
+	}
 	return nil
 }
 
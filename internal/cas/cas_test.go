@@ -0,0 +1,190 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cas
+
+import (
+	"crypto/sha256"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/rusq/fsadapter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, fsa fsadapter.FS, name string, data string) {
+	t.Helper()
+	f, err := fsa.Create(name)
+	require.NoError(t, err)
+	_, err = io.WriteString(f, data)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+func countBlobs(t *testing.T, store string) int {
+	t.Helper()
+	var n int
+	err := filepath.WalkDir(filepath.Join(store, hashAlg), func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			n++
+		}
+		return err
+	})
+	require.NoError(t, err)
+	return n
+}
+
+func TestWriter(t *testing.T) {
+	store := t.TempDir()
+	arc1, arc2 := t.TempDir(), t.TempDir()
+
+	w1, err := NewWriter(fsadapter.NewDirectory(arc1), arc1, store)
+	require.NoError(t, err)
+	writeFile(t, w1, "__uploads/F1/report.pdf", "quarterly report")
+	writeFile(t, w1, "__uploads/F2/logo.png", "logo")
+	require.NoError(t, w1.WriteFile("channels.json", []byte("[]"), 0o644))
+
+	w2, err := NewWriter(fsadapter.NewDirectory(arc2), arc2, store)
+	require.NoError(t, err)
+	writeFile(t, w2, "__uploads/F3/report-copy.pdf", "quarterly report")
+
+	assert.Equal(t, 2, countBlobs(t, store), "same contents must be stored once")
+	assert.FileExists(t, filepath.Join(arc1, "channels.json"), "non-upload files are written to the archive")
+	assert.NoDirExists(t, filepath.Join(arc1, "__uploads"))
+
+	t.Run("reading", func(t *testing.T) {
+		cfs, err := Open(os.DirFS(arc2), arc2, "")
+		require.NoError(t, err)
+		data, err := fs.ReadFile(cfs, "__uploads/F3/report-copy.pdf")
+		require.NoError(t, err)
+		assert.Equal(t, "quarterly report", string(data))
+
+		cfs, err = Open(os.DirFS(arc1), arc1, "")
+		require.NoError(t, err)
+		require.NoError(t, fstest.TestFS(cfs, "__uploads/F1/report.pdf", "__uploads/F2/logo.png"))
+		sub, err := fs.Sub(cfs, "__uploads")
+		require.NoError(t, err)
+		matches, err := fs.Glob(sub, "F1/*")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"F1/report.pdf"}, matches)
+	})
+	t.Run("reopen uses the store from the manifest", func(t *testing.T) {
+		w, err := NewWriter(fsadapter.NewDirectory(arc1), arc1, "")
+		require.NoError(t, err)
+		assert.Equal(t, w1.Store().Dir(), w.Store().Dir())
+		writeFile(t, w, "__uploads/F1/report.pdf", "quarterly report, revised")
+
+		cfs, err := Open(os.DirFS(arc1), arc1, "")
+		require.NoError(t, err)
+		data, err := fs.ReadFile(cfs, "__uploads/F1/report.pdf")
+		require.NoError(t, err)
+		assert.Equal(t, "quarterly report, revised", string(data), "later entry wins")
+	})
+	t.Run("different store", func(t *testing.T) {
+		_, err := NewWriter(fsadapter.NewDirectory(arc1), arc1, t.TempDir())
+		assert.ErrorIs(t, err, ErrStoreMismatch)
+	})
+	t.Run("no manifest and no store", func(t *testing.T) {
+		_, err := NewWriter(fsadapter.NewDirectory(t.TempDir()), t.TempDir(), "")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+	t.Run("missing blob", func(t *testing.T) {
+		cfs, err := Open(os.DirFS(arc1), arc1, "")
+		require.NoError(t, err)
+		blob, err := cfs.st.blobPath(cfs.files["__uploads/F2/logo.png"].SHA256)
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(blob))
+		_, err = fs.Stat(cfs, "__uploads/F2/logo.png")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+func TestOpen_movedStore(t *testing.T) {
+	root := t.TempDir()
+	arc, store := filepath.Join(root, "archive"), filepath.Join(root, "store")
+	w, err := NewWriter(fsadapter.NewDirectory(arc), arc, store)
+	require.NoError(t, err)
+	writeFile(t, w, "__uploads/F1/report.pdf", "quarterly report")
+
+	f, err := os.Open(filepath.Join(arc, ManifestFile))
+	require.NoError(t, err)
+	m, err := readManifest(f)
+	f.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "../store", m.store, "store path is relative to the archive")
+
+	// the archive and the store are moved together.
+	moved := t.TempDir()
+	arc2, store2 := filepath.Join(moved, "archive"), filepath.Join(moved, "store")
+	require.NoError(t, os.Rename(arc, arc2))
+	require.NoError(t, os.Rename(store, store2))
+	cfs, err := Open(os.DirFS(arc2), arc2, "")
+	require.NoError(t, err)
+	data, err := fs.ReadFile(cfs, "__uploads/F1/report.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "quarterly report", string(data))
+
+	// the store is moved elsewhere, and is given explicitly.
+	store3 := filepath.Join(t.TempDir(), "store")
+	require.NoError(t, os.Rename(store2, store3))
+	_, err = Open(os.DirFS(arc2), arc2, "")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	cfs, err = Open(os.DirFS(arc2), arc2, store3)
+	require.NoError(t, err)
+	data, err = fs.ReadFile(cfs, "__uploads/F1/report.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "quarterly report", string(data))
+
+	_, err = Open(os.DirFS(arc2), "", "")
+	assert.Error(t, err, "relative store and unknown archive directory")
+}
+
+func TestOpen_noManifest(t *testing.T) {
+	_, err := Open(os.DirFS(t.TempDir()), "", "")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.False(t, Exists(t.TempDir()))
+}
+
+func TestStore_Open_invalidHash(t *testing.T) {
+	st, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	valid := strings.Repeat("ab", sha256.Size)
+	for _, sum := range []string{
+		"",
+		"abc",
+		strings.ToUpper(valid),
+		"../../../../../../../../etc/passwd" + strings.Repeat("/", 64-34),
+		strings.Repeat("g", sha256.Size*2),
+	} {
+		_, err := st.Open(sum)
+		assert.ErrorIs(t, err, errInvalidHash, sum)
+	}
+	_, err = st.Open(valid)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestReadManifest_invalidHash(t *testing.T) {
+	manifest := `{"store":"../store"}
+{"path":"__uploads/F1/secret.txt","sha256":"` + strings.Repeat("../", 21) + `a","size":1}
+`
+	_, err := readManifest(strings.NewReader(manifest))
+	assert.ErrorIs(t, err, errInvalidHash)
+}
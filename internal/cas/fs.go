@@ -0,0 +1,211 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cas
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// FS is the read-only filesystem of the archive files, that are kept in the
+// file store.  The paths are the same as in the manifest.
+type FS struct {
+	st    *Store
+	files map[string]record
+	dirs  map[string][]string // directory -> sorted base names of children
+}
+
+var (
+	_ fs.StatFS    = (*FS)(nil)
+	_ fs.ReadDirFS = (*FS)(nil)
+)
+
+// Open reads the manifest from the root filesystem of the archive and
+// returns the filesystem of the stored files.  If there's no manifest, it
+// returns the error that wraps [fs.ErrNotExist].  The relative store path in
+// the manifest is resolved against the archive directory dir.  If storeDir
+// is not empty, it is used instead of the store referenced by the manifest,
+// i.e. if the store was moved.
+func Open(rootfs fs.FS, dir string, storeDir string) (*FS, error) {
+	f, err := rootfs.Open(ManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := readManifest(f)
+	if err != nil {
+		return nil, err
+	}
+	if storeDir == "" {
+		if storeDir, err = resolveStore(dir, m.store); err != nil {
+			return nil, err
+		}
+	} else if storeDir, err = filepath.Abs(storeDir); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(storeDir); err != nil {
+		return nil, err
+	}
+	cfs := &FS{
+		st:    &Store{dir: storeDir},
+		files: m.entries,
+		dirs:  map[string][]string{".": nil},
+	}
+	for name := range m.entries {
+		for dir, base := path.Split(name); ; dir, base = path.Split(strings.TrimSuffix(dir, "/")) {
+			dir = strings.TrimSuffix(dir, "/")
+			if dir == "" {
+				dir = "."
+			}
+			_, seen := cfs.dirs[dir]
+			if !slices.Contains(cfs.dirs[dir], base) {
+				cfs.dirs[dir] = append(cfs.dirs[dir], base)
+			}
+			if seen || dir == "." {
+				break
+			}
+		}
+	}
+	for _, children := range cfs.dirs {
+		slices.Sort(children)
+	}
+	return cfs, nil
+}
+
+func (cfs *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if rec, ok := cfs.files[name]; ok {
+		f, err := cfs.st.Open(rec.SHA256)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return &file{File: f, fi: fileInfo{name: path.Base(name), size: rec.Size}}, nil
+	}
+	if _, ok := cfs.dirs[name]; ok {
+		entries, _ := cfs.ReadDir(name)
+		return &dir{fi: fileInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (cfs *FS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	if rec, ok := cfs.files[name]; ok {
+		blob, err := cfs.st.blobPath(rec.SHA256)
+		if err != nil {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+		}
+		if _, err := os.Stat(blob); err != nil {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+		}
+		return fileInfo{name: path.Base(name), size: rec.Size}, nil
+	}
+	if _, ok := cfs.dirs[name]; ok {
+		return fileInfo{name: path.Base(name), dir: true}, nil
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+func (cfs *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	children, ok := cfs.dirs[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, base := range children {
+		full := path.Join(name, base)
+		if rec, ok := cfs.files[full]; ok {
+			entries = append(entries, fileInfo{name: base, size: rec.Size})
+		} else {
+			entries = append(entries, fileInfo{name: base, dir: true})
+		}
+	}
+	return entries, nil
+}
+
+// fileInfo is the information about the stored file or the directory.  It
+// implements both [fs.FileInfo] and [fs.DirEntry].
+type fileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (fi fileInfo) Name() string { return fi.name }
+func (fi fileInfo) Size() int64  { return fi.size }
+func (fi fileInfo) Mode() fs.FileMode {
+	if fi.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+func (fi fileInfo) ModTime() time.Time         { return time.Time{} }
+func (fi fileInfo) IsDir() bool                { return fi.dir }
+func (fi fileInfo) Sys() any                   { return nil }
+func (fi fileInfo) Type() fs.FileMode          { return fi.Mode().Type() }
+func (fi fileInfo) Info() (fs.FileInfo, error) { return fi, nil }
+
+// file is the stored file, opened for reading.
+type file struct {
+	*os.File
+	fi fileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) {
+	return f.fi, nil
+}
+
+// ReadDir shadows the method of [os.File], files are not directories.
+func (f *file) ReadDir(int) ([]fs.DirEntry, error) {
+	return nil, &fs.PathError{Op: "readdir", Path: f.fi.name, Err: fs.ErrInvalid}
+}
+
+// dir is the directory, opened for reading.
+type dir struct {
+	fi      fileInfo
+	entries []fs.DirEntry
+	off     int
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) { return d.fi, nil }
+func (d *dir) Close() error               { return nil }
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.fi.name, Err: fs.ErrInvalid}
+}
+
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.off:]
+	if n <= 0 {
+		d.off = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(rest))
+	d.off += n
+	return rest[:n], nil
+}
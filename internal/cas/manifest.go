@@ -0,0 +1,253 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cas
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/rusq/fsadapter"
+
+	"github.com/rusq/slackdump/v4/internal/chunk"
+)
+
+// ManifestFile is the name of the manifest file in the archive directory.
+const ManifestFile = "__cas.jsonl"
+
+// ErrStoreMismatch is returned if the archive already references the
+// different file store.
+var ErrStoreMismatch = errors.New("archive uses a different file store")
+
+// record is the manifest record.  The manifest is the JSON lines file, where
+// the first record has the Store set, and the rest are the file entries.
+// The later entries for the same path override the earlier ones.  The Store
+// is relative to the archive directory, if possible, so that the archive and
+// the store could be moved together.
+type record struct {
+	Store  string `json:"store,omitempty"`
+	Path   string `json:"path,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+	Size   int64  `json:"size,omitempty"`
+}
+
+// Exists returns true if the archive directory dir has the manifest.
+func Exists(dir string) bool {
+	fi, err := os.Stat(filepath.Join(dir, ManifestFile))
+	return err == nil && fi.Mode().IsRegular()
+}
+
+// manifest is the parsed manifest.
+type manifest struct {
+	store   string
+	entries map[string]record
+}
+
+// readManifest reads the manifest from r.
+func readManifest(r io.Reader) (*manifest, error) {
+	m := &manifest{entries: make(map[string]record)}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 4096), 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("manifest line %d: %w", n, err)
+		}
+		if rec.Store != "" {
+			m.store = rec.Store
+		}
+		if rec.Path != "" {
+			if err := checkSum(rec.SHA256); err != nil {
+				return nil, fmt.Errorf("manifest line %d: %w", n, err)
+			}
+			m.entries[rec.Path] = rec
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if m.store == "" {
+		return nil, errors.New("manifest does not reference the file store")
+	}
+	return m, nil
+}
+
+// Writer is the [fsadapter.FS] that puts the files within the uploads
+// directory into the file store and records them in the manifest of the
+// archive.  All other files are written to the underlying adapter.
+type Writer struct {
+	fsadapter.FS
+	st       *Store
+	manifest string // path to the manifest file
+
+	mu sync.Mutex
+}
+
+// NewWriter returns the Writer for the archive directory dir, that writes
+// the files into the store in the storeDir, and the rest into fsa.  If the
+// archive already has the manifest, storeDir may be empty, then the store
+// referenced by the manifest is used.  If storeDir is different from the
+// store in the manifest, it returns [ErrStoreMismatch].
+func NewWriter(fsa fsadapter.FS, dir string, storeDir string) (*Writer, error) {
+	mpath := filepath.Join(dir, ManifestFile)
+	f, err := os.Open(mpath)
+	if err == nil {
+		m, err := readManifest(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		mstore, err := resolveStore(dir, m.store)
+		if err != nil {
+			return nil, err
+		}
+		if storeDir == "" {
+			storeDir = mstore
+		} else if abs, err := filepath.Abs(storeDir); err != nil {
+			return nil, err
+		} else if abs != mstore {
+			return nil, fmt.Errorf("%w: %s", ErrStoreMismatch, mstore)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	} else if storeDir == "" {
+		return nil, err
+	}
+
+	st, err := NewStore(storeDir)
+	if err != nil {
+		return nil, err
+	}
+	w := &Writer{FS: fsa, st: st, manifest: mpath}
+	if !Exists(dir) {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		if err := w.append(record{Store: relStore(dir, st.Dir())}); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// relStore returns the path of the store relative to the archive directory
+// dir, or the absolute path of the store, if it is not possible.
+func relStore(dir string, store string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return store
+	}
+	rel, err := filepath.Rel(abs, store)
+	if err != nil {
+		return store
+	}
+	return filepath.ToSlash(rel)
+}
+
+// resolveStore returns the absolute path of the store referenced by the
+// manifest of the archive in the directory dir.
+func resolveStore(dir string, store string) (string, error) {
+	store = filepath.FromSlash(store)
+	if filepath.IsAbs(store) {
+		return store, nil
+	}
+	if dir == "" {
+		return "", fmt.Errorf("file store path %q is relative, and the archive directory is unknown, specify the file store directory", store)
+	}
+	return filepath.Abs(filepath.Join(dir, store))
+}
+
+// Store returns the file store of the writer.
+func (w *Writer) Store() *Store {
+	return w.st
+}
+
+// isStored returns true if the file name goes into the store.
+func isStored(name string) bool {
+	return strings.HasPrefix(path.Clean(filepath.ToSlash(name)), chunk.UploadsDir+"/")
+}
+
+// Create creates the file name.  If it is within the uploads directory, the
+// contents are stored in the file store when the file is closed.
+func (w *Writer) Create(name string) (io.WriteCloser, error) {
+	if !isStored(name) {
+		return w.FS.Create(name)
+	}
+	bw, err := w.st.newBlobWriter()
+	if err != nil {
+		return nil, err
+	}
+	return &storedFile{blobWriter: bw, w: w, name: path.Clean(filepath.ToSlash(name))}, nil
+}
+
+// WriteFile writes data to the file name.  See [Writer.Create].
+func (w *Writer) WriteFile(name string, data []byte, perm os.FileMode) error {
+	if !isStored(name) {
+		return w.FS.WriteFile(name, data, perm)
+	}
+	sum, size, err := w.st.Put(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	return w.append(record{Path: path.Clean(filepath.ToSlash(name)), SHA256: sum, Size: size})
+}
+
+// append appends the record to the manifest.
+func (w *Writer) append(rec record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	f, err := os.OpenFile(w.manifest, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// storedFile is the file being written into the store.
+type storedFile struct {
+	*blobWriter
+	w    *Writer
+	name string
+}
+
+func (sf *storedFile) Close() error {
+	sum, size, err := sf.commit()
+	if err != nil {
+		return err
+	}
+	return sf.w.append(record{Path: sf.name, SHA256: sum, Size: size})
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package cas implements the content-addressed file store.  File
+// attachments of several archives can be kept in one shared store, where each
+// file is stored once under the hash of its contents, and each archive has a
+// manifest that maps the file paths within the archive to the hashes.
+//
+// The archive directory layout is unchanged, the manifest uses the same paths
+// as the mattermost storage, i.e. "__uploads/F12345/report.pdf", but the
+// files themselves are kept in the store.
+package cas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	hashAlg = "sha256"
+	tmpDir  = "tmp"
+)
+
+// Store is the content-addressed blob store.  Blobs are kept in the
+// directory tree:
+//
+//	<dir>/
+//	  +-- sha256/
+//	  |   +-- 3a/3a7bd3e2360a3d29eea436fcfb7e44c735d117c42d1c1835420b6b9942dd4f1b
+//	  |   +-- ...
+//	  +-- tmp/
+type Store struct {
+	dir string
+}
+
+// NewStore opens the store in the directory dir, creating it if necessary.
+func NewStore(dir string) (*Store, error) {
+	if dir == "" {
+		return nil, errors.New("store directory is not specified")
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Join(abs, tmpDir), 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create the file store: %w", err)
+	}
+	return &Store{dir: abs}, nil
+}
+
+// Dir returns the absolute path of the store directory.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// errInvalidHash is returned if the hash is not the lowercase hex-encoded
+// SHA-256 hash.
+var errInvalidHash = errors.New("invalid hash")
+
+// checkSum returns [errInvalidHash] if sum is not the lowercase hex-encoded
+// SHA-256 hash.  The hashes come from the manifest of the archive, that
+// can't be trusted, and are used in the blob paths.
+func checkSum(sum string) error {
+	if len(sum) != sha256.Size*2 || strings.ToLower(sum) != sum {
+		return fmt.Errorf("%w: %q", errInvalidHash, sum)
+	}
+	if _, err := hex.DecodeString(sum); err != nil {
+		return fmt.Errorf("%w: %q", errInvalidHash, sum)
+	}
+	return nil
+}
+
+// blobPath returns the path of the blob with the hash sum.
+func (s *Store) blobPath(sum string) (string, error) {
+	if err := checkSum(sum); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, hashAlg, sum[:2], sum), nil
+}
+
+// Open opens the blob with the hash sum for reading.
+func (s *Store) Open(sum string) (*os.File, error) {
+	blob, err := s.blobPath(sum)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(blob)
+}
+
+// Put stores the contents of r, and returns its hash and size.  If the blob
+// with the same contents exists, it is not stored again.
+func (s *Store) Put(r io.Reader) (sum string, size int64, err error) {
+	bw, err := s.newBlobWriter()
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := io.Copy(bw, r); err != nil {
+		bw.abort()
+		return "", 0, err
+	}
+	return bw.commit()
+}
+
+// blobWriter writes the blob to the temporary file, calculating the hash.
+type blobWriter struct {
+	s   *Store
+	tmp *os.File
+	w   io.Writer
+	h   interface{ Sum([]byte) []byte }
+	n   int64
+}
+
+func (s *Store) newBlobWriter() (*blobWriter, error) {
+	tmp, err := os.CreateTemp(filepath.Join(s.dir, tmpDir), "blob-*")
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	return &blobWriter{s: s, tmp: tmp, w: io.MultiWriter(tmp, h), h: h}, nil
+}
+
+func (bw *blobWriter) Write(p []byte) (int, error) {
+	n, err := bw.w.Write(p)
+	bw.n += int64(n)
+	return n, err
+}
+
+// commit moves the temporary file into place, unless the blob already
+// exists, and returns the hash and the size of the blob.
+func (bw *blobWriter) commit() (string, int64, error) {
+	if err := bw.tmp.Close(); err != nil {
+		os.Remove(bw.tmp.Name())
+		return "", 0, err
+	}
+	sum := hex.EncodeToString(bw.h.Sum(nil))
+	blob, err := bw.s.blobPath(sum)
+	if err != nil {
+		os.Remove(bw.tmp.Name())
+		return "", 0, err
+	}
+	if _, err := os.Stat(blob); err == nil {
+		// deduplicated.
+		return sum, bw.n, os.Remove(bw.tmp.Name())
+	}
+	if err := os.MkdirAll(filepath.Dir(blob), 0o755); err != nil {
+		os.Remove(bw.tmp.Name())
+		return "", 0, err
+	}
+	if err := os.Rename(bw.tmp.Name(), blob); err != nil {
+		os.Remove(bw.tmp.Name())
+		return "", 0, err
+	}
+	return sum, bw.n, nil
+}
+
+// abort removes the temporary file.
+func (bw *blobWriter) abort() {
+	bw.tmp.Close()
+	os.Remove(bw.tmp.Name())
+}
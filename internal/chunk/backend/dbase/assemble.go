@@ -48,6 +48,7 @@ var assemblers = map[chunk.ChunkType]func(context.Context, sqlx.ExtContext, *rep
 	chunk.CChannelUsers:   asmChannelUsers,
 	chunk.CSearchMessages: asmSearchMessages,
 	chunk.CSearchFiles:    asmSearchFiles,
+	chunk.CEmojis:         asmEmojis,
 }
 
 var (
@@ -59,6 +60,7 @@ var (
 	rpChanUser = repository.NewChannelUserRepository()
 	rpSrchMsg  = repository.NewSearchMessageRepository()
 	rpSrchFile = repository.NewSearchFileRepository()
+	rpEmoji    = repository.NewEmojiRepository()
 )
 
 func asmMessages(ctx context.Context, conn sqlx.ExtContext, dbchunk *repository.DBChunk) (*chunk.Chunk, error) {
@@ -278,3 +280,22 @@ func asmSearchFiles(ctx context.Context, conn sqlx.ExtContext, dbchunk *reposito
 	}
 	return c, nil
 }
+
+func asmEmojis(ctx context.Context, conn sqlx.ExtContext, dbchunk *repository.DBChunk) (*chunk.Chunk, error) {
+	it, err := rpEmoji.AllForChunk(ctx, conn, dbchunk.ID)
+	if err != nil {
+		return nil, err
+	}
+	c := dbchunk.Chunk()
+	for de, err := range it {
+		if err != nil {
+			return nil, err
+		}
+		em, err := de.Val()
+		if err != nil {
+			return nil, err
+		}
+		c.Emojis = append(c.Emojis, em)
+	}
+	return c, nil
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/rusq/slackdump/v4/internal/fixtures"
 	"github.com/rusq/slackdump/v4/internal/structures"
 	"github.com/rusq/slackdump/v4/internal/testutil"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 var (
@@ -994,3 +995,73 @@ func Test_asmSearchFiles(t *testing.T) {
 		})
 	}
 }
+
+func Test_asmEmojis(t *testing.T) {
+	ee := []types.Emoji{
+		{Name: "partyparrot", URL: "https://example.com/partyparrot.gif"},
+		{Name: "parrot", URL: "alias:partyparrot", IsAlias: 1, AliasFor: "partyparrot"},
+	}
+	tests := []struct {
+		name     string
+		dbchunk  *repository.DBChunk
+		expectFn func(m *mock_repository.MockEmojiRepository)
+		want     *chunk.Chunk
+		wantErr  bool
+	}{
+		{
+			name: "ok",
+			dbchunk: &repository.DBChunk{
+				ID:         1,
+				TypeID:     chunk.CEmojis,
+				UnixTS:     1234567890,
+				NumRecords: int32(len(ee)),
+			},
+			expectFn: func(m *mock_repository.MockEmojiRepository) {
+				it := testutil.Slice2Seq2([]repository.DBEmoji{
+					{Name: ee[0].Name, ChunkID: new(int64(1)), URL: ee[0].URL, Data: testutil.MarshalJSON(t, ee[0])},
+					{Name: ee[1].Name, ChunkID: new(int64(1)), URL: ee[1].URL, IsAlias: true, Data: testutil.MarshalJSON(t, ee[1])},
+				})
+				m.EXPECT().AllForChunk(gomock.Any(), gomock.Any(), int64(1)).Return(it, nil)
+			},
+			want: &chunk.Chunk{
+				Type:      chunk.CEmojis,
+				Timestamp: 1234567890,
+				Count:     int32(len(ee)),
+				Emojis:    ee,
+			},
+		},
+		{
+			name: "repository error",
+			dbchunk: &repository.DBChunk{
+				ID:     1,
+				TypeID: chunk.CEmojis,
+			},
+			expectFn: func(m *mock_repository.MockEmojiRepository) {
+				m.EXPECT().AllForChunk(gomock.Any(), gomock.Any(), int64(1)).Return(nil, assert.AnError)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := rpEmoji
+			t.Cleanup(func() {
+				rpEmoji = old
+			})
+			ctrl := gomock.NewController(t)
+			rme := mock_repository.NewMockEmojiRepository(ctrl)
+			rpEmoji = rme
+			if tt.expectFn != nil {
+				tt.expectFn(rme)
+			}
+			got, err := asmEmojis(t.Context(), nil, tt.dbchunk)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("asmEmojis() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("asmEmojis() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
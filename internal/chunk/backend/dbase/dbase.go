@@ -28,6 +28,7 @@ import (
 
 	"github.com/rusq/slackdump/v4/internal/chunk"
 	"github.com/rusq/slackdump/v4/internal/chunk/backend/dbase/repository"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 // DBP is the database processor.
@@ -67,6 +68,9 @@ var dbInitCommands = []string{
 type options struct {
 	onlyNewOrChangedUsers bool
 	verbose               bool
+	// emojiFilenameFn returns the path of the emoji image, if emoji images
+	// are downloaded.
+	emojiFilenameFn func(*types.Emoji) string
 }
 
 func (o *options) apply(opts ...Option) {
@@ -89,6 +93,15 @@ func WithOnlyNewOrChangedUsers(v bool) Option {
 	}
 }
 
+// WithEmojiFilenameFunc sets the function that returns the path to the
+// downloaded emoji image, relative to the archive root.  It should be set if
+// the emoji images are downloaded along with the emoji chunks.
+func WithEmojiFilenameFunc(fn func(*types.Emoji) string) Option {
+	return func(o *options) {
+		o.emojiFilenameFn = fn
+	}
+}
+
 // New return the new database processor.
 func New(ctx context.Context, conn *sqlx.DB, p SessionInfo, opts ...Option) (*DBP, error) {
 	var options options
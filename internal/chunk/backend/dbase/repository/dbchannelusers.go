@@ -56,10 +56,21 @@ func (c DBChannelUser) values() []any {
 	return []any{c.ChannelID, c.UserID, c.ChunkID, c.Index}
 }
 
+// DBChannelUserSnapshot is a channel member recorded by one of the sessions.
+type DBChannelUserSnapshot struct {
+	SessionID int64  `db:"SESSION_ID"`
+	ChunkID   int64  `db:"CHUNK_ID"`
+	UnixTS    int64  `db:"UNIX_TS"`
+	UserID    string `db:"USER_ID"`
+}
+
 //go:generate mockgen -destination=mock_repository/mock_chan_user.go . ChannelUserRepository
 type ChannelUserRepository interface {
 	BulkRepository[DBChannelUser]
 	GetByChannelID(ctx context.Context, db sqlx.QueryerContext, channelID string) (iter.Seq2[DBChannelUser, error], error)
+	// Snapshots returns the channel members recorded by each of the
+	// sessions, ordered by chunk and user ID.
+	Snapshots(ctx context.Context, db sqlx.QueryerContext, channelID string) (iter.Seq2[DBChannelUserSnapshot, error], error)
 }
 
 func NewChannelUserRepository() ChannelUserRepository {
@@ -78,3 +89,20 @@ func (r channelUserRepository) GetByChannelID(ctx context.Context, db sqlx.Query
 	}
 	return r.allOfTypeWhere(ctx, db, qp, chunk.CChannelUsers)
 }
+
+const stmtChannelUserSnapshots = `WITH SNAPSHOT AS (
+    SELECT CH.SESSION_ID, MAX(CU.CHUNK_ID) AS CHUNK_ID
+    FROM CHANNEL_USER CU
+    JOIN CHUNK CH ON CH.ID = CU.CHUNK_ID
+    WHERE CU.CHANNEL_ID = ? AND CH.TYPE_ID = ?
+    GROUP BY CH.SESSION_ID
+)
+SELECT SN.SESSION_ID, SN.CHUNK_ID, CH.UNIX_TS, CU.USER_ID
+FROM SNAPSHOT SN
+JOIN CHUNK CH ON CH.ID = SN.CHUNK_ID
+JOIN CHANNEL_USER CU ON CU.CHUNK_ID = SN.CHUNK_ID AND CU.CHANNEL_ID = ?
+ORDER BY SN.CHUNK_ID, CU.USER_ID`
+
+func (r channelUserRepository) Snapshots(ctx context.Context, db sqlx.QueryerContext, channelID string) (iter.Seq2[DBChannelUserSnapshot, error], error) {
+	return query[DBChannelUserSnapshot](ctx, db, rebind(db, stmtChannelUserSnapshots), channelID, chunk.CChannelUsers, channelID)
+}
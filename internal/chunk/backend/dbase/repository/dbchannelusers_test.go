@@ -192,3 +192,44 @@ func Test_channelUserRepository_GetByChannelID(t *testing.T) {
 		})
 	}
 }
+
+func Test_channelUserRepository_Snapshots(t *testing.T) {
+	conn := testConn(t)
+	ctx := t.Context()
+	var (
+		sr  = NewSessionRepository()
+		cr  = NewChunkRepository()
+		cur = NewChannelUserRepository()
+	)
+	for _, id := range []int64{1, 2} {
+		if _, err := sr.Insert(ctx, conn, &Session{ID: id}); err != nil {
+			t.Fatalf("session insert: %v", err)
+		}
+	}
+	// session 1 records the members twice, only the latest is the snapshot.
+	members := [][]string{{"UAAA"}, {"UAAA", "UBBB"}, {"UBBB", "UCCC"}}
+	for i, sessionID := range []int64{1, 1, 2} {
+		chunkID, err := cr.Insert(ctx, conn, &DBChunk{SessionID: sessionID, UnixTS: int64(i + 1), TypeID: chunk.CChannelUsers})
+		if err != nil {
+			t.Fatalf("chunk insert: %v", err)
+		}
+		for n, userID := range members[i] {
+			cu, _ := NewDBChannelUser(chunkID, n, "C111", userID)
+			if err := cur.Insert(ctx, conn, cu); err != nil {
+				t.Fatalf("channel user insert: %v", err)
+			}
+		}
+	}
+
+	got, err := cur.Snapshots(ctx, conn, "C111")
+	if err != nil {
+		t.Fatalf("Snapshots() error = %v", err)
+	}
+	want := []testutil.TestResult[DBChannelUserSnapshot]{
+		{V: DBChannelUserSnapshot{SessionID: 1, ChunkID: 2, UnixTS: 2, UserID: "UAAA"}},
+		{V: DBChannelUserSnapshot{SessionID: 1, ChunkID: 2, UnixTS: 2, UserID: "UBBB"}},
+		{V: DBChannelUserSnapshot{SessionID: 2, ChunkID: 3, UnixTS: 3, UserID: "UBBB"}},
+		{V: DBChannelUserSnapshot{SessionID: 2, ChunkID: 3, UnixTS: 3, UserID: "UCCC"}},
+	}
+	testutil.AssertIterResult(t, want, got)
+}
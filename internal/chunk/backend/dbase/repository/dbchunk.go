@@ -26,6 +26,7 @@ import (
 	"github.com/rusq/slack"
 
 	"github.com/rusq/slackdump/v4/internal/chunk"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 // DBChunk is the database representation of the Chunk.
@@ -76,6 +77,8 @@ func (c DBChunk) Chunk() *chunk.Chunk {
 		cc.SearchMessages = make([]slack.SearchMessage, 0, c.NumRecords)
 	case chunk.CSearchFiles:
 		cc.SearchFiles = make([]slack.File, 0, c.NumRecords)
+	case chunk.CEmojis:
+		cc.Emojis = make([]types.Emoji, 0, c.NumRecords)
 	}
 	return &cc
 }
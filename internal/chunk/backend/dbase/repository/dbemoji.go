@@ -0,0 +1,186 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/rusq/slackdump/v4/types"
+)
+
+// DBEmoji is a custom workspace emoji.
+type DBEmoji struct {
+	Name     string  `db:"NAME"`
+	ChunkID  *int64  `db:"CHUNK_ID"`
+	URL      string  `db:"URL"`
+	IsAlias  bool    `db:"IS_ALIAS"`
+	AliasFor *string `db:"ALIAS_FOR"`
+	TeamID   *string `db:"TEAM_ID"`
+	UserID   *string `db:"USER_ID"`
+	Created  *int64  `db:"CREATED"`
+	Filename *string `db:"FILENAME"`
+	Data     []byte  `db:"DATA"`
+}
+
+// NewDBEmoji creates a new DBEmoji from the emoji em.  chunkID may be zero,
+// if the emoji was not recorded as a part of a chunk.  filename is the path
+// to the downloaded emoji image, relative to the archive root, it should be
+// empty if the image was not downloaded.
+func NewDBEmoji(chunkID int64, em *types.Emoji, filename string) (*DBEmoji, error) {
+	data, err := marshal(em)
+	if err != nil {
+		return nil, err
+	}
+	return &DBEmoji{
+		Name:     em.Name,
+		ChunkID:  orNull(chunkID > 0, chunkID),
+		URL:      em.URL,
+		IsAlias:  em.IsAlias != 0,
+		AliasFor: orNull(em.AliasFor != "", em.AliasFor),
+		TeamID:   orNull(em.TeamID != "", em.TeamID),
+		UserID:   orNull(em.UserID != "", em.UserID),
+		Created:  orNull(em.Created > 0, em.Created),
+		Filename: orNull(filename != "", filename),
+		Data:     data,
+	}, nil
+}
+
+func (DBEmoji) tablename() string {
+	return "EMOJI"
+}
+
+func (DBEmoji) userkey() []string {
+	return slice("NAME")
+}
+
+func (DBEmoji) columns() []string {
+	return []string{
+		"NAME",
+		"CHUNK_ID",
+		"URL",
+		"IS_ALIAS",
+		"ALIAS_FOR",
+		"TEAM_ID",
+		"USER_ID",
+		"CREATED",
+		"FILENAME",
+		"DATA",
+	}
+}
+
+func (e DBEmoji) values() []any {
+	return []any{
+		e.Name,
+		e.ChunkID,
+		e.URL,
+		e.IsAlias,
+		e.AliasFor,
+		e.TeamID,
+		e.UserID,
+		e.Created,
+		e.Filename,
+		e.Data,
+	}
+}
+
+func (e DBEmoji) Val() (types.Emoji, error) {
+	return unmarshalt[types.Emoji](e.Data)
+}
+
+// EmojiRepository provides access to the custom emoji.  Unlike the chunk
+// bound entities, there is only one row per emoji name, and inserting the
+// emoji with the same name replaces the existing one.
+//
+//go:generate mockgen -destination=mock_repository/mock_emoji.go . EmojiRepository
+type EmojiRepository interface {
+	Chunker[DBEmoji]
+	// Upsert inserts or replaces all emoji from the iterator.
+	Upsert(ctx context.Context, pconn PrepareExtContext, ee iter.Seq2[*DBEmoji, error]) (int, error)
+	// Get returns the emoji with the given name.
+	Get(ctx context.Context, conn sqlx.QueryerContext, name string) (DBEmoji, error)
+	// All returns all emoji ordered by name.
+	All(ctx context.Context, conn sqlx.QueryerContext) (iter.Seq2[DBEmoji, error], error)
+	// Count returns the number of emoji.
+	Count(ctx context.Context, conn sqlx.QueryerContext) (int64, error)
+}
+
+type emojiRepository struct {
+	genericRepository[DBEmoji]
+}
+
+func NewEmojiRepository() EmojiRepository {
+	return emojiRepository{newGenericRepository(DBEmoji{})}
+}
+
+func (r emojiRepository) stmtUpsert() string {
+	return r.stmtInsert() + `
+ON CONFLICT(NAME) DO UPDATE SET
+	CHUNK_ID = excluded.CHUNK_ID,
+	LOAD_DTTM = CURRENT_TIMESTAMP,
+	URL = excluded.URL,
+	IS_ALIAS = excluded.IS_ALIAS,
+	ALIAS_FOR = excluded.ALIAS_FOR,
+	TEAM_ID = excluded.TEAM_ID,
+	USER_ID = excluded.USER_ID,
+	CREATED = excluded.CREATED,
+	FILENAME = COALESCE(excluded.FILENAME, EMOJI.FILENAME),
+	DATA = excluded.DATA`
+}
+
+func (r emojiRepository) Upsert(ctx context.Context, pconn PrepareExtContext, ee iter.Seq2[*DBEmoji, error]) (int, error) {
+	stmt, err := pconn.PrepareContext(ctx, pconn.Rebind(r.stmtUpsert()))
+	if err != nil {
+		return 0, fmt.Errorf("upsert: prepare: %w", err)
+	}
+	defer stmt.Close()
+	var total int
+	for e, err := range ee {
+		if err != nil {
+			return total, fmt.Errorf("upsert: iterator: %w", err)
+		}
+		if _, err := stmt.ExecContext(ctx, e.values()...); err != nil {
+			return total, fmt.Errorf("upsert %s (NAME=%s): %w", e.tablename(), e.Name, err)
+		}
+		total++
+	}
+	return total, nil
+}
+
+func (r emojiRepository) Get(ctx context.Context, conn sqlx.QueryerContext, name string) (DBEmoji, error) {
+	var e DBEmoji
+	stmt := rebind(conn, "SELECT "+colAlias("", r.t.columns()...)+" FROM EMOJI WHERE NAME = ?")
+	if err := sqlx.GetContext(ctx, conn, &e, stmt, name); err != nil {
+		return DBEmoji{}, err
+	}
+	return e, nil
+}
+
+func (r emojiRepository) All(ctx context.Context, conn sqlx.QueryerContext) (iter.Seq2[DBEmoji, error], error) {
+	stmt := "SELECT " + colAlias("", r.t.columns()...) + " FROM EMOJI ORDER BY NAME"
+	return query[DBEmoji](ctx, conn, stmt)
+}
+
+func (r emojiRepository) Count(ctx context.Context, conn sqlx.QueryerContext) (int64, error) {
+	var n int64
+	if err := conn.QueryRowxContext(ctx, "SELECT COUNT(1) FROM EMOJI").Scan(&n); err != nil {
+		return 0, fmt.Errorf("count: %w", err)
+	}
+	return n, nil
+}
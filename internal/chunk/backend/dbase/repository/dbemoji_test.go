@@ -0,0 +1,92 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package repository
+
+import (
+	"database/sql"
+	"iter"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusq/slackdump/v4/types"
+)
+
+func emojiIter(ee ...*DBEmoji) iter.Seq2[*DBEmoji, error] {
+	return func(yield func(*DBEmoji, error) bool) {
+		for _, e := range ee {
+			if !yield(e, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestNewDBEmoji(t *testing.T) {
+	em := &types.Emoji{Name: "parrot", URL: "alias:party-parrot", IsAlias: 1, AliasFor: "party-parrot", Created: 1670466722}
+	got, err := NewDBEmoji(0, em, "")
+	require.NoError(t, err)
+	assert.Equal(t, "parrot", got.Name)
+	assert.Nil(t, got.ChunkID)
+	assert.True(t, got.IsAlias)
+	assert.Equal(t, "party-parrot", *got.AliasFor)
+	assert.Equal(t, int64(1670466722), *got.Created)
+	assert.Nil(t, got.Filename)
+	v, err := got.Val()
+	require.NoError(t, err)
+	assert.Equal(t, *em, v)
+}
+
+func Test_emojiRepository_Upsert(t *testing.T) {
+	conn := testConn(t)
+	r := NewEmojiRepository()
+
+	e1, err := NewDBEmoji(0, &types.Emoji{Name: "a", URL: "https://example.com/a.png"}, "emojis/a.png")
+	require.NoError(t, err)
+	e2, err := NewDBEmoji(0, &types.Emoji{Name: "b", URL: "https://example.com/b.png"}, "")
+	require.NoError(t, err)
+	n, err := r.Upsert(t.Context(), conn, emojiIter(e1, e2))
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// update of the emoji without the filename keeps the existing one.
+	e1u, err := NewDBEmoji(0, &types.Emoji{Name: "a", URL: "https://example.com/a2.png"}, "")
+	require.NoError(t, err)
+	_, err = r.Upsert(t.Context(), conn, emojiIter(e1u))
+	require.NoError(t, err)
+
+	got, err := r.Get(t.Context(), conn, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/a2.png", got.URL)
+	assert.Equal(t, "emojis/a.png", *got.Filename)
+
+	cnt, err := r.Count(t.Context(), conn)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), cnt)
+
+	it, err := r.All(t.Context(), conn)
+	require.NoError(t, err)
+	var names []string
+	for e, err := range it {
+		require.NoError(t, err)
+		names = append(names, e.Name)
+	}
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	_, err = r.Get(t.Context(), conn, "missing")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -35,8 +35,10 @@ var (
 	_ dbObject = DBChannel{}
 	_ dbObject = DBChannelUser{}
 	_ dbObject = DBChunk{}
+	_ dbObject = DBEmoji{}
 	_ dbObject = DBFile{}
 	_ dbObject = DBMessage{}
+	_ dbObject = DBReaction{}
 	_ dbObject = DBUser{}
 	_ dbObject = DBSearchFile{}
 	_ dbObject = DBSearchMessage{}
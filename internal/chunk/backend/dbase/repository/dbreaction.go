@@ -0,0 +1,139 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/internal/fasttime"
+)
+
+// DBReaction is a single user reaction on a message.
+type DBReaction struct {
+	ChannelID string `db:"CHANNEL_ID"`
+	MessageID int64  `db:"MESSAGE_ID"`
+	ChunkID   int64  `db:"CHUNK_ID"`
+	Name      string `db:"NAME"`
+	UserID    string `db:"USER_ID"`
+	Index     int    `db:"IDX"`
+}
+
+// NewDBReactions returns the reaction rows for the message msg, one row per
+// user per reaction.
+func NewDBReactions(chunkID int64, channelID string, msg *slack.Message) ([]*DBReaction, error) {
+	if len(msg.Reactions) == 0 {
+		return nil, nil
+	}
+	id, err := fasttime.TS2int(msg.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("reactions fasttime: %w", err)
+	}
+	var rr []*DBReaction
+	for i, r := range msg.Reactions {
+		for _, u := range r.Users {
+			rr = append(rr, &DBReaction{
+				ChannelID: channelID,
+				MessageID: id,
+				ChunkID:   chunkID,
+				Name:      r.Name,
+				UserID:    u,
+				Index:     i,
+			})
+		}
+	}
+	return rr, nil
+}
+
+func (DBReaction) tablename() string {
+	return "REACTION"
+}
+
+func (DBReaction) userkey() []string {
+	return slice("CHANNEL_ID", "MESSAGE_ID", "NAME", "USER_ID")
+}
+
+func (DBReaction) columns() []string {
+	return []string{"CHANNEL_ID", "MESSAGE_ID", "CHUNK_ID", "NAME", "USER_ID", "IDX"}
+}
+
+func (r DBReaction) values() []any {
+	return []any{r.ChannelID, r.MessageID, r.ChunkID, r.Name, r.UserID, r.Index}
+}
+
+// ReactionCount is the number of times the reaction was used.
+type ReactionCount struct {
+	Name  string `db:"NAME"`
+	Count int64  `db:"CNT"`
+}
+
+//go:generate mockgen -destination=mock_repository/mock_reaction.go . ReactionRepository
+type ReactionRepository interface {
+	Inserter[DBReaction]
+	Chunker[DBReaction]
+	// AllForMessage returns reactions of the latest version of the message.
+	AllForMessage(ctx context.Context, conn sqlx.QueryerContext, channelID, ts string) (iter.Seq2[DBReaction, error], error)
+	// Top returns the reaction usage counts in descending order, limited to
+	// limit rows.  If channelID is empty, counts are returned for all
+	// channels.
+	Top(ctx context.Context, conn sqlx.QueryerContext, channelID string, limit int) ([]ReactionCount, error)
+}
+
+func NewReactionRepository() ReactionRepository {
+	return reactionRepository{newGenericRepository(DBReaction{})}
+}
+
+type reactionRepository struct {
+	genericRepository[DBReaction]
+}
+
+func (r reactionRepository) AllForMessage(ctx context.Context, conn sqlx.QueryerContext, channelID, ts string) (iter.Seq2[DBReaction, error], error) {
+	id, err := fasttime.TS2int(ts)
+	if err != nil {
+		return nil, fmt.Errorf("all for message: %w", err)
+	}
+	const stmt = `
+SELECT R.CHANNEL_ID, R.MESSAGE_ID, R.CHUNK_ID, R.NAME, R.USER_ID, R.IDX
+FROM REACTION R
+WHERE R.CHANNEL_ID = ?
+  AND R.MESSAGE_ID = ?
+  AND R.CHUNK_ID = (SELECT MAX(M.CHUNK_ID) FROM MESSAGE M WHERE M.CHANNEL_ID = ? AND M.ID = ?)
+ORDER BY R.IDX, R.USER_ID`
+	return query[DBReaction](ctx, conn, rebind(conn, stmt), channelID, id, channelID, id)
+}
+
+func (r reactionRepository) Top(ctx context.Context, conn sqlx.QueryerContext, channelID string, limit int) ([]ReactionCount, error) {
+	stmt := "SELECT NAME, COUNT(1) AS CNT FROM V_REACTION WHERE 1=1"
+	var binds []any
+	if channelID != "" {
+		stmt += " AND CHANNEL_ID = ?"
+		binds = append(binds, channelID)
+	}
+	stmt += " GROUP BY NAME ORDER BY CNT DESC, NAME"
+	if limit > 0 {
+		stmt += " LIMIT ?"
+		binds = append(binds, limit)
+	}
+	var rc []ReactionCount
+	if err := sqlx.SelectContext(ctx, conn, &rc, rebind(conn, stmt), binds...); err != nil {
+		return nil, fmt.Errorf("top: %w", err)
+	}
+	return rc, nil
+}
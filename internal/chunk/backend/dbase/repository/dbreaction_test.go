@@ -0,0 +1,121 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/rusq/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusq/slackdump/v4/internal/chunk"
+	"github.com/rusq/slackdump/v4/internal/testutil"
+)
+
+func TestNewDBReactions(t *testing.T) {
+	t.Run("no reactions", func(t *testing.T) {
+		got, err := NewDBReactions(1, "C100", &slack.Message{Msg: slack.Msg{Timestamp: "1725318212.603879"}})
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+	t.Run("one row per user", func(t *testing.T) {
+		msg := &slack.Message{Msg: slack.Msg{
+			Timestamp: "1725318212.603879",
+			Reactions: []slack.ItemReaction{
+				{Name: "thumbsup", Count: 2, Users: []string{"U1", "U2"}},
+				{Name: "eyes", Count: 1, Users: []string{"U3"}},
+			},
+		}}
+		got, err := NewDBReactions(1, "C100", msg)
+		require.NoError(t, err)
+		want := []*DBReaction{
+			{ChannelID: "C100", MessageID: 1725318212603879, ChunkID: 1, Name: "thumbsup", UserID: "U1", Index: 0},
+			{ChannelID: "C100", MessageID: 1725318212603879, ChunkID: 1, Name: "thumbsup", UserID: "U2", Index: 0},
+			{ChannelID: "C100", MessageID: 1725318212603879, ChunkID: 1, Name: "eyes", UserID: "U3", Index: 1},
+		}
+		assert.Equal(t, want, got)
+	})
+	t.Run("invalid timestamp", func(t *testing.T) {
+		msg := &slack.Message{Msg: slack.Msg{
+			Timestamp: "x",
+			Reactions: []slack.ItemReaction{{Name: "eyes", Count: 1, Users: []string{"U3"}}},
+		}}
+		_, err := NewDBReactions(1, "C100", msg)
+		assert.Error(t, err)
+	})
+}
+
+func prepReactions(t *testing.T, conn PrepareExtContext) {
+	t.Helper()
+	prepChunk(chunk.CMessages, chunk.CMessages)(t, conn)
+	msg := slack.Message{Msg: slack.Msg{
+		Timestamp: "1725318212.603879",
+		Reactions: []slack.ItemReaction{
+			{Name: "thumbsup", Count: 2, Users: []string{"U1", "U2"}},
+			{Name: "eyes", Count: 1, Users: []string{"U3"}},
+		},
+	}}
+	mr := NewMessageRepository()
+	rr := NewReactionRepository()
+	for chunkID := int64(1); chunkID <= 2; chunkID++ {
+		dbm, err := NewDBMessage(chunkID, 0, "C100", &msg)
+		require.NoError(t, err)
+		require.NoError(t, mr.Insert(t.Context(), conn, dbm))
+		reactions, err := NewDBReactions(chunkID, "C100", &msg)
+		require.NoError(t, err)
+		require.NoError(t, rr.Insert(t.Context(), conn, reactions...))
+		// the later version of the message loses the "eyes" reaction.
+		msg.Reactions = msg.Reactions[:1]
+	}
+}
+
+func Test_reactionRepository_AllForMessage(t *testing.T) {
+	conn := testConn(t)
+	prepReactions(t, conn)
+
+	r := NewReactionRepository()
+	got, err := r.AllForMessage(t.Context(), conn, "C100", "1725318212.603879")
+	require.NoError(t, err)
+	want := []testutil.TestResult[DBReaction]{
+		{V: DBReaction{ChannelID: "C100", MessageID: 1725318212603879, ChunkID: 2, Name: "thumbsup", UserID: "U1", Index: 0}},
+		{V: DBReaction{ChannelID: "C100", MessageID: 1725318212603879, ChunkID: 2, Name: "thumbsup", UserID: "U2", Index: 0}},
+	}
+	testutil.AssertIterResult(t, want, got)
+}
+
+func Test_reactionRepository_Top(t *testing.T) {
+	conn := testConn(t)
+	prepReactions(t, conn)
+
+	r := NewReactionRepository()
+	got, err := r.Top(t.Context(), conn, "C100", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []ReactionCount{{Name: "thumbsup", Count: 2}}, got)
+
+	got, err = r.Top(t.Context(), conn, "C999", 10)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func Test_reactionRepository_cascade(t *testing.T) {
+	conn := testConn(t)
+	prepReactions(t, conn)
+
+	_, err := conn.ExecContext(t.Context(), "DELETE FROM MESSAGE WHERE CHUNK_ID = 2")
+	require.NoError(t, err)
+	checkCount("REACTION", 3)(t, conn)
+}
@@ -16,8 +16,13 @@
 package repository
 
 import (
+	"context"
+	"iter"
+
+	"github.com/jmoiron/sqlx"
 	"github.com/rusq/slack"
 
+	"github.com/rusq/slackdump/v4/internal/chunk"
 	"github.com/rusq/slackdump/v4/internal/structures"
 )
 
@@ -69,11 +74,48 @@ func (u DBUser) Val() (slack.User, error) {
 	return unmarshalt[slack.User](u.Data)
 }
 
+// DBUserSnapshot is the latest state of the user recorded by a session.
+type DBUserSnapshot struct {
+	SessionID int64  `db:"SESSION_ID"`
+	UnixTS    int64  `db:"UNIX_TS"`
+	Data      []byte `db:"DATA"`
+}
+
+func (s DBUserSnapshot) Val() (slack.User, error) {
+	return unmarshalt[slack.User](s.Data)
+}
+
 //go:generate mockgen -destination=mock_repository/mock_user.go . UserRepository
 type UserRepository interface {
 	BulkRepository[DBUser]
+	// History returns the latest state of the user in each of the sessions,
+	// ordered by the session.
+	History(ctx context.Context, conn sqlx.QueryerContext, userID string) (iter.Seq2[DBUserSnapshot, error], error)
+}
+
+var _ UserRepository = userRepository{}
+
+type userRepository struct {
+	genericRepository[DBUser]
 }
 
 func NewUserRepository() UserRepository {
-	return newGenericRepository(DBUser{})
+	return userRepository{newGenericRepository(DBUser{})}
+}
+
+const stmtUserHistory = `WITH SNAPSHOT AS (
+    SELECT CH.SESSION_ID, MAX(U.CHUNK_ID) AS CHUNK_ID
+    FROM S_USER U
+    JOIN CHUNK CH ON CH.ID = U.CHUNK_ID
+    WHERE U.ID = ? AND CH.TYPE_ID = ?
+    GROUP BY CH.SESSION_ID
+)
+SELECT SN.SESSION_ID, CH.UNIX_TS, U.DATA
+FROM SNAPSHOT SN
+JOIN S_USER U ON U.ID = ? AND U.CHUNK_ID = SN.CHUNK_ID
+JOIN CHUNK CH ON CH.ID = SN.CHUNK_ID
+ORDER BY SN.CHUNK_ID`
+
+func (r userRepository) History(ctx context.Context, conn sqlx.QueryerContext, userID string) (iter.Seq2[DBUserSnapshot, error], error) {
+	return query[DBUserSnapshot](ctx, conn, rebind(conn, stmtUserHistory), userID, chunk.CUsers, userID)
 }
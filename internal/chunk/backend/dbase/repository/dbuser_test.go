@@ -20,6 +20,10 @@ import (
 	"testing"
 
 	"github.com/rusq/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusq/slackdump/v4/internal/chunk"
 )
 
 var user1 = &slack.User{
@@ -85,3 +89,60 @@ func TestNewDBUser(t *testing.T) {
 		})
 	}
 }
+
+// prepUserHistory records user1 in three chunks of two sessions, the title
+// changes in each chunk.
+func prepUserHistory(t *testing.T, conn PrepareExtContext) {
+	t.Helper()
+	ctx := t.Context()
+	var (
+		sr = NewSessionRepository()
+		cr = NewChunkRepository()
+		ur = NewUserRepository()
+	)
+	for _, id := range []int64{1, 2} {
+		_, err := sr.Insert(ctx, conn, &Session{ID: id})
+		require.NoError(t, err)
+	}
+	for i, sessionID := range []int64{1, 1, 2} {
+		chunkID, err := cr.Insert(ctx, conn, &DBChunk{
+			SessionID: sessionID,
+			UnixTS:    int64(i + 1),
+			TypeID:    chunk.CUsers,
+		})
+		require.NoError(t, err)
+		u := *user1
+		u.Profile.Title = []string{"intern", "engineer", "manager"}[i]
+		dbu, err := NewDBUser(chunkID, 0, &u)
+		require.NoError(t, err)
+		require.NoError(t, ur.Insert(ctx, conn, dbu))
+	}
+}
+
+func Test_userRepository_History(t *testing.T) {
+	conn := testConn(t)
+	prepUserHistory(t, conn)
+
+	ur := NewUserRepository()
+	it, err := ur.History(t.Context(), conn, "U123")
+	require.NoError(t, err)
+	var (
+		sessions []int64
+		titles   []string
+	)
+	for s, err := range it {
+		require.NoError(t, err)
+		u, err := s.Val()
+		require.NoError(t, err)
+		sessions = append(sessions, s.SessionID)
+		titles = append(titles, u.Profile.Title)
+	}
+	assert.Equal(t, []int64{1, 2}, sessions)
+	assert.Equal(t, []string{"engineer", "manager"}, titles)
+
+	it, err = ur.History(t.Context(), conn, "U999")
+	require.NoError(t, err)
+	for range it {
+		t.Fatal("unexpected snapshot for unknown user")
+	}
+}
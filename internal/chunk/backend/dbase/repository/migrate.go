@@ -44,3 +44,22 @@ func Migrate(ctx context.Context, db *sql.DB, verbose bool) error {
 	}
 	return nil
 }
+
+// UpToDate reports whether all migrations were applied to the database db.
+// Unlike [Migrate], it does not modify the database, given that it has the
+// version table.
+func UpToDate(ctx context.Context, db *sql.DB) (bool, error) {
+	mm, err := goose.CollectMigrations("migrations", 0, goose.MaxVersion)
+	if err != nil {
+		return false, err
+	}
+	last, err := mm.Last()
+	if err != nil {
+		return false, err
+	}
+	v, err := goose.GetDBVersionContext(ctx, db)
+	if err != nil {
+		return false, fmt.Errorf("database version: %w", err)
+	}
+	return v >= last.Version, nil
+}
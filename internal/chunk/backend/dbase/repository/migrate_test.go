@@ -146,7 +146,7 @@ func TestMigrate(t *testing.T) {
 		}
 	})
 
-	t.Run("down removes size column", func(t *testing.T) {
+	t.Run("backfills reactions from messages", func(t *testing.T) {
 		ctx := context.Background()
 		db, err := sql.Open(Driver, ":memory:")
 		if err != nil {
@@ -154,16 +154,48 @@ func TestMigrate(t *testing.T) {
 		}
 		defer db.Close()
 
+		const beforeReactionMigration = int64(20260328043948)
+		if err := goose.UpToContext(ctx, db, "migrations", beforeReactionMigration); err != nil {
+			t.Fatalf("goose.UpToContext() err = %v; want nil", err)
+		}
+		if _, err := db.ExecContext(ctx, `INSERT INTO SESSION (ID, MODE) VALUES (1, 'archive')`); err != nil {
+			t.Fatalf("insert session: %v", err)
+		}
+		if _, err := db.ExecContext(ctx, `INSERT INTO CHUNK (ID, UNIX_TS, SESSION_ID, TYPE_ID, NUM_REC) VALUES (1, 0, 1, 0, 1)`); err != nil {
+			t.Fatalf("insert chunk: %v", err)
+		}
+		if _, err := db.ExecContext(ctx, `
+			INSERT INTO MESSAGE (ID, CHUNK_ID, CHANNEL_ID, TS, IDX, DATA)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, int64(1700000000000001), 1, "C123", "1700000000.000001", 0, []byte(`{"type":"message","ts":"1700000000.000001","reactions":[{"name":"thumbsup","count":2,"users":["U1","U2"]},{"name":"eyes","count":1,"users":["U2"]}]}`)); err != nil {
+			t.Fatalf("insert message: %v", err)
+		}
+
 		if err := Migrate(ctx, db, true); err != nil {
 			t.Fatalf("Migrate() err = %v; want nil", err)
 		}
 
-		if err := goose.DownContext(ctx, db, "migrations"); err != nil {
-			t.Fatalf("first goose.DownContext() err = %v; want nil", err)
+		rr := NewReactionRepository()
+		top, err := rr.Top(ctx, sqlx.NewDb(db, Driver), "", 0)
+		require.NoError(t, err)
+		require.Equal(t, []ReactionCount{{Name: "thumbsup", Count: 2}, {Name: "eyes", Count: 1}}, top)
+	})
+
+	t.Run("down removes size column", func(t *testing.T) {
+		ctx := context.Background()
+		db, err := sql.Open(Driver, ":memory:")
+		if err != nil {
+			t.Fatalf("sql.Open() err = %v; want nil", err)
 		}
+		defer db.Close()
 
-		if err := goose.DownContext(ctx, db, "migrations"); err != nil {
-			t.Fatalf("goose.DownContext() err = %v; want nil", err)
+		if err := Migrate(ctx, db, true); err != nil {
+			t.Fatalf("Migrate() err = %v; want nil", err)
+		}
+
+		const beforeFileSizeMigration = int64(20260307000000)
+		if err := goose.DownToContext(ctx, db, "migrations", beforeFileSizeMigration); err != nil {
+			t.Fatalf("goose.DownToContext() err = %v; want nil", err)
 		}
 
 		var count int
@@ -175,3 +207,21 @@ func TestMigrate(t *testing.T) {
 		}
 	})
 }
+
+func TestUpToDate(t *testing.T) {
+	ctx := t.Context()
+	db, err := sql.Open(Driver, ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	const initialMigration = int64(20250207082949)
+	require.NoError(t, goose.UpToContext(ctx, db, "migrations", initialMigration))
+	ok, err := UpToDate(ctx, db)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, Migrate(ctx, db, false))
+	ok, err = UpToDate(ctx, db)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
@@ -0,0 +1,84 @@
+-- +goose Up
+-- +goose StatementBegin
+-- REACTION IS A NORMALISED VIEW OF THE MESSAGE REACTIONS, ONE ROW PER USER
+-- PER REACTION.  ROWS FOLLOW THE MESSAGE ROW THEY WERE EXTRACTED FROM.
+CREATE TABLE IF NOT EXISTS REACTION
+(
+    CHANNEL_ID TEXT      NOT NULL,
+    MESSAGE_ID INTEGER   NOT NULL, -- MESSAGE ID (TIMESTAMP IN NUMERIC FORM)
+    CHUNK_ID   INTEGER   NOT NULL,
+    LOAD_DTTM  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    NAME       TEXT      NOT NULL, -- EMOJI NAME, I.E. 'thumbsup' OR 'party-parrot'
+    USER_ID    TEXT      NOT NULL,
+    IDX        INTEGER   NOT NULL, -- INDEX OF THE REACTION WITHIN THE MESSAGE
+    PRIMARY KEY (CHANNEL_ID, MESSAGE_ID, CHUNK_ID, NAME, USER_ID),
+    FOREIGN KEY (MESSAGE_ID, CHUNK_ID) REFERENCES MESSAGE (ID, CHUNK_ID) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS REACTION_CHUNK_ID_IDX ON REACTION (CHUNK_ID);
+CREATE INDEX IF NOT EXISTS REACTION_I1 ON REACTION (NAME);
+CREATE INDEX IF NOT EXISTS REACTION_I2 ON REACTION (USER_ID);
+
+-- EMOJI CONTAINS THE CUSTOM WORKSPACE EMOJI.  THERE IS ONLY ONE ROW PER EMOJI
+-- NAME, LATER LOADS REPLACE EARLIER ONES.
+CREATE TABLE IF NOT EXISTS EMOJI
+(
+    NAME      TEXT      NOT NULL PRIMARY KEY,
+    CHUNK_ID  INTEGER,                      -- CHUNK THAT RECORDED THE EMOJI, IF ANY
+    LOAD_DTTM TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    URL       TEXT      NOT NULL,
+    IS_ALIAS  SMALLINT  NOT NULL DEFAULT FALSE,
+    ALIAS_FOR TEXT,                         -- TARGET EMOJI NAME FOR ALIASES
+    TEAM_ID   TEXT,
+    USER_ID   TEXT,                         -- USER WHO HAS UPLOADED THE EMOJI
+    CREATED   INTEGER,                      -- UNIX TIMESTAMP OF CREATION
+    FILENAME  TEXT,                         -- RELATIVE PATH TO THE DOWNLOADED IMAGE
+    DATA      BLOB      NOT NULL,
+    FOREIGN KEY (CHUNK_ID) REFERENCES CHUNK (ID) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS EMOJI_CHUNK_ID_IDX ON EMOJI (CHUNK_ID);
+
+-- BACKFILL REACTIONS FROM THE MESSAGES THAT ARE ALREADY IN THE DATABASE.
+INSERT OR IGNORE INTO REACTION (CHANNEL_ID, MESSAGE_ID, CHUNK_ID, NAME, USER_ID, IDX)
+SELECT M.CHANNEL_ID
+     , M.ID
+     , M.CHUNK_ID
+     , JSON_EXTRACT(R.VALUE, '$.name')
+     , U.VALUE
+     , R.KEY
+FROM MESSAGE M
+         JOIN JSON_EACH(CAST(M.DATA AS TEXT), '$.reactions') R
+         JOIN JSON_EACH(R.VALUE, '$.users') U
+WHERE JSON_EXTRACT(R.VALUE, '$.name') IS NOT NULL;
+
+-- V_REACTION CONTAINS REACTIONS OF THE LATEST VERSION OF EACH MESSAGE, AND
+-- IS THE RECOMMENDED STARTING POINT FOR REACTION ANALYTICS.
+CREATE VIEW IF NOT EXISTS V_REACTION AS
+WITH LATEST AS (SELECT CHANNEL_ID, ID, MAX(CHUNK_ID) AS CHUNK_ID
+                FROM MESSAGE
+                GROUP BY CHANNEL_ID, ID)
+SELECT R.CHANNEL_ID
+     , R.MESSAGE_ID
+     , M.TS
+     , M.THREAD_TS
+     , R.NAME
+     , R.USER_ID
+     , CASE WHEN E.NAME IS NULL THEN FALSE ELSE TRUE END AS IS_CUSTOM
+FROM REACTION R
+         JOIN LATEST L
+              ON L.CHANNEL_ID = R.CHANNEL_ID
+                  AND L.ID = R.MESSAGE_ID
+                  AND L.CHUNK_ID = R.CHUNK_ID
+         JOIN MESSAGE M ON M.ID = R.MESSAGE_ID AND M.CHUNK_ID = R.CHUNK_ID
+         LEFT JOIN EMOJI E ON E.NAME = R.NAME;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP VIEW IF EXISTS V_REACTION;
+DROP INDEX IF EXISTS EMOJI_CHUNK_ID_IDX;
+DROP TABLE IF EXISTS EMOJI;
+DROP INDEX IF EXISTS REACTION_I2;
+DROP INDEX IF EXISTS REACTION_I1;
+DROP INDEX IF EXISTS REACTION_CHUNK_ID_IDX;
+DROP TABLE IF EXISTS REACTION;
+-- +goose StatementEnd
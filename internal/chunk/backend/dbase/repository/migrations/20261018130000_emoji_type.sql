@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO TYPES (ID, NAME) VALUES (12, 'EMOJIS');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM TYPES WHERE ID = 12;
+-- +goose StatementEnd
@@ -227,3 +227,18 @@ func (mr *MockChannelUserRepositoryMockRecorder) OneForChunk(ctx, conn, chunkID
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OneForChunk", reflect.TypeOf((*MockChannelUserRepository)(nil).OneForChunk), ctx, conn, chunkID)
 }
+
+// Snapshots mocks base method.
+func (m *MockChannelUserRepository) Snapshots(ctx context.Context, db sqlx.QueryerContext, channelID string) (iter.Seq2[repository.DBChannelUserSnapshot, error], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshots", ctx, db, channelID)
+	ret0, _ := ret[0].(iter.Seq2[repository.DBChannelUserSnapshot, error])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshots indicates an expected call of Snapshots.
+func (mr *MockChannelUserRepositoryMockRecorder) Snapshots(ctx, db, channelID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshots", reflect.TypeOf((*MockChannelUserRepository)(nil).Snapshots), ctx, db, channelID)
+}
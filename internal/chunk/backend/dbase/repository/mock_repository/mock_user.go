@@ -164,6 +164,21 @@ func (mr *MockUserRepositoryMockRecorder) GetType(ctx, conn, id any, ct ...any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetType", reflect.TypeOf((*MockUserRepository)(nil).GetType), varargs...)
 }
 
+// History mocks base method.
+func (m *MockUserRepository) History(ctx context.Context, conn sqlx.QueryerContext, userID string) (iter.Seq2[repository.DBUserSnapshot, error], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, conn, userID)
+	ret0, _ := ret[0].(iter.Seq2[repository.DBUserSnapshot, error])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockUserRepositoryMockRecorder) History(ctx, conn, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockUserRepository)(nil).History), ctx, conn, userID)
+}
+
 // Insert mocks base method.
 func (m *MockUserRepository) Insert(ctx context.Context, conn sqlx.ExtContext, t ...*repository.DBUser) error {
 	m.ctrl.T.Helper()
@@ -20,8 +20,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"iter"
 	"log/slog"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime/trace"
@@ -30,12 +33,15 @@ import (
 	"github.com/rusq/slackdump/v4/internal/chunk/backend/dbase/repository"
 
 	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+	"modernc.org/sqlite/vfs"
 
 	"github.com/rusq/slack"
 
 	"github.com/rusq/slackdump/v4/internal/chunk"
 	"github.com/rusq/slackdump/v4/internal/fasttime"
 	"github.com/rusq/slackdump/v4/internal/structures"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 const preallocSz = 100 // preallocate slice size
@@ -49,6 +55,10 @@ type Source struct {
 	// canClose set to false when the connection is passed to the source
 	// and should not be closed by the source.
 	canClose bool
+	// res is closed after the connection, it is the virtual filesystem or
+	// the connection that keeps the in-memory database, if the database was
+	// opened with [OpenFS].
+	res io.Closer
 }
 
 // ErrIsDirectory is returned when a directory path is passed instead of
@@ -102,6 +112,91 @@ func Open(ctx context.Context, path string) (*Source, error) {
 	return &Source{conn: conn, canClose: true}, nil
 }
 
+// OpenFS opens the database file name in fsys for reading.  SQLite reads the
+// database through the read-only virtual filesystem, that allows to read the
+// database from filesystems that are not backed by the disk, i.e. the one
+// that decrypts the sealed archive as it is read.  The database can not be
+// migrated in place, so if the schema is outdated, it is copied to memory and
+// migrated there.
+func OpenFS(ctx context.Context, fsys fs.FS, name string) (*Source, error) {
+	vfsName, vfsys, err := vfs.New(fsys)
+	if err != nil {
+		return nil, err
+	}
+	// immutable disables locking and the WAL lookup, which need the
+	// writable filesystem.
+	q := url.Values{"vfs": {vfsName}, "mode": {"ro"}, "immutable": {"1"}}
+	dsn := "file:" + name + "?" + q.Encode()
+	conn, err := sqlx.Open(repository.Driver, dsn)
+	if err != nil {
+		return nil, errors.Join(err, vfsys.Close())
+	}
+	if err := conn.PingContext(ctx); err != nil {
+		return nil, errors.Join(err, conn.Close(), vfsys.Close())
+	}
+	ok, err := repository.UpToDate(ctx, conn.DB)
+	if err != nil {
+		return nil, errors.Join(err, conn.Close(), vfsys.Close())
+	}
+	if ok {
+		return &Source{conn: conn, canClose: true, res: vfsys}, nil
+	}
+	if err := conn.Close(); err != nil {
+		return nil, errors.Join(err, vfsys.Close())
+	}
+	defer vfsys.Close()
+	return openMemory(ctx, dsn, vfsName)
+}
+
+// openMemory copies the database src to the shared in-memory database and
+// migrates it.  The in-memory database exists while at least one connection
+// to it is open, so one connection is kept until the source is closed.
+func openMemory(ctx context.Context, src string, name string) (*Source, error) {
+	conn, err := sqlx.Open(repository.Driver, "file:"+name+"-mem?mode=memory&cache=shared")
+	if err != nil {
+		return nil, err
+	}
+	keep, err := conn.Conn(ctx)
+	if err != nil {
+		return nil, errors.Join(err, conn.Close())
+	}
+	closeAll := func(err error) error {
+		return errors.Join(err, conn.Close(), keep.Close())
+	}
+	if err := keep.Raw(func(dc any) error { return restore(dc, src) }); err != nil {
+		return nil, closeAll(fmt.Errorf("copy to memory: %w", err))
+	}
+	if err := repository.Migrate(ctx, conn.DB, false); err != nil {
+		return nil, closeAll(err)
+	}
+	return &Source{conn: conn, canClose: true, res: keep}, nil
+}
+
+// restore copies the database src to the database of the driver connection
+// dc using the SQLite online backup.
+func restore(dc any, src string) error {
+	r, ok := dc.(interface {
+		NewRestore(string) (*sqlite.Backup, error)
+	})
+	if !ok {
+		return fmt.Errorf("unsupported driver connection: %T", dc)
+	}
+	b, err := r.NewRestore(src)
+	if err != nil {
+		return err
+	}
+	for {
+		more, err := b.Step(-1)
+		if err != nil {
+			return errors.Join(err, b.Finish())
+		}
+		if !more {
+			break
+		}
+	}
+	return b.Finish()
+}
+
 // OpenRW attempts to open the database at given path for reading and writing.
 // Use [Open] when only read access is needed.
 func OpenRW(ctx context.Context, path string) (*RWSource, error) {
@@ -169,6 +264,9 @@ func (s *Source) Close() error {
 		slog.Error("error closing database connection", "error", err)
 		return err
 	}
+	if s.res != nil {
+		return s.res.Close()
+	}
 	return nil
 }
 
@@ -238,6 +336,59 @@ func (s *Source) Users(ctx context.Context) ([]slack.User, error) {
 	return collect(it, preallocSz)
 }
 
+// UserSnapshots returns the state of the user recorded by each of the
+// sessions, ordered chronologically.
+func (s *Source) UserSnapshots(ctx context.Context, userID string) ([]types.UserSnapshot, error) {
+	ur := repository.NewUserRepository()
+
+	it, err := ur.History(ctx, s.conn, userID)
+	if err != nil {
+		return nil, err
+	}
+	var ss []types.UserSnapshot
+	for ds, err := range it {
+		if err != nil {
+			return nil, err
+		}
+		u, err := ds.Val()
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, types.UserSnapshot{
+			SessionID: ds.SessionID,
+			Recorded:  time.Unix(0, ds.UnixTS),
+			User:      u,
+		})
+	}
+	return ss, nil
+}
+
+// MemberSnapshots returns the channel members recorded by each of the
+// sessions, ordered chronologically.
+func (s *Source) MemberSnapshots(ctx context.Context, channelID string) ([]types.MemberSnapshot, error) {
+	cur := repository.NewChannelUserRepository()
+
+	it, err := cur.Snapshots(ctx, s.conn, channelID)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		ss      []types.MemberSnapshot
+		chunkID int64
+	)
+	for cs, err := range it {
+		if err != nil {
+			return nil, err
+		}
+		if len(ss) == 0 || cs.ChunkID != chunkID {
+			chunkID = cs.ChunkID
+			ss = append(ss, types.MemberSnapshot{Recorded: time.Unix(0, cs.UnixTS)})
+		}
+		ss[len(ss)-1].Members = append(ss[len(ss)-1].Members, cs.UserID)
+	}
+	return ss, nil
+}
+
 type valuer[T any] interface {
 	Val() (T, error)
 }
@@ -366,6 +517,27 @@ func (s *Source) Aliases() (map[string]string, error) {
 	return mm, nil
 }
 
+// Emojis returns all custom emoji ordered by name.
+func (s *Source) Emojis(ctx context.Context) ([]types.Emoji, error) {
+	er := repository.NewEmojiRepository()
+	it, err := er.All(ctx, s.conn)
+	if err != nil {
+		return nil, err
+	}
+	var ee []types.Emoji
+	for de, err := range it {
+		if err != nil {
+			return nil, err
+		}
+		em, err := de.Val()
+		if err != nil {
+			return nil, err
+		}
+		ee = append(ee, em)
+	}
+	return ee, nil
+}
+
 func (s *Source) Latest(ctx context.Context) (map[structures.SlackLink]time.Time, error) {
 	ctx, task := trace.NewTask(ctx, "Latest")
 	defer task.End()
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
 	"github.com/rusq/slack"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -116,6 +117,49 @@ func TestOpen(t *testing.T) {
 	}
 }
 
+func TestOpenFS(t *testing.T) {
+	dir := t.TempDir()
+	t.Run("reads the database", func(t *testing.T) {
+		dbfile := filepath.Join(dir, "current.db")
+		require.NoError(t, migrate(t.Context(), dbfile))
+		src, err := OpenFS(t.Context(), os.DirFS(dir), "current.db")
+		require.NoError(t, err)
+		defer src.Close()
+		_, err = src.Channels(t.Context())
+		assert.NoError(t, err)
+		// the database is read-only.
+		_, err = src.conn.ExecContext(t.Context(), "DELETE FROM SESSION")
+		assert.Error(t, err)
+	})
+	t.Run("outdated schema is migrated in memory", func(t *testing.T) {
+		dbfile := filepath.Join(dir, "outdated.db")
+		db, err := sql.Open(repository.Driver, dbfile)
+		require.NoError(t, err)
+		require.NoError(t, goose.UpToContext(t.Context(), db, "migrations", 20250301091541))
+		require.NoError(t, db.Close())
+		before, err := os.ReadFile(dbfile)
+		require.NoError(t, err)
+
+		src, err := OpenFS(t.Context(), os.DirFS(dir), "outdated.db")
+		require.NoError(t, err)
+		ok, err := repository.UpToDate(t.Context(), src.conn.DB)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		_, err = src.Channels(t.Context())
+		assert.NoError(t, err)
+		require.NoError(t, src.Close())
+
+		after, err := os.ReadFile(dbfile)
+		require.NoError(t, err)
+		assert.Equal(t, before, after, "database file was modified")
+	})
+	t.Run("not a database", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "garbage.db"), []byte("not a database"), 0o644))
+		_, err := OpenFS(t.Context(), os.DirFS(dir), "garbage.db")
+		assert.Error(t, err)
+	})
+}
+
 func Test_validateDBPath(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("permission-denied stat behavior differs on windows")
@@ -28,6 +28,7 @@ import (
 	"github.com/rusq/slack"
 
 	"github.com/rusq/slackdump/v4/internal/chunk"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 // InsertChunk inserts a chunk into the database.
@@ -123,12 +124,14 @@ func (d *DBP) insertPayload(ctx context.Context, tx repository.PrepareExtContext
 		return d.insertSearchMessages(ctx, tx, dbchunkID, c.SearchQuery, c.SearchMessages)
 	case chunk.CSearchFiles:
 		return d.insertSearchFiles(ctx, tx, dbchunkID, c.SearchQuery, c.SearchFiles)
+	case chunk.CEmojis:
+		return upsertEmojis(ctx, tx, dbchunkID, c.Emojis, d.opts.emojiFilenameFn)
 	default:
 		return 0, fmt.Errorf("insertpayload: unknown chunk type %v", c.Type)
 	}
 }
 
-func (*DBP) insertMessages(ctx context.Context, tx repository.PrepareExtContext, dbchunkID int64, channelID string, mm []slack.Message) (int, error) {
+func (d *DBP) insertMessages(ctx context.Context, tx repository.PrepareExtContext, dbchunkID int64, channelID string, mm []slack.Message) (int, error) {
 	if len(mm) == 0 {
 		return 0, nil
 	}
@@ -140,7 +143,35 @@ func (*DBP) insertMessages(ctx context.Context, tx repository.PrepareExtContext,
 			}
 		}
 	}
-	return mr.InsertAll(ctx, tx, iterfn)
+	n, err := mr.InsertAll(ctx, tx, iterfn)
+	if err != nil {
+		return n, err
+	}
+	if _, err := d.insertReactions(ctx, tx, dbchunkID, channelID, mm); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// insertReactions inserts the reactions of messages mm.  It must be called
+// after the messages are inserted, as reactions reference message rows.
+func (*DBP) insertReactions(ctx context.Context, tx repository.PrepareExtContext, dbchunkID int64, channelID string, mm []slack.Message) (int, error) {
+	rr := repository.NewReactionRepository()
+	iterfn := func(yield func(*repository.DBReaction, error) bool) {
+		for _, msg := range mm {
+			reactions, err := repository.NewDBReactions(dbchunkID, channelID, &msg)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, r := range reactions {
+				if !yield(r, nil) {
+					return
+				}
+			}
+		}
+	}
+	return rr.InsertAll(ctx, tx, iterfn)
 }
 
 func (*DBP) insertFiles(ctx context.Context, tx repository.PrepareExtContext, dbchunkID int64, channelID, threadTS, parMsgTS string, ff []slack.File) (int, error) {
@@ -292,3 +323,45 @@ func (*DBP) insertSearchFiles(ctx context.Context, tx repository.PrepareExtConte
 	}
 	return fr.InsertAll(ctx, tx, iterfn)
 }
+
+// InsertEmojis inserts or updates the custom emoji in the database.  The
+// emoji are not bound to the session chunks.  filenameFn should return the
+// path of the downloaded emoji image relative to the archive root, or an
+// empty string, if the image was not downloaded; it may be nil.
+func (d *DBP) InsertEmojis(ctx context.Context, emojis []types.Emoji, filenameFn func(*types.Emoji) string) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	txx, err := d.conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("insertemojis: begin: %w", err)
+	}
+	defer txx.Rollback()
+
+	n, err := upsertEmojis(ctx, txx, 0, emojis, filenameFn)
+	if err != nil {
+		return 0, fmt.Errorf("insertemojis: %w", err)
+	}
+	if err := txx.Commit(); err != nil {
+		return 0, fmt.Errorf("insertemojis: commit: %w", err)
+	}
+	return n, nil
+}
+
+func upsertEmojis(ctx context.Context, tx repository.PrepareExtContext, dbchunkID int64, emojis []types.Emoji, filenameFn func(*types.Emoji) string) (int, error) {
+	if len(emojis) == 0 {
+		return 0, nil
+	}
+	if filenameFn == nil {
+		filenameFn = func(*types.Emoji) string { return "" }
+	}
+	er := repository.NewEmojiRepository()
+	iterfn := func(yield func(*repository.DBEmoji, error) bool) {
+		for _, em := range emojis {
+			if !yield(repository.NewDBEmoji(dbchunkID, &em, filenameFn(&em))) {
+				return
+			}
+		}
+	}
+	return er.Upsert(ctx, tx, iterfn)
+}
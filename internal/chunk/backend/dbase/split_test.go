@@ -31,6 +31,7 @@ import (
 	"github.com/rusq/slackdump/v4/internal/chunk/backend/dbase/repository/mock_repository"
 	"github.com/rusq/slackdump/v4/internal/fixtures"
 	"github.com/rusq/slackdump/v4/internal/testutil"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 type utilityFunc func(t *testing.T, ec repository.PrepareExtContext)
@@ -278,6 +279,99 @@ func TestDBP_insertMessages(t *testing.T) {
 	}
 }
 
+func TestDBP_insertMessages_reactions(t *testing.T) {
+	db := testDB(t)
+	prepChunk(chunk.CMessages, chunk.CMessages)(t, db)
+	d := &DBP{conn: db, sessionID: 1, mr: repository.NewMessageRepository()}
+
+	mm := []slack.Message{
+		{Msg: slack.Msg{Timestamp: "123.456", Text: "hello", Reactions: []slack.ItemReaction{
+			{Name: "thumbsup", Count: 2, Users: []string{"U1", "U2"}},
+			{Name: "party-parrot", Count: 1, Users: []string{"U1"}},
+		}}},
+		{Msg: slack.Msg{Timestamp: "123.457", Text: "world"}},
+	}
+	n, err := d.insertMessages(t.Context(), db, 1, "C123", mm)
+	if err != nil {
+		t.Fatalf("insertMessages() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("insertMessages() = %d, want 2", n)
+	}
+	var count int
+	if err := db.QueryRowxContext(t.Context(), "SELECT COUNT(*) FROM REACTION WHERE CHUNK_ID = 1").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("reaction count = %d, want 3", count)
+	}
+
+	// the later version of the message has one reaction removed.
+	mm[0].Reactions = mm[0].Reactions[:1]
+	if _, err := d.insertMessages(t.Context(), db, 2, "C123", mm[:1]); err != nil {
+		t.Fatalf("insertMessages() error = %v", err)
+	}
+	rr := repository.NewReactionRepository()
+	top, err := rr.Top(t.Context(), db, "C123", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []repository.ReactionCount{{Name: "thumbsup", Count: 2}}
+	if !reflect.DeepEqual(top, want) {
+		t.Errorf("Top() = %v, want %v", top, want)
+	}
+}
+
+func TestDBP_InsertEmojis(t *testing.T) {
+	db := testDB(t)
+	d := &DBP{conn: db, sessionID: 1}
+
+	emojis := []types.Emoji{
+		{Name: "party-parrot", URL: "https://emoji.slack-edge.com/T1/party-parrot/abc.gif", UserID: "U1"},
+		{Name: "parrot", URL: "alias:party-parrot", IsAlias: 1, AliasFor: "party-parrot"},
+	}
+	filenameFn := func(em *types.Emoji) string {
+		if em.IsAlias != 0 {
+			return ""
+		}
+		return "emojis/" + em.Name + ".gif"
+	}
+	n, err := d.InsertEmojis(t.Context(), emojis, filenameFn)
+	if err != nil {
+		t.Fatalf("InsertEmojis() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("InsertEmojis() = %d, want 2", n)
+	}
+	// repeated insert updates existing emoji, and keeps the filename.
+	if _, err := d.InsertEmojis(t.Context(), emojis[:1], nil); err != nil {
+		t.Fatalf("InsertEmojis() error = %v", err)
+	}
+
+	er := repository.NewEmojiRepository()
+	cnt, err := er.Count(t.Context(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cnt != 2 {
+		t.Errorf("Count() = %d, want 2", cnt)
+	}
+	got, err := er.Get(t.Context(), db, "party-parrot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Filename == nil || *got.Filename != "emojis/party-parrot.gif" {
+		t.Errorf("Filename = %v, want emojis/party-parrot.gif", got.Filename)
+	}
+	alias, err := er.Get(t.Context(), db, "parrot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !alias.IsAlias || alias.AliasFor == nil || *alias.AliasFor != "party-parrot" {
+		t.Errorf("alias = %+v, want alias for party-parrot", alias)
+	}
+}
+
 func TestDBP_InsertChunk(t *testing.T) {
 	TestDBP_UnsafeInsertChunk(t)
 }
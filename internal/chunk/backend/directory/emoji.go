@@ -0,0 +1,32 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package directory
+
+import "github.com/rusq/slackdump/v4/internal/chunk"
+
+// Emojis is a processor that writes the custom emoji into the emoji file.
+type Emojis struct {
+	*dirproc
+}
+
+// NewEmojis creates a new emoji processor.
+func NewEmojis(cd *chunk.Directory) (*Emojis, error) {
+	p, err := newDirProc(cd, chunk.FEmoji)
+	if err != nil {
+		return nil, err
+	}
+	return &Emojis{dirproc: p}, nil
+}
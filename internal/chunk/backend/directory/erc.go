@@ -38,6 +38,7 @@ type ERC struct {
 	u    *Users
 	c    *Channels
 	s    *Search
+	em   *Emojis
 }
 
 type open struct {
@@ -46,6 +47,7 @@ type open struct {
 	u  sync.Once
 	c  sync.Once
 	s  sync.Once
+	em sync.Once
 }
 
 func NewERC(cd *chunk.Directory, lg *slog.Logger) *ERC {
@@ -84,6 +86,10 @@ func (e *ERC) ensure(c *chunk.Chunk) (err error) {
 		e.once.s.Do(func() {
 			e.s, err = NewSearch(e.cd, &processor.NopFiler{})
 		})
+	case chunk.CEmojis:
+		e.once.em.Do(func() {
+			e.em, err = NewEmojis(e.cd)
+		})
 	}
 	return nil
 }
@@ -120,6 +126,8 @@ func (e *ERC) writePayload(ctx context.Context, c *chunk.Chunk) error {
 		return e.s.SearchMessages(ctx, c.SearchQuery, c.SearchMessages)
 	case chunk.CSearchFiles:
 		return e.s.SearchFiles(ctx, c.SearchQuery, c.SearchFiles)
+	case chunk.CEmojis:
+		return e.em.Emojis(ctx, c.Emojis)
 	default:
 		return fmt.Errorf("writePayload: unknown chunk type %v", c.Type)
 	}
@@ -156,5 +164,8 @@ func (e *ERC) Close() error {
 	if e.s != nil {
 		errs = errors.Join(errs, e.s.Close())
 	}
+	if e.em != nil {
+		errs = errors.Join(errs, e.em.Close())
+	}
 	return errs
 }
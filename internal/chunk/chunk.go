@@ -23,6 +23,7 @@ import (
 	"github.com/rusq/slack"
 
 	"github.com/rusq/slackdump/v4/internal/fasttime"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 // ChunkType is the type of chunk that was recorded..
@@ -42,6 +43,7 @@ const (
 	CBookmarks
 	CSearchMessages
 	CSearchFiles
+	CEmojis
 )
 
 var ErrUnsupChunkType = fmt.Errorf("unsupported chunk type")
@@ -111,6 +113,8 @@ type Chunk struct {
 	SearchMessages []slack.SearchMessage `json:"sm,omitempty"` // Populated by SearchMessages
 	// SearchFiles contains the search results.
 	SearchFiles []slack.File `json:"sf,omitempty"` // Populated by SearchFiles
+	// Emojis contains the custom workspace emoji.
+	Emojis []types.Emoji `json:"em,omitempty"` // Populated by Emojis
 }
 
 // GroupID is a unique ID for a chunk group.  It is used to group chunks of
@@ -125,6 +129,7 @@ const (
 	wspInfoChunkID  GroupID = "iw"   // info workspace
 	srchMsgChunkID  GroupID = "sm"   // search messages results
 	srchFileChunkID GroupID = "sf"   // search file results
+	emojiChunkID    GroupID = "lem"  // list emoji
 )
 
 const (
@@ -170,6 +175,8 @@ func (c *Chunk) ID() GroupID {
 		return srchMsgChunkID
 	case CSearchFiles:
 		return srchFileChunkID
+	case CEmojis:
+		return emojiChunkID // static
 	}
 	return GroupID(fmt.Sprintf("<unknown:%s>", c.Type))
 }
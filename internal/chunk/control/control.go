@@ -47,6 +47,7 @@ func New(ctx context.Context, s Streamer, erc EncodeReferenceFinisher, opts ...O
 			tf:    &noopExpTransformer{},
 			filer: &processor.NopFiler{},
 			avp:   &processor.NopAvatars{},
+			emp:   &processor.NopEmojis{},
 		},
 	}
 	for _, opt := range opts {
@@ -73,7 +74,7 @@ func (c *Controller) Run(ctx context.Context, list *structures.EntityList) error
 
 	// got to do some explanation here: the order of processors is important:
 	// files ==> recorder ==> transformer                     2     1            3
-	conv := processor.AppendMessenger(processor.PrependFiler(rec, c.filer), c.newConvTransformer(ctx))
+	conv := processor.AppendMessenger(c.withLinks(processor.PrependFiler(rec, c.filer)), c.newConvTransformer(ctx))
 	streamer, proc := c.mkSuperprocessor(ctx, rec, conv)
 
 	return runWorkers(ctx, streamer, list, proc, c.flags)
@@ -85,7 +86,7 @@ func (c *Controller) RunNoTransform(ctx context.Context, list *structures.Entity
 	rec := chunk.NewCustomRecorder(c.erc)
 	defer rec.Close()
 
-	conv := processor.PrependFiler(rec, c.filer)
+	conv := c.withLinks(processor.PrependFiler(rec, c.filer))
 	streamer, proc := c.mkSuperprocessor(ctx, rec, conv)
 
 	// if we don't need to transform the data, we can just run the workers
@@ -126,6 +127,7 @@ func (c *Controller) mkSuperprocessor(ctx context.Context, rec *chunk.Recorder,
 		Users:         processor.JoinUsers(c.newUserCollector(ctx, c.flags.ChannelUsers), c.avp, rec),
 		Channels:      rec,
 		WorkspaceInfo: rec,
+		Emojis:        processor.JoinEmojis(c.emp, rec),
 	}
 
 	return streamer, sp
@@ -178,6 +180,14 @@ func (c *Controller) closeResources() error {
 			errs = errors.Join(errs, fmt.Errorf("error closing avatar processor: %w", err))
 		}
 	}
+	if c.emp != nil {
+		if err := c.emp.Close(); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("error closing emoji processor: %w", err))
+		}
+	}
+	if err := c.closeLinks(); err != nil {
+		errs = errors.Join(errs, err)
+	}
 	// TODO: Decide if it is necessary to close the encoder here or leave it
 	// for the caller.  Maybe make it conditional?
 	return errs
@@ -420,6 +420,7 @@ func TestNew(t *testing.T) {
 					tf:    &noopExpTransformer{},
 					filer: &noopFiler{},
 					avp:   &noopAvatarProc{},
+					emp:   &noopEmojiProc{},
 				},
 			},
 			wantErr: false,
@@ -443,6 +444,7 @@ func TestNew(t *testing.T) {
 					tf:    &noopExpTransformer{},
 					filer: &mock_processor.MockFiler{},
 					avp:   &mock_processor.MockAvatars{},
+					emp:   &noopEmojiProc{},
 				},
 			},
 			wantErr: false,
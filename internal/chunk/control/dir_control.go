@@ -59,6 +59,7 @@ func NewDir(cd *chunk.Directory, s Streamer, opts ...Option) *DirController {
 			tf:    &noopExpTransformer{},
 			filer: &noopFiler{},
 			avp:   &noopAvatarProc{},
+			emp:   &noopEmojiProc{},
 		},
 	}
 	for _, opt := range opts {
@@ -97,11 +98,21 @@ func (c *DirController) Run(ctx context.Context, list *structures.EntityList) er
 		c.avp,
 	)
 
+	var emproc processor.Emojis = c.emp
+	if c.flags.Emojis {
+		dem, err := dirproc.NewEmojis(c.cd)
+		if err != nil {
+			return Error{"emoji", "init", err}
+		}
+		emproc = processor.JoinEmojis(c.emp, dem)
+	}
+
 	mp := superprocessor{
 		Channels:      dcp,
 		WorkspaceInfo: dwsp,
 		Users:         userproc,
-		Conversations: dconv,
+		Conversations: c.withLinks(dconv),
+		Emojis:        emproc,
 	}
 
 	return runWorkers(ctx, c.s, list, mp, c.flags)
@@ -115,6 +126,14 @@ func (c *DirController) Close() error {
 			errs = errors.Join(errs, fmt.Errorf("error closing avatar processor: %w", err))
 		}
 	}
+	if c.emp != nil {
+		if err := c.emp.Close(); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("error closing emoji processor: %w", err))
+		}
+	}
+	if err := c.closeLinks(); err != nil {
+		errs = errors.Join(errs, err)
+	}
 	if c.filer != nil {
 		if err := c.filer.Close(); err != nil {
 			errs = errors.Join(errs, fmt.Errorf("error closing file processor: %w", err))
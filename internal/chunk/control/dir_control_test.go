@@ -68,6 +68,7 @@ func TestDirController_Run(t *testing.T) {
 					tf:    &noopExpTransformer{},
 					filer: &noopFiler{},
 					avp:   &noopAvatarProc{},
+					emp:   &noopEmojiProc{},
 				},
 			},
 			args: args{
@@ -96,6 +97,7 @@ func TestDirController_Run(t *testing.T) {
 					tf:    &noopExpTransformer{},
 					filer: &noopFiler{},
 					avp:   &noopAvatarProc{},
+					emp:   &noopEmojiProc{},
 				},
 			},
 			args: args{
@@ -174,6 +176,7 @@ func TestNewDir(t *testing.T) {
 					tf:    &noopExpTransformer{},
 					filer: &noopFiler{},
 					avp:   &noopAvatarProc{},
+					emp:   &noopEmojiProc{},
 				},
 			},
 		},
@@ -195,6 +198,7 @@ func TestNewDir(t *testing.T) {
 					tf:    &noopExpTransformer{},
 					filer: &mock_processor.MockFiler{},
 					avp:   &mock_processor.MockAvatars{},
+					emp:   &noopEmojiProc{},
 				},
 			},
 		},
@@ -41,6 +41,8 @@ type Streamer interface {
 	UsersBulk(ctx context.Context, proc processor.Users, ids ...string) error
 	UsersBulkWithCustom(ctx context.Context, proc processor.Users, includeLabels bool, ids ...string) error
 	UsersBulkWithCustomErr(ctx context.Context, proc processor.Users, includeLabels bool, ids []string, failErr func(error) bool) error
+	Emojis(ctx context.Context, proc processor.Emojis) error
+	Canvases(ctx context.Context, proc processor.Conversations) error
 }
 
 type TransformStarter interface {
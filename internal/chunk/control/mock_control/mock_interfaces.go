@@ -44,6 +44,20 @@ func (m *MockStreamer) EXPECT() *MockStreamerMockRecorder {
 	return m.recorder
 }
 
+// Canvases mocks base method.
+func (m *MockStreamer) Canvases(ctx context.Context, proc processor.Conversations) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Canvases", ctx, proc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Canvases indicates an expected call of Canvases.
+func (mr *MockStreamerMockRecorder) Canvases(ctx, proc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Canvases", reflect.TypeOf((*MockStreamer)(nil).Canvases), ctx, proc)
+}
+
 // Conversations mocks base method.
 func (m *MockStreamer) Conversations(ctx context.Context, proc processor.Conversations, links <-chan structures.EntityItem) error {
 	m.ctrl.T.Helper()
@@ -58,6 +72,20 @@ func (mr *MockStreamerMockRecorder) Conversations(ctx, proc, links any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Conversations", reflect.TypeOf((*MockStreamer)(nil).Conversations), ctx, proc, links)
 }
 
+// Emojis mocks base method.
+func (m *MockStreamer) Emojis(ctx context.Context, proc processor.Emojis) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Emojis", ctx, proc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Emojis indicates an expected call of Emojis.
+func (mr *MockStreamerMockRecorder) Emojis(ctx, proc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Emojis", reflect.TypeOf((*MockStreamer)(nil).Emojis), ctx, proc)
+}
+
 // ListChannels mocks base method.
 func (m *MockStreamer) ListChannels(ctx context.Context, proc processor.Channels, p *slack.GetConversationsParameters) error {
 	m.ctrl.T.Helper()
@@ -29,6 +29,7 @@ import (
 type (
 	noopFiler      = processor.NopFiler
 	noopAvatarProc = processor.NopAvatars
+	noopEmojiProc  = processor.NopEmojis
 )
 
 type noopExpTransformer struct{}
@@ -17,6 +17,7 @@ package control
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"github.com/rusq/slack"
@@ -37,6 +38,12 @@ type options struct {
 	// avp is avatar downloader (subprocessor), if not configured with options,
 	// it's a noop, as it's not necessary
 	avp processor.Avatars
+	// emp is emoji image downloader (subprocessor), if not configured with
+	// options, it's a noop.
+	emp processor.EmojiDownloader
+	// lnf is the external link fetcher (subprocessor), it is nil, if not
+	// configured with options.
+	lnf processor.LinkFetcher
 	// lg is the logger
 	lg *slog.Logger
 	// flags
@@ -57,6 +64,21 @@ func WithAvatarProcessor(avp processor.Avatars) Option {
 	}
 }
 
+// WithEmojiProcessor configures the controller with an emoji image
+// downloader.
+func WithEmojiProcessor(emp processor.EmojiDownloader) Option {
+	return func(c *options) {
+		c.emp = emp
+	}
+}
+
+// WithLinkFetcher configures the controller with an external link fetcher.
+func WithLinkFetcher(lnf processor.LinkFetcher) Option {
+	return func(c *options) {
+		c.lnf = lnf
+	}
+}
+
 // WithFlags configures the controller with flags.
 func WithFlags(f Flags) Option {
 	return func(c *options) {
@@ -84,6 +106,26 @@ func WithLogger(lg *slog.Logger) Option {
 
 // helpers
 
+// withLinks appends the link fetcher to the conversation processor conv, if
+// it is configured.
+func (o *options) withLinks(conv processor.Conversations) processor.Conversations {
+	if o.lnf == nil {
+		return conv
+	}
+	return processor.AppendMessenger(conv, o.lnf)
+}
+
+// closeLinks closes the link fetcher, if it is configured.
+func (o *options) closeLinks() error {
+	if o.lnf == nil {
+		return nil
+	}
+	if err := o.lnf.Close(); err != nil {
+		return fmt.Errorf("error closing link fetcher: %w", err)
+	}
+	return nil
+}
+
 // newUserCollector creates a new user collector.
 func (o *options) newUserCollector(ctx context.Context, allowNoUsers bool) *userCollector {
 	return &userCollector{
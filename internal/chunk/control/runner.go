@@ -53,8 +53,18 @@ type Flags struct {
 	// IncludeLabels requests API to include the labels for the custom fields.
 	// works only with ChannelUsers. Server may throttle requests hard.
 	IncludeLabels bool
+	// Emojis is the flag to fetch the custom workspace emoji.
+	Emojis bool
+	// Canvases is the flag to discover and fetch all canvases that the user
+	// has access to, including standalone canvases.
+	Canvases bool
 }
 
+// ErrTokenInvalidated is returned by the controller if the Slack API stops
+// accepting the token during the run.  The data received up to this point is
+// intact, and the run can be continued after re-authenticating.
+var ErrTokenInvalidated = errors.New("token is no longer valid")
+
 // Error is a controller error.
 type Error struct {
 	// Subroutine is the name of the subroutine that failed.
@@ -89,6 +99,7 @@ type superprocessor struct {
 	processor.Users
 	processor.Channels
 	processor.WorkspaceInfo
+	processor.Emojis
 }
 
 func newGenerator(s Streamer, p superprocessor, flags Flags, list *structures.EntityList) generator {
@@ -123,6 +134,13 @@ func runWorkers(ctx context.Context, s Streamer, list *structures.EntityList, p
 
 	lg := slog.With("in", "runWorkers")
 
+	// the token may expire or be revoked during a long run.  There's no
+	// point in letting the other workers hit the API with it, so they are
+	// stopped, and the caller gets ErrTokenInvalidated to checkpoint the data
+	// received so far.
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
 	var (
 		wg   sync.WaitGroup
 		errC = make(chan error, 1)
@@ -156,6 +174,19 @@ func runWorkers(ctx context.Context, s Streamer, list *structures.EntityList, p
 			}
 		})
 	}
+	if flags.Emojis { // custom emoji
+		wg.Go(func() {
+			defer lg.DebugContext(ctx, "emojis done")
+
+			defer func() {
+				tryClose(errC, p.Emojis)
+			}()
+			if err := emojiWorker(ctx, s, p.Emojis); err != nil {
+				errC <- Error{"emoji", StgWorker, err}
+				return
+			}
+		})
+	}
 	{ // conversations goroutine
 		wg.Go(func() {
 			defer lg.DebugContext(ctx, "conversations done")
@@ -170,6 +201,14 @@ func runWorkers(ctx context.Context, s Streamer, list *structures.EntityList, p
 				errC <- Error{"conversations", StgWorker, err}
 				return
 			}
+			if flags.Canvases {
+				// canvases share the conversation processor, so they are
+				// fetched before it is closed.
+				if err := canvasWorker(ctx, s, p.Conversations); err != nil {
+					errC <- Error{"canvases", StgWorker, err}
+					return
+				}
+			}
 		})
 	}
 	// sentinel
@@ -179,10 +218,20 @@ func runWorkers(ctx context.Context, s Streamer, list *structures.EntityList, p
 	}()
 
 	// collect returned errors
-	var allErr error
+	var allErr, authErr error
 	for cErr := range errC {
+		if authErr == nil && structures.IsAuthError(cErr) {
+			lg.WarnContext(ctx, "token is no longer valid, stopping", "error", cErr)
+			authErr = cErr
+			cancel(ErrTokenInvalidated)
+			continue
+		}
 		allErr = errors.Join(allErr, cErr)
 	}
+	if authErr != nil {
+		// other errors are likely to be caused by the cancellation.
+		return fmt.Errorf("%w: %w", ErrTokenInvalidated, authErr)
+	}
 	if allErr != nil {
 		return allErr
 	}
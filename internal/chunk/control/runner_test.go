@@ -17,6 +17,7 @@ package control
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"sort"
 	"testing"
@@ -534,6 +535,7 @@ func Test_runWorkers(t *testing.T) {
 		*mock_processor.MockUsers
 		*mock_processor.MockChannels
 		*mock_processor.MockWorkspaceInfo
+		*mock_processor.MockEmojiDownloader
 	}
 	testList := structures.NewEntityListFromItems(
 		structures.EntityItem{Id: "C11111111", Include: true},
@@ -656,6 +658,105 @@ func Test_runWorkers(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "emojis",
+			args: args{
+				ctx:   t.Context(),
+				list:  testList,
+				flags: Flags{Emojis: true},
+			},
+			expectFn: func(s *mock_control.MockStreamer, m *superMockProcessor) {
+				s.EXPECT().
+					WorkspaceInfo(gomock.Any(), m.MockWorkspaceInfo).
+					Return(nil)
+				s.EXPECT().
+					Conversations(gomock.Any(), m.MockConversations, gomock.Any()).
+					Return(nil)
+				s.EXPECT().
+					Users(gomock.Any(), m.MockUsers, gomock.Any()).
+					Return(nil)
+				s.EXPECT().
+					Emojis(gomock.Any(), m.MockEmojiDownloader).
+					Return(nil)
+				m.MockConversations.EXPECT().Close().Return(nil)
+				m.MockEmojiDownloader.EXPECT().Close().Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "emojis error",
+			args: args{
+				ctx:   t.Context(),
+				list:  testList,
+				flags: Flags{Emojis: true},
+			},
+			expectFn: func(s *mock_control.MockStreamer, m *superMockProcessor) {
+				s.EXPECT().
+					WorkspaceInfo(gomock.Any(), m.MockWorkspaceInfo).
+					Return(nil)
+				s.EXPECT().
+					Conversations(gomock.Any(), m.MockConversations, gomock.Any()).
+					Return(nil)
+				s.EXPECT().
+					Users(gomock.Any(), m.MockUsers, gomock.Any()).
+					Return(nil)
+				s.EXPECT().
+					Emojis(gomock.Any(), m.MockEmojiDownloader).
+					Return(assert.AnError)
+				m.MockConversations.EXPECT().Close().Return(nil)
+				m.MockEmojiDownloader.EXPECT().Close().Return(nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "canvases",
+			args: args{
+				ctx:   t.Context(),
+				list:  testList,
+				flags: Flags{Canvases: true},
+			},
+			expectFn: func(s *mock_control.MockStreamer, m *superMockProcessor) {
+				s.EXPECT().
+					WorkspaceInfo(gomock.Any(), m.MockWorkspaceInfo).
+					Return(nil)
+				conv := s.EXPECT().
+					Conversations(gomock.Any(), m.MockConversations, gomock.Any()).
+					Return(nil)
+				s.EXPECT().
+					Users(gomock.Any(), m.MockUsers, gomock.Any()).
+					Return(nil)
+				canvases := s.EXPECT().
+					Canvases(gomock.Any(), m.MockConversations).
+					Return(nil).
+					After(conv)
+				m.MockConversations.EXPECT().Close().Return(nil).After(canvases)
+			},
+			wantErr: false,
+		},
+		{
+			name: "canvases error",
+			args: args{
+				ctx:   t.Context(),
+				list:  testList,
+				flags: Flags{Canvases: true},
+			},
+			expectFn: func(s *mock_control.MockStreamer, m *superMockProcessor) {
+				s.EXPECT().
+					WorkspaceInfo(gomock.Any(), m.MockWorkspaceInfo).
+					Return(nil)
+				s.EXPECT().
+					Conversations(gomock.Any(), m.MockConversations, gomock.Any()).
+					Return(nil)
+				s.EXPECT().
+					Users(gomock.Any(), m.MockUsers, gomock.Any()).
+					Return(nil)
+				s.EXPECT().
+					Canvases(gomock.Any(), m.MockConversations).
+					Return(assert.AnError)
+				m.MockConversations.EXPECT().Close().Return(nil)
+			},
+			wantErr: true,
+		},
 		{
 			name: "cancelled context and list channels returns an error",
 			args: args{
@@ -689,10 +790,11 @@ func Test_runWorkers(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			s := mock_control.NewMockStreamer(ctrl)
 			m := &superMockProcessor{
-				MockConversations: mock_processor.NewMockConversations(ctrl),
-				MockUsers:         mock_processor.NewMockUsers(ctrl),
-				MockChannels:      mock_processor.NewMockChannels(ctrl),
-				MockWorkspaceInfo: mock_processor.NewMockWorkspaceInfo(ctrl),
+				MockConversations:   mock_processor.NewMockConversations(ctrl),
+				MockUsers:           mock_processor.NewMockUsers(ctrl),
+				MockChannels:        mock_processor.NewMockChannels(ctrl),
+				MockWorkspaceInfo:   mock_processor.NewMockWorkspaceInfo(ctrl),
+				MockEmojiDownloader: mock_processor.NewMockEmojiDownloader(ctrl),
 			}
 			if tt.expectFn != nil {
 				tt.expectFn(s, m)
@@ -702,6 +804,7 @@ func Test_runWorkers(t *testing.T) {
 				Users:         m.MockUsers,
 				Channels:      m.MockChannels,
 				WorkspaceInfo: m.MockWorkspaceInfo,
+				Emojis:        m.MockEmojiDownloader,
 			}
 			if err := runWorkers(tt.args.ctx, s, tt.args.list, p, tt.args.flags); (err != nil) != tt.wantErr {
 				t.Errorf("runWorkers() error = %v, wantErr %v", err, tt.wantErr)
@@ -710,6 +813,39 @@ func Test_runWorkers(t *testing.T) {
 	}
 }
 
+func Test_runWorkers_tokenInvalidated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := mock_control.NewMockStreamer(ctrl)
+	mconv := mock_processor.NewMockConversations(ctrl)
+	musers := mock_processor.NewMockUsers(ctrl)
+	mwsp := mock_processor.NewMockWorkspaceInfo(ctrl)
+
+	s.EXPECT().WorkspaceInfo(gomock.Any(), mwsp).Return(nil)
+	s.EXPECT().
+		Conversations(gomock.Any(), mconv, gomock.Any()).
+		Return(fmt.Errorf("history: %w", slack.SlackErrorResponse{Err: "token_revoked"}))
+	// users worker is still running when the token is revoked, it must be
+	// stopped.
+	s.EXPECT().
+		Users(gomock.Any(), musers, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ processor.Users, _ ...slack.GetUsersOption) error {
+			<-ctx.Done()
+			assert.ErrorIs(t, context.Cause(ctx), ErrTokenInvalidated)
+			return context.Cause(ctx)
+		})
+	mconv.EXPECT().Close().Return(nil)
+
+	p := superprocessor{
+		Conversations: mconv,
+		Users:         musers,
+		WorkspaceInfo: mwsp,
+	}
+	list := structures.NewEntityListFromItems(structures.EntityItem{Id: "C11111111", Include: true})
+	err := runWorkers(t.Context(), s, list, p, Flags{})
+	assert.ErrorIs(t, err, ErrTokenInvalidated)
+	assert.True(t, structures.IsAuthError(err))
+}
+
 func Test_runSearch(t *testing.T) {
 	type superSearchProcessor struct {
 		*mock_processor.MockWorkspaceInfo
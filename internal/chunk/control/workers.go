@@ -56,6 +56,28 @@ func workspaceWorker(ctx context.Context, s Streamer, wsproc processor.Workspace
 	return nil
 }
 
+func emojiWorker(ctx context.Context, s Streamer, emproc processor.Emojis) error {
+	lg := slog.Default()
+	lg.Debug("emojiWorker started")
+
+	if err := s.Emojis(ctx, emproc); err != nil {
+		return fmt.Errorf("error listing emoji: %w", err)
+	}
+	lg.Debug("emojiWorker done")
+	return nil
+}
+
+func canvasWorker(ctx context.Context, s Streamer, proc processor.Conversations) error {
+	lg := slog.Default()
+	lg.Debug("canvasWorker started")
+
+	if err := s.Canvases(ctx, proc); err != nil {
+		return fmt.Errorf("error fetching canvases: %w", err)
+	}
+	lg.Debug("canvasWorker done")
+	return nil
+}
+
 func searchMsgWorker(ctx context.Context, s Streamer, ms processor.MessageSearcher, query string) error {
 	lg := slog.Default()
 	lg.Debug("searchMsgWorker started")
@@ -37,6 +37,7 @@ import (
 
 	"github.com/rusq/slackdump/v4/internal/osext"
 	"github.com/rusq/slackdump/v4/internal/structures"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 // file extensions
@@ -51,11 +52,14 @@ const (
 	FUsers     FileID = "users"
 	FWorkspace FileID = "workspace"
 	FSearch    FileID = "search"
+	FEmoji     FileID = "emoji"
 )
 
 const (
 	UploadsDir = "__uploads" // for serving files
 	AvatarsDir = "__avatars"
+	EmojiDir   = "__emoji"
+	LinksDir   = "__links" // archived copies of the external links
 )
 
 // Directory is an abstraction over the directory with chunk files.  It
@@ -67,8 +71,11 @@ const (
 // compressed with GZIP, unless stated otherwise.
 type Directory struct {
 	// dir is a path to a physical directory on the filesystem with chunks and
-	// uploads.
-	dir   string
+	// uploads, or the name of the directory in fsys.
+	dir string
+	// fsys, if set, is the filesystem the chunk files are read from, i.e. a
+	// ZIP file.  Such directory is read-only.
+	fsys  fs.FS
 	cache dcache
 
 	wantCache  bool
@@ -121,6 +128,65 @@ func OpenDir(dir string, opt ...DirOption) (*Directory, error) {
 	return d, nil
 }
 
+// OpenDirFS opens the read-only directory with the chunk files in fsys, i.e. a
+// ZIP file.  name is the name of the directory, it is returned by
+// [Directory.Name].
+func OpenDirFS(fsys fs.FS, name string, opt ...DirOption) (*Directory, error) {
+	d := &Directory{
+		dir:        name,
+		fsys:       fsys,
+		wantCache:  true,
+		numWorkers: 16,
+	}
+	for _, o := range opt {
+		o(d)
+	}
+	if d.wantCache {
+		fm, err := newFileMgr()
+		if err != nil {
+			return nil, err
+		}
+		d.fm = fm
+	}
+	return d, nil
+}
+
+// ErrReadOnly is returned on attempts to modify the read-only directory.
+var ErrReadOnly = errors.New("read-only chunk directory")
+
+// FS returns the filesystem of the directory.
+func (d *Directory) FS() fs.FS {
+	if d.fsys != nil {
+		return d.fsys
+	}
+	return os.DirFS(d.dir)
+}
+
+// fsname returns the name of the file in fsys for the full filename.
+func (d *Directory) fsname(filename string) string {
+	rel, err := filepath.Rel(d.dir, filename)
+	if err != nil {
+		return filename
+	}
+	return filepath.ToSlash(rel)
+}
+
+// openFile opens the file with the full filename.
+func (d *Directory) openFile(filename string) (fs.File, error) {
+	if d.fsys != nil {
+		return d.fsys.Open(d.fsname(filename))
+	}
+	return os.Open(filename)
+}
+
+// stat returns the file info of the file with the full filename.
+func (d *Directory) stat(filename string) (fs.FileInfo, error) {
+	if d.fsys != nil {
+		return fs.Stat(d.fsys, d.fsname(filename))
+	}
+	return os.Stat(filename)
+}
+
 // CreateDir creates and opens a directory.  It will create all parent
 // directories if they don't exist.
 func CreateDir(dir string) (*Directory, error) {
@@ -134,6 +200,9 @@ func CreateDir(dir string) (*Directory, error) {
 // are closed.
 func (d *Directory) RemoveAll() error {
 	_ = d.Close()
+	if d.fsys != nil {
+		return ErrReadOnly
+	}
 	return os.RemoveAll(d.dir)
 }
 
@@ -255,7 +324,7 @@ type result struct {
 // It does not close files after the callback is called, so it's a caller's
 // responsibility to close it.
 func (d *Directory) Walk(fn func(name string, f *File, err error) error) error {
-	return filepath.WalkDir(d.dir, func(path string, de fs.DirEntry, err error) error {
+	return d.walkDir(func(path string, de fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -287,6 +356,19 @@ func (d *Directory) Walk(fn func(name string, f *File, err error) error) error {
 	})
 }
 
+// walkDir walks the directory, calling fn with the full path of each file.
+func (d *Directory) walkDir(fn fs.WalkDirFunc) error {
+	if d.fsys == nil {
+		return filepath.WalkDir(d.dir, fn)
+	}
+	return fs.WalkDir(d.fsys, ".", func(p string, de fs.DirEntry, err error) error {
+		if p == "." {
+			return fn(d.dir, de, err)
+		}
+		return fn(filepath.Join(d.dir, filepath.FromSlash(p)), de, err)
+	})
+}
+
 // WalkSync is the same as Walk, but it closes the file after the callback is
 // called.
 func (d *Directory) WalkSync(fn func(name string, f *File, err error) error) error {
@@ -305,7 +387,7 @@ func (d *Directory) Name() string {
 }
 
 func (d *Directory) Stat(id FileID) (fs.FileInfo, error) {
-	return os.Stat(d.filename(id))
+	return d.stat(d.filename(id))
 }
 
 // Users returns the collected users from the directory.
@@ -322,6 +404,16 @@ func (d *Directory) Users() ([]slack.User, error) {
 	return users, nil
 }
 
+// Emojis returns the custom emoji collected in the directory.
+func (d *Directory) Emojis() ([]types.Emoji, error) {
+	f, err := d.Open(FEmoji)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open emoji file %q: %w", d.filename(FEmoji), err)
+	}
+	defer f.Close()
+	return f.AllEmojis()
+}
+
 // Open opens a chunk file with the given name.  Extension is appended
 // automatically.
 func (d *Directory) Open(id FileID) (*File, error) {
@@ -341,15 +433,18 @@ func (d *Directory) OpenRAW(filename string) (io.ReadSeekCloser, error) {
 
 func (d *Directory) openRAW(filename string) (osext.ReadSeekCloseNamer, error) {
 	if d.wantCache {
+		if d.fsys != nil {
+			return d.fm.OpenFunc(filename, d.openChunkFile)
+		}
 		return d.fm.Open(filename)
 	}
-	return openChunks(filename)
+	return d.openChunks(filename)
 }
 
 // openChunks opens an existing chunk file and returns a ReadSeekCloser.  It
 // expects a chunkfile to be a gzip-compressed file.
-func openChunks(filename string) (osext.ReadSeekCloseNamer, error) {
-	f, err := openfile(filename)
+func (d *Directory) openChunks(filename string) (osext.ReadSeekCloseNamer, error) {
+	f, err := d.openChunkFile(filename)
 	if err != nil {
 		return nil, err
 	}
@@ -363,15 +458,15 @@ func openChunks(filename string) (osext.ReadSeekCloseNamer, error) {
 	return osext.RemoveOnClose(tf), nil
 }
 
-func openfile(filename string) (*os.File, error) {
-	if fi, err := os.Stat(filename); err != nil {
+func (d *Directory) openChunkFile(filename string) (io.ReadCloser, error) {
+	if fi, err := d.stat(filename); err != nil {
 		return nil, err
 	} else if fi.IsDir() {
 		return nil, errors.New("chunk file is a directory")
 	} else if fi.Size() == 0 {
 		return nil, errors.New("chunk file is empty")
 	}
-	return os.Open(filename)
+	return d.openFile(filename)
 }
 
 // filename returns the full path of the chunk file with the given fileID.
@@ -390,6 +485,9 @@ func (d *Directory) filename(id FileID) string {
 // It will NOT overwrite an existing file and will return an error if the file
 // exists.
 func (d *Directory) Create(fileID FileID) (io.WriteCloser, error) {
+	if d.fsys != nil {
+		return nil, ErrReadOnly
+	}
 	filename := d.filename(fileID)
 	if fi, err := os.Stat(filename); err == nil {
 		if fi.IsDir() {
@@ -472,7 +570,7 @@ func cachedFromReader(wf osext.ReadSeekCloseNamer, wantCache bool) (*File, error
 
 // File returns the file with the given id and name.
 func (d *Directory) File(id string, name string) (fs.File, error) {
-	return os.Open(filepath.Join(d.dir, UploadsDir, id, name))
+	return d.openFile(filepath.Join(d.dir, UploadsDir, id, name))
 }
 
 func (d *Directory) AllMessages(ctx context.Context, channelID string) ([]slack.Message, error) {
@@ -17,7 +17,9 @@ package chunk
 
 import (
 	"errors"
+	"io"
 	"io/fs"
+	"path/filepath"
 	"strings"
 	"testing"
 	"testing/fstest"
@@ -178,3 +180,43 @@ func TestDirectory_Walk(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenDirFS(t *testing.T) {
+	testChannels := fixtures.Load[[]slack.Channel](fixtures.TestChannelsJSON)
+	fsys := fstest.MapFS{
+		"C123.json.gz": &fstest.MapFile{
+			Data: testutil.GZCompress(t, testutil.MarshalJSON(t, Chunk{Type: CChannelInfo, ChannelID: testChannels[0].ID, Channel: &testChannels[0]})),
+		},
+		"__uploads/CNESTED.json.gz": &fstest.MapFile{
+			Data: testutil.GZCompress(t, []byte("NaN")),
+		},
+		"__uploads/F1/file.txt": &fstest.MapFile{Data: []byte("file contents")},
+	}
+	for _, cache := range []bool{true, false} {
+		d, err := OpenDirFS(fsys, "archive.zip", WithCache(cache))
+		if err != nil {
+			t.Fatalf("OpenDirFS() error = %v", err)
+		}
+		defer d.Close()
+
+		var seen []string
+		if err := d.WalkSync(func(name string, f *File, err error) error {
+			seen = append(seen, name)
+			return err
+		}); err != nil {
+			t.Fatalf("Walk() error = %v", err)
+		}
+		assert.Equal(t, []string{filepath.Join("archive.zip", "C123.json.gz")}, seen)
+
+		_, err = d.Stat("C123")
+		assert.NoError(t, err)
+		f, err := d.File("F1", "file.txt")
+		if assert.NoError(t, err) {
+			data, _ := io.ReadAll(f)
+			f.Close()
+			assert.Equal(t, "file contents", string(data))
+		}
+		_, err = d.Create("C456")
+		assert.ErrorIs(t, err, ErrReadOnly)
+	}
+}
@@ -33,6 +33,7 @@ import (
 
 	"github.com/rusq/slackdump/v4/internal/fasttime"
 	"github.com/rusq/slackdump/v4/internal/osext"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 var (
@@ -237,6 +238,13 @@ func (p *File) AllUsers() ([]slack.User, error) {
 	})
 }
 
+// AllEmojis returns all custom emoji in the chunk file.
+func (p *File) AllEmojis() ([]types.Emoji, error) {
+	return allForID(p, emojiChunkID, func(c *Chunk) []types.Emoji {
+		return c.Emojis
+	})
+}
+
 // AllChannels returns all channels collected by listing channels in the dump
 // file.
 func (p *File) AllChannels() ([]slack.Channel, error) {
@@ -26,6 +26,8 @@ import (
 
 	"github.com/rusq/slack"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/rusq/slackdump/v4/types"
 )
 
 const (
@@ -426,6 +428,35 @@ func TestFile_AllUsers(t *testing.T) {
 	}
 }
 
+func TestFile_AllEmojis(t *testing.T) {
+	emojiChunks := []Chunk{
+		{
+			Type:   CEmojis,
+			Emojis: []types.Emoji{{Name: "partyparrot", URL: "https://example.com/partyparrot.gif"}},
+		},
+		{
+			Type:   CEmojis,
+			Emojis: []types.Emoji{{Name: "parrot", URL: "alias:partyparrot", IsAlias: 1, AliasFor: "partyparrot"}},
+		},
+	}
+	rs := marshalChunks(append(emojiChunks, testChunks...)...)
+	p := &File{
+		rs:  rs,
+		idx: mkindex(rs),
+	}
+	got, err := p.AllEmojis()
+	if err != nil {
+		t.Fatalf("File.AllEmojis() error = %v", err)
+	}
+	want := []types.Emoji{
+		{Name: "partyparrot", URL: "https://example.com/partyparrot.gif"},
+		{Name: "parrot", URL: "alias:partyparrot", IsAlias: 1, AliasFor: "partyparrot"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("File.AllEmojis() = %v, want %v", got, want)
+	}
+}
+
 func TestFile_offsetTimestamps(t *testing.T) {
 	type fields struct {
 		rs io.ReadSeeker
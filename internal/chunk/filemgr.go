@@ -86,6 +86,14 @@ func (dp *filemgr) Destroy() error {
 // compressed file, unpacks it into a temporary file, and returns the handle.
 // The file is expected to be a gzip-compressed file.
 func (dp *filemgr) Open(name string) (*wrappedfile, error) {
+	return dp.OpenFunc(name, func(name string) (io.ReadCloser, error) {
+		return os.Open(name)
+	})
+}
+
+// OpenFunc is the same as Open, but uses the function open to open the
+// compressed file.
+func (dp *filemgr) OpenFunc(name string, open func(name string) (io.ReadCloser, error)) (*wrappedfile, error) {
 	// create the directory if it doesn't exist
 	var mkdirerr error
 	dp.once.Do(func() {
@@ -109,7 +117,7 @@ func (dp *filemgr) Open(name string) (*wrappedfile, error) {
 		return &wrappedfile{hash: tmpname, File: f, dp: dp}, nil
 	}
 	// open the compressed file
-	cf, err := os.Open(name)
+	cf, err := open(name)
 	if err != nil {
 		return nil, err
 	}
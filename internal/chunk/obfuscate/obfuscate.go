@@ -38,6 +38,7 @@ import (
 	"github.com/rusq/slack"
 
 	"github.com/rusq/slackdump/v4/internal/chunk"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 type doOpts struct {
@@ -132,6 +133,8 @@ func (o obfuscator) Chunk(c *chunk.Chunk) {
 		o.Channels(c.Channels...)
 	case chunk.CWorkspaceInfo:
 		o.WorkspaceInfo(c.WorkspaceInfo)
+	case chunk.CEmojis:
+		o.Emojis(c.Emojis...)
 	default:
 		log.Panicf("unknown chunk type: %s", c.Type)
 	}
@@ -399,3 +402,18 @@ func (o obfuscator) WorkspaceInfo(wi *slack.AuthTestResponse) {
 	wi.User = o.randomString(len(wi.User))
 	wi.EnterpriseID = o.EnterpriseID(wi.EnterpriseID)
 }
+
+// Emojis obfuscates the uploader details of the custom emoji.  Emoji names
+// and images are left intact.
+func (o obfuscator) Emojis(ee ...types.Emoji) {
+	for i := range ee {
+		ee[i].UserID = o.UserID(ee[i].UserID)
+		ee[i].TeamID = o.TeamID(ee[i].TeamID)
+		if ee[i].UserDisplayName != "" {
+			ee[i].UserDisplayName = o.randomString(len(ee[i].UserDisplayName))
+		}
+		if ee[i].AvatarHash != "" {
+			ee[i].AvatarHash = o.randomStringExact(len(ee[i].AvatarHash))
+		}
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"time"
 
 	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/types"
 )
 
 // Recorder records all the data it receives into a writer.
@@ -263,3 +265,19 @@ func (rec *Recorder) SearchFiles(ctx context.Context, query string, sf []slack.F
 	}
 	return nil
 }
+
+// Emojis records the custom workspace emoji.
+func (rec *Recorder) Emojis(ctx context.Context, emojis []types.Emoji) error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	chunk := Chunk{
+		Type:      CEmojis,
+		Timestamp: time.Now().UnixNano(),
+		Count:     int32(len(emojis)),
+		Emojis:    emojis,
+	}
+	if err := rec.enc.Encode(ctx, &chunk); err != nil {
+		return err
+	}
+	return nil
+}
@@ -19,12 +19,14 @@ import (
 	"context"
 	"errors"
 	"io"
+	"iter"
 	"net/http"
 
 	"github.com/rusq/chttp/v2"
 	"github.com/rusq/slack"
 
 	"github.com/rusq/slackdump/v4/auth"
+	"github.com/rusq/slackdump/v4/downloader"
 	"github.com/rusq/slackdump/v4/internal/edge"
 )
 
@@ -40,6 +42,7 @@ type Slack interface {
 	GetEmojiContext(ctx context.Context) (map[string]string, error)
 	GetFileContext(ctx context.Context, downloadURL string, writer io.Writer) error
 	GetFileInfoContext(ctx context.Context, fileID string, count int, page int) (*slack.File, []slack.Comment, *slack.Paging, error)
+	GetFilesContext(ctx context.Context, params slack.GetFilesParameters) ([]slack.File, *slack.Paging, error)
 	GetStarredContext(ctx context.Context, params slack.StarsParameters) ([]slack.StarredItem, *slack.Paging, error)
 	GetUserInfoContext(ctx context.Context, user string) (*slack.User, error)
 	GetUsersContext(ctx context.Context, options ...slack.GetUsersOption) ([]slack.User, error)
@@ -66,6 +69,7 @@ type Client struct {
 	edge          *edge.Client // nil for non-enterprise workspaces
 	wi            *slack.AuthTestResponse
 	hcl           *http.Client
+	token         string
 }
 
 // Wrap wraps a *slack.Client and returns a *Client that implements the Slack
@@ -79,6 +83,7 @@ func Wrap(cl *slack.Client) *Client {
 
 type options struct {
 	enterprise bool
+	transport  []TransportFunc
 }
 
 type Option func(*options)
@@ -92,6 +97,29 @@ func WithEnterprise(enterprise bool) Option {
 	}
 }
 
+// TransportFunc wraps the HTTP transport rt of the client.  teamID and userID
+// are the IDs of the team and the user the client is authenticated as.  If rt
+// implements [io.Closer], the returned RoundTripper should implement it too.
+type TransportFunc func(teamID, userID string, rt http.RoundTripper) http.RoundTripper
+
+// WithTransport adds the HTTP transport wrapper, i.e. a response cache.  It
+// is applied to the transports of the Slack and edge clients after the
+// authentication test.
+func WithTransport(fn TransportFunc) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.transport = append(o.transport, fn)
+		}
+	}
+}
+
+// wrapTransport wraps the transport of hcl with the transport functions.
+func (o *options) wrapTransport(wi *slack.AuthTestResponse, hcl *http.Client) {
+	for _, fn := range o.transport {
+		hcl.Transport = fn(wi.TeamID, wi.UserID, hcl.Transport)
+	}
+}
+
 // newSlackClient is a shared helper that dials Slack and runs an auth-test.
 func newSlackClient(ctx context.Context, prov auth.Provider) (*http.Client, *slack.Client, *slack.AuthTestResponse, error) {
 	cl, err := prov.HTTPClient()
@@ -120,10 +148,12 @@ func New(ctx context.Context, prov auth.Provider, opts ...Option) (*Client, erro
 		o(&opt)
 	}
 
+	opt.wrapTransport(wi, hcl)
 	c := &Client{
 		Client: scl,
 		wi:     wi,
 		hcl:    hcl,
+		token:  prov.SlackToken(),
 	}
 
 	if (opt.enterprise || wi.EnterpriseID != "") && auth.IsClientToken(prov.SlackToken()) {
@@ -131,6 +161,7 @@ func New(ctx context.Context, prov auth.Provider, opts ...Option) (*Client, erro
 		if err != nil {
 			return nil, errors.Join(err, chttp.Close(hcl))
 		}
+		opt.wrapTransport(wi, ecl.Raw())
 		c.edge = ecl
 	}
 	return c, nil
@@ -155,6 +186,15 @@ func (c *Client) Edge() *edge.Client {
 	return c.edge
 }
 
+// OpenFileRange opens the file at downloadURL starting at the offset.  It
+// implements [downloader.RangeOpener].
+func (c *Client) OpenFileRange(ctx context.Context, downloadURL string, offset int64, ifRange string) (*downloader.RangeResponse, error) {
+	if c.hcl == nil {
+		return nil, errors.ErrUnsupported
+	}
+	return downloader.OpenRange(ctx, c.hcl, c.token, downloadURL, offset, ifRange)
+}
+
 // Close releases any HTTP transports owned by the client.
 func (c *Client) Close() error {
 	var err error
@@ -190,6 +230,17 @@ func (c *Client) GetConversationsContextEx(ctx context.Context, params *slack.Ge
 	return c.edge.GetConversationsContextEx(ctx, params, onlyMy)
 }
 
+// AdminEmojiList returns the custom emoji with the uploader details, using
+// the edge API.  It yields [ErrOpNotSupported] when there is no edge client.
+func (c *Client) AdminEmojiList(ctx context.Context) iter.Seq2[edge.EmojiResult, error] {
+	if c.edge == nil {
+		return func(yield func(edge.EmojiResult, error) bool) {
+			yield(edge.EmojiResult{}, ErrOpNotSupported)
+		}
+	}
+	return c.edge.AdminEmojiList(ctx)
+}
+
 // GetConversationInfoContext overrides the standard method with the edge client
 // for enterprise workspaces.
 func (c *Client) GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error) {
@@ -102,3 +102,31 @@ func TestClientClose_wrapIsNoOp(t *testing.T) {
 
 	assert.NoError(t, cl.Close())
 }
+
+func TestWithTransport(t *testing.T) {
+	rt := &closeTransport{}
+	hcl := &http.Client{Transport: rt}
+
+	type wrapped struct {
+		http.RoundTripper
+		teamID string
+		userID string
+	}
+	var opt options
+	WithTransport(func(teamID, userID string, next http.RoundTripper) http.RoundTripper {
+		return &wrapped{RoundTripper: next, teamID: teamID, userID: userID}
+	})(&opt)
+	WithTransport(nil)(&opt)
+	opt.wrapTransport(&slack.AuthTestResponse{TeamID: "T123", UserID: "U123"}, hcl)
+
+	w, ok := hcl.Transport.(*wrapped)
+	if !ok {
+		t.Fatalf("transport is %T, want *wrapped", hcl.Transport)
+	}
+	if w.teamID != "T123" || w.userID != "U123" {
+		t.Errorf("teamID, userID = %q, %q, want T123, U123", w.teamID, w.userID)
+	}
+	if w.RoundTripper != rt {
+		t.Error("wrapper does not wrap the original transport")
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package client
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/internal/network"
+	"github.com/rusq/slackdump/v4/internal/structures"
+)
+
+// ClientStats contains the usage statistics of a single client in the
+// [Pool].
+type ClientStats struct {
+	// Name is the client name, i.e. the workspace name.
+	Name string
+	// Calls is the number of API calls made with the client.
+	Calls int
+	// Errors is the number of calls that returned an error, including rate
+	// limit errors.
+	Errors int
+	// RateLimited is the number of times the client was rate limited.
+	RateLimited int
+	// LastLimited is the time when the client was last rate limited.
+	LastLimited time.Time
+	// Evicted is true if the client was removed from rotation because its
+	// token was revoked.
+	Evicted bool
+	// EvictReason is the error that caused the eviction.
+	EvictReason string
+}
+
+// health tracks the health of the clients in the pool.
+type health struct {
+	stats []ClientStats
+	// limitedUntil holds the time until which the client is rate limited,
+	// per API tier.
+	limitedUntil []map[network.Tier]time.Time
+}
+
+func newHealth(total int) *health {
+	h := &health{
+		stats:        make([]ClientStats, total),
+		limitedUntil: make([]map[network.Tier]time.Time, total),
+	}
+	for i := range h.limitedUntil {
+		h.limitedUntil[i] = make(map[network.Tier]time.Time)
+	}
+	return h
+}
+
+// available returns true if the client i is not evicted, and is not rate
+// limited on the tier at the time now.
+func (h *health) available(i int, tier network.Tier, now time.Time) bool {
+	return !h.stats[i].Evicted && !now.Before(h.limitedUntil[i][tier])
+}
+
+// soonest returns the client that is not evicted, and that recovers from the
+// rate limit on the tier first.
+func (h *health) soonest(tier network.Tier) int {
+	best := -1
+	for i := range h.stats {
+		if h.stats[i].Evicted {
+			continue
+		}
+		if best == -1 || h.limitedUntil[i][tier].Before(h.limitedUntil[best][tier]) {
+			best = i
+		}
+	}
+	if best == -1 {
+		return 0
+	}
+	return best
+}
+
+// active returns the number of clients that were not evicted.
+func (h *health) active() int {
+	var n int
+	for i := range h.stats {
+		if !h.stats[i].Evicted {
+			n++
+		}
+	}
+	return n
+}
+
+// record records the outcome err of the call made with the client i on the
+// tier at the time now.  It returns true if the call should be repeated with
+// another client, which is the case when the client got rate limited or
+// evicted, and there's another client available.
+func (h *health) record(i int, tier network.Tier, now time.Time, err error) (retry bool) {
+	st := &h.stats[i]
+	st.Calls++
+	if err == nil {
+		return false
+	}
+	st.Errors++
+
+	var rle *slack.RateLimitedError
+	if errors.As(err, &rle) {
+		st.RateLimited++
+		st.LastLimited = now
+		h.limitedUntil[i][tier] = now.Add(rle.RetryAfter)
+		return h.anyAvailable(tier, now)
+	}
+	if structures.IsAuthError(err) && h.active() > 1 {
+		// the last client is never evicted, so that the caller gets the
+		// error.
+		st.Evicted = true
+		st.EvictReason = err.Error()
+		return true
+	}
+	return false
+}
+
+func (h *health) anyAvailable(tier network.Tier, now time.Time) bool {
+	for i := range h.stats {
+		if h.available(i, tier, now) {
+			return true
+		}
+	}
+	return false
+}
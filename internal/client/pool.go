@@ -17,112 +17,437 @@ package client
 
 import (
 	"context"
+	"errors"
 	"io"
+	"iter"
 	"log/slog"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/downloader"
+	"github.com/rusq/slackdump/v4/internal/edge"
+	"github.com/rusq/slackdump/v4/internal/network"
 )
 
 // Pool is a pool of Slack clients that can be used to make API calls.
 // Zero value is not usable, must be initialised with [NewPool].
 //
+// Pool tracks the health of each client per API tier:  if the client gets
+// rate limited, the call is repeated with the next available client, and the
+// rate limited client is not used for that tier until it recovers.  A client
+// whose token was revoked is evicted from the pool, unless it is the last one.
+//
 // IMPORTANT: Every method on the [Slack] interface must be delegated here.
 // When new methods are added to [Slack], add the corresponding delegation
 // below.  A compile-time assertion below catches interface drift.
 type Pool struct {
-	pool []Slack
-	mu   sync.Mutex
+	pool   []Slack
+	mu     sync.Mutex
+	health *health
 	strategy
 }
 
 var _ Slack = (*Pool)(nil) // compile-time: Pool must implement Slack
 
-// NewPool wraps the slack.Client with the edge client, so that the edge
-// client can be used as a fallback.
+// timeNow is the time function, overridden in tests.
+var timeNow = time.Now
+
+// PoolOption configures the [Pool].
+type PoolOption func(*poolOptions)
+
+type poolOptions struct {
+	strategy Strategy
+	names    []string
+}
+
+// WithStrategy sets the client selection strategy.
+func WithStrategy(s Strategy) PoolOption {
+	return func(o *poolOptions) {
+		o.strategy = s
+	}
+}
+
+// WithNames sets the client names, that are reported in [Pool.Stats].  The
+// names are assigned to the clients in order.
+func WithNames(names ...string) PoolOption {
+	return func(o *poolOptions) {
+		o.names = names
+	}
+}
+
+// NewPool creates a new pool of clients with the round-robin strategy.
 func NewPool(scl ...Slack) *Pool {
 	return &Pool{
 		pool:     scl,
+		health:   newHealth(len(scl)),
 		strategy: newRoundRobin(len(scl)),
 	}
 }
 
-// next returns the next client in the pool using the current strategy.
-func (p *Pool) next() Slack {
+// NewPoolWithOptions creates a new pool of clients configured with the
+// options.  It returns an error if the strategy is unknown.
+func NewPoolWithOptions(scl []Slack, opts ...PoolOption) (*Pool, error) {
+	var o poolOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	st, err := newStrategy(o.strategy, len(scl))
+	if err != nil {
+		return nil, err
+	}
+	p := &Pool{
+		pool:     scl,
+		health:   newHealth(len(scl)),
+		strategy: st,
+	}
+	for i := range min(len(o.names), len(scl)) {
+		p.health.stats[i].Name = o.names[i]
+	}
+	return p, nil
+}
+
+// Len returns the number of clients in the pool.
+func (p *Pool) Len() int {
+	return len(p.pool)
+}
+
+// Stats returns the per-client usage statistics.
+func (p *Pool) Stats() []ClientStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.health == nil {
+		return nil
+	}
+	stats := make([]ClientStats, len(p.health.stats))
+	copy(stats, p.health.stats)
+	return stats
+}
+
+// pick returns the index of the next client for the API tier using the
+// current strategy.
+func (p *Pool) pick(tier network.Tier) int {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	if len(p.pool) == 0 {
 		panic("no clients in pool")
 	}
-	next := p.strategy.next()
+	if p.health == nil {
+		p.health = newHealth(len(p.pool))
+	}
+	next := p.strategy.next(p.health, tier, timeNow())
 	slog.Debug("next client", "index", next)
-	return p.pool[next]
+	return next
+}
+
+// next returns the next client in the pool using the current strategy.
+func (p *Pool) next() Slack {
+	return p.pool[p.pick(network.NoTier)]
+}
+
+// do calls fn with the next client for the API tier, and records the outcome.
+// If the client got rate limited, or was evicted, and there's another client
+// available, fn is called again with that client.
+func (p *Pool) do(tier network.Tier, fn func(cl Slack) error) error {
+	return p.doUntil(tier, nil, fn)
+}
+
+// doUntil is like do, but it does not call fn again once committed returns
+// true, i.e. when the failed call had already passed a part of the result to
+// the caller, and repeating it would duplicate or corrupt the result.  The
+// outcome is recorded in either case.
+func (p *Pool) doUntil(tier network.Tier, committed func() bool, fn func(cl Slack) error) error {
+	var err error
+	for range len(p.pool) {
+		i := p.pick(tier)
+		err = fn(p.pool[i])
+
+		p.mu.Lock()
+		retry := p.health.record(i, tier, timeNow(), err)
+		p.mu.Unlock()
+		if !retry || (committed != nil && committed()) {
+			return err
+		}
+		slog.Debug("retrying with another client", "index", i, "error", err)
+	}
+	return err
+}
+
+// Close closes all clients in the pool that implement [io.Closer].
+func (p *Pool) Close() error {
+	var errs error
+	for _, cl := range p.pool {
+		if c, ok := cl.(io.Closer); ok {
+			errs = errors.Join(errs, c.Close())
+		}
+	}
+	return errs
 }
 
 func (p *Pool) AuthTestContext(ctx context.Context) (response *slack.AuthTestResponse, err error) {
-	return p.next().AuthTestContext(ctx)
+	err = p.do(network.Tier4, func(cl Slack) error {
+		var err error
+		response, err = cl.AuthTestContext(ctx)
+		return err
+	})
+	return
 }
 
-func (p *Pool) GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
-	return p.next().GetConversationHistoryContext(ctx, params)
+func (p *Pool) GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (resp *slack.GetConversationHistoryResponse, err error) {
+	err = p.do(network.Tier3, func(cl Slack) error {
+		var err error
+		resp, err = cl.GetConversationHistoryContext(ctx, params)
+		return err
+	})
+	return
 }
 
 func (p *Pool) GetConversationRepliesContext(ctx context.Context, params *slack.GetConversationRepliesParameters) (msgs []slack.Message, hasMore bool, nextCursor string, err error) {
-	return p.next().GetConversationRepliesContext(ctx, params)
+	err = p.do(network.Tier3, func(cl Slack) error {
+		var err error
+		msgs, hasMore, nextCursor, err = cl.GetConversationRepliesContext(ctx, params)
+		return err
+	})
+	return
 }
 
+// GetUsersPaginated returns the pagination bound to the next client.  The
+// pagination calls the client directly, so the calls are not accounted for
+// in the client health, use [Pool.GetUsersPage] instead.
 func (p *Pool) GetUsersPaginated(options ...slack.GetUsersOption) slack.UserPagination {
-	return p.next().GetUsersPaginated(options...)
+	return p.pool[p.pick(network.Tier2)].GetUsersPaginated(options...)
+}
+
+// GetUsersPage returns the page of users starting at the cursor, and the
+// cursor of the next page, which is empty on the last page.  The empty cursor
+// requests the first page.  Each page may be
+// fetched with a different client.
+func (p *Pool) GetUsersPage(ctx context.Context, cursor string, options ...slack.GetUsersOption) (users []slack.User, nextCursor string, err error) {
+	opts := options
+	if cursor != "" {
+		opts = append(slices.Clip(options), slack.GetUsersOptionCursor(cursor))
+	}
+	err = p.do(network.Tier2, func(cl Slack) error {
+		up, err := cl.GetUsersPaginated(opts...).Next(ctx)
+		if up.Done(err) {
+			users, nextCursor = nil, ""
+			return nil
+		} else if err != nil {
+			return err
+		}
+		users, nextCursor = up.Users, up.Cursor
+		return nil
+	})
+	return
 }
 
-func (p *Pool) GetStarredContext(ctx context.Context, params slack.StarsParameters) ([]slack.StarredItem, *slack.Paging, error) {
-	return p.next().GetStarredContext(ctx, params)
+func (p *Pool) GetStarredContext(ctx context.Context, params slack.StarsParameters) (items []slack.StarredItem, paging *slack.Paging, err error) {
+	err = p.do(network.Tier3, func(cl Slack) error {
+		var err error
+		items, paging, err = cl.GetStarredContext(ctx, params)
+		return err
+	})
+	return
 }
 
-func (p *Pool) ListBookmarks(channelID string) ([]slack.Bookmark, error) {
-	return p.next().ListBookmarks(channelID)
+func (p *Pool) ListBookmarks(channelID string) (bookmarks []slack.Bookmark, err error) {
+	err = p.do(network.Tier3, func(cl Slack) error {
+		var err error
+		bookmarks, err = cl.ListBookmarks(channelID)
+		return err
+	})
+	return
 }
 
 func (p *Pool) GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) (channels []slack.Channel, nextCursor string, err error) {
-	return p.next().GetConversationsContext(ctx, params)
+	err = p.do(network.Tier2, func(cl Slack) error {
+		var err error
+		channels, nextCursor, err = cl.GetConversationsContext(ctx, params)
+		return err
+	})
+	return
 }
 
-func (p *Pool) GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error) {
-	return p.next().GetConversationInfoContext(ctx, input)
+func (p *Pool) GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (channel *slack.Channel, err error) {
+	err = p.do(network.Tier3, func(cl Slack) error {
+		var err error
+		channel, err = cl.GetConversationInfoContext(ctx, input)
+		return err
+	})
+	return
 }
 
-func (p *Pool) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error) {
-	return p.next().GetUsersInConversationContext(ctx, params)
+func (p *Pool) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) (ids []string, nextCursor string, err error) {
+	err = p.do(network.Tier4, func(cl Slack) error {
+		var err error
+		ids, nextCursor, err = cl.GetUsersInConversationContext(ctx, params)
+		return err
+	})
+	return
 }
 
+// GetFileContext downloads the file into the writer.  The download is
+// repeated with another client only if nothing was written yet.
 func (p *Pool) GetFileContext(ctx context.Context, downloadURL string, writer io.Writer) error {
-	return p.next().GetFileContext(ctx, downloadURL, writer)
+	cw := &countWriter{w: writer}
+	return p.doUntil(network.NoTier, func() bool { return cw.n > 0 }, func(cl Slack) error {
+		return cl.GetFileContext(ctx, downloadURL, cw)
+	})
 }
 
-func (p *Pool) GetUsersContext(ctx context.Context, options ...slack.GetUsersOption) ([]slack.User, error) {
-	return p.next().GetUsersContext(ctx, options...)
+// countWriter counts the bytes written to w.
+type countWriter struct {
+	w io.Writer
+	n int64
 }
 
-func (p *Pool) GetEmojiContext(ctx context.Context) (map[string]string, error) {
-	return p.next().GetEmojiContext(ctx)
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
 }
 
-func (p *Pool) SearchMessagesContext(ctx context.Context, query string, params slack.SearchParameters) (*slack.SearchMessages, error) {
-	return p.next().SearchMessagesContext(ctx, query, params)
+// OpenFileRange opens the file at downloadURL starting at the offset, using
+// one of the clients in the pool.  It returns [errors.ErrUnsupported] if the
+// client does not implement [downloader.RangeOpener].
+func (p *Pool) OpenFileRange(ctx context.Context, downloadURL string, offset int64, ifRange string) (rr *downloader.RangeResponse, err error) {
+	err = p.do(network.NoTier, func(cl Slack) error {
+		ro, ok := cl.(downloader.RangeOpener)
+		if !ok {
+			return errors.ErrUnsupported
+		}
+		var err error
+		rr, err = ro.OpenFileRange(ctx, downloadURL, offset, ifRange)
+		return err
+	})
+	return
 }
 
-func (p *Pool) SearchFilesContext(ctx context.Context, query string, params slack.SearchParameters) (*slack.SearchFiles, error) {
-	return p.next().SearchFilesContext(ctx, query, params)
+func (p *Pool) GetUsersContext(ctx context.Context, options ...slack.GetUsersOption) (users []slack.User, err error) {
+	err = p.do(network.Tier2, func(cl Slack) error {
+		var err error
+		users, err = cl.GetUsersContext(ctx, options...)
+		return err
+	})
+	return
 }
 
-func (p *Pool) GetFileInfoContext(ctx context.Context, fileID string, count int, page int) (*slack.File, []slack.Comment, *slack.Paging, error) {
-	return p.next().GetFileInfoContext(ctx, fileID, count, page)
+func (p *Pool) GetEmojiContext(ctx context.Context) (emoji map[string]string, err error) {
+	err = p.do(network.Tier2, func(cl Slack) error {
+		var err error
+		emoji, err = cl.GetEmojiContext(ctx)
+		return err
+	})
+	return
 }
 
-func (p *Pool) GetUserInfoContext(ctx context.Context, user string) (*slack.User, error) {
-	return p.next().GetUserInfoContext(ctx, user)
+func (p *Pool) SearchMessagesContext(ctx context.Context, query string, params slack.SearchParameters) (sm *slack.SearchMessages, err error) {
+	err = p.do(network.Tier2, func(cl Slack) error {
+		var err error
+		sm, err = cl.SearchMessagesContext(ctx, query, params)
+		return err
+	})
+	return
 }
 
-func (w *Pool) GetUserProfileContext(ctx context.Context, params *slack.GetUserProfileParameters) (*slack.UserProfile, error) {
-	return w.next().GetUserProfileContext(ctx, params)
+func (p *Pool) SearchFilesContext(ctx context.Context, query string, params slack.SearchParameters) (sf *slack.SearchFiles, err error) {
+	err = p.do(network.Tier2, func(cl Slack) error {
+		var err error
+		sf, err = cl.SearchFilesContext(ctx, query, params)
+		return err
+	})
+	return
+}
+
+func (p *Pool) GetFileInfoContext(ctx context.Context, fileID string, count int, page int) (file *slack.File, comments []slack.Comment, paging *slack.Paging, err error) {
+	err = p.do(network.Tier4, func(cl Slack) error {
+		var err error
+		file, comments, paging, err = cl.GetFileInfoContext(ctx, fileID, count, page)
+		return err
+	})
+	return
+}
+
+func (p *Pool) GetFilesContext(ctx context.Context, params slack.GetFilesParameters) (files []slack.File, paging *slack.Paging, err error) {
+	err = p.do(network.Tier3, func(cl Slack) error {
+		var err error
+		files, paging, err = cl.GetFilesContext(ctx, params)
+		return err
+	})
+	return
+}
+
+func (p *Pool) GetUserInfoContext(ctx context.Context, user string) (u *slack.User, err error) {
+	err = p.do(network.Tier4, func(cl Slack) error {
+		var err error
+		u, err = cl.GetUserInfoContext(ctx, user)
+		return err
+	})
+	return
+}
+
+func (p *Pool) GetUserProfileContext(ctx context.Context, params *slack.GetUserProfileParameters) (profile *slack.UserProfile, err error) {
+	err = p.do(network.Tier4, func(cl Slack) error {
+		var err error
+		profile, err = cl.GetUserProfileContext(ctx, params)
+		return err
+	})
+	return
+}
+
+// ---------------------------------------------------------------------------
+// Edge-aware methods
+// ---------------------------------------------------------------------------
+
+// GetConversationsContextEx calls the extended method on the next client, if
+// it supports it, otherwise it returns [ErrOpNotSupported].
+func (p *Pool) GetConversationsContextEx(ctx context.Context, params *slack.GetConversationsParameters, onlyMy bool) (channels []slack.Channel, nextCursor string, err error) {
+	err = p.do(network.Tier2, func(cl Slack) error {
+		ex, ok := cl.(interface {
+			GetConversationsContextEx(ctx context.Context, params *slack.GetConversationsParameters, onlyMy bool) ([]slack.Channel, string, error)
+		})
+		if !ok {
+			return ErrOpNotSupported
+		}
+		var err error
+		channels, nextCursor, err = ex.GetConversationsContextEx(ctx, params, onlyMy)
+		return err
+	})
+	return
+}
+
+// AdminEmojiList returns the custom emoji using the edge API of the next
+// client, if it supports it, otherwise it yields [ErrOpNotSupported].  The
+// listing is repeated with another client only if no page was yielded yet.
+func (p *Pool) AdminEmojiList(ctx context.Context) iter.Seq2[edge.EmojiResult, error] {
+	return func(yield func(edge.EmojiResult, error) bool) {
+		var (
+			yielded bool
+			stopped bool
+		)
+		err := p.doUntil(network.Tier2, func() bool { return yielded }, func(cl Slack) error {
+			el, ok := cl.(interface {
+				AdminEmojiList(ctx context.Context) iter.Seq2[edge.EmojiResult, error]
+			})
+			if !ok {
+				return ErrOpNotSupported
+			}
+			for res, err := range el.AdminEmojiList(ctx) {
+				if err != nil {
+					return err
+				}
+				yielded = true
+				if !yield(res, nil) {
+					stopped = true
+					return nil
+				}
+			}
+			return nil
+		})
+		if err != nil && !stopped {
+			yield(edge.EmojiResult{}, err)
+		}
+	}
 }
@@ -16,15 +16,23 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"iter"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/rusq/slack"
 	"go.uber.org/mock/gomock"
 
 	"github.com/rusq/slackdump/v4/internal/client/mock_client"
+	"github.com/rusq/slackdump/v4/internal/edge"
+	"github.com/rusq/slackdump/v4/internal/structures"
 )
 
 func TestPool_next(t *testing.T) {
@@ -191,3 +199,237 @@ func TestPool_AuthTestContext(t *testing.T) {
 		})
 	}
 }
+
+func TestPool_rateLimitedFailover(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mc0, mc1 := mock_client.NewMockSlack(ctrl), mock_client.NewMockSlack(ctrl)
+	p, err := NewPoolWithOptions([]Slack{mc0, mc1}, WithNames("one", "two"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gomock.InOrder(
+		mc0.EXPECT().GetEmojiContext(gomock.Any()).Return(nil, &slack.RateLimitedError{RetryAfter: time.Minute}),
+		mc1.EXPECT().GetEmojiContext(gomock.Any()).Return(map[string]string{"a": "b"}, nil),
+		// client 0 is still limited on Tier2, so client 1 is used again.
+		mc1.EXPECT().GetEmojiContext(gomock.Any()).Return(map[string]string{"a": "b"}, nil),
+		// but it is available on the other tiers.
+		mc0.EXPECT().GetUserInfoContext(gomock.Any(), "U1").Return(&slack.User{ID: "U1"}, nil),
+	)
+	for range 2 {
+		if _, err := p.GetEmojiContext(t.Context()); err != nil {
+			t.Fatalf("Pool.GetEmojiContext() error = %v", err)
+		}
+	}
+	if _, err := p.GetUserInfoContext(t.Context(), "U1"); err != nil {
+		t.Fatalf("Pool.GetUserInfoContext() error = %v", err)
+	}
+
+	want := []ClientStats{
+		{Name: "one", Calls: 2, Errors: 1, RateLimited: 1},
+		{Name: "two", Calls: 2},
+	}
+	got := p.Stats()
+	got[0].LastLimited = time.Time{}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Pool.Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPool_evictsRevoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mc0, mc1 := mock_client.NewMockSlack(ctrl), mock_client.NewMockSlack(ctrl)
+	p := NewPool(mc0, mc1)
+
+	errRevoked := slack.SlackErrorResponse{Err: "token_revoked"}
+	gomock.InOrder(
+		mc0.EXPECT().GetConversationInfoContext(gomock.Any(), gomock.Any()).Return(nil, errRevoked),
+		mc1.EXPECT().GetConversationInfoContext(gomock.Any(), gomock.Any()).Return(&slack.Channel{}, nil),
+		mc1.EXPECT().GetConversationInfoContext(gomock.Any(), gomock.Any()).Return(nil, errRevoked),
+	)
+	if _, err := p.GetConversationInfoContext(t.Context(), &slack.GetConversationInfoInput{ChannelID: "C1"}); err != nil {
+		t.Fatalf("Pool.GetConversationInfoContext() error = %v", err)
+	}
+	// the last client is not evicted, the error is returned to the caller.
+	if _, err := p.GetConversationInfoContext(t.Context(), &slack.GetConversationInfoInput{ChannelID: "C1"}); !structures.IsAuthError(err) {
+		t.Fatalf("Pool.GetConversationInfoContext() error = %v, want %v", err, errRevoked)
+	}
+	stats := p.Stats()
+	if !stats[0].Evicted || stats[0].EvictReason != "token_revoked" {
+		t.Errorf("client 0 should be evicted, got %+v", stats[0])
+	}
+	if stats[1].Evicted {
+		t.Errorf("last client should not be evicted, got %+v", stats[1])
+	}
+}
+
+func TestPool_OpenFileRange(t *testing.T) {
+	t.Run("unsupported by the client", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		p := NewPool(mock_client.NewMockSlack(ctrl))
+		if _, err := p.OpenFileRange(t.Context(), "https://files.slack.com/x", 0, ""); !errors.Is(err, errors.ErrUnsupported) {
+			t.Errorf("Pool.OpenFileRange() error = %v, want %v", err, errors.ErrUnsupported)
+		}
+	})
+	t.Run("wrapped client without http client", func(t *testing.T) {
+		p := NewPool(Wrap(slack.New("xoxb-test")))
+		if _, err := p.OpenFileRange(t.Context(), "https://files.slack.com/x", 0, ""); !errors.Is(err, errors.ErrUnsupported) {
+			t.Errorf("Pool.OpenFileRange() error = %v, want %v", err, errors.ErrUnsupported)
+		}
+	})
+}
+
+func TestPool_GetFileContext(t *testing.T) {
+	errLimited := &slack.RateLimitedError{RetryAfter: time.Minute}
+	t.Run("retries before the first write", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mc0, mc1 := mock_client.NewMockSlack(ctrl), mock_client.NewMockSlack(ctrl)
+		p := NewPool(mc0, mc1)
+		gomock.InOrder(
+			mc0.EXPECT().GetFileContext(gomock.Any(), "https://files.slack.com/x", gomock.Any()).Return(errLimited),
+			mc1.EXPECT().GetFileContext(gomock.Any(), "https://files.slack.com/x", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, w io.Writer) error {
+				_, err := io.WriteString(w, "data")
+				return err
+			}),
+		)
+		var buf bytes.Buffer
+		if err := p.GetFileContext(t.Context(), "https://files.slack.com/x", &buf); err != nil {
+			t.Fatalf("Pool.GetFileContext() error = %v", err)
+		}
+		if buf.String() != "data" {
+			t.Errorf("Pool.GetFileContext() wrote %q, want %q", buf.String(), "data")
+		}
+	})
+	t.Run("does not retry after a partial write", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mc0, mc1 := mock_client.NewMockSlack(ctrl), mock_client.NewMockSlack(ctrl)
+		p := NewPool(mc0, mc1)
+		mc0.EXPECT().GetFileContext(gomock.Any(), "https://files.slack.com/x", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, w io.Writer) error {
+			io.WriteString(w, "da")
+			return errLimited
+		})
+		var buf bytes.Buffer
+		if err := p.GetFileContext(t.Context(), "https://files.slack.com/x", &buf); !errors.As(err, &errLimited) {
+			t.Fatalf("Pool.GetFileContext() error = %v, want %v", err, errLimited)
+		}
+		if buf.String() != "da" {
+			t.Errorf("Pool.GetFileContext() wrote %q, want %q", buf.String(), "da")
+		}
+		if st := p.Stats(); st[0].RateLimited != 1 {
+			t.Errorf("client 0 stats = %+v, want it rate limited", st[0])
+		}
+	})
+}
+
+func TestPool_GetUsersPage(t *testing.T) {
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer limited.Close()
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.FormValue("cursor") == "" {
+			io.WriteString(w, `{"ok":true,"members":[{"id":"U1"}],"response_metadata":{"next_cursor":"c2"}}`)
+			return
+		}
+		io.WriteString(w, `{"ok":true,"members":[{"id":"U2"}],"response_metadata":{"next_cursor":""}}`)
+	}))
+	defer ok.Close()
+
+	p := NewPool(
+		Wrap(slack.New("xoxb-test", slack.OptionAPIURL(limited.URL+"/"))),
+		Wrap(slack.New("xoxb-test", slack.OptionAPIURL(ok.URL+"/"))),
+	)
+	users, cursor, err := p.GetUsersPage(t.Context(), "")
+	if err != nil {
+		t.Fatalf("Pool.GetUsersPage() error = %v", err)
+	}
+	if len(users) != 1 || users[0].ID != "U1" || cursor != "c2" {
+		t.Errorf("Pool.GetUsersPage() = %v, %q, want U1, %q", users, cursor, "c2")
+	}
+	users, cursor, err = p.GetUsersPage(t.Context(), cursor)
+	if err != nil {
+		t.Fatalf("Pool.GetUsersPage() error = %v", err)
+	}
+	if len(users) != 1 || users[0].ID != "U2" || cursor != "" {
+		t.Errorf("Pool.GetUsersPage() = %v, %q, want U2 and no cursor", users, cursor)
+	}
+	st := p.Stats()
+	if st[0].Calls != 1 || st[0].RateLimited != 1 || st[1].Calls != 2 {
+		t.Errorf("Pool.Stats() = %+v, want 1 rate limited call and 2 calls", st)
+	}
+}
+
+// emojiClient is the client that lists the emoji with the function fn.
+type emojiClient struct {
+	*mock_client.MockSlack
+	fn func(yield func(edge.EmojiResult, error) bool)
+}
+
+func (c emojiClient) AdminEmojiList(context.Context) iter.Seq2[edge.EmojiResult, error] {
+	return c.fn
+}
+
+func TestPool_AdminEmojiList(t *testing.T) {
+	errRevoked := slack.SlackErrorResponse{Err: "token_revoked"}
+	page := func(name string) edge.EmojiResult {
+		return edge.EmojiResult{Emoji: []edge.Emoji{{Name: name}}}
+	}
+	collect := func(p *Pool) ([]string, error) {
+		var names []string
+		for res, err := range p.AdminEmojiList(t.Context()) {
+			if err != nil {
+				return names, err
+			}
+			for _, e := range res.Emoji {
+				names = append(names, e.Name)
+			}
+		}
+		return names, nil
+	}
+
+	t.Run("evicts the revoked client before the first page", func(t *testing.T) {
+		p := NewPool(
+			emojiClient{fn: func(yield func(edge.EmojiResult, error) bool) { yield(edge.EmojiResult{}, errRevoked) }},
+			emojiClient{fn: func(yield func(edge.EmojiResult, error) bool) { _ = yield(page("a"), nil) && yield(page("b"), nil) }},
+		)
+		names, err := collect(p)
+		if err != nil {
+			t.Fatalf("Pool.AdminEmojiList() error = %v", err)
+		}
+		if !reflect.DeepEqual(names, []string{"a", "b"}) {
+			t.Errorf("Pool.AdminEmojiList() = %v, want [a b]", names)
+		}
+		if st := p.Stats(); !st[0].Evicted || st[1].Calls != 1 {
+			t.Errorf("Pool.Stats() = %+v, want client 0 evicted", st)
+		}
+	})
+	t.Run("does not repeat the yielded pages", func(t *testing.T) {
+		p := NewPool(
+			emojiClient{fn: func(yield func(edge.EmojiResult, error) bool) {
+				_ = yield(page("a"), nil) && yield(edge.EmojiResult{}, errRevoked)
+			}},
+			emojiClient{fn: func(yield func(edge.EmojiResult, error) bool) { t.Error("unexpected call") }},
+		)
+		names, err := collect(p)
+		if !structures.IsAuthError(err) {
+			t.Fatalf("Pool.AdminEmojiList() error = %v, want %v", err, errRevoked)
+		}
+		if !reflect.DeepEqual(names, []string{"a"}) {
+			t.Errorf("Pool.AdminEmojiList() = %v, want [a]", names)
+		}
+		if st := p.Stats(); !st[0].Evicted {
+			t.Errorf("Pool.Stats() = %+v, want client 0 evicted", st)
+		}
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		p := NewPool(mock_client.NewMockSlack(gomock.NewController(t)))
+		if _, err := collect(p); !errors.Is(err, ErrOpNotSupported) {
+			t.Errorf("Pool.AdminEmojiList() error = %v, want %v", err, ErrOpNotSupported)
+		}
+	})
+}
@@ -15,11 +15,45 @@
 
 package client
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/rusq/slackdump/v4/internal/network"
+)
+
+// Strategy is the client selection strategy of the [Pool].
+type Strategy string
+
+const (
+	// StrategyRoundRobin selects the clients in turns.
+	StrategyRoundRobin Strategy = "round-robin"
+	// StrategyLeastLimited selects the client that was rate limited least
+	// recently.
+	StrategyLeastLimited Strategy = "least-limited"
+)
+
+// Strategies lists all supported strategies.
+var Strategies = []Strategy{StrategyRoundRobin, StrategyLeastLimited}
+
 // strategy is an interface that defines the strategy for selecting the next
 // item.
 type strategy interface {
-	// next returns the next item in the pool.
-	next() int
+	// next returns the next item in the pool for the API tier.  It should
+	// select among the items that are available at the time now, and if there
+	// are none, return the item that recovers first.
+	next(h *health, tier network.Tier, now time.Time) int
+}
+
+func newStrategy(s Strategy, total int) (strategy, error) {
+	switch s {
+	case StrategyRoundRobin, "":
+		return newRoundRobin(total), nil
+	case StrategyLeastLimited:
+		return leastLimited{}, nil
+	default:
+		return nil, fmt.Errorf("unknown pool strategy: %q", s)
+	}
 }
 
 // roundRobin implements the round-robin strategy.
@@ -36,7 +70,41 @@ func newRoundRobin(total int) *roundRobin {
 	return &roundRobin{total: total, i: total - 1}
 }
 
-func (r *roundRobin) next() int {
-	r.i = (r.i + 1) % r.total
+func (r *roundRobin) next(h *health, tier network.Tier, now time.Time) int {
+	for range r.total {
+		r.i = (r.i + 1) % r.total
+		if h.available(r.i, tier, now) {
+			return r.i
+		}
+	}
+	r.i = h.soonest(tier)
 	return r.i
 }
+
+// leastLimited implements the least-recently-rate-limited strategy.  The
+// clients that were never rate limited are preferred, the ties are broken by
+// the number of calls.
+type leastLimited struct{}
+
+func (leastLimited) next(h *health, tier network.Tier, now time.Time) int {
+	best := -1
+	for i := range h.stats {
+		if !h.available(i, tier, now) {
+			continue
+		}
+		if best == -1 || lessLimited(h.stats[i], h.stats[best]) {
+			best = i
+		}
+	}
+	if best == -1 {
+		return h.soonest(tier)
+	}
+	return best
+}
+
+func lessLimited(a, b ClientStats) bool {
+	if !a.LastLimited.Equal(b.LastLimited) {
+		return a.LastLimited.Before(b.LastLimited)
+	}
+	return a.Calls < b.Calls
+}
@@ -16,7 +16,13 @@
 package client
 
 import (
+	"slices"
 	"testing"
+	"time"
+
+	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/internal/network"
 )
 
 func Test_roundRobin_next(t *testing.T) {
@@ -66,9 +72,62 @@ func Test_roundRobin_next(t *testing.T) {
 				total: tt.fields.total,
 				i:     tt.fields.i,
 			}
-			if got := r.next(); got != tt.want {
+			if got := r.next(newHealth(tt.fields.total), network.Tier3, time.Now()); got != tt.want {
 				t.Errorf("roundRobin.next() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_roundRobin_next_skipsLimited(t *testing.T) {
+	now := time.Now()
+	h := newHealth(3)
+	h.record(1, network.Tier3, now, &slack.RateLimitedError{RetryAfter: time.Minute})
+
+	r := newRoundRobin(3)
+	var got []int
+	for range 4 {
+		got = append(got, r.next(h, network.Tier3, now))
+	}
+	if want := []int{0, 2, 0, 2}; !slices.Equal(got, want) {
+		t.Errorf("roundRobin.next() sequence = %v, want %v", got, want)
+	}
+	// other tiers are not affected
+	if !h.available(1, network.Tier2, now) {
+		t.Error("client rate limited on Tier3 should be available on Tier2")
+	}
+	// all limited: returns the one that recovers first
+	h.record(0, network.Tier3, now, &slack.RateLimitedError{RetryAfter: 2 * time.Minute})
+	h.record(2, network.Tier3, now, &slack.RateLimitedError{RetryAfter: 3 * time.Minute})
+	if got := r.next(h, network.Tier3, now); got != 1 {
+		t.Errorf("roundRobin.next() all limited = %v, want 1", got)
+	}
+}
+
+func Test_leastLimited_next(t *testing.T) {
+	now := time.Now()
+	h := newHealth(3)
+	h.record(0, network.Tier3, now.Add(-2*time.Minute), &slack.RateLimitedError{RetryAfter: time.Second})
+	h.record(1, network.Tier3, now.Add(-time.Minute), &slack.RateLimitedError{RetryAfter: time.Second})
+	h.record(2, network.Tier3, now, nil)
+
+	var s leastLimited
+	if got := s.next(h, network.Tier3, now); got != 2 {
+		t.Errorf("leastLimited.next() = %v, want 2 (never limited)", got)
+	}
+	h.record(2, network.Tier3, now, &slack.RateLimitedError{RetryAfter: time.Minute})
+	if got := s.next(h, network.Tier3, now); got != 0 {
+		t.Errorf("leastLimited.next() = %v, want 0 (limited least recently)", got)
+	}
+}
+
+func Test_newStrategy(t *testing.T) {
+	for _, s := range append(Strategies, "") {
+		if _, err := newStrategy(s, 2); err != nil {
+			t.Errorf("newStrategy(%q) error = %v", s, err)
+		}
+	}
+	if _, err := newStrategy("random", 2); err == nil {
+		t.Error("newStrategy() expected an error for unknown strategy")
+	}
+}
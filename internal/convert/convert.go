@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/rusq/slackdump/v4/internal/nametmpl"
 	"github.com/rusq/slackdump/v4/internal/structures"
 	"github.com/rusq/slackdump/v4/source"
 
@@ -60,6 +61,10 @@ type options struct {
 	avtrFileLoc func(*slack.User) string
 	// dmMode controls how single-member IMs are serialized into dms.json.
 	dmMode structures.DMMode
+	// period is the period of messages in each export message file.
+	period source.ExportPeriod
+	// namer names the channel directories of the export conversion.
+	namer *nametmpl.ChannelNamer
 	// lg is the logger
 	lg *slog.Logger
 }
@@ -111,6 +116,24 @@ func WithDMMode(mode structures.DMMode) Option {
 	}
 }
 
+// WithExportPeriod sets the period of messages in each message file of the
+// export conversion.
+func WithExportPeriod(p source.ExportPeriod) Option {
+	return func(c *options) {
+		if p != "" {
+			c.period = p
+		}
+	}
+}
+
+// WithChannelNamer sets the channel namer, that names the channel
+// directories of the export conversion.
+func WithChannelNamer(n *nametmpl.ChannelNamer) Option {
+	return func(c *options) {
+		c.namer = n
+	}
+}
+
 func (o *options) Validate() error {
 	const format = "convert: internal error: %s: %w"
 	if o.includeFiles {
@@ -70,6 +70,7 @@ func NewToExport(src source.Sourcer, trg fsadapter.FS, opt ...Option) *ToExport
 			includeFiles:   false,
 			includeAvatars: false,
 			dmMode:         structures.DMSingle,
+			period:         source.PeriodDay,
 			trgFileLoc:     source.MattermostFilepath,
 			avtrFileLoc:    fileproc.AvatarPath,
 			lg:             slog.Default(),
@@ -130,9 +131,14 @@ func (c *ToExport) Convert(ctx context.Context) error {
 		return err
 	}
 
+	if c.opts.namer != nil {
+		c.opts.namer.SetUsers(users)
+	}
 	tfopts := []transform.ExpCvtOption{
+		transform.ExpWithChannelNamer(c.opts.namer),
 		transform.ExpWithUsers(users),
 		transform.ExpWithDMMode(c.opts.dmMode),
+		transform.ExpWithPeriod(c.opts.period),
 	}
 	// 1. generator
 	chC := sliceToChan(channels)
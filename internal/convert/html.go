@@ -30,6 +30,8 @@ import (
 	"github.com/rusq/fsadapter"
 	"github.com/rusq/slack"
 
+	"github.com/rusq/slackdump/v4/internal/avatar"
+	"github.com/rusq/slackdump/v4/internal/nametmpl"
 	"github.com/rusq/slackdump/v4/internal/structures"
 	"github.com/rusq/slackdump/v4/internal/viewer"
 	"github.com/rusq/slackdump/v4/internal/viewer/renderer"
@@ -37,9 +39,10 @@ import (
 )
 
 type HTMLConverter struct {
-	src source.Sourcer
-	trg fsadapter.FS
-	lg  *slog.Logger
+	src   source.Sourcer
+	trg   fsadapter.FS
+	namer *nametmpl.ChannelNamer // names the channel page directories
+	lg    *slog.Logger
 }
 
 func NewToHTML(src source.Sourcer, trg fsadapter.FS, opts ...Option) *HTMLConverter {
@@ -55,6 +58,7 @@ func NewToHTML(src source.Sourcer, trg fsadapter.FS, opts ...Option) *HTMLConver
 	if cfg.lg != nil {
 		c.lg = cfg.lg
 	}
+	c.namer = cfg.namer
 	return c
 }
 
@@ -70,23 +74,29 @@ func (c *HTMLConverter) Convert(ctx context.Context) error {
 		return err
 	}
 
-	v, err := viewer.New(ctx, "", c.src, viewer.WithMode(renderer.ModeStatic))
+	channels, err := c.src.Channels(ctx)
 	if err != nil {
 		return err
 	}
-
-	if err := c.renderPage(ctx, v.RenderIndex, "index.html"); err != nil {
-		return fmt.Errorf("index: %w", err)
+	chanDir, err := c.channelDirs(ctx, channels)
+	if err != nil {
+		return err
 	}
 
-	channels, err := c.src.Channels(ctx)
+	v, err := viewer.New(ctx, "", c.src, viewer.WithMode(renderer.ModeStatic), viewer.WithChannelDir(chanDir))
 	if err != nil {
 		return err
 	}
+
+	if err := c.renderPage(ctx, v.RenderIndex, "index.html"); err != nil {
+		return fmt.Errorf("index: %w", err)
+	}
+
 	for _, ch := range channels {
+		dir := chanDir(ch.ID)
 		if err := c.renderPage(ctx, func(ctx context.Context, w io.Writer) error {
 			return v.RenderChannel(ctx, ch.ID, w)
-		}, channelPagePath(ch.ID)); err != nil {
+		}, channelPagePath(dir)); err != nil {
 			return fmt.Errorf("channel %s: %w", ch.ID, err)
 		}
 
@@ -97,7 +107,7 @@ func (c *HTMLConverter) Convert(ctx context.Context) error {
 		for _, threadTS := range threadRoots {
 			if err := c.renderPage(ctx, func(ctx context.Context, w io.Writer) error {
 				return v.RenderThread(ctx, ch.ID, threadTS, w)
-			}, threadPagePath(ch.ID, threadTS)); err != nil {
+			}, threadPagePath(dir, threadTS)); err != nil {
 				return fmt.Errorf("channel %s thread %s: %w", ch.ID, threadTS, err)
 			}
 		}
@@ -105,12 +115,12 @@ func (c *HTMLConverter) Convert(ctx context.Context) error {
 		if ch.Properties != nil && ch.Properties.Canvas.FileId != "" {
 			if err := c.renderPage(ctx, func(ctx context.Context, w io.Writer) error {
 				return v.RenderCanvas(ctx, ch.ID, w)
-			}, canvasPagePath(ch.ID)); err != nil {
+			}, canvasPagePath(dir)); err != nil {
 				return fmt.Errorf("channel %s canvas: %w", ch.ID, err)
 			}
 			if err := c.renderRaw(ctx, func(ctx context.Context, w io.Writer) error {
 				return v.RenderCanvasContent(ctx, ch.ID, w)
-			}, canvasContentPath(ch.ID)); err != nil && !errors.Is(err, source.ErrNotFound) && !errors.Is(err, fs.ErrNotExist) {
+			}, canvasContentPath(dir)); err != nil && !errors.Is(err, source.ErrNotFound) && !errors.Is(err, fs.ErrNotExist) {
 				return fmt.Errorf("channel %s canvas content: %w", ch.ID, err)
 			}
 		}
@@ -137,6 +147,12 @@ func (c *HTMLConverter) Convert(ctx context.Context) error {
 	if err := c.copyAvatars(users); err != nil {
 		return fmt.Errorf("avatars: %w", err)
 	}
+	if err := c.copyEmojis(ctx); err != nil {
+		return fmt.Errorf("emoji: %w", err)
+	}
+	if err := c.copyLinks(); err != nil {
+		return fmt.Errorf("links: %w", err)
+	}
 	if err := c.copyStaticAssets(); err != nil {
 		return fmt.Errorf("static assets: %w", err)
 	}
@@ -223,9 +239,83 @@ func (c *HTMLConverter) copyAvatars(users []slack.User) error {
 			return err
 		}
 	}
+	return c.copyAvatarStore()
+}
+
+// copyAvatarStore copies the avatar store, so that the messages can show the
+// avatar that the user had at the time of the message.
+func (c *HTMLConverter) copyAvatarStore() error {
+	fsys := c.src.Avatars().FS()
+	if _, err := fs.Stat(fsys, avatar.StoreDir); err != nil {
+		return nil // no store
+	}
+	return fs.WalkDir(fsys, avatar.StoreDir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return copy2trg(c.trg, htmlAvatarPath(avatar.StoreDir, path.Base(name)), fsys, name)
+	})
+}
+
+// copyEmojis copies the downloaded custom emoji images, if the source has
+// any.
+func (c *HTMLConverter) copyEmojis(ctx context.Context) error {
+	es, ok := c.src.(source.Emojier)
+	if !ok || es.EmojiStorage().Type() == source.STnone {
+		return nil
+	}
+	ee, err := es.Emojis(ctx)
+	if err != nil {
+		if errors.Is(err, source.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	for _, em := range ee {
+		if em.IsAlias != 0 || em.URL == "" {
+			continue
+		}
+		srcPath, err := es.EmojiStorage().File(source.EmojiParams(&em))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		if err := copy2trg(c.trg, htmlEmojiPath(srcPath), es.EmojiStorage().FS(), srcPath); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				c.lg.Warn("skipping missing emoji asset", "emoji", em.Name, "error", err)
+				continue
+			}
+			return err
+		}
+	}
 	return nil
 }
 
+// copyLinks copies the archived copies of the external links.  The pages are
+// saved as text, see [renderer.StaticLinkName].
+func (c *HTMLConverter) copyLinks() error {
+	ls, ok := c.src.(source.Linker)
+	if !ok || ls.LinkStorage().Type() == source.STnone {
+		return nil
+	}
+	fsys := ls.LinkStorage().FS()
+	return fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		dst := path.Join(path.Dir(name), renderer.StaticLinkName(path.Base(name)))
+		return copy2trg(c.trg, htmlLinkPath(dst), fsys, name)
+	})
+}
+
 func (c *HTMLConverter) copyStaticAssets() error {
 	staticFS := viewer.StaticFS()
 	return fs.WalkDir(staticFS, ".", func(name string, d fs.DirEntry, err error) error {
@@ -298,20 +388,47 @@ func relativePrefix(outputPath string) string {
 	return strings.Repeat("../", strings.Count(dir, "/")+1)
 }
 
-func channelPagePath(channelID string) string {
-	return path.Join("archives", channelID, "index.html")
+// channelDirs names the page directories of all channels, and returns the
+// function that returns the directory for the channel ID.  Without the
+// channel namer, the directories are named after the channel IDs.
+func (c *HTMLConverter) channelDirs(ctx context.Context, channels []slack.Channel) (func(id string) string, error) {
+	if c.namer == nil {
+		return func(id string) string { return id }, nil
+	}
+	if users, err := c.src.Users(ctx); err == nil {
+		c.namer.SetUsers(users)
+	}
+	if wi, err := c.src.WorkspaceInfo(ctx); err == nil && wi != nil {
+		c.namer.SetWorkspace(wi.Team)
+	}
+	for i := range channels {
+		if _, err := c.namer.Dir(&channels[i]); err != nil {
+			return nil, fmt.Errorf("channel %s: %w", channels[i].ID, err)
+		}
+	}
+	dirs := c.namer.Dirs()
+	return func(id string) string {
+		if dir, ok := dirs[id]; ok {
+			return dir
+		}
+		return id
+	}, nil
+}
+
+func channelPagePath(dir string) string {
+	return path.Join("archives", dir, "index.html")
 }
 
-func threadPagePath(channelID, threadTS string) string {
-	return path.Join("archives", channelID, "threads", threadTS+".html")
+func threadPagePath(dir, threadTS string) string {
+	return path.Join("archives", dir, "threads", threadTS+".html")
 }
 
-func canvasPagePath(channelID string) string {
-	return path.Join("archives", channelID, "canvas", "index.html")
+func canvasPagePath(dir string) string {
+	return path.Join("archives", dir, "canvas", "index.html")
 }
 
-func canvasContentPath(channelID string) string {
-	return path.Join("archives", channelID, "canvas", "content.html")
+func canvasContentPath(dir string) string {
+	return path.Join("archives", dir, "canvas", "content.html")
 }
 
 func userPagePath(userID string) string {
@@ -326,6 +443,14 @@ func htmlAvatarPath(userID, filename string) string {
 	return path.Join("avatars", userID, filename)
 }
 
+func htmlEmojiPath(filename string) string {
+	return path.Join("emoji", filename)
+}
+
+func htmlLinkPath(name string) string {
+	return path.Join("links", name)
+}
+
 func htmlStaticAssetPath(name string) string {
 	return path.Join("static", name)
 }
@@ -7,6 +7,7 @@ import (
 	"iter"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"testing"
 	"testing/fstest"
@@ -16,6 +17,7 @@ import (
 	"github.com/rusq/slack"
 
 	"github.com/rusq/slackdump/v4/source"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 func TestHTMLConverter_Convert(t *testing.T) {
@@ -224,6 +226,38 @@ func TestHTMLConverter_Convert(t *testing.T) {
 	})
 }
 
+// htmlLinkerStub is the source with the archived links.
+type htmlLinkerStub struct {
+	*htmlSourceStub
+	links source.Storage
+}
+
+func (s htmlLinkerStub) LinkStorage() source.Storage { return s.links }
+
+func TestHTMLConverter_copyLinks(t *testing.T) {
+	links, err := source.NewLinkStorage(fstest.MapFS{
+		"__links/k1/index.html": {Data: []byte("<script>alert(1)</script>")},
+		"__links/k2/report.pdf": {Data: []byte("%PDF-1.4")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	outDir := t.TempDir()
+	conv := NewToHTML(htmlLinkerStub{htmlSourceStub: &htmlSourceStub{}, links: links}, fsadapter.NewDirectory(outDir))
+	if err := conv.copyLinks(); err != nil {
+		t.Fatalf("copyLinks() error = %v", err)
+	}
+	for _, name := range []string{"links/k1/index.html.txt", "links/k2/report.pdf"} {
+		if _, err := os.Stat(filepath.Join(outDir, name)); err != nil {
+			fatalTree(t, outDir)
+			t.Fatalf("missing %s: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "links", "k1", "index.html")); !os.IsNotExist(err) {
+		t.Errorf("archived page must not be saved as HTML, stat error: %v", err)
+	}
+}
+
 func TestRelativePrefix(t *testing.T) {
 	tests := []struct {
 		path string
@@ -305,6 +339,10 @@ func (s *htmlSourceStub) ChannelInfo(_ context.Context, channelID string) (*slac
 	}
 	return nil, source.ErrNotFound
 }
+
+func (s *htmlSourceStub) ChannelMembership(context.Context, string) ([]types.MembershipEvent, error) {
+	return nil, source.ErrNotFound
+}
 func (s *htmlSourceStub) Files() source.Storage { return s.files }
 func (s *htmlSourceStub) Avatars() source.Storage {
 	if s.avatars == nil {
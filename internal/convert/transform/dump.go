@@ -23,6 +23,7 @@ import (
 	"log/slog"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/rusq/slackdump/v4/source"
 
@@ -36,11 +37,8 @@ import (
 
 type DumpOption func(*DumpConverter)
 
-type Templater interface {
-	Execute(c *types.Conversation) string
-}
-
-func DumpWithTemplate(tmpl Templater) DumpOption {
+// DumpWithTemplate sets the file naming template.
+func DumpWithTemplate(tmpl *nametmpl.Template) DumpOption {
 	return func(s *DumpConverter) {
 		s.tmpl = tmpl
 	}
@@ -93,11 +91,17 @@ func (p pipeline) apply(channelID, threadTS string, mm []slack.Message) error {
 
 // DumpConverter is a converter of chunk files into the Slackdump format.
 type DumpConverter struct {
-	src      source.Sourcer // source of the data
-	fsa      fsadapter.FS   // output file system adapter
-	tmpl     Templater      // file name template
-	lg       *slog.Logger   // logger
-	pipeline []pipelineFunc // pipeline filter functions
+	src      source.Sourcer     // source of the data
+	fsa      fsadapter.FS       // output file system adapter
+	tmpl     *nametmpl.Template // file name template
+	names    nametmpl.Namer     // guards against file name collisions
+	lg       *slog.Logger       // logger
+	pipeline []pipelineFunc     // pipeline filter functions
+
+	metaOnce  sync.Once
+	uidx      structures.UserIndex     // users, for the DM member names
+	workspace string                   // workspace name
+	resolver  *structures.NameResolver // resolves the DM names
 }
 
 // Convert converts the chunk file to Slackdump json format.
@@ -120,22 +124,60 @@ func (s *DumpConverter) Convert(ctx context.Context, channelID, threadID string)
 	if err != nil {
 		return err
 	}
+	s.loadMeta(ctx)
 	conv := &types.Conversation{
 		ID:       ci.ID,
 		Name:     ci.Name,
 		ThreadTS: threadID,
 		Messages: msgs,
 	}
+	if conv.Name == "" && ci.IsIM {
+		// DMs have no name, name them after the other participant.
+		conv.Name = s.resolver.Name(*ci)
+	}
 
-	f, err := s.fsa.Create(s.tmpl.Execute(conv))
+	name, err := s.filename(ctx, ci, conv)
+	if err != nil {
+		return err
+	}
+	f, err := s.fsa.Create(name)
 	if err != nil {
-		name := s.tmpl.Execute(conv)
 		return fmt.Errorf("fsadapter: unable to create file %s: %w", name, err)
 	}
 	defer f.Close()
 	return json.NewEncoder(f).Encode(conv)
 }
 
+// loadMeta loads the users and the workspace information once.
+func (s *DumpConverter) loadMeta(ctx context.Context) {
+	s.metaOnce.Do(func() {
+		if uu, err := s.src.Users(ctx); err == nil {
+			s.uidx = structures.NewUserIndex(uu)
+		}
+		if wi, err := s.src.WorkspaceInfo(ctx); err == nil && wi != nil {
+			s.workspace = wi.Team
+		}
+		r, err := source.NameResolver(ctx, s.src)
+		if err != nil {
+			r = structures.NewNameResolver(s.uidx, "", nil)
+		}
+		s.resolver = r
+	})
+}
+
+// filename returns the name of the output file for the conversation.
+func (s *DumpConverter) filename(ctx context.Context, ci *slack.Channel, conv *types.Conversation) (string, error) {
+	s.loadMeta(ctx)
+	tc := nametmpl.ChannelContext(ci, s.uidx, s.workspace)
+	cc := nametmpl.ConversationContext(conv)
+	tc.ThreadTS, tc.From, tc.To = cc.ThreadTS, cc.From, cc.To
+	name, err := s.names.Name(s.tmpl, tc)
+	if err != nil {
+		return "", fmt.Errorf("file name: %w", err)
+	}
+	return name, nil
+}
+
 func collect[T any](it iter.Seq2[T, error], sz int) ([]T, error) {
 	vs := make([]T, 0, sz)
 	for c, err := range it {
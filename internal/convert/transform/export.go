@@ -23,6 +23,7 @@ import (
 	"log/slog"
 	"path/filepath"
 	"runtime/trace"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/rusq/slack"
 
 	"github.com/rusq/slackdump/v4/export"
+	"github.com/rusq/slackdump/v4/internal/nametmpl"
 	"github.com/rusq/slackdump/v4/internal/structures"
 	"github.com/rusq/slackdump/v4/source"
 	"github.com/rusq/slackdump/v4/types"
@@ -57,12 +59,33 @@ func ExpWithDMMode(mode structures.DMMode) ExpCvtOption {
 	}
 }
 
+// ExpWithPeriod sets the period of messages in each message file, see
+// [source.ExportPeriod].
+func ExpWithPeriod(p source.ExportPeriod) ExpCvtOption {
+	return func(t *ExpConverter) {
+		t.period = p
+	}
+}
+
+// ExpWithChannelNamer sets the channel namer, that names the channel
+// directories.  By default, directories are named after the channels, see
+// [source.ExportChanName].
+func ExpWithChannelNamer(n *nametmpl.ChannelNamer) ExpCvtOption {
+	return func(t *ExpConverter) {
+		t.namer = n
+	}
+}
+
 type ExpConverter struct {
 	src     source.Sourcer
 	fsa     fsadapter.FS
 	users   atomic.Value
 	dmMode  structures.DMMode
+	period  source.ExportPeriod
+	namer   *nametmpl.ChannelNamer
 	msgFunc []msgUpdFunc
+
+	wspOnce sync.Once
 }
 
 func NewExpConverter(src source.Sourcer, fsa fsadapter.FS, opt ...ExpCvtOption) *ExpConverter {
@@ -70,6 +93,7 @@ func NewExpConverter(src source.Sourcer, fsa fsadapter.FS, opt ...ExpCvtOption)
 		src:    src,
 		fsa:    fsa,
 		dmMode: structures.DMSingle,
+		period: source.PeriodDay,
 	}
 	for _, o := range opt {
 		o(e)
@@ -79,6 +103,9 @@ func NewExpConverter(src source.Sourcer, fsa fsadapter.FS, opt ...ExpCvtOption)
 
 func (e *ExpConverter) SetUsers(users []slack.User) {
 	e.users.Store(users)
+	if e.namer != nil {
+		e.namer.SetUsers(users)
+	}
 }
 
 func (e *ExpConverter) getUsers() []slack.User {
@@ -118,7 +145,10 @@ func (e *ExpConverter) Convert(ctx context.Context, channelID, _ string) error {
 
 func (e *ExpConverter) writeMessages(ctx context.Context, ci *slack.Channel) (err error) {
 	lg := slog.With("in", "writeMessages", "channel", ci.ID)
-	acc := e.newAccumulator(ctx, ci)
+	acc, err := e.newAccumulator(ctx, ci)
+	if err != nil {
+		return err
+	}
 	defer func() {
 		e := acc.Flush()
 		err = errors.Join(err, e)
@@ -208,33 +238,104 @@ func (e *ExpConverter) WriteIndex(ctx context.Context) error {
 	if err := eidx.Marshal(e.fsa); err != nil {
 		return fmt.Errorf("error writing export index: %w", err)
 	}
+	if err := e.writeEmojis(ctx); err != nil {
+		return fmt.Errorf("error writing emoji index: %w", err)
+	}
+	if err := e.writeDirs(ctx, chans); err != nil {
+		return fmt.Errorf("error writing channel directories: %w", err)
+	}
 	return nil
 }
 
+// writeDirs writes the channel directory names, if they are not named after
+// the channels, so that the export can be read back.  It names all channels,
+// as the index may be written while the channels are being converted.
+func (e *ExpConverter) writeDirs(ctx context.Context, chans []slack.Channel) error {
+	if e.namer == nil {
+		return nil
+	}
+	for i := range chans {
+		if _, err := e.chanDir(ctx, &chans[i]); err != nil {
+			return err
+		}
+	}
+	wc, err := e.fsa.Create(source.ExportDirsFile)
+	if err != nil {
+		return err
+	}
+	defer wc.Close()
+	enc := json.NewEncoder(wc)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e.namer.Dirs())
+}
+
+// chanDir returns the directory name for the channel.
+func (e *ExpConverter) chanDir(ctx context.Context, ch *slack.Channel) (string, error) {
+	if e.namer == nil {
+		return source.ExportChanName(ch), nil
+	}
+	e.wspOnce.Do(func() {
+		if wi, err := e.src.WorkspaceInfo(ctx); err == nil && wi != nil {
+			e.namer.SetWorkspace(wi.Team)
+		}
+	})
+	return e.namer.Dir(ch)
+}
+
+// writeEmojis writes the custom emoji into the emoji file, if the source
+// has any.
+func (e *ExpConverter) writeEmojis(ctx context.Context) error {
+	es, ok := e.src.(source.Emojier)
+	if !ok {
+		return nil
+	}
+	ee, err := es.Emojis(ctx)
+	if err != nil {
+		if errors.Is(err, source.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	wc, err := e.fsa.Create(source.ExportEmojiFile)
+	if err != nil {
+		return err
+	}
+	defer wc.Close()
+	enc := json.NewEncoder(wc)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ee)
+}
+
 // HasUsers returns true if the converter has users.
 func (e *ExpConverter) HasUsers() bool {
 	return len(e.getUsers()) > 0
 }
 
-func (e *ExpConverter) newAccumulator(ctx context.Context, channel *slack.Channel) *expmsgAccum {
+func (e *ExpConverter) newAccumulator(ctx context.Context, channel *slack.Channel) (*expmsgAccum, error) {
+	dir, err := e.chanDir(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
 	return &expmsgAccum{
 		ctx:     ctx,
 		channel: channel,
 		src:     e.src,
-		trgdir:  source.ExportChanName(channel),
+		trgdir:  dir,
 		uidx:    types.Users(e.getUsers()).IndexByID(),
+		period:  e.period,
 		msgfunc: e.msgFunc,
 		flushFn: e.writeout,
-	}
+	}, nil
 }
 
 // expmsgAccum is the message accumulator for the export conversion.
 type expmsgAccum struct {
-	mm             []export.ExportMessage
-	prevDt, currDt string
+	mm                 []export.ExportMessage
+	prevFile, currFile string
 
 	src    source.Sourcer
 	trgdir string
+	period source.ExportPeriod
 
 	ctx     context.Context
 	channel *slack.Channel
@@ -250,24 +351,24 @@ const (
 
 func (a *expmsgAccum) next() {
 	a.mm = make([]export.ExportMessage, 0, msgBufSz)
-	a.prevDt = a.currDt
+	a.prevFile = a.currFile
 }
 
 func (a *expmsgAccum) shouldFlush() bool {
-	return a.currDt != a.prevDt || a.prevDt == ""
+	return a.currFile != a.prevFile || a.prevFile == ""
 }
 
 // Seattle timezone
 var exportLoc, _ = time.LoadLocation("America/Los_Angeles")
 
 // Append appends a message to the accumulator.  It flushes the messages to the
-// file when the date changes. It also updates the message with the user
+// file when the period changes. It also updates the message with the user
 // profile information and thread information if it is a lead message of a
 // thread.
 func (a *expmsgAccum) Append(ts time.Time, m *slack.Message) error {
-	a.currDt = ts.In(exportLoc).Format("2006-01-02")
+	a.currFile = a.period.Filename(ts.In(exportLoc))
 	if a.shouldFlush() {
-		// flush the previous day.
+		// flush the previous period.
 		if err := a.Flush(); err != nil {
 			return err
 		}
@@ -308,8 +409,8 @@ func (a *expmsgAccum) Append(ts time.Time, m *slack.Message) error {
 }
 
 func (a *expmsgAccum) Flush() error {
-	if a.prevDt != "" && len(a.mm) > 0 {
-		return a.flushFn(filepath.Join(a.trgdir, a.prevDt+".json"), a.mm)
+	if a.prevFile != "" && len(a.mm) > 0 {
+		return a.flushFn(filepath.Join(a.trgdir, a.prevFile), a.mm)
 	}
 	return nil
 }
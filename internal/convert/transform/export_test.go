@@ -17,17 +17,25 @@ package transform
 
 import (
 	"context"
+	"encoding/json"
+	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/rusq/fsadapter"
 	"github.com/rusq/slack"
+	"go.uber.org/mock/gomock"
 
+	"github.com/rusq/slackdump/v4/export"
 	"github.com/rusq/slackdump/v4/internal/chunk"
 	"github.com/rusq/slackdump/v4/internal/fixtures"
+	"github.com/rusq/slackdump/v4/internal/nametmpl"
 	"github.com/rusq/slackdump/v4/source"
+	"github.com/rusq/slackdump/v4/source/mock_source"
 )
 
 func Test_transform(t *testing.T) {
@@ -224,9 +232,92 @@ func TestExpConverter_newAccumulator(t *testing.T) {
 				users:   tt.fields.users,
 				msgFunc: tt.fields.msgFunc,
 			}
-			if got := e.newAccumulator(tt.args.ctx, tt.args.channel); !reflect.DeepEqual(got, tt.want) {
+			if got, _ := e.newAccumulator(tt.args.ctx, tt.args.channel); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("ExpConverter.newAccumulator() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_expmsgAccum_period(t *testing.T) {
+	msgs := []time.Time{
+		time.Date(2024, 1, 30, 20, 0, 0, 0, exportLoc),
+		time.Date(2024, 1, 31, 20, 0, 0, 0, exportLoc),
+		time.Date(2024, 2, 1, 20, 0, 0, 0, exportLoc),
+	}
+	tests := []struct {
+		period source.ExportPeriod
+		want   map[string]int // file -> number of messages
+	}{
+		{source.PeriodDay, map[string]int{"general/2024-01-30.json": 1, "general/2024-01-31.json": 1, "general/2024-02-01.json": 1}},
+		{source.PeriodWeek, map[string]int{"general/2024-W05.json": 3}},
+		{source.PeriodMonth, map[string]int{"general/2024-01.json": 2, "general/2024-02.json": 1}},
+		{source.PeriodYear, map[string]int{"general/2024.json": 3}},
+		{source.PeriodSingle, map[string]int{"general/" + source.ExportSingleFile: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period.String(), func(t *testing.T) {
+			got := make(map[string]int)
+			a := &expmsgAccum{
+				ctx:     t.Context(),
+				channel: &slack.Channel{GroupConversation: slack.GroupConversation{Name: "general"}},
+				trgdir:  "general",
+				period:  tt.period,
+				flushFn: func(filename string, mm []export.ExportMessage) error {
+					got[filepath.ToSlash(filename)] += len(mm)
+					return nil
+				},
+			}
+			for i, ts := range msgs {
+				m := slack.Message{Msg: slack.Msg{Timestamp: strconv.Itoa(int(ts.Unix())) + ".00000" + strconv.Itoa(i)}}
+				if err := a.Append(ts, &m); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := a.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("files = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpConverter_writeDirs(t *testing.T) {
+	tmpl, err := nametmpl.New(`{{.Workspace}}/{{.Type}}/{{join "-" .Members}}{{.Name}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctrl := gomock.NewController(t)
+	src := mock_source.NewMockSourcer(ctrl)
+	src.EXPECT().WorkspaceInfo(gomock.Any()).Return(&slack.AuthTestResponse{Team: "acme"}, nil)
+
+	dir := t.TempDir()
+	fsa := fsadapter.NewDirectory(dir)
+	defer fsa.Close()
+
+	namer := nametmpl.NewChannelNamer(tmpl)
+	namer.SetUsers([]slack.User{{ID: "U1", Name: "alice"}})
+	e := NewExpConverter(src, fsa, ExpWithChannelNamer(namer))
+
+	chans := []slack.Channel{
+		{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C1"}, Name: "general"}},
+		{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "D1", IsIM: true, User: "U1"}}},
+	}
+	if err := e.writeDirs(t.Context(), chans); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, source.ExportDirsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"C1": "acme/public_channel/general", "D1": "acme/im/alice"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dirs = %v, want %v", got, want)
+	}
+}
@@ -17,22 +17,60 @@ package fileproc
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
 	"path"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/internal/avatar"
+	"github.com/rusq/slackdump/v4/internal/chunk"
 )
 
+// AvatarProc downloads the user avatars.
 type AvatarProc struct {
 	dl       Downloader
 	filepath func(u *slack.User) string
+	hist     *avatarHistory
 }
 
-func NewAvatarProc(dl Downloader) AvatarProc {
-	return AvatarProc{
+// AvatarOption is the option for the [AvatarProc].
+type AvatarOption func(*AvatarProc)
+
+// WithAvatarHistory makes the AvatarProc keep the avatars in the
+// content-addressed store within the avatar directory of the archive
+// directory dir, and record the avatar history of each user, see
+// [avatar].  The avatar that is already in the store is not downloaded
+// again.
+func WithAvatarHistory(dir string) AvatarOption {
+	return func(a *AvatarProc) {
+		if _, ok := a.dl.(NoopDownloader); ok {
+			// avatars are not downloaded, nothing to record.
+			return
+		}
+		a.hist = &avatarHistory{
+			dir:     filepath.Join(dir, chunk.AvatarsDir),
+			seen:    make(map[string]struct{}),
+			users:   make(map[string][]avatar.Entry),
+			changed: make(map[string]struct{}),
+		}
+	}
+}
+
+func NewAvatarProc(dl Downloader, opts ...AvatarOption) AvatarProc {
+	a := AvatarProc{
 		dl:       dl,
 		filepath: AvatarPath,
 	}
+	for _, opt := range opts {
+		opt(&a)
+	}
+	return a
 }
 
 func (a AvatarProc) Users(ctx context.Context, users []slack.User) error {
@@ -41,6 +79,12 @@ func (a AvatarProc) Users(ctx context.Context, users []slack.User) error {
 			// skip empty
 			continue
 		}
+		if a.hist != nil {
+			if err := a.hist.add(a.dl, &u, a.removeDoubleDots(u.Profile.ImageOriginal)); err != nil {
+				return err
+			}
+			continue
+		}
 		if err := a.dl.Download(a.filepath(&u), a.removeDoubleDots(u.Profile.ImageOriginal)); err != nil {
 			return err
 		}
@@ -48,11 +92,88 @@ func (a AvatarProc) Users(ctx context.Context, users []slack.User) error {
 	return nil
 }
 
+// Close stops the downloader and writes the avatar history, if it is kept.
 func (a AvatarProc) Close() error {
 	a.dl.Stop()
+	if a.hist != nil {
+		return a.hist.flush()
+	}
 	return nil
 }
 
+// avatarHistory keeps track of the avatars in the store and the avatar
+// history of the users.
+type avatarHistory struct {
+	dir string // avatar directory
+
+	mu      sync.Mutex
+	seen    map[string]struct{}       // store paths scheduled for download
+	users   map[string][]avatar.Entry // history of the users
+	changed map[string]struct{}       // users with changed history
+}
+
+// add schedules the download of the avatar of the user u from the uri,
+// unless it is already in the store, and records it in the user's history.
+func (h *avatarHistory) add(dl Downloader, u *slack.User, uri string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	pth := avatar.StorePath(u.Profile.ImageOriginal)
+	if _, ok := h.seen[pth]; !ok {
+		h.seen[pth] = struct{}{}
+		if _, err := os.Stat(filepath.Join(h.dir, filepath.FromSlash(pth))); err != nil {
+			if err := dl.Download(path.Join(chunk.AvatarsDir, pth), uri); err != nil {
+				return err
+			}
+		}
+	}
+
+	hh, ok := h.users[u.ID]
+	if !ok {
+		var err error
+		hh, err = avatar.ReadHistory(os.DirFS(h.dir), u.ID)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	hh, appended := avatar.Append(hh, avatar.Entry{
+		Recorded: time.Now().UTC(),
+		URL:      u.Profile.ImageOriginal,
+		Path:     pth,
+	})
+	h.users[u.ID] = hh
+	if appended {
+		h.changed[u.ID] = struct{}{}
+	}
+	return nil
+}
+
+// flush writes the changed histories to disk.
+func (h *avatarHistory) flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var errs error
+	for id := range h.changed {
+		data, err := json.Marshal(h.users[id])
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		fp := filepath.Join(h.dir, filepath.FromSlash(avatar.HistoryPath(id)))
+		if err := os.MkdirAll(filepath.Dir(fp), 0o755); err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		if err := os.WriteFile(fp, data, 0o644); err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		delete(h.changed, id)
+	}
+	return errs
+}
+
 func AvatarPath(u *slack.User) string {
 	filename := path.Base(u.Profile.ImageOriginal)
 	return filepath.Join(
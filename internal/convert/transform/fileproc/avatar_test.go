@@ -16,10 +16,17 @@
 package fileproc
 
 import (
+	"os"
+	"path"
 	"path/filepath"
 	"testing"
 
 	"github.com/rusq/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusq/slackdump/v4/internal/avatar"
+	"github.com/rusq/slackdump/v4/internal/chunk"
 )
 
 func Test_avatarPath(t *testing.T) {
@@ -116,3 +123,73 @@ func TestAvatarProc_removeDoubleDots(t *testing.T) {
 		})
 	}
 }
+
+func TestAvatarProc_History(t *testing.T) {
+	const (
+		urlA = "https://avatars.slack-edge.com/2020/a_original.png"
+		urlB = "https://avatars.slack-edge.com/2024/b_original.png"
+	)
+	user := func(id, u string) slack.User {
+		return slack.User{ID: id, Profile: slack.UserProfile{ImageOriginal: u}}
+	}
+	dir := t.TempDir()
+	avdir := filepath.Join(dir, chunk.AvatarsDir)
+
+	// first run: two users share the same avatar, it's downloaded once.
+	dl := &recordingDownloader{}
+	ap := NewAvatarProc(dl, WithAvatarHistory(dir))
+	if err := ap.Users(t.Context(), []slack.User{user("U1", urlA), user("U2", urlA), user("U1", urlA)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ap.Close(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{path.Join(chunk.AvatarsDir, avatar.StorePath(urlA)): urlA}, dl.got)
+	assert.True(t, dl.stopped)
+	for _, id := range []string{"U1", "U2"} {
+		hh, err := avatar.ReadHistory(os.DirFS(avdir), id)
+		require.NoError(t, err)
+		require.Len(t, hh, 1)
+		assert.Equal(t, urlA, hh[0].URL)
+		assert.Equal(t, avatar.StorePath(urlA), hh[0].Path)
+	}
+
+	// pretend that the avatar was downloaded.
+	storeA := filepath.Join(avdir, filepath.FromSlash(avatar.StorePath(urlA)))
+	require.NoError(t, os.MkdirAll(filepath.Dir(storeA), 0o755))
+	require.NoError(t, os.WriteFile(storeA, []byte("a"), 0o644))
+
+	// second run: U1 changes the avatar, U2 does not, avatar A is not
+	// downloaded again.
+	dl = &recordingDownloader{}
+	ap = NewAvatarProc(dl, WithAvatarHistory(dir))
+	if err := ap.Users(t.Context(), []slack.User{user("U1", urlB), user("U2", urlA)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ap.Close(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{path.Join(chunk.AvatarsDir, avatar.StorePath(urlB)): urlB}, dl.got)
+	hh, err := avatar.ReadHistory(os.DirFS(avdir), "U1")
+	require.NoError(t, err)
+	require.Len(t, hh, 2)
+	assert.Equal(t, urlA, hh[0].URL)
+	assert.Equal(t, urlB, hh[1].URL)
+	hh, err = avatar.ReadHistory(os.DirFS(avdir), "U2")
+	require.NoError(t, err)
+	assert.Len(t, hh, 1)
+}
+
+func TestAvatarProc_HistoryNoop(t *testing.T) {
+	dir := t.TempDir()
+	ap := NewAvatarProc(NoopDownloader{}, WithAvatarHistory(dir))
+	if err := ap.Users(t.Context(), []slack.User{{ID: "U1", Profile: slack.UserProfile{ImageOriginal: "https://example.com/a.png"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ap.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, chunk.AvatarsDir)); !os.IsNotExist(err) {
+		t.Errorf("avatar directory must not be created when avatars are disabled, got: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package fileproc
+
+import (
+	"context"
+	"path"
+
+	"github.com/rusq/slackdump/v4/internal/chunk"
+	"github.com/rusq/slackdump/v4/types"
+)
+
+// EmojiProc downloads the custom emoji images.
+type EmojiProc struct {
+	dl Downloader
+}
+
+func NewEmojiProc(dl Downloader) EmojiProc {
+	return EmojiProc{dl: dl}
+}
+
+func (e EmojiProc) Emojis(ctx context.Context, emojis []types.Emoji) error {
+	for _, em := range emojis {
+		pth := EmojiPath(&em)
+		if pth == "" {
+			// aliases do not have images.
+			continue
+		}
+		if err := e.dl.Download(pth, em.URL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e EmojiProc) Close() error {
+	e.dl.Stop()
+	return nil
+}
+
+// EmojiPath returns the path of the emoji image within the archive, i.e.
+// "__emoji/party-parrot.gif".  It returns an empty string for aliases and
+// emoji without URL.
+func EmojiPath(em *types.Emoji) string {
+	if em.IsAlias != 0 || em.URL == "" {
+		return ""
+	}
+	return path.Join(chunk.EmojiDir, em.Name+path.Ext(em.URL))
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package fileproc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rusq/slackdump/v4/types"
+)
+
+func TestEmojiPath(t *testing.T) {
+	tests := []struct {
+		name string
+		em   *types.Emoji
+		want string
+	}{
+		{
+			name: "emoji",
+			em:   &types.Emoji{Name: "partyparrot", URL: "https://emoji.slack-edge.com/T1/partyparrot/abc.gif"},
+			want: "__emoji/partyparrot.gif",
+		},
+		{
+			name: "alias",
+			em:   &types.Emoji{Name: "parrot", URL: "alias:partyparrot", IsAlias: 1, AliasFor: "partyparrot"},
+			want: "",
+		},
+		{
+			name: "no url",
+			em:   &types.Emoji{Name: "empty"},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EmojiPath(tt.em); got != tt.want {
+				t.Errorf("EmojiPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type recordingDownloader struct {
+	got     map[string]string
+	stopped bool
+}
+
+func (d *recordingDownloader) Download(fullpath string, url string) error {
+	if d.got == nil {
+		d.got = make(map[string]string)
+	}
+	d.got[fullpath] = url
+	return nil
+}
+
+func (d *recordingDownloader) Stop() {
+	d.stopped = true
+}
+
+func TestEmojiProc_Emojis(t *testing.T) {
+	dl := &recordingDownloader{}
+	ep := NewEmojiProc(dl)
+	err := ep.Emojis(context.Background(), []types.Emoji{
+		{Name: "partyparrot", URL: "https://example.com/partyparrot.gif"},
+		{Name: "parrot", URL: "alias:partyparrot", IsAlias: 1, AliasFor: "partyparrot"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"__emoji/partyparrot.gif": "https://example.com/partyparrot.gif"}, dl.got)
+	assert.NoError(t, ep.Close())
+	assert.True(t, dl.stopped)
+}
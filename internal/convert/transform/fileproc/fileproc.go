@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"path/filepath"
 
 	"github.com/rusq/fsadapter"
 	"github.com/rusq/slack"
@@ -47,17 +48,34 @@ type Downloader interface {
 type FileProcessor struct {
 	dcl      Downloader
 	filepath func(ci *slack.Channel, f *slack.File) string
+	thumbs   bool
+}
+
+// Option configures the file processor.
+type Option func(*FileProcessor)
+
+// WithThumbs enables the download of the Slack thumbnail of the image files,
+// that is used for the image preview, see [files.PreviewThumbs].  It is
+// placed next to the file.
+func WithThumbs(enabled bool) Option {
+	return func(b *FileProcessor) {
+		b.thumbs = enabled
+	}
 }
 
 // NewWithPathFn initialises the file processor.
-func NewWithPathFn(dl Downloader, fp func(ci *slack.Channel, f *slack.File) string) FileProcessor {
+func NewWithPathFn(dl Downloader, fp func(ci *slack.Channel, f *slack.File) string, opts ...Option) FileProcessor {
 	if fp == nil {
 		panic("filepath function is nil")
 	}
-	return FileProcessor{
+	b := FileProcessor{
 		dcl:      dl,
 		filepath: fp,
 	}
+	for _, opt := range opts {
+		opt(&b)
+	}
+	return b
 }
 
 func (b FileProcessor) Files(_ context.Context, channel *slack.Channel, _ slack.Message, ff []slack.File) error {
@@ -68,10 +86,26 @@ func (b FileProcessor) Files(_ context.Context, channel *slack.Channel, _ slack.
 		if err := b.dcl.Download(b.filepath(channel, &f), f.URLPrivateDownload); err != nil {
 			return err
 		}
+		if err := b.thumb(channel, &f); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// thumb downloads the preferred Slack thumbnail of the file f, if enabled.
+func (b FileProcessor) thumb(channel *slack.Channel, f *slack.File) error {
+	if !b.thumbs {
+		return nil
+	}
+	thumbs := files.PreviewThumbs(f)
+	if len(thumbs) == 0 {
+		return nil
+	}
+	tf := slack.File{ID: f.ID, Name: files.ThumbName(thumbs[0])}
+	return b.dcl.Download(b.filepath(channel, &tf), thumbs[0])
+}
+
 func (b FileProcessor) Close() error {
 	b.dcl.Stop()
 	return nil
@@ -162,13 +196,25 @@ type FileGetter interface {
 	GetFileContext(ctx context.Context, downloadURL string, writer io.Writer) error
 }
 
+// PartialDir is the directory within the archive directory, where the
+// partially downloaded files are kept, so that the interrupted downloads can
+// be continued by resume or redownload.
+const PartialDir = ".partial"
+
+// ResumableIn returns the downloader option that keeps the partially
+// downloaded files in the [PartialDir] within the directory dir.
+func ResumableIn(dir string) downloader.Option {
+	return downloader.WithPartialDir(filepath.Join(dir, PartialDir))
+}
+
 // NewDownloader initializes the downloader and returns it, along with a
-// function that should be called to stop it.
-func NewDownloader(ctx context.Context, enabled bool, cl FileGetter, fsa fsadapter.FS, lg *slog.Logger) (sdl Downloader) {
+// function that should be called to stop it.  Options are passed to the
+// downloader.
+func NewDownloader(ctx context.Context, enabled bool, cl FileGetter, fsa fsadapter.FS, lg *slog.Logger, opts ...downloader.Option) (sdl Downloader) {
 	if !enabled {
 		return NoopDownloader{}
 	} else {
-		dl := downloader.New(cl, fsa, downloader.WithLogger(lg))
+		dl := downloader.New(cl, fsa, append([]downloader.Option{downloader.WithLogger(lg)}, opts...)...)
 		if err := dl.Start(ctx); err != nil {
 			lg.Error("failed to start downloader", "error", err)
 			return NoopDownloader{}
@@ -20,6 +20,9 @@
 package fileproc
 
 import (
+	"context"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/rusq/slack"
@@ -187,3 +190,47 @@ func TestIsValidWithReason(t *testing.T) {
 		})
 	}
 }
+
+func TestFileProcessor_Files(t *testing.T) {
+	f := slack.File{
+		ID:                 "F1",
+		Name:               "photo.jpg",
+		Mimetype:           "image/jpeg",
+		URLPrivateDownload: "https://files.slack.com/files-pri/T1-F1/download/photo.jpg",
+		Thumb360:           "https://files.slack.com/files-tmb/T1-F1-x/photo_360.jpg",
+		Thumb480:           "https://files.slack.com/files-tmb/T1-F1-x/photo_480.jpg",
+	}
+	tests := []struct {
+		name string
+		opts []Option
+		want map[string]string
+	}{
+		{
+			"file only",
+			nil,
+			map[string]string{
+				filepath.Join("__uploads", "F1", "photo.jpg"): f.URLPrivateDownload,
+			},
+		},
+		{
+			"with thumbnail",
+			[]Option{WithThumbs(true)},
+			map[string]string{
+				filepath.Join("__uploads", "F1", "photo.jpg"):     f.URLPrivateDownload,
+				filepath.Join("__uploads", "F1", "photo_480.jpg"): f.Thumb480,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dl := &recordingDownloader{}
+			fp := New(dl, tt.opts...)
+			if err := fp.Files(context.Background(), &slack.Channel{}, slack.Message{}, []slack.File{f}); err != nil {
+				t.Fatalf("Files() error = %v", err)
+			}
+			if !reflect.DeepEqual(dl.got, tt.want) {
+				t.Errorf("Files() downloaded %v, want %v", dl.got, tt.want)
+			}
+		})
+	}
+}
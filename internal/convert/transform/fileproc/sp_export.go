@@ -16,6 +16,8 @@
 package fileproc
 
 import (
+	"github.com/rusq/slack"
+
 	"github.com/rusq/slackdump/v4/processor"
 	"github.com/rusq/slackdump/v4/source"
 )
@@ -24,9 +26,16 @@ import (
 // type.  This subprocessor can be later plugged into the
 // [expproc.Conversations] processor.
 func NewExport(typ source.StorageType, dl Downloader) processor.Filer {
+	return NewExportWithDir(typ, dl, source.ExportChanName)
+}
+
+// NewExportWithDir is the same as [NewExport], but the dir function returns
+// the channel directory, for the storage types that keep the files in the
+// channel directories.
+func NewExportWithDir(typ source.StorageType, dl Downloader, dir func(*slack.Channel) string) processor.Filer {
 	switch typ {
 	case source.STstandard:
-		return NewWithPathFn(dl, source.StdFilepath)
+		return NewWithPathFn(dl, source.StdFilepathWithDir(dir))
 	case source.STmattermost:
 		return NewWithPathFn(dl, source.MattermostFilepath)
 	default:
@@ -36,6 +45,6 @@ func NewExport(typ source.StorageType, dl Downloader) processor.Filer {
 
 // New creates a new file processor that uses mattermost file naming
 // pattern.
-func New(dl Downloader) processor.Filer {
-	return NewWithPathFn(dl, source.MattermostFilepath)
+func New(dl Downloader, opts ...Option) processor.Filer {
+	return NewWithPathFn(dl, source.MattermostFilepath, opts...)
 }
@@ -18,6 +18,8 @@ package edge
 import (
 	"context"
 	"fmt"
+
+	"github.com/rusq/slackdump/v4/internal/structures"
 )
 
 // CanvasDocumentComment holds the document_comment subfields of a canvas message.
@@ -50,15 +52,6 @@ type canvasHistoryResponse struct {
 	HasMore  bool                   `json:"has_more,omitempty"`
 }
 
-// canvasChannelFromFileID derives the dedicated canvas channel ID from a file ID.
-// Valid canvas channels reuse the file suffix with a leading C instead of F.
-func canvasChannelFromFileID(fileID string) string {
-	if len(fileID) < 2 || fileID[0] != 'F' {
-		return ""
-	}
-	return "C" + fileID[1:]
-}
-
 func (cl *Client) conversationsHistoryForCanvas(ctx context.Context, channelID string) ([]canvasHistoryMessage, error) {
 	const ep = "conversations.history"
 	type form struct {
@@ -101,7 +94,7 @@ func (cl *Client) conversationsHistoryForCanvas(ctx context.Context, channelID s
 // CanvasThreadRoots returns the root messages for all comment threads on a
 // canvas file. fileID is the Slack file ID, for example F06R4HA3ZS8.
 func (cl *Client) CanvasThreadRoots(ctx context.Context, fileID string) ([]CanvasMessage, error) {
-	canvasChannelID := canvasChannelFromFileID(fileID)
+	canvasChannelID := structures.CanvasChannelID(fileID)
 	if canvasChannelID == "" {
 		return nil, fmt.Errorf("canvas: invalid file ID %q", fileID)
 	}
@@ -24,14 +24,6 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestCanvasChannelFromFileID(t *testing.T) {
-	assert.Equal(t, "C06R4HA3ZS8", canvasChannelFromFileID("F06R4HA3ZS8"))
-	assert.Equal(t, "", canvasChannelFromFileID(""))
-	assert.Equal(t, "", canvasChannelFromFileID("C06R4HA3ZS8"))
-	assert.Equal(t, "", canvasChannelFromFileID("X06R4HA3ZS8"))
-	assert.Equal(t, "", canvasChannelFromFileID("F"))
-}
-
 func TestClient_conversationsHistoryForCanvas_paginates(t *testing.T) {
 	var cursors []string
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
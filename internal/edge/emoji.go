@@ -19,6 +19,8 @@ import (
 	"context"
 	"iter"
 	"runtime/trace"
+
+	"github.com/rusq/slackdump/v4/types"
 )
 
 type emojiResponse struct {
@@ -39,20 +41,7 @@ type EmojiResult struct {
 }
 
 // Emoji represents a custom emoji as read by the Client API.
-type Emoji struct {
-	Name            string   `json:"name"`
-	IsAlias         int      `json:"is_alias,omitempty"`
-	AliasFor        string   `json:"alias_for,omitempty"`
-	URL             string   `json:"url"`
-	TeamID          string   `json:"team_id,omitempty"`
-	UserID          string   `json:"user_id,omitempty"`
-	Created         int64    `json:"created,omitempty"`
-	IsBad           bool     `json:"is_bad,omitempty"`
-	UserDisplayName string   `json:"user_display_name,omitempty"`
-	AvatarHash      string   `json:"avatar_hash,omitempty"`
-	CanDelete       bool     `json:"can_delete,omitempty"`
-	Synonyms        []string `json:"synonyms,omitempty"`
-}
+type Emoji = types.Emoji
 
 type Paging struct {
 	Count int64 `json:"count,omitempty"`
@@ -101,6 +101,10 @@ func (w *Wrapper) GetFileInfoContext(ctx context.Context, fileID string, count i
 	return w.cl.GetFileInfoContext(ctx, fileID, count, page)
 }
 
+func (w *Wrapper) GetFilesContext(ctx context.Context, params slack.GetFilesParameters) ([]slack.File, *slack.Paging, error) {
+	return w.cl.GetFilesContext(ctx, params)
+}
+
 func (w *Wrapper) GetUserInfoContext(ctx context.Context, user string) (*slack.User, error) {
 	return w.cl.GetUserInfoContext(ctx, user)
 }
@@ -119,19 +119,19 @@ func (c *CSV) channelsFull(_ context.Context, csv *csv.Writer, u []slack.User, c
 		return err
 	}
 
-	ui := types.Users(u).IndexByID()
+	names := c.opts.resolver(types.Users(u).IndexByID())
 
-	for _, c := range chans {
+	for _, ch := range chans {
 		if err := csv.Write([]string{
-			c.ID,
-			NVL(c.Name, ui.DisplayName(c.User)),
-			_ft(int64(c.Created)),
-			_fb(c.IsArchived),
-			_fb(c.IsChannel),
-			_fb(c.IsMpIM),
-			_fb(c.IsPrivate),
-			_fb(c.IsIM),
-			c.Purpose.Value,
+			ch.ID,
+			names.Name(ch),
+			_ft(int64(ch.Created)),
+			_fb(ch.IsArchived),
+			_fb(ch.IsChannel),
+			_fb(ch.IsMpIM),
+			_fb(ch.IsPrivate),
+			_fb(ch.IsIM),
+			ch.Purpose.Value,
 		}); err != nil {
 			return err
 		}
@@ -83,7 +83,8 @@ type options struct {
 	textOptions
 	csvOptions
 	jsonOptions
-	bare bool // bare output format
+	bare  bool                     // bare output format
+	names *structures.NameResolver // resolves the conversation names
 }
 
 // Option is the converter option.
@@ -115,6 +116,24 @@ func WithBareFormat(b bool) Option {
 	}
 }
 
+// WithNameResolver sets the resolver of the conversation names, i.e. the
+// one returned by [source.NameResolver].  If not set, the names are resolved
+// with the users given to the formatter.
+func WithNameResolver(r *structures.NameResolver) Option {
+	return func(o *options) {
+		o.names = r
+	}
+}
+
+// resolver returns the name resolver, or the one that uses the user index
+// ui, if it was not set.
+func (o *options) resolver(ui structures.UserIndex) *structures.NameResolver {
+	if o.names != nil {
+		return o.names
+	}
+	return structures.NewNameResolver(ui, "", nil)
+}
+
 // userReplacer returns a replacer that replaces all user IDs with their
 // DisplayNames.
 func userReplacer(userIdx structures.UserIndex) *strings.Replacer {
@@ -196,13 +196,14 @@ func (txt *Text) channelsFull(_ context.Context, w io.Writer, u []slack.User, cc
 	const strFormat = "%s\t%s\t%s\n"
 
 	ui := structures.NewUserIndex(u)
+	names := txt.opts.resolver(ui)
 
 	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
 	defer writer.Flush()
 
 	fmt.Fprintf(writer, strFormat, "ID", "Arch", "What")
 	for i, ch := range cc {
-		who := ui.ChannelName(ch)
+		who := names.ChannelName(ch)
 		archived := "-"
 		if cc[i].IsArchived || ui.IsDeleted(ch.User) {
 			archived = "arch"
@@ -0,0 +1,175 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package httptape records the HTTP traffic of the Slack clients to a file,
+// and replays it, so that the bugs can be reproduced deterministically, and
+// the whole pipeline can be tested without network.
+//
+// The tape is a JSON lines file.  The first line is the [Header], followed
+// by the recorded [Entry] for each request.  The tokens are removed from the
+// recorded requests, but responses are recorded as is, so the tape contains
+// the workspace data, and should be treated accordingly.
+//
+// On replay, the requests are matched by the method, URL and body.  If there
+// are several responses for the same request, they are returned in the order
+// they were recorded, and the last response is repeated, once they are
+// exhausted.  If there is no exact match, the request is matched ignoring the
+// "oldest" and "latest" time boundaries, as the default latest time is the
+// time of the run.
+package httptape
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Version is the current tape format version.
+const Version = 1
+
+// ErrNotRecorded is returned by the replaying transport, if the request was
+// not found on the tape.
+var ErrNotRecorded = errors.New("request not recorded")
+
+// Header is the tape header.
+type Header struct {
+	// Version is the tape format version.
+	Version int `json:"version"`
+	// ClientToken is true, if the traffic was recorded with the web-client
+	// token (xoxc), which enables the edge API.
+	ClientToken bool `json:"client_token,omitempty"`
+}
+
+// Entry is the recorded request and response.
+type Entry struct {
+	// Method is the request method.
+	Method string `json:"method"`
+	// URL is the request URL without the token.
+	URL string `json:"url"`
+	// Body is the request body without the token.
+	Body []byte `json:"body,omitempty"`
+	// Status is the response status code.
+	Status int `json:"status"`
+	// Header is the response header, without cookies.
+	Header http.Header `json:"header,omitempty"`
+	// Response is the response body.
+	Response []byte `json:"response,omitempty"`
+}
+
+// volatileParams are the request parameters, that are ignored by the loose
+// match.
+var volatileParams = []string{"oldest", "latest"}
+
+// key returns the exact match key of the entry.
+func (e *Entry) key() string {
+	return hash(e.Method, e.URL, e.Body)
+}
+
+// looseKey returns the key of the entry, that ignores the volatile
+// parameters.
+func (e *Entry) looseKey() string {
+	u, err := url.Parse(e.URL)
+	if err != nil {
+		return e.key()
+	}
+	u.RawQuery = dropParams(u.Query(), volatileParams...).Encode()
+	body := e.Body
+	if form, err := url.ParseQuery(string(e.Body)); err == nil && len(e.Body) > 0 && !json.Valid(e.Body) {
+		body = []byte(dropParams(form, volatileParams...).Encode())
+	}
+	return hash(e.Method, u.String(), body)
+}
+
+func hash(method, u string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + u + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func dropParams(v url.Values, names ...string) url.Values {
+	for _, name := range names {
+		v.Del(name)
+	}
+	return v
+}
+
+// newEntry creates a new entry from the request, removing the token.  It
+// reads the request body and restores it, so that the request can be sent.
+func newEntry(req *http.Request) (*Entry, error) {
+	u := *req.URL
+	if q := u.Query(); q.Has("token") {
+		u.RawQuery = dropParams(q, "token").Encode()
+	}
+	e := &Entry{Method: req.Method, URL: u.String()}
+	if req.Body == nil || req.Body == http.NoBody {
+		return e, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	e.Body = sanitiseBody(req.Header.Get("Content-Type"), body)
+	return e, nil
+}
+
+// sanitiseBody removes the token from the form or JSON request body.
+func sanitiseBody(contentType string, body []byte) []byte {
+	ct, _, _ := mime.ParseMediaType(contentType)
+	switch ct {
+	case "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return body
+		}
+		return []byte(dropParams(form, "token").Encode())
+	case "application/json":
+		var m map[string]any
+		if err := json.Unmarshal(body, &m); err != nil {
+			return body
+		}
+		delete(m, "token")
+		b, err := json.Marshal(m)
+		if err != nil {
+			return body
+		}
+		return b
+	}
+	return body
+}
+
+// response returns the http response for the entry.
+func (e *Entry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(e.Status) + " " + http.StatusText(e.Status),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Response)),
+		ContentLength: int64(len(e.Response)),
+		Request:       req,
+	}
+}
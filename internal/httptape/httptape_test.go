@@ -0,0 +1,164 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package httptape
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+func TestRecordReplay(t *testing.T) {
+	var n int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "d=secret")
+		io.WriteString(w, `{"ok":true,"channel":"`+r.Form.Get("channel")+`","n":`+strconv.Itoa(n)+`}`)
+	}))
+	defer srv.Close()
+
+	var tape bytes.Buffer
+	rec, err := NewRecorder(nopCloser{&tape}, Header{ClientToken: true})
+	require.NoError(t, err)
+	cl := &http.Client{Transport: rec.Transport(http.DefaultTransport)}
+
+	post := func(cl *http.Client, form url.Values) (string, error) {
+		resp, err := cl.PostForm(srv.URL+"/api/conversations.history", form)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		return string(b), err
+	}
+	want1, err := post(cl, url.Values{"token": {"xoxc-secret"}, "channel": {"C1"}, "latest": {"100"}})
+	require.NoError(t, err)
+	want2, err := post(cl, url.Values{"token": {"xoxc-secret"}, "channel": {"C1"}, "latest": {"100"}})
+	require.NoError(t, err)
+	want3, err := post(cl, url.Values{"token": {"xoxc-secret"}, "channel": {"C2"}, "latest": {"100"}})
+	require.NoError(t, err)
+	require.NoError(t, rec.Close())
+
+	assert.NotContains(t, tape.String(), "secret", "token and cookies must not be recorded")
+
+	p, err := NewPlayer(&tape)
+	require.NoError(t, err)
+	assert.Equal(t, Header{Version: Version, ClientToken: true}, p.Header())
+	cl = &http.Client{Transport: p.Transport()}
+
+	// responses for the same request are replayed in order, and the last one
+	// is repeated.
+	got, err := post(cl, url.Values{"token": {"xoxc-other"}, "channel": {"C1"}, "latest": {"100"}})
+	require.NoError(t, err)
+	assert.Equal(t, want1, got)
+	got, err = post(cl, url.Values{"channel": {"C1"}, "latest": {"100"}})
+	require.NoError(t, err)
+	assert.Equal(t, want2, got)
+	got, err = post(cl, url.Values{"channel": {"C1"}, "latest": {"100"}})
+	require.NoError(t, err)
+	assert.Equal(t, want2, got)
+
+	// time boundaries are ignored, if there is no exact match.
+	got, err = post(cl, url.Values{"channel": {"C2"}, "latest": {"200"}})
+	require.NoError(t, err)
+	assert.Equal(t, want3, got)
+
+	_, err = post(cl, url.Values{"channel": {"C3"}})
+	assert.ErrorIs(t, err, ErrNotRecorded)
+}
+
+func TestPlayer_next(t *testing.T) {
+	var n int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"ok":true,"page":`+strconv.Itoa(n)+`}`)
+	}))
+	defer srv.Close()
+
+	var tape bytes.Buffer
+	rec, err := NewRecorder(nopCloser{&tape}, Header{})
+	require.NoError(t, err)
+	post := func(cl *http.Client, latest string) string {
+		t.Helper()
+		resp, err := cl.PostForm(srv.URL+"/api/conversations.history", url.Values{"channel": {"C1"}, "latest": {latest}})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(b)
+	}
+	cl := &http.Client{Transport: rec.Transport(http.DefaultTransport)}
+	var want []string
+	for _, latest := range []string{"300", "200", "100"} {
+		want = append(want, post(cl, latest))
+	}
+	require.NoError(t, rec.Close())
+
+	p, err := NewPlayer(&tape)
+	require.NoError(t, err)
+	cl = &http.Client{Transport: p.Transport()}
+	// the exact matches consume the pages, so the loose match gets the
+	// next one.
+	assert.Equal(t, want[0], post(cl, "300"))
+	assert.Equal(t, want[1], post(cl, "200"))
+	assert.Equal(t, want[2], post(cl, "150"))
+	// all pages were replayed, the exact match repeats its page, and the
+	// loose match repeats the last page.
+	assert.Equal(t, want[1], post(cl, "200"))
+	assert.Equal(t, want[2], post(cl, "50"))
+}
+
+func TestNewPlayer_version(t *testing.T) {
+	_, err := NewPlayer(strings.NewReader(`{"version":42}`))
+	assert.Error(t, err)
+}
+
+func Test_sanitiseBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"form", "application/x-www-form-urlencoded", "token=xoxc-1&channel=C1", "channel=C1"},
+		{"json", "application/json; charset=utf-8", `{"token":"xoxc-1","channel":"C1"}`, `{"channel":"C1"}`},
+		{"other", "text/plain", "token=xoxc-1", "token=xoxc-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, string(sanitiseBody(tt.contentType, []byte(tt.body))))
+		})
+	}
+}
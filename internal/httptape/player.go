@@ -0,0 +1,146 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package httptape
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Player replays the recorded traffic.  It is safe for concurrent use.
+type Player struct {
+	hdr Header
+
+	mu sync.Mutex
+	// loose holds the recorded entries in order, keyed by the loose match
+	// key.
+	loose map[string][]*tapeEntry
+}
+
+// tapeEntry is the recorded entry with its replay state.
+type tapeEntry struct {
+	*Entry
+	key  string // exact match key
+	used bool   // the entry was replayed
+}
+
+// NewPlayer reads the tape from r.
+func NewPlayer(r io.Reader) (*Player, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	var hdr Header
+	if err := dec.Decode(&hdr); err != nil {
+		return nil, fmt.Errorf("tape header: %w", err)
+	}
+	if hdr.Version != Version {
+		return nil, fmt.Errorf("unsupported tape version: %d", hdr.Version)
+	}
+	p := &Player{
+		hdr:   hdr,
+		loose: make(map[string][]*tapeEntry),
+	}
+	for {
+		var e Entry
+		if err := dec.Decode(&e); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		lk := e.looseKey()
+		p.loose[lk] = append(p.loose[lk], &tapeEntry{Entry: &e, key: e.key()})
+	}
+	return p, nil
+}
+
+// Open opens the tape file filename.
+func Open(filename string) (*Player, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewPlayer(f)
+}
+
+// Header returns the tape header.
+func (p *Player) Header() Header {
+	return p.hdr
+}
+
+// Transport returns the transport, that replays the recorded responses.  It
+// never makes network requests.
+func (p *Player) Transport() http.RoundTripper {
+	return playTransport{p}
+}
+
+// next returns the next response for the request entry e.  It is the first
+// entry not yet replayed that matches e exactly, or, if there's none, the
+// first entry not yet replayed that matches loosely.  Both matches consume
+// the entries from the same ordered list, so that the paginated requests get
+// the pages in the recorded order.  When all matching entries were replayed,
+// the last one is repeated.
+func (p *Player) next(e *Entry) (*Entry, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ee := p.loose[e.looseKey()]
+	if len(ee) == 0 {
+		return nil, false
+	}
+	key := e.key()
+	var last *tapeEntry // last replayed exact match
+	for _, te := range ee {
+		if te.key != key {
+			continue
+		}
+		if !te.used {
+			te.used = true
+			return te.Entry, true
+		}
+		last = te
+	}
+	for _, te := range ee {
+		if !te.used {
+			te.used = true
+			return te.Entry, true
+		}
+	}
+	if last != nil {
+		return last.Entry, true
+	}
+	return ee[len(ee)-1].Entry, true
+}
+
+type playTransport struct {
+	p *Player
+}
+
+func (t playTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	e, err := newEntry(req)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := t.p.next(e)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, e.Method, e.URL)
+	}
+	return r.response(req), nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package httptape
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Recorder records the HTTP traffic of the transports, returned by
+// [Recorder.Transport], to the tape.  It is safe for concurrent use.
+type Recorder struct {
+	mu  sync.Mutex
+	w   io.WriteCloser
+	enc *json.Encoder
+}
+
+// NewRecorder creates a new recorder, that writes the tape to w.  It writes
+// the header hdr immediately.
+func NewRecorder(w io.WriteCloser, hdr Header) (*Recorder, error) {
+	hdr.Version = Version
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(hdr); err != nil {
+		return nil, err
+	}
+	return &Recorder{w: w, enc: enc}, nil
+}
+
+// Create creates the tape file filename and returns the recorder for it.
+func Create(filename string, hdr Header) (*Recorder, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewRecorder(f, hdr)
+	if err != nil {
+		return nil, errors.Join(err, f.Close())
+	}
+	return r, nil
+}
+
+// Transport returns the transport, that records the traffic of rt.
+func (r *Recorder) Transport(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &recordTransport{r: r, next: rt}
+}
+
+// Close closes the tape.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.w.Close()
+}
+
+func (r *Recorder) record(e *Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enc.Encode(e)
+}
+
+type recordTransport struct {
+	r    *Recorder
+	next http.RoundTripper
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	e, err := newEntry(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	e.Status = resp.StatusCode
+	e.Header = resp.Header.Clone()
+	e.Header.Del("Set-Cookie")
+	e.Response = body
+	if err := t.r.record(e); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Close closes the underlying transport, if it implements [io.Closer].  It
+// does not close the tape.
+func (t *recordTransport) Close() error {
+	if c, ok := t.next.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,446 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package linkfetch archives the publicly accessible targets of the external
+// links found in the messages: the link unfurls, and the external files, such
+// as Google Drive or Dropbox documents, that Slack keeps as metadata only.
+//
+// Only the public addresses are fetched: the links that point, or redirect,
+// to the loopback, private or link-local addresses are refused, so that the
+// message author can't make Slackdump fetch the internal resources, such as
+// the cloud instance metadata.
+//
+// Each link is saved as it is served, the raw file or the HTML page, into its
+// own directory in the links storage area:
+//
+//	__links/<key>/<filename>
+//
+// where key is derived from the link URL, see [Key].
+package linkfetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rusq/fsadapter"
+	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/internal/chunk"
+)
+
+const (
+	// DefaultMaxSize is the default size limit of the archived link.
+	DefaultMaxSize = 10 << 20
+	// defNumWorkers is the default number of fetch workers.
+	defNumWorkers = 4
+	// defTimeout is the default timeout of the link request.
+	defTimeout = 1 * time.Minute
+	// maxRedirects is the maximum number of redirects followed.
+	maxRedirects = 5
+	// htmlName is the file name of the HTML page snapshot.
+	htmlName = "index.html"
+	// defName is used when the file name can not be determined.
+	defName = "file"
+)
+
+var (
+	// ErrTooLarge is returned if the link target exceeds the size limit.
+	ErrTooLarge = errors.New("link target exceeds the size limit")
+	// ErrNotAllowed is returned if the link is not allowed to be fetched.
+	ErrNotAllowed = errors.New("link is not allowed")
+)
+
+// nonPublic are the address ranges, that are not covered by the
+// [netip.Addr] methods, and are not reachable from the internet.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("198.18.0.0/15"), // benchmarking
+}
+
+// slackDomains are the domains that require authentication, the links to
+// them are never fetched.
+var slackDomains = []string{"slack.com", "slack-edge.com", "slack-files.com", "slack-imgs.com"}
+
+// Key returns the key of the link url in the links storage area.
+func Key(u string) string {
+	sum := sha256.Sum256([]byte(u))
+	return hex.EncodeToString(sum[:16])
+}
+
+// AttachmentURL returns the URL of the link unfurled in the attachment a, or
+// an empty string, if the attachment does not unfurl a link.
+func AttachmentURL(a slack.Attachment) string {
+	for _, u := range []string{a.OriginalURL, a.FromURL, a.TitleLink} {
+		if u != "" {
+			return u
+		}
+	}
+	return ""
+}
+
+// FileURL returns the URL of the external file f, or an empty string, if f is
+// not an external file.
+func FileURL(f slack.File) string {
+	if f.Mode != "external" && !f.IsExternal {
+		return ""
+	}
+	return f.URLPrivate
+}
+
+// URLs returns the external link URLs of the message m, without duplicates.
+func URLs(m *slack.Message) []string {
+	var uu []string
+	seen := make(map[string]struct{})
+	add := func(u string) {
+		if _, ok := seen[u]; ok || u == "" {
+			return
+		}
+		seen[u] = struct{}{}
+		uu = append(uu, u)
+	}
+	for _, f := range m.Files {
+		add(FileURL(f))
+	}
+	for _, a := range m.Attachments {
+		add(AttachmentURL(a))
+	}
+	return uu
+}
+
+// Fetcher archives the link targets into the links storage area of the
+// filesystem.  Links are fetched in the background, the Fetcher must be
+// closed to wait for the pending fetches to complete.
+type Fetcher struct {
+	ctx     context.Context
+	fsa     fsadapter.FS
+	cl      *http.Client
+	allow   []string
+	maxSize int64
+	lg      *slog.Logger
+	// addrOK returns an error if the address can not be connected to, if
+	// nil, only public addresses are allowed, see [checkPublic].
+	addrOK func(netip.AddrPort) error
+
+	mu     sync.Mutex
+	seen   map[string]struct{}
+	q      chan string
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// Option is the functional option for the [Fetcher].
+type Option func(*Fetcher)
+
+// WithAllowedDomains limits the links to the domains in dd and their
+// subdomains.  If not set, links to any domain are fetched.
+func WithAllowedDomains(dd []string) Option {
+	return func(f *Fetcher) {
+		for _, d := range dd {
+			if d = strings.Trim(strings.ToLower(strings.TrimSpace(d)), "."); d != "" {
+				f.allow = append(f.allow, d)
+			}
+		}
+	}
+}
+
+// WithMaxSize sets the size limit of the archived link, links that exceed it
+// are skipped.  The default is [DefaultMaxSize].
+func WithMaxSize(n int64) Option {
+	return func(f *Fetcher) {
+		if n > 0 {
+			f.maxSize = n
+		}
+	}
+}
+
+// WithLogger sets the logger.
+func WithLogger(lg *slog.Logger) Option {
+	return func(f *Fetcher) {
+		if lg != nil {
+			f.lg = lg
+		}
+	}
+}
+
+// New creates a new Fetcher, that saves the links to fsa, and starts the
+// workers, which stop when ctx is cancelled or the Fetcher is closed.
+func New(ctx context.Context, fsa fsadapter.FS, opts ...Option) *Fetcher {
+	f := &Fetcher{
+		ctx:     ctx,
+		fsa:     fsa,
+		maxSize: DefaultMaxSize,
+		lg:      slog.Default(),
+		seen:    make(map[string]struct{}),
+		q:       make(chan string, 100),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	f.cl = f.newClient()
+	for range defNumWorkers {
+		f.wg.Go(f.worker)
+	}
+	return f
+}
+
+// Messages queues the external links of the messages for fetching.
+func (f *Fetcher) Messages(ctx context.Context, _ string, _ int, _ bool, mm []slack.Message) error {
+	return f.queue(ctx, mm)
+}
+
+// ThreadMessages queues the external links of the thread messages for
+// fetching.
+func (f *Fetcher) ThreadMessages(ctx context.Context, _ string, _ slack.Message, _, _ bool, mm []slack.Message) error {
+	return f.queue(ctx, mm)
+}
+
+func (f *Fetcher) queue(ctx context.Context, mm []slack.Message) error {
+	for i := range mm {
+		for _, u := range URLs(&mm[i]) {
+			if !f.add(u) {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-f.ctx.Done():
+				return f.ctx.Err()
+			case f.q <- u:
+			}
+		}
+	}
+	return nil
+}
+
+// add returns true if the url u is allowed and was not seen before.
+func (f *Fetcher) add(u string) bool {
+	if err := f.allowed(u); err != nil {
+		f.lg.Debug("skipping link", "url", u, "error", err)
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return false
+	}
+	if _, ok := f.seen[u]; ok {
+		return false
+	}
+	f.seen[u] = struct{}{}
+	return true
+}
+
+// Close waits for the queued links to be fetched.
+func (f *Fetcher) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil
+	}
+	f.closed = true
+	f.mu.Unlock()
+	close(f.q)
+	f.wg.Wait()
+	return nil
+}
+
+func (f *Fetcher) worker() {
+	for u := range f.q {
+		if f.ctx.Err() != nil {
+			continue // drain the queue
+		}
+		if err := f.Fetch(f.ctx, u); err != nil {
+			f.lg.Warn("unable to archive the link", "url", u, "error", err)
+		}
+	}
+}
+
+// newClient returns the HTTP client, that connects only to the addresses
+// allowed by [Fetcher.checkAddr], after the host name is resolved, and checks
+// each redirect with [Fetcher.allowed].  The proxy is not used, as the
+// address of the link target could not be checked then.
+func (f *Fetcher) newClient() *http.Client {
+	d := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			ap, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			return f.checkAddr(ap)
+		},
+	}
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.Proxy = nil
+	tr.DialContext = d.DialContext
+	return &http.Client{
+		Transport: tr,
+		Timeout:   defTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("%w: stopped after %d redirects", ErrNotAllowed, maxRedirects)
+			}
+			return f.allowed(req.URL.String())
+		},
+	}
+}
+
+// checkAddr returns an error if the address ap can not be connected to.
+func (f *Fetcher) checkAddr(ap netip.AddrPort) error {
+	if f.addrOK != nil {
+		return f.addrOK(ap)
+	}
+	return checkPublic(ap)
+}
+
+// checkPublic returns an error if the address ap is not a public unicast
+// address: loopback, private (RFC 1918, IPv6 ULA), link-local, i.e. the
+// instance metadata at 169.254.169.254, multicast, and others.
+func checkPublic(ap netip.AddrPort) error {
+	addr := ap.Addr().Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return fmt.Errorf("%w: %s is not a public address", ErrNotAllowed, addr)
+	}
+	for _, p := range nonPublic {
+		if p.Contains(addr) {
+			return fmt.Errorf("%w: %s is not a public address", ErrNotAllowed, addr)
+		}
+	}
+	return nil
+}
+
+// allowed returns an error if the link url u can not be fetched.
+func (f *Fetcher) allowed(u string) error {
+	pu, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+	if pu.Scheme != "http" && pu.Scheme != "https" {
+		return fmt.Errorf("%w: unsupported scheme %q", ErrNotAllowed, pu.Scheme)
+	}
+	host := strings.ToLower(pu.Hostname())
+	if host == "" {
+		return fmt.Errorf("%w: no host", ErrNotAllowed)
+	}
+	if pu.User != nil {
+		return fmt.Errorf("%w: credentials in the link", ErrNotAllowed)
+	}
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		// literal IP address, the host names are checked when connecting.
+		port, _ := strconv.ParseUint(pu.Port(), 10, 16)
+		if err := f.checkAddr(netip.AddrPortFrom(addr, uint16(port))); err != nil {
+			return err
+		}
+	}
+	if inDomains(host, slackDomains) {
+		return fmt.Errorf("%w: %s requires authentication", ErrNotAllowed, host)
+	}
+	if len(f.allow) > 0 && !inDomains(host, f.allow) {
+		return fmt.Errorf("%w: %s is not in the allowed domains", ErrNotAllowed, host)
+	}
+	return nil
+}
+
+// inDomains returns true if host is one of the domains dd, or their
+// subdomain.
+func inDomains(host string, dd []string) bool {
+	for _, d := range dd {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetch archives the target of the link url u.  The link is requested
+// without any credentials, so only the publicly accessible targets are
+// archived.  Links to the non-public addresses are refused, see the package
+// documentation.  HTML pages are saved as index.html, other files under their
+// own names.
+func (f *Fetcher) Fetch(ctx context.Context, u string) error {
+	if err := f.allowed(u); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := f.cl.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	if resp.ContentLength > f.maxSize {
+		return fmt.Errorf("%w: %d bytes", ErrTooLarge, resp.ContentLength)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, f.maxSize+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > f.maxSize {
+		return ErrTooLarge
+	}
+	pth := path.Join(chunk.LinksDir, Key(u), filename(resp))
+	if err := f.fsa.WriteFile(pth, data, 0o644); err != nil {
+		return err
+	}
+	f.lg.Debug("archived link", "url", u, "path", pth, "size", len(data))
+	return nil
+}
+
+var unsafeNameRe = regexp.MustCompile(`[<>:"/\\|?*\x00-\x1f]`)
+
+// filename returns the name of the file to save the response to.
+func filename(resp *http.Response) string {
+	if mt, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil && (mt == "text/html" || mt == "application/xhtml+xml") {
+		return htmlName
+	}
+	var name string
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		name = params["filename"]
+	}
+	if name == "" && resp.Request != nil {
+		name = path.Base(resp.Request.URL.Path)
+	}
+	if name = path.Base(name); name == "/" {
+		name = ""
+	}
+	name = strings.Trim(unsafeNameRe.ReplaceAllString(name, "_"), " .")
+	if name == "" || name == htmlName {
+		return defName
+	}
+	return name
+}
@@ -147,6 +147,7 @@ Once a source is loaded the following tools become available:
 - list_users     – list all users/members
 - get_messages   – read messages from a channel (paginated)
 - get_thread     – read thread replies
+- get_channel_membership – who joined or left a channel, and when
 - get_workspace_info – get workspace information
 `
 	}
@@ -159,6 +160,7 @@ Available tools allow you to:
 - List all users/members
 - Read messages from a channel (paginated)
 - Read thread replies
+- Find out who was a member of a channel at a given time
 - Get workspace information
 - Get command-line flag help for slackdump subcommands
 
@@ -244,6 +246,7 @@ func (s *Server) tools() []mcpsrv.ServerTool {
 		s.toolListUsers(),
 		s.toolGetMessages(),
 		s.toolGetThread(),
+		s.toolGetChannelMembership(),
 		s.toolGetWorkspaceInfo(),
 	}
 }
@@ -21,11 +21,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	mcplib "github.com/mark3labs/mcp-go/mcp"
 	mcpsrv "github.com/mark3labs/mcp-go/server"
 
 	"github.com/rusq/slackdump/v4/source"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 // errNoSource is returned by tool handlers when no source has been loaded yet.
@@ -82,7 +84,7 @@ func (s *Server) handleLoadSource(ctx context.Context, req mcplib.CallToolReques
 
 func (s *Server) toolListChannels() mcpsrv.ServerTool {
 	tool := mcplib.NewTool("list_channels",
-		mcplib.WithDescription("List all channels (conversations) present in the Slackdump archive. Returns channel IDs, names, types, and member counts."),
+		mcplib.WithDescription("List all channels (conversations) present in the Slackdump archive. Returns channel IDs, names, display names (DMs and group DMs are named after their participants), types, and member counts."),
 		mcplib.WithReadOnlyHintAnnotation(true),
 	)
 	return mcpsrv.ServerTool{Tool: tool, Handler: s.handleListChannels}
@@ -92,6 +94,7 @@ func (s *Server) toolListChannels() mcpsrv.ServerTool {
 type channelSummary struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
+	DisplayName string `json:"display_name,omitempty"` // human-readable name, DMs are named after the participants
 	IsChannel   bool   `json:"is_channel,omitempty"`
 	IsGroup     bool   `json:"is_group,omitempty"`
 	IsIM        bool   `json:"is_im,omitempty"`
@@ -116,6 +119,11 @@ func (s *Server) handleListChannels(ctx context.Context, req mcplib.CallToolRequ
 		return resultErr(fmt.Errorf("list_channels: %w", err)), nil
 	}
 
+	names, err := source.NameResolver(ctx, src)
+	if err != nil {
+		return resultErr(fmt.Errorf("list_channels: %w", err)), nil
+	}
+
 	summaries := make([]channelSummary, 0, len(channels))
 	for _, c := range channels {
 		topic := ""
@@ -129,6 +137,7 @@ func (s *Server) handleListChannels(ctx context.Context, req mcplib.CallToolRequ
 		summaries = append(summaries, channelSummary{
 			ID:          c.ID,
 			Name:        c.Name,
+			DisplayName: names.Name(c),
 			IsChannel:   c.IsChannel,
 			IsGroup:     c.IsGroup,
 			IsIM:        c.IsIM,
@@ -413,6 +422,93 @@ func (s *Server) handleGetThread(ctx context.Context, req mcplib.CallToolRequest
 	return result, nil
 }
 
+// ─── get_channel_membership ───────────────────────────────────────────────────
+
+func (s *Server) toolGetChannelMembership() mcpsrv.ServerTool {
+	tool := mcplib.NewTool("get_channel_membership",
+		mcplib.WithDescription(`Return the membership timeline of a channel: who joined or left it, and when.
+
+The timeline is derived from the join/leave messages and, for database archives,
+from the member lists recorded by each archive session.  Events derived from the
+member lists are marked as "inferred": the change happened at some point before
+the event time.  Set the 'at' parameter to get the list of channel members at
+the given point in time instead of the timeline.`),
+		mcplib.WithString("channel_id",
+			mcplib.Description("The Slack channel ID (e.g. C01234ABCD)"),
+			mcplib.Required(),
+		),
+		mcplib.WithString("at",
+			mcplib.Description("Return the members of the channel at this time (RFC 3339, e.g. \"2024-01-31T12:00:00Z\", or a date, e.g. \"2024-01-31\", meaning the end of that day in UTC)."),
+		),
+		mcplib.WithReadOnlyHintAnnotation(true),
+	)
+	return mcpsrv.ServerTool{Tool: tool, Handler: s.handleGetChannelMembership}
+}
+
+// membersAt is the JSON-serialisable list of channel members at the given
+// time.
+type membersAt struct {
+	ChannelID string    `json:"channel_id"`
+	At        time.Time `json:"at"`
+	Members   []string  `json:"members"`
+}
+
+// parseAt parses the "at" argument of get_channel_membership.
+func parseAt(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: expected RFC 3339 or YYYY-MM-DD", s)
+	}
+	return t.Add(24*time.Hour - time.Nanosecond), nil
+}
+
+func (s *Server) handleGetChannelMembership(ctx context.Context, req mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	src := s.source()
+	if src == nil {
+		return resultErr(errNoSource), nil
+	}
+
+	channelID, ok := stringArg(req, "channel_id")
+	if !ok || channelID == "" {
+		return resultErr(errors.New("get_channel_membership: channel_id is required")), nil
+	}
+	var (
+		at    time.Time
+		hasAt bool
+	)
+	if v, ok := stringArg(req, "at"); ok && v != "" {
+		t, err := parseAt(v)
+		if err != nil {
+			return resultErr(fmt.Errorf("get_channel_membership: %w", err)), nil
+		}
+		at, hasAt = t, true
+	}
+
+	timeline, err := src.ChannelMembership(ctx, channelID)
+	if err != nil {
+		if errors.Is(err, source.ErrNotFound) {
+			return resultText(fmt.Sprintf("No membership information found for channel %q.", channelID)), nil
+		}
+		if errors.Is(err, source.ErrNotSupported) {
+			return resultText("This archive type does not support channel membership lookup."), nil
+		}
+		return resultErr(fmt.Errorf("get_channel_membership: %w", err)), nil
+	}
+
+	var v any = timeline
+	if hasAt {
+		v = membersAt{ChannelID: channelID, At: at, Members: types.MembersAt(timeline, at)}
+	}
+	result, err := resultJSON(v)
+	if err != nil {
+		return resultErr(fmt.Errorf("get_channel_membership: serialise: %w", err)), nil
+	}
+	return result, nil
+}
+
 // ─── get_workspace_info ───────────────────────────────────────────────────────
 
 func (s *Server) toolGetWorkspaceInfo() mcpsrv.ServerTool {
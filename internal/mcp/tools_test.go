@@ -20,6 +20,7 @@ import (
 	"errors"
 	"iter"
 	"testing"
+	"time"
 
 	mcplib "github.com/mark3labs/mcp-go/mcp"
 	"github.com/rusq/slack"
@@ -29,6 +30,7 @@ import (
 
 	"github.com/rusq/slackdump/v4/source"
 	"github.com/rusq/slackdump/v4/source/mock_source"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 // seqOf returns an iter.Seq2[slack.Message, error] that yields the given
@@ -80,13 +82,29 @@ func TestHandleListChannels(t *testing.T) {
 					{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C1"}, Name: "general"}, IsChannel: true},
 					{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C2"}, Name: "random"}, IsChannel: true},
 				}, nil)
+				m.EXPECT().Users(gomock.Any()).Return(nil, source.ErrNotFound)
+				m.EXPECT().WorkspaceInfo(gomock.Any()).Return(nil, source.ErrNotFound)
 			},
 			wantText: "C1",
 		},
+		{
+			name: "DMs are named after the other participants",
+			setup: func(m *mock_source.MockSourceResumeCloser) {
+				m.EXPECT().Channels(gomock.Any()).Return([]slack.Channel{
+					{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "D1", IsIM: true, User: "U2"}}},
+					{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "G1", IsMpIM: true}, Name: "mpdm-alice--bob--carol-1", Members: []string{"U1", "U2", "U3"}}},
+				}, nil)
+				m.EXPECT().Users(gomock.Any()).Return([]slack.User{{ID: "U1", Name: "alice"}, {ID: "U2", Name: "bob"}, {ID: "U3", Name: "carol"}}, nil)
+				m.EXPECT().WorkspaceInfo(gomock.Any()).Return(&slack.AuthTestResponse{UserID: "U1"}, nil)
+			},
+			wantText: `"display_name":"bob, carol"`,
+		},
 		{
 			name: "empty list returns empty JSON array",
 			setup: func(m *mock_source.MockSourceResumeCloser) {
 				m.EXPECT().Channels(gomock.Any()).Return([]slack.Channel{}, nil)
+				m.EXPECT().Users(gomock.Any()).Return(nil, source.ErrNotFound)
+				m.EXPECT().WorkspaceInfo(gomock.Any()).Return(nil, source.ErrNotFound)
 			},
 			wantText: "[]",
 		},
@@ -475,6 +493,86 @@ func TestHandleGetWorkspaceInfo(t *testing.T) {
 	}
 }
 
+// ─── handleGetChannelMembership ───────────────────────────────────────────────
+
+func TestHandleGetChannelMembership(t *testing.T) {
+	timeline := []types.MembershipEvent{
+		{Time: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), UserID: "U1", Action: types.MemberJoined},
+		{Time: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC), UserID: "U2", Action: types.MemberJoined},
+		{Time: time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC), UserID: "U1", Action: types.MemberLeft},
+	}
+	tests := []struct {
+		name        string
+		args        map[string]any
+		setup       func(m *mock_source.MockSourceResumeCloser)
+		wantIsError bool
+		wantText    string
+	}{
+		{
+			name:        "missing channel_id returns error result",
+			args:        nil,
+			setup:       func(m *mock_source.MockSourceResumeCloser) {},
+			wantIsError: true,
+			wantText:    "channel_id",
+		},
+		{
+			name:        "invalid at returns error result",
+			args:        map[string]any{"channel_id": "C1", "at": "yesterday"},
+			setup:       func(m *mock_source.MockSourceResumeCloser) {},
+			wantIsError: true,
+			wantText:    "yesterday",
+		},
+		{
+			name: "returns the timeline",
+			args: map[string]any{"channel_id": "C1"},
+			setup: func(m *mock_source.MockSourceResumeCloser) {
+				m.EXPECT().ChannelMembership(gomock.Any(), "C1").Return(timeline, nil)
+			},
+			wantText: `"action":"left"`,
+		},
+		{
+			name: "returns members at date",
+			args: map[string]any{"channel_id": "C1", "at": "2024-01-02"},
+			setup: func(m *mock_source.MockSourceResumeCloser) {
+				m.EXPECT().ChannelMembership(gomock.Any(), "C1").Return(timeline, nil)
+			},
+			wantText: `"members":["U1","U2"]`,
+		},
+		{
+			name: "ErrNotFound returns informational text",
+			args: map[string]any{"channel_id": "C999"},
+			setup: func(m *mock_source.MockSourceResumeCloser) {
+				m.EXPECT().ChannelMembership(gomock.Any(), "C999").Return(nil, source.ErrNotFound)
+			},
+			wantText: "C999",
+		},
+		{
+			name: "generic error returns error result",
+			args: map[string]any{"channel_id": "C1"},
+			setup: func(m *mock_source.MockSourceResumeCloser) {
+				m.EXPECT().ChannelMembership(gomock.Any(), "C1").Return(nil, errors.New("db error"))
+			},
+			wantIsError: true,
+			wantText:    "db error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv, mock := newTestServer(t, ctrl)
+			tt.setup(mock)
+
+			result, err := srv.handleGetChannelMembership(t.Context(), toolReq(tt.args))
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantIsError, isErrorResult(result))
+			if tt.wantText != "" {
+				assert.Contains(t, firstText(t, result), tt.wantText)
+			}
+		})
+	}
+}
+
 // ─── handleLoadSource ─────────────────────────────────────────────────────────
 
 func TestHandleLoadSource(t *testing.T) {
@@ -587,6 +685,7 @@ func TestHandlers_nilSource(t *testing.T) {
 		{"list_users", srv.handleListUsers},
 		{"get_messages", srv.handleGetMessages},
 		{"get_thread", srv.handleGetThread},
+		{"get_channel_membership", srv.handleGetChannelMembership},
 		{"get_workspace_info", srv.handleGetWorkspaceInfo},
 	}
 
@@ -0,0 +1,410 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package mockslack
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/internal/fasttime"
+	"github.com/rusq/slackdump/v4/source"
+)
+
+const (
+	defLimit = 100  // default page size
+	maxLimit = 1000 // maximum page size
+)
+
+type responseMetadata struct {
+	NextCursor string `json:"next_cursor"`
+}
+
+type authTestResponse struct {
+	slack.SlackResponse
+	slack.AuthTestResponse
+}
+
+type channelsResponse struct {
+	slack.SlackResponse
+	Channels []slack.Channel  `json:"channels"`
+	Metadata responseMetadata `json:"response_metadata"`
+}
+
+type channelResponse struct {
+	slack.SlackResponse
+	Channel *slack.Channel `json:"channel"`
+}
+
+type membersResponse struct {
+	slack.SlackResponse
+	Members  []string         `json:"members"`
+	Metadata responseMetadata `json:"response_metadata"`
+}
+
+type messagesResponse struct {
+	slack.SlackResponse
+	HasMore  bool             `json:"has_more"`
+	Messages []slack.Message  `json:"messages"`
+	Metadata responseMetadata `json:"response_metadata"`
+}
+
+type usersResponse struct {
+	slack.SlackResponse
+	Members  []slack.User     `json:"members"`
+	Metadata responseMetadata `json:"response_metadata"`
+}
+
+type userResponse struct {
+	slack.SlackResponse
+	User *slack.User `json:"user"`
+}
+
+type fileResponse struct {
+	slack.SlackResponse
+	File *slack.File `json:"file"`
+}
+
+var okResponse = slack.SlackResponse{Ok: true}
+
+func (s *Server) handleAuthTest(w http.ResponseWriter, r *http.Request) {
+	wi, err := s.src.WorkspaceInfo(r.Context())
+	if err != nil || wi == nil {
+		// not all sources have the workspace information.
+		wi = &slack.AuthTestResponse{
+			URL:    baseURL(r) + "/",
+			Team:   "mock",
+			User:   "mock",
+			TeamID: "T00000000",
+			UserID: "U00000000",
+		}
+	}
+	s.writeJSON(w, r, authTestResponse{SlackResponse: okResponse, AuthTestResponse: *wi})
+}
+
+func (s *Server) handleConversationsList(w http.ResponseWriter, r *http.Request) {
+	channels, err := s.allChannels(r.Context())
+	if err != nil {
+		s.writeFailure(w, r, err)
+		return
+	}
+	if types := r.FormValue("types"); types != "" {
+		want := strings.Split(types, ",")
+		channels = slices.DeleteFunc(slices.Clone(channels), func(ch slack.Channel) bool {
+			return !slices.Contains(want, channelType(&ch))
+		})
+	}
+	pg, next, err := paginate(channels, r)
+	if err != nil {
+		s.writeError(w, r, "invalid_cursor")
+		return
+	}
+	s.writeJSON(w, r, channelsResponse{SlackResponse: okResponse, Channels: pg, Metadata: responseMetadata{next}})
+}
+
+func (s *Server) handleConversationsInfo(w http.ResponseWriter, r *http.Request) {
+	ch, err := s.channel(r)
+	if err != nil {
+		s.writeChannelError(w, r, err)
+		return
+	}
+	s.writeJSON(w, r, channelResponse{SlackResponse: okResponse, Channel: ch})
+}
+
+func (s *Server) handleConversationsMembers(w http.ResponseWriter, r *http.Request) {
+	ch, err := s.channel(r)
+	if err != nil {
+		s.writeChannelError(w, r, err)
+		return
+	}
+	pg, next, err := paginate(nonNil(ch.Members), r)
+	if err != nil {
+		s.writeError(w, r, "invalid_cursor")
+		return
+	}
+	s.writeJSON(w, r, membersResponse{SlackResponse: okResponse, Members: pg, Metadata: responseMetadata{next}})
+}
+
+func (s *Server) handleConversationsHistory(w http.ResponseWriter, r *http.Request) {
+	ch, err := s.channel(r)
+	if err != nil {
+		s.writeChannelError(w, r, err)
+		return
+	}
+	mm, err := s.channelMessages(r.Context(), ch.ID)
+	if err != nil {
+		s.writeFailure(w, r, err)
+		return
+	}
+	inRange, err := timeRange(r)
+	if err != nil {
+		s.writeError(w, r, "invalid_ts_oldest")
+		return
+	}
+	mm = slices.DeleteFunc(slices.Clone(mm), func(m slack.Message) bool { return !inRange(m.Timestamp) })
+	s.writeMessages(w, r, mm)
+}
+
+func (s *Server) handleConversationsReplies(w http.ResponseWriter, r *http.Request) {
+	ch, err := s.channel(r)
+	if err != nil {
+		s.writeChannelError(w, r, err)
+		return
+	}
+	ts := r.FormValue("ts")
+	if ts == "" {
+		s.writeError(w, r, "invalid_arguments")
+		return
+	}
+	mm, err := s.threadMessages(r.Context(), ch.ID, ts)
+	if err != nil {
+		if errors.Is(err, source.ErrNotFound) {
+			s.writeError(w, r, "thread_not_found")
+		} else {
+			s.writeFailure(w, r, err)
+		}
+		return
+	}
+	inRange, err := timeRange(r)
+	if err != nil {
+		s.writeError(w, r, "invalid_ts_oldest")
+		return
+	}
+	// the thread lead is always returned, the range applies to replies.
+	mm = slices.DeleteFunc(mm, func(m slack.Message) bool { return m.Timestamp != ts && !inRange(m.Timestamp) })
+	s.writeMessages(w, r, mm)
+}
+
+func (s *Server) writeMessages(w http.ResponseWriter, r *http.Request, mm []slack.Message) {
+	pg, next, err := paginate(mm, r)
+	if err != nil {
+		s.writeError(w, r, "invalid_cursor")
+		return
+	}
+	base := baseURL(r)
+	for i := range pg {
+		pg[i].Files = rewriteFiles(base, pg[i].Files)
+	}
+	s.writeJSON(w, r, messagesResponse{
+		SlackResponse: okResponse,
+		HasMore:       next != "",
+		Messages:      pg,
+		Metadata:      responseMetadata{next},
+	})
+}
+
+func (s *Server) handleUsersList(w http.ResponseWriter, r *http.Request) {
+	users, err := s.allUsers(r.Context())
+	if err != nil {
+		s.writeFailure(w, r, err)
+		return
+	}
+	pg, next, err := paginate(users, r)
+	if err != nil {
+		s.writeError(w, r, "invalid_cursor")
+		return
+	}
+	s.writeJSON(w, r, usersResponse{SlackResponse: okResponse, Members: pg, Metadata: responseMetadata{next}})
+}
+
+func (s *Server) handleUsersInfo(w http.ResponseWriter, r *http.Request) {
+	users, err := s.allUsers(r.Context())
+	if err != nil {
+		s.writeFailure(w, r, err)
+		return
+	}
+	id := r.FormValue("user")
+	idx := slices.IndexFunc(users, func(u slack.User) bool { return u.ID == id })
+	if idx < 0 {
+		s.writeError(w, r, "user_not_found")
+		return
+	}
+	s.writeJSON(w, r, userResponse{SlackResponse: okResponse, User: &users[idx]})
+}
+
+func (s *Server) handleFilesInfo(w http.ResponseWriter, r *http.Request) {
+	idx, err := s.fileIndex(r.Context())
+	if err != nil {
+		s.writeFailure(w, r, err)
+		return
+	}
+	f, ok := idx[r.FormValue("file")]
+	if !ok {
+		s.writeError(w, r, "file_not_found")
+		return
+	}
+	ff := rewriteFiles(baseURL(r), []slack.File{f})
+	s.writeJSON(w, r, fileResponse{SlackResponse: okResponse, File: &ff[0]})
+}
+
+func (s *Server) handleUnknown(w http.ResponseWriter, r *http.Request) {
+	s.writeError(w, r, "unknown_method")
+}
+
+// handleFile serves the file contents from the source file storage.
+func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
+	st := s.src.Files()
+	if st == nil {
+		http.NotFound(w, r)
+		return
+	}
+	name, err := st.File(r.PathValue("id"), r.PathValue("name"))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			s.lg.ErrorContext(r.Context(), "error locating file", "id", r.PathValue("id"), "error", err)
+		}
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFileFS(w, r, st.FS(), name)
+}
+
+// channel returns the channel requested in the "channel" parameter.
+func (s *Server) channel(r *http.Request) (*slack.Channel, error) {
+	id := r.FormValue("channel")
+	if id == "" {
+		return nil, source.ErrNotFound
+	}
+	ch, err := s.src.ChannelInfo(r.Context(), id)
+	if err == nil {
+		return ch, nil
+	}
+	// some sources do not have the channel info for all channels that have
+	// messages, so fall back to the channel list.
+	channels, lerr := s.allChannels(r.Context())
+	if lerr != nil {
+		return nil, lerr
+	}
+	if idx := slices.IndexFunc(channels, func(c slack.Channel) bool { return c.ID == id }); idx >= 0 {
+		return &channels[idx], nil
+	}
+	return nil, err
+}
+
+func (s *Server) writeChannelError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, source.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+		s.writeError(w, r, "channel_not_found")
+		return
+	}
+	s.writeFailure(w, r, err)
+}
+
+// channelType returns the conversation type, as used in the "types"
+// parameter of conversations.list.
+func channelType(ch *slack.Channel) string {
+	switch {
+	case ch.IsIM:
+		return "im"
+	case ch.IsMpIM:
+		return "mpim"
+	case ch.IsPrivate:
+		return "private_channel"
+	default:
+		return "public_channel"
+	}
+}
+
+// paginate returns the page of items requested by the "cursor" and "limit"
+// parameters, and the cursor of the next page, that is empty on the last
+// page.  The cursor is the offset of the first item of the page.
+func paginate[T any](items []T, r *http.Request) ([]T, string, error) {
+	var offset int
+	if c := r.FormValue("cursor"); c != "" {
+		var err error
+		if offset, err = strconv.Atoi(c); err != nil || offset < 0 {
+			return nil, "", errors.New("invalid cursor")
+		}
+	}
+	limit, err := strconv.Atoi(r.FormValue("limit"))
+	if err != nil || limit <= 0 {
+		limit = defLimit
+	}
+	limit = min(limit, maxLimit)
+
+	if offset >= len(items) {
+		return []T{}, "", nil
+	}
+	end := min(offset+limit, len(items))
+	var next string
+	if end < len(items) {
+		next = strconv.Itoa(end)
+	}
+	return items[offset:end], next, nil
+}
+
+// timeRange returns the function that reports whether the timestamp is within
+// the range requested by the "oldest", "latest" and "inclusive" parameters.
+func timeRange(r *http.Request) (func(ts string) bool, error) {
+	oldest, err := tsParam(r.FormValue("oldest"))
+	if err != nil {
+		return nil, err
+	}
+	latest, err := tsParam(r.FormValue("latest"))
+	if err != nil {
+		return nil, err
+	}
+	inclusive := r.FormValue("inclusive") == "true" || r.FormValue("inclusive") == "1"
+	return func(ts string) bool {
+		t, err := fasttime.TS2int(ts)
+		if err != nil {
+			return false
+		}
+		if oldest > 0 && (t < oldest || (t == oldest && !inclusive)) {
+			return false
+		}
+		if latest > 0 && (t > latest || (t == latest && !inclusive)) {
+			return false
+		}
+		return true
+	}, nil
+}
+
+func tsParam(s string) (int64, error) {
+	if s == "" || s == "0" {
+		return 0, nil
+	}
+	return fasttime.TS2int(s)
+}
+
+// rewriteFiles returns the copy of files with the download URLs pointing to
+// the server.
+func rewriteFiles(base string, files []slack.File) []slack.File {
+	if len(files) == 0 {
+		return files
+	}
+	ff := slices.Clone(files)
+	for i := range ff {
+		u := base + "/files/" + url.PathEscape(ff[i].ID) + "/" + url.PathEscape(ff[i].Name)
+		ff[i].URLPrivate = u
+		ff[i].URLPrivateDownload = u
+	}
+	return ff
+}
+
+// baseURL returns the URL of the server as seen by the client.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
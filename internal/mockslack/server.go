@@ -0,0 +1,310 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package mockslack serves the contents of any [source.Sourcer] as a fake
+// Slack Web API.  It is intended for integration tests of tools built on top
+// of slackdump, that should not depend on a real workspace.
+package mockslack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/internal/fasttime"
+	"github.com/rusq/slackdump/v4/source"
+)
+
+// Server is the fake Slack API server.  It implements [http.Handler], API
+// methods are served under the "/api/" prefix, and the file contents under
+// "/files/".
+type Server struct {
+	src source.Sourcer
+	mux *http.ServeMux
+	lg  *slog.Logger
+
+	latency    time.Duration
+	limitEvery int
+	retryAfter time.Duration
+	requests   atomic.Int64
+	apiCalls   atomic.Int64 // API requests, for the rate limit injection
+
+	mu       sync.Mutex
+	channels []slack.Channel
+	users    []slack.User
+	messages map[string][]slack.Message // channel ID -> messages, newest first
+	files    map[string]slack.File      // file ID -> file, populated lazily
+}
+
+// Option is the function that configures the [Server].
+type Option func(*Server)
+
+// WithLatency delays every response by d.
+func WithLatency(d time.Duration) Option {
+	return func(s *Server) {
+		if d > 0 {
+			s.latency = d
+		}
+	}
+}
+
+// WithRateLimit makes the server respond with HTTP 429 "Too Many Requests"
+// to every n-th API request, asking the client to retry after retryAfter.
+// File downloads are not counted and never rate limited.
+// Zero or negative n disables the injected rate limiting.
+func WithRateLimit(n int, retryAfter time.Duration) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.limitEvery = n
+			s.retryAfter = max(retryAfter, time.Second)
+		}
+	}
+}
+
+// WithLogger sets the logger for the server.
+func WithLogger(lg *slog.Logger) Option {
+	return func(s *Server) {
+		if lg != nil {
+			s.lg = lg
+		}
+	}
+}
+
+// New creates a new fake API server, that serves the data from src.
+func New(src source.Sourcer, opts ...Option) *Server {
+	s := &Server{
+		src:      src,
+		lg:       slog.Default(),
+		messages: make(map[string][]slack.Message),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.mux = s.router()
+	return s
+}
+
+func (s *Server) router() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth.test", s.handleAuthTest)
+	mux.HandleFunc("/api/conversations.list", s.handleConversationsList)
+	mux.HandleFunc("/api/conversations.info", s.handleConversationsInfo)
+	mux.HandleFunc("/api/conversations.members", s.handleConversationsMembers)
+	mux.HandleFunc("/api/conversations.history", s.handleConversationsHistory)
+	mux.HandleFunc("/api/conversations.replies", s.handleConversationsReplies)
+	mux.HandleFunc("/api/users.list", s.handleUsersList)
+	mux.HandleFunc("/api/users.info", s.handleUsersInfo)
+	mux.HandleFunc("/api/files.info", s.handleFilesInfo)
+	mux.HandleFunc("/api/", s.handleUnknown)
+	mux.HandleFunc("GET /files/{id}/{name}", s.handleFile)
+	return mux
+}
+
+// ServeHTTP implements [http.Handler].
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := s.requests.Add(1)
+	if s.latency > 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(s.latency):
+		}
+	}
+	if s.limitEvery > 0 && strings.HasPrefix(r.URL.Path, "/api/") && s.apiCalls.Add(1)%int64(s.limitEvery) == 0 {
+		s.lg.DebugContext(r.Context(), "injecting rate limit", "path", r.URL.Path, "request", n)
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(s.retryAfter.Seconds()))))
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	s.lg.DebugContext(r.Context(), "request", "path", r.URL.Path, "request", n)
+	s.mux.ServeHTTP(w, r)
+}
+
+// Requests returns the number of requests served so far, including the
+// rate limited ones.
+func (s *Server) Requests() int64 {
+	return s.requests.Load()
+}
+
+// allChannels returns the cached list of channels of the source.
+func (s *Server) allChannels(ctx context.Context) ([]slack.Channel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.channels != nil {
+		return s.channels, nil
+	}
+	ch, err := s.src.Channels(ctx)
+	if err != nil && !errors.Is(err, source.ErrNotFound) {
+		return nil, err
+	}
+	s.channels = nonNil(ch)
+	return s.channels, nil
+}
+
+// allUsers returns the cached list of users of the source.
+func (s *Server) allUsers(ctx context.Context) ([]slack.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.users != nil {
+		return s.users, nil
+	}
+	u, err := s.src.Users(ctx)
+	if err != nil && !errors.Is(err, source.ErrNotFound) {
+		return nil, err
+	}
+	s.users = nonNil(u)
+	return s.users, nil
+}
+
+// channelMessages returns the cached channel messages, sorted newest first,
+// as the conversations.history API returns them.
+func (s *Server) channelMessages(ctx context.Context, channelID string) ([]slack.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if mm, ok := s.messages[channelID]; ok {
+		return mm, nil
+	}
+	it, err := s.src.AllMessages(ctx, channelID)
+	if err != nil && !errors.Is(err, source.ErrNotFound) {
+		return nil, err
+	}
+	var mm []slack.Message
+	if err == nil {
+		if mm, err = collect(it); err != nil {
+			return nil, err
+		}
+	}
+	slices.SortStableFunc(mm, func(a, b slack.Message) int {
+		return cmpTS(b.Timestamp, a.Timestamp)
+	})
+	mm = nonNil(mm)
+	s.messages[channelID] = mm
+	return mm, nil
+}
+
+// threadMessages returns the thread messages, sorted oldest first, with the
+// thread lead being the first message.
+func (s *Server) threadMessages(ctx context.Context, channelID, threadTS string) ([]slack.Message, error) {
+	it, err := s.src.AllThreadMessages(ctx, channelID, threadTS)
+	if err != nil {
+		return nil, err
+	}
+	mm, err := collect(it)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(mm, func(a, b slack.Message) int {
+		return cmpTS(a.Timestamp, b.Timestamp)
+	})
+	return mm, nil
+}
+
+// fileIndex returns the index of all files referenced in the channel
+// messages of the source.  It is built on the first call.
+func (s *Server) fileIndex(ctx context.Context) (map[string]slack.File, error) {
+	channels, err := s.allChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	if s.files != nil {
+		defer s.mu.Unlock()
+		return s.files, nil
+	}
+	s.mu.Unlock()
+
+	idx := make(map[string]slack.File)
+	for _, ch := range channels {
+		mm, err := s.channelMessages(ctx, ch.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range mm {
+			for _, f := range m.Files {
+				idx[f.ID] = f
+			}
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files = idx
+	return s.files, nil
+}
+
+func collect(it iter.Seq2[slack.Message, error]) ([]slack.Message, error) {
+	var mm []slack.Message
+	for m, err := range it {
+		if err != nil {
+			return nil, err
+		}
+		mm = append(mm, m)
+	}
+	return mm, nil
+}
+
+// cmpTS compares two slack timestamps.
+func cmpTS(a, b string) int {
+	ia, errA := fasttime.TS2int(a)
+	ib, errB := fasttime.TS2int(b)
+	if errA != nil || errB != nil {
+		return 0
+	}
+	switch {
+	case ia < ib:
+		return -1
+	case ia > ib:
+		return 1
+	}
+	return 0
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// writeJSON writes v as the JSON response.
+func (s *Server) writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.lg.ErrorContext(r.Context(), "error encoding response", "path", r.URL.Path, "error", err)
+	}
+}
+
+// writeError writes the Slack API error response with the error code.
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, code string) {
+	s.writeJSON(w, r, slack.SlackResponse{Ok: false, Error: code})
+}
+
+// writeFailure logs the internal error and writes the Slack "internal_error"
+// response.
+func (s *Server) writeFailure(w http.ResponseWriter, r *http.Request, err error) {
+	s.lg.ErrorContext(r.Context(), "error serving request", "path", r.URL.Path, "error", err)
+	s.writeError(w, r, "internal_error")
+}
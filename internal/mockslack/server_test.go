@@ -0,0 +1,225 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package mockslack
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/rusq/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/rusq/slackdump/v4/source"
+	"github.com/rusq/slackdump/v4/source/mock_source"
+)
+
+var (
+	testChannels = []slack.Channel{
+		{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C1"}, Name: "general", Members: []string{"U1", "U2"}}},
+		{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "D1", IsIM: true}}},
+	}
+	testUsers = []slack.User{{ID: "U1", Name: "alice"}, {ID: "U2", Name: "bob"}}
+	testMsgs  = []slack.Message{
+		{Msg: slack.Msg{Timestamp: "1700000001.000000", Text: "one"}},
+		{Msg: slack.Msg{Timestamp: "1700000002.000000", Text: "two", ThreadTimestamp: "1700000002.000000", Files: []slack.File{{ID: "F1", Name: "hello.txt"}}}},
+		{Msg: slack.Msg{Timestamp: "1700000003.000000", Text: "three"}},
+	}
+	testThread = []slack.Message{
+		{Msg: slack.Msg{Timestamp: "1700000002.000000", Text: "two", ThreadTimestamp: "1700000002.000000"}},
+		{Msg: slack.Msg{Timestamp: "1700000004.000000", Text: "reply", ThreadTimestamp: "1700000002.000000"}},
+	}
+)
+
+func seq(mm []slack.Message) iter.Seq2[slack.Message, error] {
+	return func(yield func(slack.Message, error) bool) {
+		for _, m := range mm {
+			if !yield(m, nil) {
+				return
+			}
+		}
+	}
+}
+
+func testSource(t *testing.T) source.Sourcer {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	src := mock_source.NewMockSourcer(ctrl)
+	st := mock_source.NewMockStorage(ctrl)
+
+	src.EXPECT().Channels(gomock.Any()).Return(testChannels, nil).AnyTimes()
+	src.EXPECT().Users(gomock.Any()).Return(testUsers, nil).AnyTimes()
+	src.EXPECT().ChannelInfo(gomock.Any(), "C1").Return(&testChannels[0], nil).AnyTimes()
+	src.EXPECT().ChannelInfo(gomock.Any(), gomock.Any()).Return(nil, source.ErrNotFound).AnyTimes()
+	src.EXPECT().AllMessages(gomock.Any(), "C1").Return(seq(testMsgs), nil).AnyTimes()
+	src.EXPECT().AllMessages(gomock.Any(), gomock.Any()).Return(nil, source.ErrNotFound).AnyTimes()
+	src.EXPECT().AllThreadMessages(gomock.Any(), "C1", "1700000002.000000").Return(seq(testThread), nil).AnyTimes()
+	src.EXPECT().AllThreadMessages(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, source.ErrNotFound).AnyTimes()
+	src.EXPECT().WorkspaceInfo(gomock.Any()).Return(nil, source.ErrNotFound).AnyTimes()
+	src.EXPECT().Files().Return(st).AnyTimes()
+	st.EXPECT().FS().Return(fstest.MapFS{"F1-hello.txt": {Data: []byte("hello")}}).AnyTimes()
+	st.EXPECT().File("F1", "hello.txt").Return("F1-hello.txt", nil).AnyTimes()
+	st.EXPECT().File(gomock.Any(), gomock.Any()).Return("", fs.ErrNotExist).AnyTimes()
+	return src
+}
+
+func newTestClient(t *testing.T, opts ...Option) (*slack.Client, *httptest.Server) {
+	t.Helper()
+	srv := httptest.NewServer(New(testSource(t), opts...))
+	t.Cleanup(srv.Close)
+	return slack.New("xoxp-test", slack.OptionAPIURL(srv.URL+"/api/")), srv
+}
+
+func TestServer_AuthTest(t *testing.T) {
+	cl, _ := newTestClient(t)
+	resp, err := cl.AuthTestContext(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "T00000000", resp.TeamID)
+}
+
+func TestServer_ConversationsList(t *testing.T) {
+	cl, _ := newTestClient(t)
+	ch, next, err := cl.GetConversationsContext(t.Context(), &slack.GetConversationsParameters{Types: []string{"public_channel"}})
+	require.NoError(t, err)
+	assert.Empty(t, next)
+	require.Len(t, ch, 1)
+	assert.Equal(t, "C1", ch[0].ID)
+
+	ch, next, err = cl.GetConversationsContext(t.Context(), &slack.GetConversationsParameters{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "1", next)
+	assert.Len(t, ch, 1)
+}
+
+func TestServer_ConversationsInfo(t *testing.T) {
+	cl, _ := newTestClient(t)
+	ch, err := cl.GetConversationInfoContext(t.Context(), &slack.GetConversationInfoInput{ChannelID: "C1"})
+	require.NoError(t, err)
+	assert.Equal(t, "general", ch.Name)
+
+	_, err = cl.GetConversationInfoContext(t.Context(), &slack.GetConversationInfoInput{ChannelID: "C404"})
+	assert.ErrorContains(t, err, "channel_not_found")
+}
+
+func TestServer_ConversationsHistory(t *testing.T) {
+	cl, srv := newTestClient(t)
+	t.Run("pages newest first", func(t *testing.T) {
+		resp, err := cl.GetConversationHistoryContext(t.Context(), &slack.GetConversationHistoryParameters{ChannelID: "C1", Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, resp.Messages, 2)
+		assert.True(t, resp.HasMore)
+		assert.Equal(t, "three", resp.Messages[0].Text)
+		assert.Equal(t, srv.URL+"/files/F1/hello.txt", resp.Messages[1].Files[0].URLPrivateDownload)
+
+		resp, err = cl.GetConversationHistoryContext(t.Context(), &slack.GetConversationHistoryParameters{ChannelID: "C1", Limit: 2, Cursor: resp.ResponseMetaData.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, resp.Messages, 1)
+		assert.False(t, resp.HasMore)
+		assert.Equal(t, "one", resp.Messages[0].Text)
+	})
+	t.Run("time range", func(t *testing.T) {
+		resp, err := cl.GetConversationHistoryContext(t.Context(), &slack.GetConversationHistoryParameters{ChannelID: "C1", Oldest: "1700000001.000000", Latest: "1700000002.000000", Inclusive: true})
+		require.NoError(t, err)
+		assert.Len(t, resp.Messages, 2)
+
+		resp, err = cl.GetConversationHistoryContext(t.Context(), &slack.GetConversationHistoryParameters{ChannelID: "C1", Oldest: "1700000001.000000"})
+		require.NoError(t, err)
+		assert.Len(t, resp.Messages, 2)
+	})
+}
+
+func TestServer_ConversationsReplies(t *testing.T) {
+	cl, _ := newTestClient(t)
+	mm, hasMore, _, err := cl.GetConversationRepliesContext(t.Context(), &slack.GetConversationRepliesParameters{ChannelID: "C1", Timestamp: "1700000002.000000"})
+	require.NoError(t, err)
+	assert.False(t, hasMore)
+	require.Len(t, mm, 2)
+	assert.Equal(t, "reply", mm[1].Text)
+
+	_, _, _, err = cl.GetConversationRepliesContext(t.Context(), &slack.GetConversationRepliesParameters{ChannelID: "C1", Timestamp: "1.0"})
+	assert.ErrorContains(t, err, "thread_not_found")
+}
+
+func TestServer_Users(t *testing.T) {
+	cl, _ := newTestClient(t)
+	uu, err := cl.GetUsersContext(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, uu, 2)
+
+	u, err := cl.GetUserInfoContext(t.Context(), "U2")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", u.Name)
+}
+
+func TestServer_Files(t *testing.T) {
+	cl, srv := newTestClient(t)
+	f, _, _, err := cl.GetFileInfoContext(t.Context(), "F1", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "hello.txt", f.Name)
+
+	resp, err := http.Get(f.URLPrivateDownload)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(body))
+
+	resp404, err := http.Get(srv.URL + "/files/F2/missing.txt")
+	require.NoError(t, err)
+	resp404.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp404.StatusCode)
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	cl, _ := newTestClient(t, WithRateLimit(2, 3*time.Second))
+	_, err := cl.AuthTestContext(t.Context())
+	require.NoError(t, err)
+	_, err = cl.AuthTestContext(t.Context())
+	var rlErr *slack.RateLimitedError
+	require.ErrorAs(t, err, &rlErr)
+	assert.Equal(t, 3*time.Second, rlErr.RetryAfter)
+}
+
+func TestServer_RateLimitSkipsFiles(t *testing.T) {
+	cl, _ := newTestClient(t, WithRateLimit(2, time.Second))
+	f, _, _, err := cl.GetFileInfoContext(t.Context(), "F1", 0, 0) // API call 1
+	require.NoError(t, err)
+	for range 3 {
+		resp, err := http.Get(f.URLPrivateDownload)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "file downloads must not be rate limited")
+	}
+	_, err = cl.AuthTestContext(t.Context()) // API call 2
+	var rlErr *slack.RateLimitedError
+	require.ErrorAs(t, err, &rlErr)
+}
+
+func TestServer_Latency(t *testing.T) {
+	cl, _ := newTestClient(t, WithLatency(50*time.Millisecond))
+	start := time.Now()
+	_, err := cl.AuthTestContext(context.Background())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
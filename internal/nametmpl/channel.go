@@ -0,0 +1,96 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package nametmpl
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/internal/structures"
+)
+
+// ChannelNamer names the channel directories with the template.  The name of
+// the channel is fixed at the first call of [ChannelNamer.Dir], so that
+// the channel directory and the paths of the files within it always agree,
+// even if the users or the workspace name become known later.  It is safe
+// for concurrent use.
+type ChannelNamer struct {
+	t *Template
+
+	mu        sync.Mutex
+	idx       structures.UserIndex
+	workspace string
+	names     Namer
+	dirs      map[string]string // channel ID -> directory
+}
+
+// NewChannelNamer returns the channel namer for the template t.
+func NewChannelNamer(t *Template) *ChannelNamer {
+	return &ChannelNamer{t: t, dirs: make(map[string]string)}
+}
+
+// SetUsers sets the users to resolve the DM member names.
+func (n *ChannelNamer) SetUsers(users []slack.User) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.idx = structures.NewUserIndex(users)
+}
+
+// SetWorkspace sets the workspace name.
+func (n *ChannelNamer) SetWorkspace(name string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.workspace = name
+}
+
+// Dir returns the directory name of the channel.
+func (n *ChannelNamer) Dir(ch *slack.Channel) (string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if dir, ok := n.dirs[ch.ID]; ok {
+		return dir, nil
+	}
+	dir, err := n.names.Name(n.t, ChannelContext(ch, n.idx, n.workspace))
+	if err != nil {
+		return "", err
+	}
+	n.dirs[ch.ID] = dir
+	return dir, nil
+}
+
+// DirFunc returns the function that returns the directory name of the
+// channel, or the result of the fallback function, if the channel can not be
+// named.  It is meant for the file path functions, that can not return an
+// error, the error is reported by the caller of [ChannelNamer.Dir].
+func (n *ChannelNamer) DirFunc(fallback func(*slack.Channel) string) func(*slack.Channel) string {
+	return func(ch *slack.Channel) string {
+		dir, err := n.Dir(ch)
+		if err != nil {
+			return fallback(ch)
+		}
+		return dir
+	}
+}
+
+// Dirs returns the copy of the map of the channel IDs to the directory
+// names, given so far.
+func (n *ChannelNamer) Dirs() map[string]string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return maps.Clone(n.dirs)
+}
@@ -14,15 +14,24 @@
 // along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
 // Package nametmpl contains the name template logic.
+//
+// The templates are executed against the [Context], that describes the
+// conversation, and are used to name the dump files, the export channel
+// directories, and the HTML pages of the channels.
 package nametmpl
 
 import (
+	"errors"
 	"fmt"
+	"path"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/rusq/slack"
 
+	"github.com/rusq/slackdump/v4/internal/structures"
 	"github.com/rusq/slackdump/v4/types"
 )
 
@@ -38,13 +47,53 @@ const (
 	mPartialOK = "$$PARTIAL$$" // partial (only goes well with OK)
 )
 
+// Context is the template context.
+type Context struct {
+	// ID is the conversation ID.
+	ID string
+	// Name is the channel name, it is empty for DMs.
+	Name string
+	// ThreadTS is the thread timestamp, if the template is executed for a
+	// thread.
+	ThreadTS string
+	// Type is the conversation type: "public_channel", "private_channel",
+	// "im" or "mpim".
+	Type string
+	// Members is the list of the usernames of the DM or group DM members.
+	Members []string
+	// Workspace is the workspace (team) name.
+	Workspace string
+	// From and To are the timestamps of the first and the last message, if
+	// known, otherwise they are zero.
+	From, To time.Time
+}
+
 // marking all the fields we want with OK, all the rest (the ones we DO NOT
 // WANT) with NotOK.
-var tc = types.Conversation{
-	Name:     mOK,
-	ID:       mOK,
-	Messages: []types.Message{{Message: slack.Message{Msg: slack.Msg{Channel: mNotOK}}}},
-	ThreadTS: mPartialOK,
+var tc = Context{
+	Name:      mOK,
+	ID:        mOK,
+	ThreadTS:  mPartialOK,
+	Type:      mPartialOK,
+	Members:   []string{mPartialOK, mPartialOK},
+	Workspace: mPartialOK,
+	From:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	To:        time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+}
+
+// funcs are the functions available in the template.
+var funcs = template.FuncMap{
+	// date formats the time as YYYY-MM-DD, or returns an empty string if the
+	// time is zero.
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format("2006-01-02")
+	},
+	"join":  func(sep string, ss []string) string { return strings.Join(ss, sep) },
+	"lower": strings.ToLower,
+	"safe":  Sanitize,
 }
 
 type Template struct {
@@ -72,12 +121,11 @@ func NewDefault() *Template {
 // Compile checks the template for validness and compiles it returning the
 // template and an error if any.
 func compile(t string) (*template.Template, error) {
-	tmpl, err := template.New(filenameTmplName).Parse(t)
+	tmpl, err := template.New(filenameTmplName).Funcs(funcs).Parse(t)
 	if err != nil {
 		return nil, err
 	}
 	// are you ready for some filth? Here we go!
-
 	// now we render the template and check for OK/NotOK values in the output.
 	var buf strings.Builder
 	if err := tmpl.ExecuteTemplate(&buf, filenameTmplName, tc); err != nil {
@@ -90,18 +138,30 @@ func compile(t string) (*template.Template, error) {
 		// must contain at least one OK
 		return nil, fmt.Errorf("this does not resolve to anything useful: %q", t)
 	}
+	if err := validName(buf.String()); err != nil {
+		return nil, fmt.Errorf("%w: %q", err, t)
+	}
 	return tmpl, nil
 }
 
-// Execute executes the template and returns the result.  It panics if the
-// template cannot be executed, but please note that the template is checked
-// for validity at compile time.
+// Execute executes the template for the conversation and returns the result.
+// It panics if the template cannot be executed, but please note that the
+// template is checked for validity at compile time.
 func (t *Template) Execute(c *types.Conversation) string {
+	return Must(t.Render(ConversationContext(c)))
+}
+
+// Render executes the template with the context c, and returns the result.
+func (t *Template) Render(c *Context) (string, error) {
 	var buf strings.Builder
 	if err := t.t.ExecuteTemplate(&buf, filenameTmplName, c); err != nil {
-		panic(err)
+		return "", err
+	}
+	name := buf.String()
+	if err := validName(name); err != nil {
+		return "", fmt.Errorf("%w: %q", err, name)
 	}
-	return buf.String()
+	return name, nil
 }
 
 func Must(s string, err error) string {
@@ -110,3 +170,106 @@ func Must(s string, err error) string {
 	}
 	return s
 }
+
+// ConversationContext returns the template context for the conversation c.
+// It populates the message date range from the conversation messages.
+func ConversationContext(c *types.Conversation) *Context {
+	ctx := &Context{
+		ID:       c.ID,
+		Name:     c.Name,
+		ThreadTS: c.ThreadTS,
+	}
+	for i := range c.Messages {
+		t, err := structures.ParseSlackTS(c.Messages[i].Timestamp)
+		if err != nil {
+			continue
+		}
+		if ctx.From.IsZero() || t.Before(ctx.From) {
+			ctx.From = t
+		}
+		if t.After(ctx.To) {
+			ctx.To = t
+		}
+	}
+	return ctx
+}
+
+// ChannelContext returns the template context for the channel ch.  idx is
+// used to resolve the usernames of the DM participants, see
+// [structures.NameResolver.Participants], workspace is the name of the
+// workspace.
+func ChannelContext(ch *slack.Channel, idx structures.UserIndex, workspace string) *Context {
+	return &Context{
+		ID:        ch.ID,
+		Name:      ch.Name,
+		Type:      structures.ChannelType(*ch),
+		Members:   structures.NewNameResolver(idx, "", nil).Participants(*ch),
+		Workspace: workspace,
+	}
+}
+
+var (
+	// ErrInvalidName is returned when the template renders into an empty or
+	// unsafe name.
+	ErrInvalidName = errors.New("invalid name")
+	// ErrCollision is returned by [Namer] when two conversations get the same
+	// name.
+	ErrCollision = errors.New("name collision")
+)
+
+// validName checks that the name is not empty, and does not escape the
+// output directory.
+func validName(name string) error {
+	if name == "" || path.IsAbs(name) || strings.Contains(name, "\\") {
+		return ErrInvalidName
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidName
+		}
+	}
+	return nil
+}
+
+// Sanitize replaces the characters that are not allowed in file names with
+// underscores.
+func Sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		if r < 0x20 {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// Namer renders the names with the template and guarantees that no two
+// conversations get the same name.  Zero value is ready to use.
+type Namer struct {
+	mu    sync.Mutex
+	taken map[string]string // name -> conversation key
+}
+
+// Name renders the name for the context c with the template t.  It returns
+// [ErrCollision] if the name was already given to another conversation.
+// The same conversation always gets the same name.
+func (n *Namer) Name(t *Template, c *Context) (string, error) {
+	name, err := t.Render(c)
+	if err != nil {
+		return "", err
+	}
+	key := c.ID + ":" + c.ThreadTS
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.taken == nil {
+		n.taken = make(map[string]string)
+	}
+	if other, ok := n.taken[name]; ok && other != key {
+		return "", fmt.Errorf("%w: %q is used by %s and %s, add {{.ID}} to the template", ErrCollision, name, strings.TrimSuffix(other, ":"), strings.TrimSuffix(key, ":"))
+	}
+	n.taken[name] = key
+	return name, nil
+}
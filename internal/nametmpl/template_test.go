@@ -16,8 +16,17 @@
 package nametmpl
 
 import (
+	"errors"
+	"maps"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/internal/structures"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 func TestCompile(t *testing.T) {
@@ -78,6 +87,36 @@ func TestCompile(t *testing.T) {
 			"",
 			true,
 		},
+		{
+			"just the type is not ok",
+			args{"{{.Type}}"},
+			"",
+			true,
+		},
+		{
+			"type, members and dates with ID are ok",
+			args{`{{.Type}}/{{join "-" .Members}}-{{date .From}}-{{.ID}}`},
+			"$$PARTIAL$$/$$PARTIAL$$-$$PARTIAL$$-2024-01-01-$$OK$$",
+			false,
+		},
+		{
+			"workspace and name are ok",
+			args{"{{.Workspace}}/{{.Name}}"},
+			"$$PARTIAL$$/$$OK$$",
+			false,
+		},
+		{
+			"escaping the directory is not ok",
+			args{"../{{.ID}}"},
+			"",
+			true,
+		},
+		{
+			"absolute path is not ok",
+			args{"/{{.ID}}"},
+			"",
+			true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -99,3 +138,117 @@ func TestCompile(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplate_Render(t *testing.T) {
+	tmpl, err := New(`{{.Type}}/{{if .Members}}{{join "-" .Members}}{{else}}{{.Name}}{{end}}_{{.ID}}_{{date .From}}.json`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := tmpl.Render(&Context{
+		ID:      "D1",
+		Type:    structures.CIM,
+		Members: []string{"alice"},
+		From:    time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "im/alice_D1_2024-01-31.json"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+	// DMs have no name, so the directory would be empty.
+	tmpl, err = New("{{.Name}}/{{.ID}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmpl.Render(&Context{ID: "D1"}); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Render() error = %v, want %v", err, ErrInvalidName)
+	}
+}
+
+func TestConversationContext(t *testing.T) {
+	c := ConversationContext(&types.Conversation{
+		ID:   "C1",
+		Name: "general",
+		Messages: []types.Message{
+			{Message: slack.Message{Msg: slack.Msg{Timestamp: "1706700000.000100"}}},
+			{Message: slack.Message{Msg: slack.Msg{Timestamp: "1704067200.000100"}}},
+		},
+	})
+	if got, want := c.From.UTC().Format("2006-01-02"), "2024-01-01"; got != want {
+		t.Errorf("From = %s, want %s", got, want)
+	}
+	if got, want := c.To.UTC().Format("2006-01-02"), "2024-01-31"; got != want {
+		t.Errorf("To = %s, want %s", got, want)
+	}
+}
+
+func TestChannelContext(t *testing.T) {
+	idx := structures.NewUserIndex([]slack.User{{ID: "U1", Name: "alice"}, {ID: "U2", Name: "bob"}})
+	mpim := &slack.Channel{
+		GroupConversation: slack.GroupConversation{
+			Conversation: slack.Conversation{ID: "G1", IsMpIM: true},
+			Name:         "mpdm-alice--bob-1",
+			Members:      []string{"U1", "U2", "U3"},
+		},
+	}
+	c := ChannelContext(mpim, idx, "acme")
+	if c.Type != structures.CMPIM || c.Workspace != "acme" {
+		t.Errorf("ChannelContext() = %+v", c)
+	}
+	if want := []string{"alice", "bob", "U3"}; !slices.Equal(c.Members, want) {
+		t.Errorf("Members = %v, want %v", c.Members, want)
+	}
+	im := &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "D1", IsIM: true, User: "U2"}}}
+	if got := ChannelContext(im, idx, "").Members; !slices.Equal(got, []string{"bob"}) {
+		t.Errorf("Members = %v, want [bob]", got)
+	}
+}
+
+func TestNamer_Name(t *testing.T) {
+	tmpl, err := New("{{.Name}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n Namer
+	if _, err := n.Name(tmpl, &Context{ID: "C1", Name: "general"}); err != nil {
+		t.Fatal(err)
+	}
+	// same conversation, same name.
+	if _, err := n.Name(tmpl, &Context{ID: "C1", Name: "general"}); err != nil {
+		t.Errorf("Name() for the same conversation error = %v", err)
+	}
+	if _, err := n.Name(tmpl, &Context{ID: "C2", Name: "general"}); !errors.Is(err, ErrCollision) {
+		t.Errorf("Name() error = %v, want %v", err, ErrCollision)
+	}
+}
+
+func TestChannelNamer(t *testing.T) {
+	tmpl, err := New(`{{.Type}}/{{join "-" .Members}}{{.Name}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := NewChannelNamer(tmpl)
+	d1 := &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "D1", IsIM: true, User: "U1"}}}
+	// users are not known yet, the name is fixed at the first call.
+	if got, err := n.Dir(d1); err != nil || got != "im/U1" {
+		t.Fatalf("Dir() = %q, %v, want %q", got, err, "im/U1")
+	}
+	n.SetUsers([]slack.User{{ID: "U1", Name: "alice"}})
+	if got, _ := n.Dir(d1); got != "im/U1" {
+		t.Errorf("Dir() = %q, want %q", got, "im/U1")
+	}
+	// user U2 is named "U1", so D2 resolves to the same directory as D1.
+	d2 := &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "D2", IsIM: true, User: "U2"}}}
+	n.SetUsers([]slack.User{{ID: "U2", Name: "U1"}})
+	if _, err := n.Dir(d2); !errors.Is(err, ErrCollision) {
+		t.Errorf("Dir() error = %v, want %v", err, ErrCollision)
+	}
+	fn := n.DirFunc(func(ch *slack.Channel) string { return ch.ID })
+	if got := fn(d2); got != "D2" {
+		t.Errorf("DirFunc() = %q, want %q", got, "D2")
+	}
+	if want := map[string]string{"D1": "im/U1"}; !maps.Equal(n.Dirs(), want) {
+		t.Errorf("Dirs() = %v, want %v", n.Dirs(), want)
+	}
+}
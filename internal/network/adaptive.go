@@ -0,0 +1,233 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+	"weak"
+
+	"golang.org/x/time/rate"
+)
+
+// Limiter is the rate limiter used by [WithRetry].  It is satisfied by
+// *[rate.Limiter] and *[AdaptiveLimiter].
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// feedbackLimiter is the limiter that adjusts its rate based on the outcome
+// of the API calls.
+type feedbackLimiter interface {
+	// RateLimited is called when the API call was rate limited.
+	RateLimited(retryAfter time.Duration)
+	// Succeeded is called when the API call succeeded.
+	Succeeded()
+}
+
+const (
+	// minFactor is the minimum rate factor, the rate never goes below this
+	// fraction of the configured rate.
+	minFactor = 0.05
+	// decreaseFactor is applied to the rate factor when the API call is rate
+	// limited.
+	decreaseFactor = 0.5
+	// probeEvery is the number of successful calls after which the rate
+	// factor is increased.
+	probeEvery = 50
+	// probeStep is the rate factor increment.
+	probeStep = 0.05
+	// cooldown is the minimum interval between the rate decreases, so that
+	// the burst of 429s from the concurrent workers halves the rate only once.
+	cooldown = 5 * time.Second
+)
+
+// TierRate is the learned rate of the API tier.
+type TierRate struct {
+	// Tier is the API tier.
+	Tier Tier `json:"tier"`
+	// Factor is the fraction of the configured tier rate, that is used for
+	// the requests, between 0.05 and 1.
+	Factor float64 `json:"factor"`
+	// Throttled is the number of rate limit responses observed.
+	Throttled int `json:"throttled"`
+	// Updated is the time of the last factor change.
+	Updated time.Time `json:"updated"`
+}
+
+// Adaptive is the adaptive rate controller.  It maintains the rate factor for
+// each API tier, which is shared by all [AdaptiveLimiter]s of that tier:  when
+// Slack responds with 429, the rate of the tier is halved, and then it slowly
+// probes upward on successful calls, until it reaches the configured rate.
+//
+// Zero value is not usable, use [NewAdaptive].
+type Adaptive struct {
+	mu    sync.Mutex
+	tiers map[Tier]*tierState
+}
+
+type tierState struct {
+	TierRate
+	successes int
+	// limiters are held weakly, so that the limiters of the streams that are
+	// no longer used can be collected.
+	limiters []weak.Pointer[AdaptiveLimiter]
+}
+
+// each calls fn for each live limiter of the tier, and removes the ones that
+// were collected.
+func (ts *tierState) each(fn func(l *AdaptiveLimiter)) {
+	ts.limiters = slices.DeleteFunc(ts.limiters, func(p weak.Pointer[AdaptiveLimiter]) bool {
+		l := p.Value()
+		if l == nil {
+			return true
+		}
+		fn(l)
+		return false
+	})
+}
+
+// NewAdaptive creates a new adaptive rate controller.  It can be initialised
+// with the rates learned previously.
+func NewAdaptive(learned ...TierRate) *Adaptive {
+	a := &Adaptive{tiers: make(map[Tier]*tierState)}
+	for _, tr := range learned {
+		tr.Factor = clampFactor(tr.Factor)
+		a.tiers[tr.Tier] = &tierState{TierRate: tr}
+	}
+	return a
+}
+
+func clampFactor(f float64) float64 {
+	return max(minFactor, min(1, f))
+}
+
+// state returns the state of the tier, creating it, if necessary.  Must be
+// called with mu held.
+func (a *Adaptive) state(t Tier) *tierState {
+	ts, ok := a.tiers[t]
+	if !ok {
+		ts = &tierState{TierRate: TierRate{Tier: t, Factor: 1}}
+		a.tiers[t] = ts
+	}
+	return ts
+}
+
+// Limiter returns the new adaptive limiter for the tier.  The configured rate
+// of the limiter is calculated in the same way as in [NewLimiter], the
+// effective rate is the configured rate multiplied by the tier rate factor.
+func (a *Adaptive) Limiter(t Tier, burst uint, boost int) *AdaptiveLimiter {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ts := a.state(t)
+	l := &AdaptiveLimiter{
+		a:    a,
+		tier: t,
+		base: rate.Every(every(t, boost)),
+	}
+	l.lim = rate.NewLimiter(l.base*rate.Limit(ts.Factor), int(burst))
+	ts.each(func(*AdaptiveLimiter) {}) // prune
+	ts.limiters = append(ts.limiters, weak.Make(l))
+	return l
+}
+
+// Rates returns the current rates of all tiers, ordered by tier.
+func (a *Adaptive) Rates() []TierRate {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	rates := make([]TierRate, 0, len(a.tiers))
+	for _, ts := range a.tiers {
+		rates = append(rates, ts.TierRate)
+	}
+	slices.SortFunc(rates, func(a, b TierRate) int { return int(a.Tier - b.Tier) })
+	return rates
+}
+
+// setFactor sets the rate factor of the tier and updates all the limiters.
+// Must be called with mu held.
+func (a *Adaptive) setFactor(ts *tierState, f float64) {
+	f = clampFactor(f)
+	if f == ts.Factor {
+		return
+	}
+	ts.Factor = f
+	ts.Updated = time.Now()
+	ts.each(func(l *AdaptiveLimiter) {
+		l.lim.SetLimit(l.base * rate.Limit(f))
+	})
+}
+
+func (a *Adaptive) rateLimited(t Tier) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ts := a.state(t)
+	ts.Throttled++
+	ts.successes = 0
+	if time.Since(ts.Updated) < cooldown && ts.Factor < 1 {
+		return
+	}
+	a.setFactor(ts, ts.Factor*decreaseFactor)
+}
+
+func (a *Adaptive) succeeded(t Tier) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ts := a.state(t)
+	if ts.Factor >= 1 {
+		return
+	}
+	ts.successes++
+	if ts.successes < probeEvery {
+		return
+	}
+	ts.successes = 0
+	a.setFactor(ts, ts.Factor+probeStep)
+}
+
+// AdaptiveLimiter is the rate limiter which rate is controlled by the
+// [Adaptive] controller.  It can be used with [WithRetry], which reports the
+// outcome of each call to the limiter.
+type AdaptiveLimiter struct {
+	a    *Adaptive
+	tier Tier
+	base rate.Limit
+	lim  *rate.Limiter
+}
+
+var _ feedbackLimiter = (*AdaptiveLimiter)(nil)
+
+// Wait waits for the limiter to permit the request.
+func (l *AdaptiveLimiter) Wait(ctx context.Context) error {
+	return l.lim.Wait(ctx)
+}
+
+// Limit returns the current effective rate limit.
+func (l *AdaptiveLimiter) Limit() rate.Limit {
+	return l.lim.Limit()
+}
+
+// RateLimited lowers the rate of the limiter tier.
+func (l *AdaptiveLimiter) RateLimited(time.Duration) {
+	l.a.rateLimited(l.tier)
+}
+
+// Succeeded records the successful call, the rate of the tier is increased
+// after a number of successful calls.
+func (l *AdaptiveLimiter) Succeeded() {
+	l.a.succeeded(l.tier)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/rusq/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestAdaptive_rateLimited(t *testing.T) {
+	a := NewAdaptive()
+	l1 := a.Limiter(Tier2, 1, 0)
+	l2 := a.Limiter(Tier2, 1, 0)
+	l3 := a.Limiter(Tier3, 1, 0)
+	base := rate.Every(every(Tier2, 0))
+
+	l1.RateLimited(time.Second)
+	assert.InDelta(t, float64(base/2), float64(l1.Limit()), 1e-9)
+	assert.InDelta(t, float64(base/2), float64(l2.Limit()), 1e-9, "limiters of the same tier must share the factor")
+	assert.InDelta(t, float64(rate.Every(every(Tier3, 0))), float64(l3.Limit()), 1e-9, "other tiers must not be affected")
+
+	// the burst of 429s within the cooldown period halves the rate only once.
+	l2.RateLimited(time.Second)
+	assert.InDelta(t, float64(base/2), float64(l1.Limit()), 1e-9)
+
+	rates := a.Rates()
+	require.Len(t, rates, 2)
+	assert.Equal(t, Tier2, rates[0].Tier)
+	assert.Equal(t, 0.5, rates[0].Factor)
+	assert.Equal(t, 2, rates[0].Throttled)
+	assert.Equal(t, Tier3, rates[1].Tier)
+	assert.Equal(t, 1.0, rates[1].Factor)
+}
+
+func TestAdaptive_succeeded(t *testing.T) {
+	a := NewAdaptive(TierRate{Tier: Tier3, Factor: 0.5})
+	l := a.Limiter(Tier3, 1, 0)
+	base := rate.Every(every(Tier3, 0))
+	assert.InDelta(t, float64(base/2), float64(l.Limit()), 1e-9)
+
+	for range probeEvery - 1 {
+		l.Succeeded()
+	}
+	assert.Equal(t, 0.5, a.Rates()[0].Factor, "must not increase before probeEvery successes")
+	l.Succeeded()
+	assert.InDelta(t, 0.5+probeStep, a.Rates()[0].Factor, 1e-9)
+
+	// never exceeds the configured rate.
+	for range 100 * probeEvery {
+		l.Succeeded()
+	}
+	assert.Equal(t, 1.0, a.Rates()[0].Factor)
+	assert.InDelta(t, float64(base), float64(l.Limit()), 1e-9)
+}
+
+func TestNewAdaptive_clamps(t *testing.T) {
+	a := NewAdaptive(TierRate{Tier: Tier2, Factor: 0}, TierRate{Tier: Tier3, Factor: 5})
+	rates := a.Rates()
+	require.Len(t, rates, 2)
+	assert.Equal(t, minFactor, rates[0].Factor)
+	assert.Equal(t, 1.0, rates[1].Factor)
+}
+
+func TestAdaptive_releasesLimiters(t *testing.T) {
+	a := NewAdaptive()
+	for range 10 {
+		a.Limiter(Tier2, 1, 0) // dropped immediately
+	}
+	runtime.GC()
+	l := a.Limiter(Tier2, 1, 0)
+	a.mu.Lock()
+	n := len(a.tiers[Tier2].limiters)
+	a.mu.Unlock()
+	assert.Equal(t, 1, n, "collected limiters must be removed")
+
+	l.RateLimited(time.Second)
+	assert.InDelta(t, float64(rate.Every(every(Tier2, 0))/2), float64(l.Limit()), 1e-9)
+}
+
+func TestWithRetry_adaptive(t *testing.T) {
+	a := NewAdaptive()
+	l := a.Limiter(NoTier, 100, 0)
+	calls := 0
+	err := WithRetry(context.Background(), l, 3, func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return &slack.RateLimitedError{RetryAfter: 1 * time.Millisecond}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	rates := a.Rates()
+	require.Len(t, rates, 1)
+	assert.Equal(t, 0.5, rates[0].Factor)
+	assert.Equal(t, 1, rates[0].Throttled)
+}
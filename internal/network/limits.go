@@ -16,6 +16,8 @@
 package network
 
 import (
+	"time"
+
 	"github.com/go-playground/locales/en"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
@@ -36,6 +38,8 @@ type Limits struct {
 	Tier4 TierLimit `json:"tier_4" yaml:"tier_4,omitempty" toml:"tier_4,omitempty"`
 	// Request Limits
 	Request RequestLimit `json:"per_request" yaml:"per_request,omitempty" toml:"per_request,omitempty"`
+	// API response cache time-to-live
+	APICache CacheLimit `json:"api_cache" yaml:"api_cache,omitempty" toml:"api_cache,omitempty"`
 }
 
 // TierLimit represents a Slack API Tier limits.
@@ -59,6 +63,18 @@ type RequestLimit struct {
 	Replies int `json:"replies,omitempty" yaml:"replies,omitempty" validate:"gt=0,lte=1000" toml:"replies,omitempty"`
 }
 
+// CacheLimit defines for how long the responses of the read-only API calls
+// are cached on disk.  Zero value disables caching of the respective calls.
+type CacheLimit struct {
+	// conversations.info and conversations.genericInfo (enterprise) responses
+	Conversations time.Duration `json:"conversations,omitempty" yaml:"conversations,omitempty" validate:"gte=0" toml:"conversations,omitempty"`
+	// conversations.members responses.  Not cached by default, as the
+	// members are recorded in the archive, and must be current.
+	Members time.Duration `json:"members,omitempty" yaml:"members,omitempty" validate:"gte=0" toml:"members,omitempty"`
+	// users.info responses
+	Users time.Duration `json:"users,omitempty" yaml:"users,omitempty" validate:"gte=0" toml:"users,omitempty"`
+}
+
 var DefLimits = Limits{
 	Workers:         4, // number of parallel goroutines downloading files.
 	DownloadRetries: 3, // this shouldn't even happen, as we have no limiter on files download.
@@ -82,6 +98,10 @@ var DefLimits = Limits{
 		Channels:      100, // channels are Tier2 rate limited. Slack is greedy and never returns more than 100 per call.
 		Replies:       200, // the API-default is 1000 (see conversations.replies), but on large threads it may fail (see #54)
 	},
+	APICache: CacheLimit{
+		Conversations: time.Hour,
+		Users:         time.Hour,
+	},
 }
 
 // NoLimits is setting the limits to high values, effectively disabling them.
@@ -143,9 +163,33 @@ func (o *Limits) Apply(other Limits) error {
 	apply(&o.Request.Conversations, other.Request.Conversations)
 	apply(&o.Request.Channels, other.Request.Channels)
 	apply(&o.Request.Replies, other.Request.Replies)
+	apply(&o.APICache.Conversations, other.APICache.Conversations)
+	apply(&o.APICache.Members, other.APICache.Members)
+	apply(&o.APICache.Users, other.APICache.Users)
 	return o.Validate()
 }
 
+// Scale returns the limits for n clients that share the load, i.e. a client
+// pool.  The tier request rates and bursts are multiplied by n.
+func (o Limits) Scale(n int) Limits {
+	if n <= 1 {
+		return o
+	}
+	o.Tier2 = o.Tier2.scale(Tier2, uint(n))
+	o.Tier3 = o.Tier3.scale(Tier3, uint(n))
+	o.Tier4 = o.Tier4.scale(Tier4, uint(n))
+	return o
+}
+
+// scale scales the tier limits for n clients.  The rate of the tier is
+// (tier + boost) requests per minute, so the boost is adjusted to get n times
+// the rate.
+func (t TierLimit) scale(tier Tier, n uint) TierLimit {
+	t.Boost = n*(uint(tier)+t.Boost) - uint(tier)
+	t.Burst *= n
+	return t
+}
+
 func (o *Limits) Validate() error {
 	return cfgValidator.Struct(o)
 }
@@ -29,6 +29,7 @@ func TestLimits_Apply(t *testing.T) {
 		Tier3           TierLimit
 		Tier4           TierLimit
 		Request         RequestLimit
+		APICache        CacheLimit
 	}
 	type args struct {
 		other Limits
@@ -71,6 +72,7 @@ func TestLimits_Apply(t *testing.T) {
 				Tier2:           tt.fields.Tier2,
 				Tier3:           tt.fields.Tier3,
 				Request:         tt.fields.Request,
+				APICache:        tt.fields.APICache,
 			}
 			if err := o.Apply(tt.args.other); (err != nil) != tt.wantErr {
 				t.Errorf("o.Apply() error=%v wantErr=%v", err, tt.wantErr)
@@ -88,6 +90,7 @@ func TestLimits_Validate(t *testing.T) {
 		Tier3           TierLimit
 		Tier4           TierLimit
 		Request         RequestLimit
+		APICache        CacheLimit
 	}
 	tests := []struct {
 		name    string
@@ -139,8 +142,21 @@ func TestLimits_Validate(t *testing.T) {
 				Tier2:           tt.fields.Tier2,
 				Tier3:           tt.fields.Tier3,
 				Request:         tt.fields.Request,
+				APICache:        tt.fields.APICache,
 			}
 			tt.wantErr(t, o.Validate(), "Validate()")
 		})
 	}
 }
+
+func TestLimits_Scale(t *testing.T) {
+	assert.Equal(t, DefLimits, DefLimits.Scale(1))
+
+	got := DefLimits.Scale(3)
+	assert.Equal(t, every(Tier2, int(DefLimits.Tier2.Boost))/3, every(Tier2, int(got.Tier2.Boost)))
+	assert.Equal(t, every(Tier3, int(DefLimits.Tier3.Boost))/3, every(Tier3, int(got.Tier3.Boost)))
+	assert.Equal(t, every(Tier4, int(DefLimits.Tier4.Boost))/3, every(Tier4, int(got.Tier4.Boost)))
+	assert.Equal(t, 3*DefLimits.Tier3.Burst, got.Tier3.Burst)
+	assert.Equal(t, DefLimits.Tier3.Retries, got.Tier3.Retries)
+	assert.Equal(t, DefLimits.Request, got.Request)
+}
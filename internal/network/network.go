@@ -29,7 +29,6 @@ import (
 	"time"
 
 	"github.com/rusq/slack"
-	"golang.org/x/time/rate"
 )
 
 // defNumAttempts is the default number of retry attempts.
@@ -96,12 +95,15 @@ func (e *ErrRetryFailed) Is(target error) bool {
 // WithRetry will run the callback function fn. If the function returns
 // slack.RateLimitedError, it will delay, and then call it again up to
 // maxAttempts times. It will return an error if it runs out of attempts.
-func WithRetry(ctx context.Context, lim *rate.Limiter, maxAttempts int, fn func(ctx context.Context) error) error {
+// If the limiter is an [AdaptiveLimiter], it is notified about the rate limit
+// responses and successful calls.
+func WithRetry(ctx context.Context, lim Limiter, maxAttempts int, fn func(ctx context.Context) error) error {
 	var ok bool
 	if maxAttempts == 0 {
 		maxAttempts = defNumAttempts
 	}
 	lg := slog.With("maxAttempts", maxAttempts)
+	fl, _ := lim.(feedbackLimiter)
 
 	var lastErr error
 	for attempt := 0; attempt < maxAttempts; attempt++ {
@@ -123,6 +125,9 @@ func WithRetry(ctx context.Context, lim *rate.Limiter, maxAttempts int, fn func(
 		if cbErr == nil {
 			// success
 			ok = true
+			if fl != nil {
+				fl.Succeeded()
+			}
 			break
 		}
 		lastErr = cbErr
@@ -148,6 +153,9 @@ func WithRetry(ctx context.Context, lim *rate.Limiter, maxAttempts int, fn func(
 			slog.Debug("resuming after EOF")
 			continue
 		case errors.As(cbErr, &rle):
+			if fl != nil {
+				fl.RateLimited(rle.RetryAfter)
+			}
 			slog.InfoContext(ctx, "got rate limited, sleeping", "retry_after", rle.RetryAfter.String(), "error", cbErr)
 			tracelogf(ctx, "info", "got rate limited, sleeping %s (%s)", rle.RetryAfter, cbErr)
 			if err := sleepCtx(ctx, rle.RetryAfter); err != nil {
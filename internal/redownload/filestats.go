@@ -25,6 +25,9 @@ import (
 type FileStats struct {
 	NumFiles uint
 	NumBytes uint64
+	// NumUnavailable is the number of files that can not be downloaded, see
+	// [UnavailableFile].
+	NumUnavailable uint
 }
 
 func (fs *FileStats) add(other FileStats) {
@@ -33,5 +36,9 @@ func (fs *FileStats) add(other FileStats) {
 }
 
 func (fs *FileStats) Attr() slog.Attr {
-	return slog.Group("file_stats", slog.Uint64("num_files", uint64(fs.NumFiles)), slog.String("total_bytes", humanize.Bytes(fs.NumBytes)))
+	attrs := []any{slog.Uint64("num_files", uint64(fs.NumFiles)), slog.String("total_bytes", humanize.Bytes(fs.NumBytes))}
+	if fs.NumUnavailable > 0 {
+		attrs = append(attrs, slog.Uint64("num_unavailable", uint64(fs.NumUnavailable)))
+	}
+	return slog.Group("file_stats", attrs...)
 }
@@ -21,17 +21,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"iter"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime/trace"
+	"sync"
+	"time"
 
 	"github.com/rusq/fsadapter"
 	"github.com/rusq/slack"
 
+	"github.com/rusq/slackdump/v4/downloader"
+	"github.com/rusq/slackdump/v4/internal/cas"
 	"github.com/rusq/slackdump/v4/internal/convert/transform/fileproc"
 	"github.com/rusq/slackdump/v4/internal/primitive"
+	"github.com/rusq/slackdump/v4/internal/s3"
 	"github.com/rusq/slackdump/v4/internal/structures"
 	"github.com/rusq/slackdump/v4/processor"
 	"github.com/rusq/slackdump/v4/source"
@@ -46,6 +52,11 @@ type Redownloader struct {
 
 	// dir is the path to the source.
 	dir string
+	// since, if not zero, limits the files to the messages posted since
+	// this time.
+	since time.Time
+	// unavail is the report of the files that can not be downloaded.
+	unavail *report
 }
 
 type Option func(*Redownloader)
@@ -58,10 +69,21 @@ func WithLogger(lg *slog.Logger) Option {
 	}
 }
 
+// WithSince limits the files to those attached to the messages posted at or
+// after t.
+func WithSince(t time.Time) Option {
+	return func(r *Redownloader) {
+		r.since = t
+	}
+}
+
 // New initialises the new Redownloader for the given directory.  Source type
 // is detected automatically. It validates if the source is of the supported
 // type and returns any errors.
 func New(ctx context.Context, dir string, opts ...Option) (*Redownloader, error) {
+	if s3.IsURL(dir) {
+		return nil, errors.New("validation error: unable to update archives in the object storage, download it first")
+	}
 	st, err := source.Type(dir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to determine type: %w", err)
@@ -73,11 +95,16 @@ func New(ctx context.Context, dir string, opts ...Option) (*Redownloader, error)
 	if err != nil {
 		return nil, fmt.Errorf("error opening source data: %w", err)
 	}
+	rep, err := loadReport(src.Name())
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("error reading %s: %w", UnavailableFile, err), src.Close())
+	}
 	r := &Redownloader{
-		src:   src,
-		flags: st,
-		dir:   dir,
-		lg:    slog.Default(),
+		src:     src,
+		flags:   st,
+		dir:     dir,
+		lg:      slog.Default(),
+		unavail: rep,
 	}
 	for _, opt := range opts {
 		opt(r)
@@ -100,6 +127,14 @@ func validate(st source.Flags) error {
 	return nil
 }
 
+// report returns the report of the unavailable files.
+func (r *Redownloader) report() *report {
+	if r.unavail == nil {
+		r.unavail = &report{files: make(map[string]Unavailable)}
+	}
+	return r.unavail
+}
+
 // pathFunc returns effective path function.
 func (r *Redownloader) pathFunc() func(ch *slack.Channel, f *slack.File) string {
 	if r.src.Files().Type() != source.STnone {
@@ -167,6 +202,7 @@ func (r *Redownloader) Stats(ctx context.Context) (FileStats, error) {
 		}
 		ret.add(chstat)
 	}
+	ret.NumUnavailable = uint(r.report().len())
 
 	return ret, nil
 }
@@ -178,12 +214,39 @@ func (r *Redownloader) Download(ctx context.Context, cl fileproc.FileGetter) (Fi
 		return ret, err
 	}
 
+	var fsa fsadapter.FS = fsadapter.NewDirectory(r.src.Name())
+	if cas.Exists(r.src.Name()) {
+		// archive keeps files in the content-addressed file store.
+		if fsa, err = cas.NewWriter(fsa, r.src.Name(), ""); err != nil {
+			return ret, err
+		}
+	}
+	// files that Slack no longer has are recorded in the report, so that the
+	// subsequent runs do not retry them.
+	var (
+		mu     sync.Mutex
+		queued = make(map[string]dlItem) // by download URL
+	)
+	onError := func(_, url string, err error) {
+		reason := downloadReason(err)
+		if reason == "" {
+			return
+		}
+		mu.Lock()
+		item, ok := queued[url]
+		mu.Unlock()
+		if ok {
+			r.report().add(unavailable(item.ch, item.msg, item.f, reason))
+		}
+	}
 	dl := fileproc.NewDownloader(
 		ctx,
 		true,
 		cl,
-		fsadapter.NewDirectory(r.src.Name()),
+		fsa,
 		r.lg,
+		fileproc.ResumableIn(r.src.Name()),
+		downloader.WithErrorFunc(onError),
 	)
 	defer dl.Stop()
 
@@ -196,6 +259,9 @@ func (r *Redownloader) Download(ctx context.Context, cl fileproc.FileGetter) (Fi
 
 	for _, ch := range channels {
 		chstats, err := r.processChannel(ctx, &ch, func(item *dlItem) error {
+			mu.Lock()
+			queued[item.f.URLPrivateDownload] = *item
+			mu.Unlock()
 			if err := fproc.Files(ctx, item.ch, *item.msg, []slack.File{*item.f}); err != nil {
 				return err
 			}
@@ -206,6 +272,11 @@ func (r *Redownloader) Download(ctx context.Context, cl fileproc.FileGetter) (Fi
 		}
 		ret.add(chstats)
 	}
+	dl.Stop() // wait for the downloads to finish
+	if err := r.report().save(r.src.Name()); err != nil {
+		return ret, fmt.Errorf("error writing %s: %w", UnavailableFile, err)
+	}
+	ret.NumUnavailable = uint(r.report().len())
 	return ret, nil
 }
 
@@ -280,11 +351,20 @@ func (r *Redownloader) scanMsgs(ctx context.Context, ch *slack.Channel, msgIt it
 			}
 		}
 
+		if r.before(&m) {
+			continue
+		}
+
 		// collect all missing files from the message.
 		var missing []slack.File
 		for _, ff := range m.Files {
-			if !fileproc.IsValid(&ff) {
-				lg.Debug("file is not valid for download", "ID", ff.ID)
+			if r.report().has(ff.ID) {
+				lg.Debug("file is known to be unavailable", "ID", ff.ID)
+				continue
+			}
+			if reason := unavailableReason(&ff); reason != "" {
+				lg.Debug("file is not valid for download", "ID", ff.ID, "reason", reason)
+				r.report().add(unavailable(ch, &m, &ff, reason))
 				continue
 			}
 
@@ -292,22 +372,16 @@ func (r *Redownloader) scanMsgs(ctx context.Context, ch *slack.Channel, msgIt it
 			lg := lg.With("file", name)
 			lg.Debug("checking file")
 
-			if fi, err := os.Stat(name); err != nil {
-				if os.IsNotExist(err) {
-					// file does not exist
-					lg.Debug("missing file")
-					missing = append(missing, ff)
-				} else {
-					lg.Error("error accessing file", "error", err)
-					// some other error
-					return toDl, fmt.Errorf("error accessing file: %w", err)
-				}
-			} else if fi.Size() == 0 {
-				// zero length files are considered missing
-				lg.Debug("zero length file")
-				missing = append(missing, ff)
-			} else {
+			ok, err := r.present(ch, &ff, name)
+			if err != nil {
+				lg.Error("error accessing file", "error", err)
+				return toDl, fmt.Errorf("error accessing file: %w", err)
+			}
+			if ok {
 				lg.Debug("file OK")
+			} else {
+				lg.Debug("missing or zero length file")
+				missing = append(missing, ff)
 			}
 		}
 
@@ -321,3 +395,46 @@ func (r *Redownloader) scanMsgs(ctx context.Context, ch *slack.Channel, msgIt it
 	}
 	return toDl, nil
 }
+
+// before returns true if the message was posted before the since time.
+func (r *Redownloader) before(m *slack.Message) bool {
+	if r.since.IsZero() {
+		return false
+	}
+	ts, err := structures.ParseSlackTS(m.Timestamp)
+	if err != nil {
+		return false
+	}
+	return ts.Before(r.since)
+}
+
+// present returns true if the file is present in the archive and is not
+// empty.  The file is looked up in the file storage of the source, that
+// knows its layout, and then at the path name, where the file would be
+// downloaded to.  Zero length files are considered missing.
+func (r *Redownloader) present(ch *slack.Channel, f *slack.File, name string) (bool, error) {
+	st := r.src.Files()
+	if pth, err := st.File(f.ID, f.Name); err == nil {
+		if fi, err := fs.Stat(st.FS(), pth); err == nil && fi.Size() > 0 {
+			return true, nil
+		}
+	}
+	fi, err := os.Stat(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return fi.Size() > 0, nil
+}
+
+func unavailable(ch *slack.Channel, m *slack.Message, f *slack.File, reason string) Unavailable {
+	return Unavailable{
+		FileID:    f.ID,
+		Name:      f.Name,
+		ChannelID: ch.ID,
+		Timestamp: m.Timestamp,
+		Reason:    reason,
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"iter"
@@ -34,6 +35,7 @@ import (
 	"github.com/rusq/slackdump/v4/internal/structures"
 	"github.com/rusq/slackdump/v4/source"
 	"github.com/rusq/slackdump/v4/source/mock_source"
+	"github.com/rusq/slackdump/v4/types"
 	"go.uber.org/mock/gomock"
 )
 
@@ -101,6 +103,9 @@ func (s stubSource) Avatars() source.Storage
 func (s stubSource) WorkspaceInfo(context.Context) (*slack.AuthTestResponse, error) {
 	return nil, nil
 }
+func (s stubSource) ChannelMembership(context.Context, string) ([]types.MembershipEvent, error) {
+	return nil, source.ErrNotFound
+}
 func (s stubSource) Latest(context.Context) (map[structures.SlackLink]time.Time, error) {
 	return nil, nil
 }
@@ -588,3 +593,136 @@ func TestDownload(t *testing.T) {
 		t.Fatalf("downloaded file missing: %v", err)
 	}
 }
+
+func Test_scanMsgs_unavailable(t *testing.T) {
+	tmp := t.TempDir()
+	ch := slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C1"}}}
+	r := &Redownloader{
+		src: stubSource{name: tmp, storage: stubStorage{typ: source.STnone}},
+		lg:  slog.Default(),
+	}
+	r.report().add(Unavailable{FileID: "F3", ChannelID: "C1", Reason: ReasonNotFound})
+
+	msgs := []slack.Message{
+		{Msg: slack.Msg{Timestamp: "1.0", Files: []slack.File{
+			{ID: "F1", Name: "deleted.txt", Mode: "tombstone"},
+			{ID: "F2", Name: "ok.txt"},
+			{ID: "F3", Name: "gone.txt"},
+		}}},
+	}
+	items, err := r.scanMsgs(t.Context(), &ch, seqFromMessages(msgs), false)
+	if err != nil {
+		t.Fatalf("scanMsgs() error = %v", err)
+	}
+	if len(items) != 1 || items[0].f.ID != "F2" {
+		t.Fatalf("scanMsgs() = %#v, want only F2", items)
+	}
+	want := Unavailable{FileID: "F1", Name: "deleted.txt", ChannelID: "C1", Timestamp: "1.0", Reason: "tombstone"}
+	if got := r.report().files["F1"]; got != want {
+		t.Fatalf("report entry = %+v, want %+v", got, want)
+	}
+
+	if err := r.report().save(tmp); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := loadReport(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.len() != 2 || !rep.has("F1") || !rep.has("F3") {
+		t.Fatalf("loaded report = %+v, want F1 and F3", rep.files)
+	}
+}
+
+func Test_scanMsgs_since(t *testing.T) {
+	ch := slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C1"}}}
+	r := &Redownloader{
+		src:   stubSource{name: t.TempDir(), storage: stubStorage{typ: source.STnone}},
+		lg:    slog.Default(),
+		since: time.Unix(1700000000, 0),
+	}
+	msgs := []slack.Message{
+		{Msg: slack.Msg{Timestamp: "1600000000.000100", Files: []slack.File{{ID: "FOLD", Name: "old.txt"}}}},
+		{Msg: slack.Msg{Timestamp: "1700000001.000100", Files: []slack.File{{ID: "FNEW", Name: "new.txt"}}}},
+	}
+	items, err := r.scanMsgs(t.Context(), &ch, seqFromMessages(msgs), false)
+	if err != nil {
+		t.Fatalf("scanMsgs() error = %v", err)
+	}
+	if len(items) != 1 || items[0].f.ID != "FNEW" {
+		t.Fatalf("scanMsgs() = %#v, want only FNEW", items)
+	}
+}
+
+func Test_present_storage(t *testing.T) {
+	ch := slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C1"}}}
+	st, err := source.OpenMattermostStorage(fstest.MapFS{
+		"__uploads/F1/file.txt":  &fstest.MapFile{Data: []byte("data")},
+		"__uploads/F2/empty.txt": &fstest.MapFile{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Redownloader{src: stubSource{name: t.TempDir(), storage: st}, lg: slog.Default()}
+	for _, tt := range []struct {
+		f    slack.File
+		want bool
+	}{
+		{slack.File{ID: "F1", Name: "file.txt"}, true},
+		{slack.File{ID: "F2", Name: "empty.txt"}, false},
+		{slack.File{ID: "F3", Name: "missing.txt"}, false},
+	} {
+		got, err := r.present(&ch, &tt.f, filepath.Join(r.src.Name(), "nonexistent"))
+		if err != nil {
+			t.Fatalf("present(%s) error = %v", tt.f.ID, err)
+		}
+		if got != tt.want {
+			t.Errorf("present(%s) = %v, want %v", tt.f.ID, got, tt.want)
+		}
+	}
+}
+
+func TestDownload_notFound(t *testing.T) {
+	tmp := t.TempDir()
+	ch := slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C1"}}}
+	file := slack.File{ID: "F1", Name: "file.txt", URLPrivateDownload: "https://files.slack.test/file1", Size: 5}
+	fg := fileGetterFunc(func(ctx context.Context, downloadURL string, w io.Writer) error {
+		return slack.StatusCodeError{Code: 404, Status: "404 Not Found"}
+	})
+	r := &Redownloader{
+		flags: source.FDump,
+		src: stubSource{
+			name:     tmp,
+			storage:  stubStorage{typ: source.STnone},
+			channels: []slack.Channel{ch},
+			messages: func(ctx context.Context, channelID string) (iter.Seq2[slack.Message, error], error) {
+				return seqFromMessages([]slack.Message{{Msg: slack.Msg{Timestamp: "1.0", Files: []slack.File{file}}}}), nil
+			},
+		},
+		lg: slog.Default(),
+	}
+	stats, err := r.Download(t.Context(), fg)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if stats.NumUnavailable != 1 {
+		t.Fatalf("Download() stats = %+v, want 1 unavailable", stats)
+	}
+	rep, err := loadReport(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rep.files["F1"].Reason; got != ReasonNotFound {
+		t.Fatalf("report reason = %q, want %q", got, ReasonNotFound)
+	}
+
+	// the next run does not retry the file.
+	r.unavail = rep
+	stats, err = r.Stats(t.Context())
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.NumFiles != 0 {
+		t.Fatalf("Stats() = %+v, want no files to download", stats)
+	}
+}
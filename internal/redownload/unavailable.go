@@ -0,0 +1,151 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package redownload
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/internal/convert/transform/fileproc"
+)
+
+// UnavailableFile is the name of the report, within the archive directory,
+// that lists the files that can not be downloaded.  These files are not
+// retried by the subsequent runs.
+const UnavailableFile = "__unavailable.json"
+
+// Reasons why the file is unavailable, in addition to the file modes that are
+// skipped, see [fileproc.SkipReason].
+const (
+	ReasonInvalid  = "invalid"   // file has no name
+	ReasonNotFound = "not_found" // Slack responded with 404 or 410
+)
+
+// Unavailable is the file that can not be downloaded.
+type Unavailable struct {
+	FileID    string `json:"file_id"`
+	Name      string `json:"name,omitempty"`
+	ChannelID string `json:"channel_id"`
+	// Timestamp is the timestamp of the message with the file.
+	Timestamp string `json:"ts,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// unavailableReason returns the reason why the file can not be downloaded,
+// or an empty string, if it can be.
+func unavailableReason(f *slack.File) string {
+	if reason, ok := fileproc.SkipReason(f); ok {
+		return reason
+	}
+	if fileproc.IsValidWithReason(f) != nil {
+		return ReasonInvalid
+	}
+	return ""
+}
+
+// downloadReason returns the reason why the file is unavailable for the
+// download error err, or an empty string, if the error may be temporary.
+func downloadReason(err error) string {
+	var sce slack.StatusCodeError
+	if !errors.As(err, &sce) {
+		return ""
+	}
+	if sce.Code == http.StatusNotFound || sce.Code == http.StatusGone {
+		return ReasonNotFound
+	}
+	return ""
+}
+
+// report is the list of the unavailable files.
+type report struct {
+	mu      sync.Mutex
+	files   map[string]Unavailable // by file ID
+	changed bool
+}
+
+// loadReport loads the report from the archive directory dir.  If there is no
+// report, it returns the empty report.
+func loadReport(dir string) (*report, error) {
+	rep := &report{files: make(map[string]Unavailable)}
+	data, err := os.ReadFile(filepath.Join(dir, UnavailableFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return rep, nil
+		}
+		return nil, err
+	}
+	var uu []Unavailable
+	if err := json.Unmarshal(data, &uu); err != nil {
+		return nil, err
+	}
+	for _, u := range uu {
+		rep.files[u.FileID] = u
+	}
+	return rep, nil
+}
+
+func (r *report) has(fileID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.files[fileID]
+	return ok
+}
+
+func (r *report) add(u Unavailable) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.files[u.FileID]; ok && old == u {
+		return
+	}
+	r.files[u.FileID] = u
+	r.changed = true
+}
+
+func (r *report) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.files)
+}
+
+// save writes the report to the archive directory dir, if it has changed.
+func (r *report) save(dir string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.changed {
+		return nil
+	}
+	uu := slices.SortedFunc(maps.Values(r.files), func(a, b Unavailable) int {
+		return strings.Compare(a.FileID, b.FileID)
+	})
+	data, err := json.MarshalIndent(uu, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, UnavailableFile), data, 0o644); err != nil {
+		return err
+	}
+	r.changed = false
+	return nil
+}
@@ -0,0 +1,353 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const scheme = "s3://"
+
+// IsURL returns true if s is the S3 URL, i.e. "s3://bucket/prefix".
+func IsURL(s string) bool {
+	return len(s) > len(scheme) && strings.EqualFold(s[:len(scheme)], scheme)
+}
+
+// ParseURL parses the S3 URL "s3://bucket/prefix" into the bucket and the
+// prefix.  The prefix has no leading or trailing slashes.
+func ParseURL(s string) (bucket string, prefix string, err error) {
+	if !IsURL(s) {
+		return "", "", fmt.Errorf("not an S3 URL: %q", s)
+	}
+	bucket, prefix, _ = strings.Cut(s[len(scheme):], "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("bucket is not specified: %q", s)
+	}
+	return bucket, strings.Trim(prefix, "/"), nil
+}
+
+// FS is the filesystem of the objects in the bucket under the prefix.  It
+// implements [fs.FS] for reading, and the fsadapter.FS interface for
+// writing, so it can be used as the archive output.
+//
+// Writes are buffered in the temporary file, and the object is uploaded on
+// Close.  Objects larger than 5 GiB are not supported.
+type FS struct {
+	ctx    context.Context
+	c      *Client
+	bucket string
+	prefix string
+}
+
+var (
+	_ fs.StatFS    = (*FS)(nil)
+	_ fs.ReadDirFS = (*FS)(nil)
+)
+
+// NewFS returns the filesystem of the bucket objects under the prefix.  ctx
+// is used for all requests.
+func NewFS(ctx context.Context, c *Client, bucket, prefix string) *FS {
+	return &FS{ctx: ctx, c: c, bucket: bucket, prefix: strings.Trim(prefix, "/")}
+}
+
+// Open returns the filesystem for the S3 URL, using the configuration from
+// the environment, see [ConfigFromEnv].
+func Open(ctx context.Context, s3url string) (*FS, error) {
+	bucket, prefix, err := ParseURL(s3url)
+	if err != nil {
+		return nil, err
+	}
+	c, err := New(ConfigFromEnv())
+	if err != nil {
+		return nil, err
+	}
+	return NewFS(ctx, c, bucket, prefix), nil
+}
+
+// Sub returns the filesystem of the subdirectory dir.
+func (sfs *FS) Sub(dir string) (fs.FS, error) {
+	if !fs.ValidPath(dir) {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: fs.ErrInvalid}
+	}
+	return NewFS(sfs.ctx, sfs.c, sfs.bucket, sfs.key(dir)), nil
+}
+
+func (sfs *FS) String() string {
+	return scheme + path.Join(sfs.bucket, sfs.prefix)
+}
+
+// key returns the object key for the name.
+func (sfs *FS) key(name string) string {
+	if name == "." {
+		return sfs.prefix
+	}
+	if sfs.prefix == "" {
+		return name
+	}
+	return sfs.prefix + "/" + name
+}
+
+// dirPrefix returns the listing prefix of the directory name.
+func (sfs *FS) dirPrefix(name string) string {
+	if k := sfs.key(name); k != "" {
+		return k + "/"
+	}
+	return ""
+}
+
+func (sfs *FS) Open(name string) (fs.File, error) {
+	fi, err := sfs.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return &dir{sfs: sfs, name: name, fi: fi}, nil
+	}
+	return &object{sfs: sfs, key: sfs.key(name), fi: fi}, nil
+}
+
+func (sfs *FS) Stat(name string) (fs.FileInfo, error) {
+	return sfs.stat("stat", name)
+}
+
+func (sfs *FS) stat(op, name string) (fileInfo, error) {
+	if !fs.ValidPath(name) {
+		return fileInfo{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name != "." {
+		oi, err := sfs.c.HeadObject(sfs.ctx, sfs.bucket, sfs.key(name))
+		if err == nil {
+			return fileInfo{name: path.Base(name), size: oi.Size, mtime: oi.LastModified}, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return fileInfo{}, &fs.PathError{Op: op, Path: name, Err: err}
+		}
+	}
+	// directories do not exist in S3, name is a directory if there are
+	// objects under it.
+	objs, prefixes, err := sfs.c.ListObjects(sfs.ctx, sfs.bucket, sfs.dirPrefix(name), "/", 1)
+	if err != nil {
+		return fileInfo{}, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	if len(objs) == 0 && len(prefixes) == 0 && name != "." {
+		return fileInfo{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return fileInfo{name: path.Base(name), dir: true}, nil
+}
+
+func (sfs *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	pfx := sfs.dirPrefix(name)
+	objs, prefixes, err := sfs.c.ListObjects(sfs.ctx, sfs.bucket, pfx, "/", 0)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	if len(objs) == 0 && len(prefixes) == 0 && name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	entries := make([]fs.DirEntry, 0, len(objs)+len(prefixes))
+	for _, p := range prefixes {
+		entries = append(entries, fileInfo{name: strings.TrimSuffix(strings.TrimPrefix(p, pfx), "/"), dir: true})
+	}
+	for _, o := range objs {
+		base := strings.TrimPrefix(o.Key, pfx)
+		if base == "" {
+			continue // directory marker
+		}
+		entries = append(entries, fileInfo{name: base, size: o.Size, mtime: o.LastModified})
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
+
+// Create creates the object name.  The object is uploaded when the returned
+// writer is closed.
+func (sfs *FS) Create(name string) (io.WriteCloser, error) {
+	tmp, err := os.CreateTemp("", "s3-*")
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	return &objectWriter{sfs: sfs, key: sfs.key(path.Clean(filepath.ToSlash(name))), tmp: tmp, h: h, w: io.MultiWriter(tmp, h)}, nil
+}
+
+// WriteFile uploads data as the object name.
+func (sfs *FS) WriteFile(name string, data []byte, _ os.FileMode) error {
+	sum := sha256.Sum256(data)
+	return sfs.c.PutObject(sfs.ctx, sfs.bucket, sfs.key(path.Clean(filepath.ToSlash(name))), bytes.NewReader(data), int64(len(data)), hex.EncodeToString(sum[:]))
+}
+
+// Close does nothing, it exists to satisfy the fsadapter.FSCloser interface.
+func (sfs *FS) Close() error {
+	return nil
+}
+
+type objectWriter struct {
+	sfs *FS
+	key string
+	tmp *os.File
+	h   hash.Hash
+	w   io.Writer
+	n   int64
+}
+
+func (ow *objectWriter) Write(p []byte) (int, error) {
+	n, err := ow.w.Write(p)
+	ow.n += int64(n)
+	return n, err
+}
+
+func (ow *objectWriter) Close() error {
+	defer os.Remove(ow.tmp.Name())
+	defer ow.tmp.Close()
+	if _, err := ow.tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return ow.sfs.c.PutObject(ow.sfs.ctx, ow.sfs.bucket, ow.key, ow.tmp, ow.n, hex.EncodeToString(ow.h.Sum(nil)))
+}
+
+// fileInfo implements both [fs.FileInfo] and [fs.DirEntry].
+type fileInfo struct {
+	name  string
+	size  int64
+	mtime time.Time
+	dir   bool
+}
+
+func (fi fileInfo) Name() string { return fi.name }
+func (fi fileInfo) Size() int64  { return fi.size }
+func (fi fileInfo) Mode() fs.FileMode {
+	if fi.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+func (fi fileInfo) ModTime() time.Time         { return fi.mtime }
+func (fi fileInfo) IsDir() bool                { return fi.dir }
+func (fi fileInfo) Sys() any                   { return nil }
+func (fi fileInfo) Type() fs.FileMode          { return fi.Mode().Type() }
+func (fi fileInfo) Info() (fs.FileInfo, error) { return fi, nil }
+
+// object is the object opened for reading.  The contents are requested on
+// the first read, and seeking requests the contents from the new offset.
+type object struct {
+	sfs  *FS
+	key  string
+	fi   fileInfo
+	off  int64
+	body io.ReadCloser
+}
+
+func (o *object) Stat() (fs.FileInfo, error) { return o.fi, nil }
+
+func (o *object) Read(p []byte) (int, error) {
+	if o.off >= o.fi.size {
+		return 0, io.EOF
+	}
+	if o.body == nil {
+		body, err := o.sfs.c.GetObject(o.sfs.ctx, o.sfs.bucket, o.key, o.off)
+		if err != nil {
+			return 0, err
+		}
+		o.body = body
+	}
+	n, err := o.body.Read(p)
+	o.off += int64(n)
+	return n, err
+}
+
+func (o *object) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = o.off + offset
+	case io.SeekEnd:
+		abs = o.fi.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if abs < 0 {
+		return 0, errors.New("negative position")
+	}
+	if abs != o.off && o.body != nil {
+		o.body.Close()
+		o.body = nil
+	}
+	o.off = abs
+	return abs, nil
+}
+
+func (o *object) Close() error {
+	if o.body != nil {
+		return o.body.Close()
+	}
+	return nil
+}
+
+// dir is the directory opened for reading.
+type dir struct {
+	sfs     *FS
+	name    string
+	fi      fileInfo
+	entries []fs.DirEntry
+	read    bool
+	off     int
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) { return d.fi, nil }
+func (d *dir) Close() error               { return nil }
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: fs.ErrInvalid}
+}
+
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		entries, err := d.sfs.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.read = entries, true
+	}
+	rest := d.entries[d.off:]
+	if n <= 0 {
+		d.off = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(rest))
+	d.off += n
+	return rest[:n], nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package s3_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusq/slackdump/v4/internal/s3"
+	"github.com/rusq/slackdump/v4/internal/s3/s3test"
+)
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		in         string
+		wantBucket string
+		wantPrefix string
+		wantErr    bool
+	}{
+		{"s3://bucket", "bucket", "", false},
+		{"s3://bucket/", "bucket", "", false},
+		{"S3://bucket/a/b/", "bucket", "a/b", false},
+		{"s3://", "", "", true},
+		{"s3:///prefix", "", "", true},
+		{"/local/dir", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			bucket, prefix, err := s3.ParseURL(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantBucket, bucket)
+			assert.Equal(t, tt.wantPrefix, prefix)
+		})
+	}
+}
+
+func newFS(t *testing.T, prefix string) (*s3test.Server, *s3.FS) {
+	t.Helper()
+	srv := s3test.NewServer(t)
+	c, err := s3.New(srv.Config())
+	require.NoError(t, err)
+	return srv, s3.NewFS(t.Context(), c, "bucket", prefix)
+}
+
+func TestFS(t *testing.T) {
+	srv, sfs := newFS(t, "backups/ws")
+
+	files := map[string]string{
+		"channels.json":              "[]",
+		"general/2024-01-01.json":    "[{}]",
+		"__uploads/F1/file name.txt": "hello, world",
+	}
+	for i := range 7 { // more than a page of the test server
+		files["many/"+strconv.Itoa(i)+".json"] = strconv.Itoa(i)
+	}
+	for name, data := range files {
+		w, err := sfs.Create(name)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+	got, ok := srv.Object("bucket", "backups/ws/__uploads/F1/file name.txt")
+	require.True(t, ok)
+	assert.Equal(t, "hello, world", string(got))
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	require.NoError(t, fstest.TestFS(sfs, names...))
+
+	t.Run("not exist", func(t *testing.T) {
+		_, err := sfs.Stat("missing.json")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		_, err = fs.ReadDir(sfs, "missing")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+	t.Run("seek", func(t *testing.T) {
+		f, err := sfs.Open("__uploads/F1/file name.txt")
+		require.NoError(t, err)
+		defer f.Close()
+		rs := f.(io.ReadSeeker)
+		_, err = rs.Seek(7, io.SeekStart)
+		require.NoError(t, err)
+		data, err := io.ReadAll(rs)
+		require.NoError(t, err)
+		assert.Equal(t, "world", string(data))
+	})
+}
+
+func TestFS_signature(t *testing.T) {
+	srv := s3test.NewServer(t)
+	cfg := srv.Config()
+	cfg.SecretAccessKey = "wrong"
+	c, err := s3.New(cfg)
+	require.NoError(t, err)
+	err = s3.NewFS(t.Context(), c, "bucket", "").WriteFile("x", []byte("x"), 0o644)
+	var s3err *s3.Error
+	require.True(t, errors.As(err, &s3err), "got %v", err)
+	assert.Equal(t, "SignatureDoesNotMatch", s3err.Code)
+}
+
+func TestUploadDownload(t *testing.T) {
+	_, sfs := newFS(t, "archive")
+	src := t.TempDir()
+	for _, name := range []string{"slackdump.sqlite", "__uploads/F1/a.txt", ".partial/x.part"} {
+		p := filepath.Join(src, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(name), 0o644))
+	}
+	skipPartial := func(name string) bool { return name == ".partial" }
+	require.NoError(t, s3.Upload(context.Background(), sfs, src, skipPartial))
+
+	_, err := sfs.Stat(".partial/x.part")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	dst := t.TempDir()
+	skipUploads := func(name string) bool { return name == "__uploads" }
+	require.NoError(t, s3.Download(context.Background(), dst, sfs, skipUploads))
+	data, err := os.ReadFile(filepath.Join(dst, "slackdump.sqlite"))
+	require.NoError(t, err)
+	assert.Equal(t, "slackdump.sqlite", string(data))
+	assert.NoFileExists(t, filepath.Join(dst, "__uploads", "F1", "a.txt"))
+}
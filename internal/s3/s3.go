@@ -0,0 +1,280 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package s3 implements the minimal client for the S3-compatible object
+// storage, and the filesystem adapter on top of it, that allows to write
+// archives to the bucket and read them back.
+package s3
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defRegion = "us-east-1"
+	// emptySHA256 is the SHA-256 of the empty payload.
+	emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// Config is the S3 client configuration.
+type Config struct {
+	// Endpoint is the URL of the S3-compatible service, i.e.
+	// "http://localhost:9000".  If empty, the AWS endpoint for the Region
+	// is used.
+	Endpoint string
+	// Region is the bucket region, "us-east-1" by default.
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// PathStyle forces the path-style requests (endpoint/bucket/key),
+	// which is the default for custom endpoints.
+	PathStyle bool
+	// HTTPClient is the HTTP client to use, http.DefaultClient if nil.
+	HTTPClient *http.Client
+}
+
+// ConfigFromEnv returns the configuration from the standard AWS environment
+// variables: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN,
+// AWS_REGION (or AWS_DEFAULT_REGION) and AWS_ENDPOINT_URL_S3 (or
+// AWS_ENDPOINT_URL) for the S3-compatible services.
+func ConfigFromEnv() Config {
+	endpoint := cmp.Or(os.Getenv("AWS_ENDPOINT_URL_S3"), os.Getenv("AWS_ENDPOINT_URL"))
+	return Config{
+		Endpoint:        endpoint,
+		Region:          cmp.Or(os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION")),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		PathStyle:       endpoint != "",
+	}
+}
+
+// Client is the S3 client.
+type Client struct {
+	endpoint  *url.URL
+	pathStyle bool
+	region    string
+	creds     credentials
+	hcl       *http.Client
+	now       func() time.Time
+}
+
+// New creates the new client with the configuration cfg.
+func New(cfg Config) (*Client, error) {
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("s3: access key and secret key are required")
+	}
+	region := cmp.Or(cfg.Region, defRegion)
+	endpoint := cmp.Or(cfg.Endpoint, "https://s3."+region+".amazonaws.com")
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("s3: invalid endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("s3: invalid endpoint scheme: %q", endpoint)
+	}
+	return &Client{
+		endpoint:  u,
+		pathStyle: cfg.PathStyle,
+		region:    region,
+		creds:     credentials{accessKey: cfg.AccessKeyID, secretKey: cfg.SecretAccessKey, token: cfg.SessionToken},
+		hcl:       cmp.Or(cfg.HTTPClient, http.DefaultClient),
+		now:       time.Now,
+	}, nil
+}
+
+// Error is the error response of the service.
+type Error struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+	Key        string `xml:"Key"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("s3: %s: %s", e.Code, e.Message)
+}
+
+// Is allows to match the "not found" errors with [fs.ErrNotExist].
+func (e *Error) Is(target error) bool {
+	return target == fs.ErrNotExist && e.StatusCode == http.StatusNotFound
+}
+
+// objectURL returns the URL of the object key in the bucket.
+func (c *Client) objectURL(bucket, key string) *url.URL {
+	u := *c.endpoint
+	p := "/" + key
+	if c.pathStyle {
+		p = "/" + bucket + p
+	} else {
+		u.Host = bucket + "." + u.Host
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + p
+	// the path is escaped the same way, as in the canonical request.
+	u.RawPath = escapePath(u.Path)
+	return &u
+}
+
+// do signs and executes the request.  payloadHash is the hex SHA-256 of the
+// body.
+func (c *Client) do(ctx context.Context, method string, u *url.URL, body io.Reader, size int64, payloadHash string, hdr http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	for k, vv := range hdr {
+		req.Header[k] = vv
+	}
+	c.creds.sign(req, c.region, payloadHash, c.now())
+	resp, err := c.hcl.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		e := &Error{StatusCode: resp.StatusCode}
+		if method != http.MethodHead {
+			_ = xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(e)
+		}
+		return nil, e
+	}
+	return resp, nil
+}
+
+// PutObject uploads the object key of the given size.  sum is the hex
+// SHA-256 of the contents.
+func (c *Client) PutObject(ctx context.Context, bucket, key string, body io.Reader, size int64, sum string) error {
+	resp, err := c.do(ctx, http.MethodPut, c.objectURL(bucket, key), body, size, sum, nil)
+	if err != nil {
+		return fmt.Errorf("put %s: %w", key, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// GetObject returns the contents of the object key, starting at the offset.
+func (c *Client) GetObject(ctx context.Context, bucket, key string, offset int64) (io.ReadCloser, error) {
+	var hdr http.Header
+	if offset > 0 {
+		hdr = http.Header{"Range": {"bytes=" + strconv.FormatInt(offset, 10) + "-"}}
+	}
+	resp, err := c.do(ctx, http.MethodGet, c.objectURL(bucket, key), nil, 0, emptySHA256, hdr)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", key, err)
+	}
+	return resp.Body, nil
+}
+
+// ObjectInfo is the information about the object.
+type ObjectInfo struct {
+	Key          string    `xml:"Key"`
+	Size         int64     `xml:"Size"`
+	LastModified time.Time `xml:"LastModified"`
+}
+
+// HeadObject returns the information about the object key.
+func (c *Client) HeadObject(ctx context.Context, bucket, key string) (ObjectInfo, error) {
+	resp, err := c.do(ctx, http.MethodHead, c.objectURL(bucket, key), nil, 0, emptySHA256, nil)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("head %s: %w", key, err)
+	}
+	resp.Body.Close()
+	oi := ObjectInfo{Key: key, Size: resp.ContentLength}
+	if lm, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		oi.LastModified = lm
+	}
+	return oi, nil
+}
+
+type listResult struct {
+	Contents              []ObjectInfo              `xml:"Contents"`
+	CommonPrefixes        []struct{ Prefix string } `xml:"CommonPrefixes"`
+	IsTruncated           bool                      `xml:"IsTruncated"`
+	NextContinuationToken string                    `xml:"NextContinuationToken"`
+}
+
+// ListObjects lists the objects with the prefix.  If delimiter is not empty,
+// the keys that contain the delimiter after the prefix are rolled up into
+// the common prefixes.  If limit is positive, at most limit objects and
+// prefixes are returned.
+func (c *Client) ListObjects(ctx context.Context, bucket, prefix, delimiter string, limit int) (objects []ObjectInfo, prefixes []string, err error) {
+	var token string
+	for {
+		u := c.objectURL(bucket, "")
+		q := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if delimiter != "" {
+			q.Set("delimiter", delimiter)
+		}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		if limit > 0 {
+			q.Set("max-keys", strconv.Itoa(limit))
+		}
+		u.RawQuery = q.Encode()
+		resp, err := c.do(ctx, http.MethodGet, u, nil, 0, emptySHA256, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("list %s: %w", prefix, err)
+		}
+		var lr listResult
+		err = xml.NewDecoder(resp.Body).Decode(&lr)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("list %s: %w", prefix, err)
+		}
+		objects = append(objects, lr.Contents...)
+		for _, p := range lr.CommonPrefixes {
+			prefixes = append(prefixes, p.Prefix)
+		}
+		if !lr.IsTruncated || lr.NextContinuationToken == "" || (limit > 0 && len(objects)+len(prefixes) >= limit) {
+			return objects, prefixes, nil
+		}
+		token = lr.NextContinuationToken
+	}
+}
+
+// hashReader returns the hex SHA-256 of the contents of r, and rewinds it.
+func hashReader(r io.ReadSeeker) (string, int64, error) {
+	h := sha256.New()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package s3test provides the in-memory S3-compatible server for tests.
+package s3test
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rusq/slackdump/v4/internal/s3"
+)
+
+const (
+	accessKey = "AKIDTEST"
+	secretKey = "secret"
+	region    = "us-east-1"
+)
+
+// Server is the in-memory S3-compatible server.  It supports path-style
+// PutObject, GetObject, HeadObject and ListObjectsV2 requests, and verifies
+// request signatures.
+type Server struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	objects map[string]object // key is "bucket/key"
+}
+
+type object struct {
+	data  []byte
+	mtime time.Time
+}
+
+// NewServer starts the server, that is closed on test cleanup.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{objects: make(map[string]object)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Config returns the client configuration for the server.
+func (s *Server) Config() s3.Config {
+	return s3.Config{
+		Endpoint:        s.URL,
+		Region:          region,
+		AccessKeyID:     accessKey,
+		SecretAccessKey: secretKey,
+		PathStyle:       true,
+		HTTPClient:      s.Client(),
+	}
+}
+
+// Setenv sets the environment variables for [s3.ConfigFromEnv] to point to
+// the server.
+func (s *Server) Setenv(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL_S3", s.URL)
+	t.Setenv("AWS_REGION", region)
+	t.Setenv("AWS_ACCESS_KEY_ID", accessKey)
+	t.Setenv("AWS_SECRET_ACCESS_KEY", secretKey)
+	t.Setenv("AWS_SESSION_TOKEN", "")
+}
+
+// Object returns the contents of the object.
+func (s *Server) Object(bucket, key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.objects[bucket+"/"+key]
+	return o.data, ok
+}
+
+// Keys returns the sorted list of object keys in the bucket.
+func (s *Server) Keys(bucket string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for k := range s.objects {
+		if key, ok := strings.CutPrefix(k, bucket+"/"); ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if got, want := s3.Signature(r, secretKey, region); got == "" || got != want {
+		writeError(w, http.StatusForbidden, "SignatureDoesNotMatch")
+		return
+	}
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodPut && key != "":
+		s.put(w, r, bucket, key)
+	case r.Method == http.MethodGet && key == "" && r.URL.Query().Get("list-type") == "2":
+		s.list(w, r, bucket)
+	case (r.Method == http.MethodGet || r.Method == http.MethodHead) && key != "":
+		s.get(w, r, bucket, key)
+	default:
+		writeError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (s *Server) put(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody")
+		return
+	}
+	s.mu.Lock()
+	s.objects[bucket+"/"+key] = object{data: data, mtime: time.Now().UTC().Truncate(time.Second)}
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	s.mu.Lock()
+	o, ok := s.objects[bucket+"/"+key]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+	w.Header().Set("Last-Modified", o.mtime.Format(http.TimeFormat))
+	data := o.data
+	status := http.StatusOK
+	if rng, ok := strings.CutPrefix(r.Header.Get("Range"), "bytes="); ok {
+		start, err := strconv.Atoi(strings.TrimSuffix(rng, "-"))
+		if err != nil || start >= len(data) {
+			writeError(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange")
+			return
+		}
+		w.Header().Set("Content-Range", "bytes "+strconv.Itoa(start)+"-"+strconv.Itoa(len(data)-1)+"/"+strconv.Itoa(len(data)))
+		data, status = data[start:], http.StatusPartialContent
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(status)
+	if r.Method == http.MethodGet {
+		w.Write(data)
+	}
+}
+
+type listResult struct {
+	XMLName               xml.Name       `xml:"ListBucketResult"`
+	Contents              []listObject   `xml:"Contents"`
+	CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+}
+
+type listObject struct {
+	Key          string    `xml:"Key"`
+	Size         int64     `xml:"Size"`
+	LastModified time.Time `xml:"LastModified"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// pageSize is small to exercise pagination in tests.
+const pageSize = 5
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, bucket string) {
+	q := r.URL.Query()
+	prefix, delim := q.Get("prefix"), q.Get("delimiter")
+	limit := pageSize
+	if mk, err := strconv.Atoi(q.Get("max-keys")); err == nil && mk > 0 && mk < limit {
+		limit = mk
+	}
+
+	// entries are sorted keys and common prefixes, as S3 returns them.
+	type entry struct {
+		key    string
+		prefix bool
+		obj    object
+	}
+	var entries []entry
+	seen := make(map[string]bool)
+	s.mu.Lock()
+	for k, o := range s.objects {
+		key, ok := strings.CutPrefix(k, bucket+"/")
+		if !ok || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if delim != "" {
+			if i := strings.Index(key[len(prefix):], delim); i >= 0 {
+				cp := key[:len(prefix)+i+len(delim)]
+				if !seen[cp] {
+					seen[cp] = true
+					entries = append(entries, entry{key: cp, prefix: true})
+				}
+				continue
+			}
+		}
+		entries = append(entries, entry{key: key, obj: o})
+	}
+	s.mu.Unlock()
+	slices.SortFunc(entries, func(a, b entry) int { return strings.Compare(a.key, b.key) })
+
+	if after := q.Get("continuation-token"); after != "" {
+		i, _ := slices.BinarySearchFunc(entries, after, func(e entry, t string) int { return strings.Compare(e.key, t) })
+		for i < len(entries) && entries[i].key <= after {
+			i++
+		}
+		entries = entries[i:]
+	}
+	var res listResult
+	if len(entries) > limit {
+		entries = entries[:limit]
+		res.IsTruncated = true
+		res.NextContinuationToken = entries[len(entries)-1].key
+	}
+	for _, e := range entries {
+		if e.prefix {
+			res.CommonPrefixes = append(res.CommonPrefixes, commonPrefix{Prefix: e.key})
+		} else {
+			res.Contents = append(res.Contents, listObject{Key: e.key, Size: int64(len(e.obj.data)), LastModified: e.obj.mtime})
+		}
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(res)
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	io.WriteString(w, "<Error><Code>"+code+"</Code></Error>")
+}
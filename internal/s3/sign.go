@@ -0,0 +1,173 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	sigAlgorithm = "AWS4-HMAC-SHA256"
+	amzDate      = "20060102T150405Z"
+	shortDate    = "20060102"
+	service      = "s3"
+)
+
+// credentials sign the requests with the AWS Signature Version 4.
+type credentials struct {
+	accessKey string
+	secretKey string
+	token     string
+}
+
+// sign adds the signature headers to the request.
+func (c credentials) sign(req *http.Request, region string, payloadHash string, now time.Time) {
+	now = now.UTC()
+	req.Header.Set("X-Amz-Date", now.Format(amzDate))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if c.token != "" {
+		req.Header.Set("X-Amz-Security-Token", c.token)
+	}
+	scope := now.Format(shortDate) + "/" + region + "/" + service + "/aws4_request"
+	canonical, signed := canonicalRequest(req, payloadHash)
+	sum := sha256.Sum256([]byte(canonical))
+	toSign := sigAlgorithm + "\n" + now.Format(amzDate) + "\n" + scope + "\n" + hex.EncodeToString(sum[:])
+
+	key := hmacSHA256([]byte("AWS4"+c.secretKey), now.Format(shortDate))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	sig := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", sigAlgorithm+" Credential="+c.accessKey+"/"+scope+", SignedHeaders="+signed+", Signature="+sig)
+}
+
+// Signature returns the signature from the Authorization header of the
+// request, and the signature calculated with the secret key.  It is used by
+// the test server to verify the requests.
+func Signature(req *http.Request, secretKey string, region string) (got string, want string) {
+	auth := req.Header.Get("Authorization")
+	_, got, _ = strings.Cut(auth, "Signature=")
+	t, err := time.Parse(amzDate, req.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return got, ""
+	}
+	clone := req.Clone(req.Context())
+	clone.Header.Del("Authorization")
+	credentials{secretKey: secretKey, token: req.Header.Get("X-Amz-Security-Token")}.sign(clone, region, req.Header.Get("X-Amz-Content-Sha256"), t)
+	_, want, _ = strings.Cut(clone.Header.Get("Authorization"), "Signature=")
+	return got, want
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// signedHeaders are the headers, besides the x-amz-* ones, that are signed,
+// if present.
+var signedHeaders = []string{"content-md5", "content-type", "range"}
+
+// canonicalRequest returns the canonical request and the list of signed
+// headers.  The host, all x-amz-* headers and [signedHeaders] are signed.
+func canonicalRequest(req *http.Request, payloadHash string) (string, string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	hdrs := map[string]string{"host": host}
+	for k, vv := range req.Header {
+		lk := strings.ToLower(k)
+		if strings.HasPrefix(lk, "x-amz-") || slices.Contains(signedHeaders, lk) {
+			hdrs[lk] = strings.TrimSpace(strings.Join(vv, ","))
+		}
+	}
+	names := make([]string, 0, len(hdrs))
+	for k := range hdrs {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	var ch strings.Builder
+	for _, k := range names {
+		ch.WriteString(k + ":" + hdrs[k] + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	return strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.EscapedPath()),
+		canonicalQuery(req.URL.Query()),
+		ch.String(),
+		signed,
+		payloadHash,
+	}, "\n"), signed
+}
+
+// escapePath returns the canonical URI path: each segment is unescaped and
+// escaped again per the S3 rules.
+func escapePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	segs := strings.Split(p, "/")
+	for i, s := range segs {
+		if u, err := url.PathUnescape(s); err == nil {
+			s = u
+		}
+		segs[i] = uriEncode(s)
+	}
+	return strings.Join(segs, "/")
+}
+
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var parts []string
+	for _, k := range keys {
+		vv := slices.Clone(q[k])
+		slices.Sort(vv)
+		for _, v := range vv {
+			parts = append(parts, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode encodes s per the AWS rules: everything except the unreserved
+// characters is percent-encoded.
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			b.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package seal
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/rusq/fsadapter"
+)
+
+// Adapter returns the fs adapter that seals the files as they are written to
+// fsa, so that the plaintext never reaches the disk.
+func Adapter(fsa fsadapter.FS, s *Secret) fsadapter.FS {
+	return &sealedAdapter{fsa: fsa, s: s}
+}
+
+type sealedAdapter struct {
+	fsa fsadapter.FS
+	s   *Secret
+}
+
+// Create implements fsadapter.FS.
+func (a *sealedAdapter) Create(name string) (io.WriteCloser, error) {
+	wc, err := a.fsa.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	sw, err := NewWriter(wc, a.s)
+	if err != nil {
+		return nil, errors.Join(err, wc.Close())
+	}
+	return &sealedFile{Writer: sw, wc: wc}, nil
+}
+
+// WriteFile implements fsadapter.FS.
+func (a *sealedAdapter) WriteFile(name string, data []byte, perm os.FileMode) error {
+	var buf bytes.Buffer
+	sw, err := NewWriter(&buf, a.s)
+	if err != nil {
+		return err
+	}
+	if _, err := sw.Write(data); err != nil {
+		return err
+	}
+	if err := sw.Close(); err != nil {
+		return err
+	}
+	return a.fsa.WriteFile(name, buf.Bytes(), perm)
+}
+
+// sealedFile writes the last segment and closes the underlying file on
+// Close.
+type sealedFile struct {
+	*Writer
+	wc io.WriteCloser
+}
+
+func (f *sealedFile) Close() error {
+	return errors.Join(f.Writer.Close(), f.wc.Close())
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package seal
+
+import (
+	"context"
+	"sync"
+)
+
+type secretCtxKey struct{}
+
+// SecretFunc returns the secret, i.e. by asking the user for the passphrase.
+type SecretFunc func() (*Secret, error)
+
+// WithSecretFunc returns the context with the function that returns the
+// secret for the sealed archives.  The function is called once, when the
+// secret is needed for the first time, and the result is reused.
+func WithSecretFunc(ctx context.Context, fn SecretFunc) context.Context {
+	return context.WithValue(ctx, secretCtxKey{}, sync.OnceValues(fn))
+}
+
+// WithSecret returns the context with the secret for the sealed archives.
+func WithSecret(ctx context.Context, s *Secret) context.Context {
+	return WithSecretFunc(ctx, func() (*Secret, error) { return s, nil })
+}
+
+// SecretFromContext returns the secret from the context.  It returns
+// [ErrNoSecret] if the context does not have one.
+func SecretFromContext(ctx context.Context) (*Secret, error) {
+	fn, ok := ctx.Value(secretCtxKey{}).(func() (*Secret, error))
+	if !ok {
+		return nil, ErrNoSecret
+	}
+	s, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, ErrNoSecret
+	}
+	return s, nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package seal
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// SealFile seals the file in place.  Files that are already sealed are left
+// as they are.
+func SealFile(filename string, s *Secret) error {
+	return replace(filename, func(w io.Writer, f *os.File, _ int64) error {
+		if IsSealed(f) {
+			return errSkip
+		}
+		sw, err := NewWriter(w, s)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(sw, f); err != nil {
+			return err
+		}
+		return sw.Close()
+	})
+}
+
+// UnsealFile decrypts the sealed file in place.  Files that are not sealed
+// are left as they are.
+func UnsealFile(filename string, s *Secret) error {
+	return replace(filename, func(w io.Writer, f *os.File, size int64) error {
+		r, err := NewReader(f, size, s)
+		if err != nil {
+			if errors.Is(err, ErrNotSealed) {
+				return errSkip
+			}
+			return err
+		}
+		_, err = io.Copy(w, r)
+		return err
+	})
+}
+
+// errSkip is returned by the replace function to leave the file unchanged.
+var errSkip = errors.New("skip")
+
+// replace replaces the contents of the file with the output of fn, atomically.
+func replace(filename string, fn func(w io.Writer, f *os.File, size int64) error) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := fn(tmp, f, fi.Size()); err != nil {
+		tmp.Close()
+		if errors.Is(err, errSkip) {
+			return nil
+		}
+		return err
+	}
+	if err := tmp.Chmod(fi.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	f.Close()
+	return os.Rename(tmp.Name(), filename)
+}
+
+// SealDir seals all regular files in the directory dir and its
+// subdirectories, in place.  Files that are already sealed are left as they
+// are.  It returns the number of files processed.
+func SealDir(dir string, s *Secret) (int, error) {
+	return walkFiles(dir, nil, func(path string) error { return SealFile(path, s) })
+}
+
+// UnsealDir decrypts all sealed files in the directory dir and its
+// subdirectories, in place, except the subdirectories in keep, given as the
+// slash-separated paths relative to dir, that are left sealed.  It returns
+// the number of files processed.
+func UnsealDir(dir string, s *Secret, keep ...string) (int, error) {
+	return walkFiles(dir, keep, func(path string) error { return UnsealFile(path, s) })
+}
+
+func walkFiles(dir string, skip []string, fn func(path string) error) (int, error) {
+	var n int
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && len(skip) > 0 {
+			if rel, err := filepath.Rel(dir, path); err == nil && slices.Contains(skip, filepath.ToSlash(rel)) {
+				return filepath.SkipDir
+			}
+		}
+		if !d.Type().IsRegular() || d.Name() == PendingFile {
+			return nil
+		}
+		if err := fn(path); err != nil {
+			return &fs.PathError{Op: "seal", Path: path, Err: err}
+		}
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// PendingFile is the name of the marker file, that is created in the archive
+// directory while its files are in plaintext and are going to be sealed, i.e.
+// while "resume" is running.  If the marker is present when the archive is
+// opened, the run that created it was interrupted, and the archive must be
+// sealed again.
+const PendingFile = ".slackdump-unsealed"
+
+// ErrPending is returned if the archive was left in plaintext by the
+// interrupted run.
+var ErrPending = errors.New("archive was left decrypted by an interrupted run, encrypt it again with \"slackdump tools seal\"")
+
+// MarkPending creates the marker in the archive directory dir, see
+// [PendingFile].
+func MarkPending(dir string) error {
+	return os.WriteFile(filepath.Join(dir, PendingFile), nil, 0o600)
+}
+
+// ClearPending removes the marker from the archive directory dir.
+func ClearPending(dir string) error {
+	if err := os.Remove(filepath.Join(dir, PendingFile)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// IsPending reports whether the archive directory dir has the marker.
+func IsPending(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, PendingFile))
+	return err == nil
+}
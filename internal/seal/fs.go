@@ -0,0 +1,168 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package seal
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+)
+
+// FS returns the filesystem that transparently decrypts the sealed files of
+// fsys with the secret.  Files that are not sealed are returned as is.
+func FS(fsys fs.FS, s *Secret) fs.FS {
+	return &sealedFS{fsys: fsys, s: s}
+}
+
+type sealedFS struct {
+	fsys fs.FS
+	s    *Secret
+}
+
+func (sfs *sealedFS) Open(name string) (fs.File, error) {
+	f, err := sfs.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if fi.IsDir() {
+		if df, ok := f.(fs.ReadDirFile); ok {
+			return &dirFile{ReadDirFile: df, sfs: sfs, name: name}, nil
+		}
+		return f, nil
+	}
+	if !fi.Mode().IsRegular() {
+		return f, nil
+	}
+	ra, ok := f.(io.ReaderAt)
+	if !ok {
+		// i.e. files in ZIP archives, read the file into memory.
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		rf := &memFile{Reader: bytes.NewReader(data), fi: fi}
+		if !IsSealed(rf.Reader) {
+			return rf, nil
+		}
+		ra, f = rf.Reader, rf
+	}
+	if !IsSealed(ra) {
+		return f, nil
+	}
+	r, err := NewReader(ra, fi.Size(), sfs.s)
+	if err != nil {
+		f.Close()
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &file{Reader: r, f: f, fi: fi}, nil
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (sfs *sealedFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	ee, err := fs.ReadDir(sfs.fsys, name)
+	return sfs.wrapEntries(name, ee), err
+}
+
+func (sfs *sealedFS) wrapEntries(dir string, ee []fs.DirEntry) []fs.DirEntry {
+	for i := range ee {
+		if ee[i].Type().IsRegular() {
+			ee[i] = dirEntry{DirEntry: ee[i], sfs: sfs, name: path.Join(dir, ee[i].Name())}
+		}
+	}
+	return ee
+}
+
+// plainInfo returns the file info of the regular file name with the
+// plaintext size, if the file is sealed.  It does not decrypt the file.
+func (sfs *sealedFS) plainInfo(name string, fi fs.FileInfo) (fs.FileInfo, error) {
+	f, err := sfs.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var m [len(magic)]byte
+	if _, err := io.ReadFull(f, m[:]); err != nil || string(m[:]) != magic {
+		return fi, nil
+	}
+	_, size := layout(fi.Size())
+	return fileInfo{FileInfo: fi, size: size}, nil
+}
+
+// dirFile is the directory, that reports the plaintext size of the sealed
+// files in its entries.
+type dirFile struct {
+	fs.ReadDirFile
+	sfs  *sealedFS
+	name string
+}
+
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	ee, err := d.ReadDirFile.ReadDir(n)
+	return d.sfs.wrapEntries(d.name, ee), err
+}
+
+type dirEntry struct {
+	fs.DirEntry
+	sfs  *sealedFS
+	name string
+}
+
+func (e dirEntry) Info() (fs.FileInfo, error) {
+	fi, err := e.DirEntry.Info()
+	if err != nil {
+		return nil, err
+	}
+	return e.sfs.plainInfo(e.name, fi)
+}
+
+// file is the decrypting file.
+type file struct {
+	*Reader
+	f  fs.File
+	fi fs.FileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) {
+	return fileInfo{FileInfo: f.fi, size: f.Size()}, nil
+}
+
+func (f *file) Close() error {
+	return f.f.Close()
+}
+
+// fileInfo overrides the size of the underlying file.
+type fileInfo struct {
+	fs.FileInfo
+	size int64
+}
+
+func (fi fileInfo) Size() int64 { return fi.size }
+
+// memFile is the file read into memory.
+type memFile struct {
+	*bytes.Reader
+	fi fs.FileInfo
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.fi, nil }
+func (f *memFile) Close() error               { return nil }
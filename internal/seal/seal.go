@@ -0,0 +1,399 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package seal implements the passphrase-based encryption of archives at rest.
+//
+// A sealed file starts with the header, that contains the magic bytes, the
+// salt for the key derivation function, and the random nonce prefix, followed
+// by the plaintext split into segments, each encrypted with AES-256-GCM.  The
+// nonce of the segment is the nonce prefix, the segment number, and the flag
+// that marks the last segment, so that segments can not be reordered, and the
+// file can not be truncated undetected.  The header is authenticated as
+// additional data of every segment.
+//
+// Segments are encrypted independently, which allows random access to the
+// plaintext, see [Reader].
+//
+// The key is derived from the passphrase (or the contents of the key file)
+// with scrypt.  Key derivation is slow by design, so the derived keys are
+// cached in the [Secret], and all files sealed with the same Secret share the
+// salt.
+package seal
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	magic       = "SDSEAL01"
+	saltSize    = 16
+	prefixSize  = 7
+	headerSize  = len(magic) + saltSize + prefixSize
+	segmentSize = 64 << 10 // plaintext segment size
+	tagSize     = 16       // GCM tag size
+	keySize     = 32
+
+	// scrypt parameters, as recommended for interactive logins in 2017.
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	// ErrNotSealed is returned if the data is not sealed.
+	ErrNotSealed = errors.New("data is not sealed")
+	// ErrBadSecret is returned if the passphrase or key file is wrong, or the
+	// data is corrupted.
+	ErrBadSecret = errors.New("wrong passphrase or key file, or the data is corrupted")
+	// ErrNoSecret is returned if the archive is sealed, but no passphrase or
+	// key file was provided.
+	ErrNoSecret = errors.New("archive is encrypted, passphrase or key file is required")
+	// ErrEmptySecret is returned if the passphrase is empty.
+	ErrEmptySecret = errors.New("passphrase is empty")
+)
+
+// Secret is the passphrase or the key file contents, and the cache of keys
+// derived from it.  It is safe for concurrent use.
+type Secret struct {
+	pass []byte
+
+	mu   sync.Mutex
+	salt []byte            // salt for newly sealed files
+	keys map[string][]byte // salt -> derived key
+}
+
+// NewSecret returns the Secret for the passphrase.
+func NewSecret(passphrase []byte) (*Secret, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrEmptySecret
+	}
+	return &Secret{pass: bytes.Clone(passphrase), keys: make(map[string][]byte)}, nil
+}
+
+// ReadKeyFile returns the Secret with the contents of the key file.  The
+// trailing newline, if any, is ignored, so that the file could be created with
+// the text editor.
+func ReadKeyFile(filename string) (*Secret, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return NewSecret(bytes.TrimRight(data, "\r\n"))
+}
+
+// key returns the key for the salt.
+func (s *Secret) key(salt []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if k, ok := s.keys[string(salt)]; ok {
+		return k, nil
+	}
+	k, err := scrypt.Key(s.pass, salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, err
+	}
+	s.keys[string(salt)] = k
+	return k, nil
+}
+
+// newSalt returns the salt for new files.
+func (s *Secret) newSalt() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.salt == nil {
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		s.salt = salt
+	}
+	return s.salt, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// nonce returns the nonce for the segment n.
+func nonce(prefix []byte, n uint32, last bool) []byte {
+	nc := make([]byte, 0, prefixSize+5)
+	nc = append(nc, prefix...)
+	nc = binary.BigEndian.AppendUint32(nc, n)
+	if last {
+		return append(nc, 1)
+	}
+	return append(nc, 0)
+}
+
+// Writer seals the data written to it.  Close must be called to write the
+// last segment.
+type Writer struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	header []byte
+	buf    []byte
+	n      uint32
+	closed bool
+}
+
+// NewWriter returns the Writer that writes the sealed data to w.  Closing the
+// Writer does not close w.
+func NewWriter(w io.Writer, s *Secret) (*Writer, error) {
+	salt, err := s.newSalt()
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.key(salt)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, headerSize)
+	header = append(header, magic...)
+	header = append(header, salt...)
+	prefix := make([]byte, prefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+	header = append(header, prefix...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &Writer{w: w, aead: aead, header: header, buf: make([]byte, 0, segmentSize)}, nil
+}
+
+// Write implements io.Writer.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	var total int
+	for len(p) > 0 {
+		if len(w.buf) == segmentSize {
+			// more data follows, so the buffered segment is not the last one.
+			if err := w.flush(false); err != nil {
+				return total, err
+			}
+		}
+		n := copy(w.buf[len(w.buf):segmentSize], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		total += n
+	}
+	return total, nil
+}
+
+func (w *Writer) flush(last bool) error {
+	ct := w.aead.Seal(nil, nonce(w.header[len(magic)+saltSize:], w.n, last), w.buf, w.header)
+	if _, err := w.w.Write(ct); err != nil {
+		return err
+	}
+	w.n++
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// Close writes the last segment.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.flush(true)
+}
+
+// Reader provides random access to the plaintext of the sealed data.  It
+// implements io.Reader, io.ReaderAt and io.Seeker.
+type Reader struct {
+	ra     io.ReaderAt
+	aead   cipher.AEAD
+	header []byte
+	nseg   int64 // number of segments
+	size   int64 // plaintext size
+
+	// the last decrypted segment
+	segN  int64
+	seg   []byte
+	segCT []byte
+
+	mu  sync.Mutex
+	off int64 // current offset for Read and Seek
+}
+
+// IsSealed reports whether the data in ra starts with the sealed header.
+func IsSealed(ra io.ReaderAt) bool {
+	var m [len(magic)]byte
+	if _, err := ra.ReadAt(m[:], 0); err != nil {
+		return false
+	}
+	return string(m[:]) == magic
+}
+
+// IsSealedFile reports whether the file is sealed.
+func IsSealedFile(filename string) (bool, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	return IsSealed(f), nil
+}
+
+// NewReader returns the Reader for the sealed data in ra of the given size.
+// It returns [ErrNotSealed] if the data is not sealed, and [ErrBadSecret] if
+// the secret does not match.
+func NewReader(ra io.ReaderAt, size int64, s *Secret) (*Reader, error) {
+	if size < int64(headerSize+tagSize) {
+		return nil, ErrNotSealed
+	}
+	header := make([]byte, headerSize)
+	if _, err := ra.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, ErrNotSealed
+	}
+	key, err := s.key(header[len(magic) : len(magic)+saltSize])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nseg, ptSize := layout(size)
+	r := &Reader{
+		ra:     ra,
+		aead:   aead,
+		header: header,
+		nseg:   nseg,
+		size:   ptSize,
+		segN:   -1,
+		segCT:  make([]byte, segmentSize+tagSize),
+	}
+	if r.size < 0 {
+		return nil, ErrBadSecret
+	}
+	// verify the secret on the first segment.
+	if err := r.load(0); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// layout returns the number of segments and the plaintext size of the sealed
+// data of the given size.
+func layout(size int64) (nseg int64, ptSize int64) {
+	ctSize := size - int64(headerSize)
+	nseg = (ctSize + segmentSize + tagSize - 1) / (segmentSize + tagSize)
+	return nseg, ctSize - nseg*tagSize
+}
+
+// Size returns the plaintext size.
+func (r *Reader) Size() int64 {
+	return r.size
+}
+
+// load decrypts the segment n.
+func (r *Reader) load(n int64) error {
+	if n == r.segN {
+		return nil
+	}
+	off := int64(headerSize) + n*(segmentSize+tagSize)
+	ct := r.segCT
+	if n == r.nseg-1 {
+		ct = ct[:r.size-n*segmentSize+tagSize]
+	}
+	if _, err := r.ra.ReadAt(ct, off); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	pt, err := r.aead.Open(r.seg[:0], nonce(r.header[len(magic)+saltSize:], uint32(n), n == r.nseg-1), ct, r.header)
+	if err != nil {
+		r.segN = -1
+		return ErrBadSecret
+	}
+	r.seg, r.segN = pt, n
+	return nil
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *Reader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("seal: negative offset %d", off)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var total int
+	for len(p) > 0 {
+		if off >= r.size {
+			return total, io.EOF
+		}
+		n := off / segmentSize
+		if err := r.load(n); err != nil {
+			return total, err
+		}
+		c := copy(p, r.seg[off-n*segmentSize:])
+		p = p[c:]
+		off += int64(c)
+		total += c
+	}
+	return total, nil
+}
+
+// Read implements io.Reader.
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.ReadAt(p, r.off)
+	r.off += int64(n)
+	if n > 0 && errors.Is(err, io.EOF) {
+		err = nil
+	}
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.off
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, fmt.Errorf("seal: invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("seal: negative position %d", offset)
+	}
+	r.off = offset
+	return offset, nil
+}
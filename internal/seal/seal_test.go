@@ -0,0 +1,231 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package seal
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/rusq/fsadapter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustSecret(t *testing.T, pass string) *Secret {
+	t.Helper()
+	s, err := NewSecret([]byte(pass))
+	require.NoError(t, err)
+	return s
+}
+
+func sealBytes(t *testing.T, s *Secret, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, s)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	s := mustSecret(t, "correct horse battery staple")
+	for _, size := range []int{0, 1, segmentSize - 1, segmentSize, segmentSize + 1, 3*segmentSize + 17} {
+		data := make([]byte, size)
+		_, _ = rand.Read(data)
+		sealed := sealBytes(t, s, data)
+		assert.True(t, IsSealed(bytes.NewReader(sealed)))
+
+		r, err := NewReader(bytes.NewReader(sealed), int64(len(sealed)), s)
+		require.NoError(t, err, size)
+		assert.Equal(t, int64(size), r.Size())
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, data, got, size)
+
+		if size > segmentSize+5 {
+			// random access across the segment boundary
+			p := make([]byte, 10)
+			_, err := r.ReadAt(p, segmentSize-5)
+			require.NoError(t, err)
+			assert.Equal(t, data[segmentSize-5:segmentSize+5], p)
+		}
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	s := mustSecret(t, "secret")
+	data := bytes.Repeat([]byte("x"), 2*segmentSize+10)
+	sealed := sealBytes(t, s, data)
+
+	t.Run("wrong secret", func(t *testing.T) {
+		_, err := NewReader(bytes.NewReader(sealed), int64(len(sealed)), mustSecret(t, "wrong"))
+		assert.ErrorIs(t, err, ErrBadSecret)
+	})
+	t.Run("not sealed", func(t *testing.T) {
+		_, err := NewReader(bytes.NewReader(data), int64(len(data)), s)
+		assert.ErrorIs(t, err, ErrNotSealed)
+	})
+	t.Run("truncated at segment boundary", func(t *testing.T) {
+		trunc := sealed[:headerSize+2*(segmentSize+tagSize)]
+		r, err := NewReader(bytes.NewReader(trunc), int64(len(trunc)), s)
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		assert.ErrorIs(t, err, ErrBadSecret)
+	})
+	t.Run("tampered", func(t *testing.T) {
+		bad := bytes.Clone(sealed)
+		bad[len(bad)-20] ^= 1
+		r, err := NewReader(bytes.NewReader(bad), int64(len(bad)), s)
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		assert.ErrorIs(t, err, ErrBadSecret)
+	})
+}
+
+func TestSealDir(t *testing.T) {
+	s := mustSecret(t, "secret")
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"slackdump.sqlite":            []byte("SQLite format 3\x00"),
+		"__uploads/F1/hello.txt":      []byte("hello"),
+		"__avatars/U1/avatar.png":     bytes.Repeat([]byte{1}, segmentSize+1),
+		"__uploads/F2/empty_file.txt": {},
+	}
+	for name, data := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o644))
+	}
+
+	n, err := SealDir(dir, s)
+	require.NoError(t, err)
+	assert.Equal(t, len(files), n)
+	for name := range files {
+		ok, err := IsSealedFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.True(t, ok, name)
+	}
+	// sealing twice is a no-op.
+	_, err = SealDir(dir, s)
+	require.NoError(t, err)
+
+	// transparent access
+	fsys := FS(os.DirFS(dir), s)
+	for name, data := range files {
+		got, err := fs.ReadFile(fsys, name)
+		require.NoError(t, err, name)
+		assert.Equal(t, data, got, name)
+		fi, err := fs.Stat(fsys, name)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), fi.Size())
+	}
+
+	// the marker is not sealed.
+	require.NoError(t, MarkPending(dir))
+	assert.True(t, IsPending(dir))
+	_, err = SealDir(dir, s)
+	require.NoError(t, err)
+	ok, err := IsSealedFile(filepath.Join(dir, PendingFile))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	require.NoError(t, ClearPending(dir))
+	assert.False(t, IsPending(dir))
+	require.NoError(t, ClearPending(dir), "no marker")
+
+	// the kept directories are left sealed.
+	n, err = UnsealDir(dir, s, "__uploads")
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	for name, want := range map[string]bool{"slackdump.sqlite": false, "__avatars/U1/avatar.png": false, "__uploads/F1/hello.txt": true} {
+		ok, err := IsSealedFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, want, ok, name)
+	}
+	_, err = SealDir(dir, s)
+	require.NoError(t, err)
+
+	_, err = UnsealDir(dir, mustSecret(t, "wrong"))
+	assert.ErrorIs(t, err, ErrBadSecret)
+
+	_, err = UnsealDir(dir, s)
+	require.NoError(t, err)
+	for name, data := range files {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, data, got, name)
+	}
+}
+
+func TestAdapter(t *testing.T) {
+	s := mustSecret(t, "secret")
+	dir := t.TempDir()
+	fsa := Adapter(fsadapter.NewDirectory(dir), s)
+
+	data := bytes.Repeat([]byte("data"), segmentSize)
+	wc, err := fsa.Create("__uploads/F1/big.bin")
+	require.NoError(t, err)
+	_, err = wc.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+	require.NoError(t, fsa.WriteFile("__links/L1/page.html", []byte("page"), 0o644))
+
+	fsys := FS(os.DirFS(dir), s)
+	for name, want := range map[string][]byte{"__uploads/F1/big.bin": data, "__links/L1/page.html": []byte("page")} {
+		ok, err := IsSealedFile(filepath.Join(dir, filepath.FromSlash(name)))
+		require.NoError(t, err)
+		assert.True(t, ok, name)
+		got, err := fs.ReadFile(fsys, name)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, name)
+	}
+}
+
+func TestFS_passthrough(t *testing.T) {
+	s := mustSecret(t, "secret")
+	fsys := FS(fstest.MapFS{
+		"plain.txt":  {Data: []byte("plain")},
+		"sealed.txt": {Data: sealBytes(t, s, []byte("sealed"))},
+	}, s)
+	require.NoError(t, fstest.TestFS(fsys, "plain.txt", "sealed.txt"))
+	got, err := fs.ReadFile(fsys, "sealed.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "sealed", string(got))
+}
+
+func TestSecretFromContext(t *testing.T) {
+	_, err := SecretFromContext(context.Background())
+	assert.ErrorIs(t, err, ErrNoSecret)
+
+	var calls int
+	ctx := WithSecretFunc(context.Background(), func() (*Secret, error) {
+		calls++
+		return NewSecret([]byte("x"))
+	})
+	s1, err := SecretFromContext(ctx)
+	require.NoError(t, err)
+	s2, err := SecretFromContext(ctx)
+	require.NoError(t, err)
+	assert.Same(t, s1, s2)
+	assert.Equal(t, 1, calls)
+}
@@ -45,6 +45,9 @@ const (
 // have no prefix character (their canonical display is derived from the purpose
 // text, not a fixed symbol), so an empty string is returned for that type.
 func ChannelPrefix(ch slack.Channel) string {
+	if IsCanvas(ch) {
+		return "📄 "
+	}
 	switch ChannelType(ch) {
 	case CIM:
 		return "@"
@@ -82,3 +85,21 @@ func IsMember(ch *slack.Channel) bool {
 	}
 	return ch.IsMember
 }
+
+// CanvasChannelID returns the ID of the dedicated channel that holds the
+// comment threads of the canvas with the given file ID.  Canvas channels reuse
+// the file ID suffix with a leading "C" instead of "F".  It returns an empty
+// string if fileID is not a file ID.
+func CanvasChannelID(fileID string) string {
+	if len(fileID) < 2 || fileID[0] != 'F' {
+		return ""
+	}
+	return "C" + fileID[1:]
+}
+
+// IsCanvas returns true if the channel is a canvas channel, i.e. the channel
+// that belongs to a standalone canvas, rather than a conversation that has a
+// canvas attached.
+func IsCanvas(ch slack.Channel) bool {
+	return ch.Properties != nil && ch.Properties.Canvas.FileId != "" && ch.ID == CanvasChannelID(ch.Properties.Canvas.FileId)
+}
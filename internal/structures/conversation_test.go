@@ -192,3 +192,49 @@ func TestIsMember(t *testing.T) {
 		})
 	}
 }
+
+func TestCanvasChannelID(t *testing.T) {
+	tests := []struct {
+		name   string
+		fileID string
+		want   string
+	}{
+		{"file id", "F06R4HA3ZS8", "C06R4HA3ZS8"},
+		{"empty", "", ""},
+		{"channel id", "C06R4HA3ZS8", ""},
+		{"unknown prefix", "X06R4HA3ZS8", ""},
+		{"prefix only", "F", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanvasChannelID(tt.fileID); got != tt.want {
+				t.Errorf("CanvasChannelID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsCanvas(t *testing.T) {
+	withCanvas := func(id, fileID string) slack.Channel {
+		ch := *ChannelFromID(id)
+		ch.Properties = &slack.Properties{Canvas: slack.Canvas{FileId: fileID}}
+		return ch
+	}
+	tests := []struct {
+		name string
+		ch   slack.Channel
+		want bool
+	}{
+		{"canvas channel", withCanvas("C06R4HA3ZS8", "F06R4HA3ZS8"), true},
+		{"channel with canvas", withCanvas("C012345", "F06R4HA3ZS8"), false},
+		{"no properties", *ChannelFromID("C06R4HA3ZS8"), false},
+		{"invalid file id", withCanvas("C06R4HA3ZS8", "X06R4HA3ZS8"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCanvas(tt.ch); got != tt.want {
+				t.Errorf("IsCanvas() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ package structures
 
 import (
 	"errors"
+	"slices"
 
 	"github.com/rusq/slack"
 )
@@ -29,3 +30,20 @@ func IsSlackResponseError(e error, s string) bool {
 	var se slack.SlackErrorResponse
 	return errors.As(e, &se) && se.Err == s
 }
+
+// authErrCodes are the Slack API error codes meaning that the token is no
+// longer accepted, and the user has to authenticate again.
+var authErrCodes = []string{
+	"invalid_auth",
+	"not_authed",
+	"token_expired",
+	"token_revoked",
+	"account_inactive",
+}
+
+// IsAuthError returns true if e is a [slack.SlackErrorResponse] signalling
+// that the token has expired, was revoked, or is otherwise invalid.
+func IsAuthError(e error) bool {
+	var se slack.SlackErrorResponse
+	return errors.As(e, &se) && slices.Contains(authErrCodes, se.Err)
+}
@@ -16,6 +16,7 @@
 package structures
 
 import (
+	"fmt"
 	"io"
 	"testing"
 
@@ -87,3 +88,25 @@ func TestIsSlackResponseError(t *testing.T) {
 		})
 	}
 }
+
+func TestIsAuthError(t *testing.T) {
+	tests := []struct {
+		name string
+		e    error
+		want bool
+	}{
+		{"invalid_auth", slack.SlackErrorResponse{Err: "invalid_auth"}, true},
+		{"token_revoked", slack.SlackErrorResponse{Err: "token_revoked"}, true},
+		{"wrapped", fmt.Errorf("history: %w", slack.SlackErrorResponse{Err: "token_expired"}), true},
+		{"other slack error", slack.SlackErrorResponse{Err: "channel_not_found"}, false},
+		{"different error", io.EOF, false},
+		{"nil error", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAuthError(tt.e); got != tt.want {
+				t.Errorf("IsAuthError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,9 @@ package files
 
 import (
 	"net/url"
+	"path"
+	"slices"
+	"strings"
 
 	"github.com/rusq/slack"
 )
@@ -66,6 +69,26 @@ func fileThumbLinks(f *slack.File) []*string {
 	}
 }
 
+// PreviewThumbs returns the URLs of the thumbnails of the file f, that are
+// suitable for the image preview, in the order of preference.  For GIF
+// images, it is the animated thumbnail only.
+func PreviewThumbs(f *slack.File) []string {
+	thumbs := []string{f.Thumb480, f.Thumb720, f.Thumb360, f.Thumb960, f.Thumb1024}
+	if strings.EqualFold(f.Mimetype, "image/gif") {
+		// keep the animation.
+		thumbs = []string{f.Thumb360Gif}
+	}
+	return slices.DeleteFunc(thumbs, func(s string) bool { return s == "" })
+}
+
+// ThumbName returns the file name of the thumbnail with the URL uri.
+func ThumbName(uri string) string {
+	if u, err := url.Parse(uri); err == nil {
+		return path.Base(u.Path)
+	}
+	return path.Base(uri)
+}
+
 // UpdateAllLinks calls fn with pointer to each file URL except permalinks.
 // fn can modify the string pointed by ptrS.
 func UpdateAllLinks(f *slack.File, fn func(ptrS *string) error) error {
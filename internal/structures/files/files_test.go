@@ -211,3 +211,50 @@ const fileTokenPlaceholder = `{
 	  "private": null
 	}
   }`
+
+func TestPreviewThumbs(t *testing.T) {
+	tests := []struct {
+		name string
+		f    *slack.File
+		want []string
+	}{
+		{
+			"preferred size first",
+			&slack.File{Mimetype: "image/png", Thumb360: "t360", Thumb480: "t480", Thumb1024: "t1024"},
+			[]string{"t480", "t360", "t1024"},
+		},
+		{
+			"animated gif",
+			&slack.File{Mimetype: "image/gif", Thumb360Gif: "t360gif", Thumb480: "t480"},
+			[]string{"t360gif"},
+		},
+		{
+			"no thumbnails",
+			&slack.File{Mimetype: "application/pdf"},
+			[]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PreviewThumbs(tt.f); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PreviewThumbs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThumbName(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"https://files.slack.com/files-tmb/T1-F1-x/photo_480.jpg", "photo_480.jpg"},
+		{"https://files.slack.com/files-tmb/T1-F1-x/photo_480.jpg?t=xoxe-1", "photo_480.jpg"},
+		{"photo_480.jpg", "photo_480.jpg"},
+	}
+	for _, tt := range tests {
+		if got := ThumbName(tt.uri); got != tt.want {
+			t.Errorf("ThumbName(%q) = %q, want %q", tt.uri, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package structures
+
+import (
+	"strings"
+
+	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/internal/primitive"
+)
+
+// NameResolver resolves the human-readable names of the conversations.  DMs
+// and group DMs are named after their participants, other than the current
+// user.  If none of the participants can be found in the user index, the
+// alias of the conversation is used, if there is one.
+type NameResolver struct {
+	users   UserIndex
+	me      string            // current user ID
+	aliases map[string]string // conversation ID -> alias
+}
+
+// NewNameResolver returns the resolver that uses the user index idx.  me is
+// the ID of the current user, and aliases maps the conversation IDs to their
+// aliases, both are optional.
+func NewNameResolver(idx UserIndex, me string, aliases map[string]string) *NameResolver {
+	return &NameResolver{users: idx, me: me, aliases: aliases}
+}
+
+// Participants returns the usernames of the DM or group DM participants,
+// other than the current user, or nil for other conversation types.  The
+// participants are taken from the conversation members, which, for the
+// database archives, are the recorded channel users, and, if there are
+// none, from the group DM name.  The users that are not in the index are
+// returned as IDs.
+func (r *NameResolver) Participants(ch slack.Channel) []string {
+	names, _ := r.participants(ch)
+	return names
+}
+
+// participants returns the participant names and the number of names that
+// were resolved.
+func (r *NameResolver) participants(ch slack.Channel) ([]string, int) {
+	var ids []string
+	switch ChannelType(ch) {
+	case CIM:
+		if ch.User != "" {
+			ids = []string{ch.User}
+		} else {
+			ids = ch.Members
+		}
+	case CMPIM:
+		ids = ch.Members
+		if len(ids) == 0 {
+			names := r.others(mpimNames(ch.Name), r.myName())
+			return names, len(names)
+		}
+	default:
+		return nil, 0
+	}
+	var (
+		names = make([]string, 0, len(ids))
+		known int
+	)
+	for _, id := range r.others(ids, r.me) {
+		if u, ok := r.users[id]; ok && u.Name != "" {
+			names = append(names, u.Name)
+			known++
+		} else {
+			names = append(names, id)
+		}
+	}
+	return names, known
+}
+
+// others returns the elements of ss, except me, unless me is the only one,
+// as in the DM with oneself.
+func (*NameResolver) others(ss []string, me string) []string {
+	if me == "" {
+		return ss
+	}
+	var res []string
+	for _, s := range ss {
+		if s != me {
+			res = append(res, s)
+		}
+	}
+	if len(res) == 0 {
+		return ss
+	}
+	return res
+}
+
+// myName returns the username of the current user.
+func (r *NameResolver) myName() string {
+	if u, ok := r.users[r.me]; ok {
+		return u.Name
+	}
+	return ""
+}
+
+// mpimNames returns the usernames from the group DM name, i.e.
+// "mpdm-alice--bob--carol-1".
+func mpimNames(name string) []string {
+	name, ok := strings.CutPrefix(name, "mpdm-")
+	if !ok {
+		return nil
+	}
+	if i := strings.LastIndexByte(name, '-'); i > 0 {
+		name = name[:i]
+	}
+	var names []string
+	for n := range strings.SplitSeq(name, "--") {
+		if n != "" {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// Name returns the human-readable name of the conversation without the
+// prefix:  the channel name for channels, and the comma-separated list of
+// the participants for DMs and group DMs.
+func (r *NameResolver) Name(ch slack.Channel) string {
+	if t := ChannelType(ch); IsCanvas(ch) || (t != CIM && t != CMPIM) {
+		return NVL(ch.NameNormalized, ch.Name, r.aliases[ch.ID], ch.ID)
+	}
+	names, known := r.participants(ch)
+	if known > 0 {
+		return strings.Join(names, ", ")
+	}
+	return NVL(r.aliases[ch.ID], strings.Join(names, ", "), ch.Name, ch.ID)
+}
+
+// ChannelName returns the "beautified" name of the conversation, with the
+// type prefix and the archived mark.
+func (r *NameResolver) ChannelName(ch slack.Channel) string {
+	return ChannelPrefix(ch) + r.Name(ch) + primitive.IfTrue(ch.IsArchived, " (archived)", "")
+}
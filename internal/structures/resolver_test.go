@@ -0,0 +1,132 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package structures
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/rusq/slack"
+)
+
+func TestNameResolver_Name(t *testing.T) {
+	idx := NewUserIndex([]slack.User{
+		{ID: "UME", Name: "me"},
+		{ID: "U1", Name: "alice"},
+		{ID: "U2", Name: "bob"},
+	})
+	im := func(id, user string) slack.Channel {
+		return slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: id, IsIM: true, User: user}}}
+	}
+	mpim := func(id, name string, members ...string) slack.Channel {
+		return slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: id, IsMpIM: true}, Name: name, Members: members}}
+	}
+	tests := []struct {
+		name    string
+		aliases map[string]string
+		ch      slack.Channel
+		want    string
+	}{
+		{
+			name: "channel",
+			ch:   slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C1"}, Name: "general"}},
+			want: "general",
+		},
+		{
+			name: "dm",
+			ch:   im("D1", "U1"),
+			want: "alice",
+		},
+		{
+			name: "dm with oneself",
+			ch:   im("D1", "UME"),
+			want: "me",
+		},
+		{
+			name: "dm without the user, from members",
+			ch: slack.Channel{GroupConversation: slack.GroupConversation{
+				Conversation: slack.Conversation{ID: "D1", IsIM: true},
+				Members:      []string{"UME", "U2"},
+			}},
+			want: "bob",
+		},
+		{
+			name:    "dm resolved ignores the alias",
+			aliases: map[string]string{"D1": "boss"},
+			ch:      im("D1", "U1"),
+			want:    "alice",
+		},
+		{
+			name:    "dm with unknown user falls back to the alias",
+			aliases: map[string]string{"D1": "boss"},
+			ch:      im("D1", "U9"),
+			want:    "boss",
+		},
+		{
+			name: "dm with unknown user and no alias",
+			ch:   im("D1", "U9"),
+			want: "U9",
+		},
+		{
+			name: "dm without users",
+			ch:   im("D1", ""),
+			want: "D1",
+		},
+		{
+			name: "group dm from members",
+			ch:   mpim("G1", "mpdm-me--alice--bob-1", "UME", "U1", "U2"),
+			want: "alice, bob",
+		},
+		{
+			name: "group dm from name",
+			ch:   mpim("G1", "mpdm-me--alice--bob-1"),
+			want: "alice, bob",
+		},
+		{
+			name:    "group dm with unknown members falls back to the alias",
+			aliases: map[string]string{"G1": "team"},
+			ch:      mpim("G1", "", "U8", "U9"),
+			want:    "team",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewNameResolver(idx, "UME", tt.aliases)
+			if got := r.Name(tt.ch); got != tt.want {
+				t.Errorf("NameResolver.Name() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameResolver_ChannelName(t *testing.T) {
+	r := NewNameResolver(NewUserIndex([]slack.User{{ID: "U1", Name: "alice"}}), "", nil)
+	ch := slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "D1", IsIM: true, User: "U1"}, IsArchived: true}}
+	if got, want := r.ChannelName(ch), "@alice (archived)"; got != want {
+		t.Errorf("NameResolver.ChannelName() = %q, want %q", got, want)
+	}
+}
+
+func TestNameResolver_Participants(t *testing.T) {
+	r := NewNameResolver(nil, "", nil)
+	ch := slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "G1", IsMpIM: true}, Name: "mpdm-alice--bob-jr--carol-2"}}
+	if got, want := r.Participants(ch), []string{"alice", "bob-jr", "carol"}; !slices.Equal(got, want) {
+		t.Errorf("NameResolver.Participants() = %v, want %v", got, want)
+	}
+	if got := r.Participants(slack.Channel{GroupConversation: slack.GroupConversation{Name: "general"}}); got != nil {
+		t.Errorf("NameResolver.Participants() = %v, want nil", got)
+	}
+}
@@ -16,10 +16,7 @@
 package structures
 
 import (
-	"strings"
-
 	"github.com/rusq/slack"
-	"github.com/rusq/slackdump/v4/internal/primitive"
 )
 
 // UserIndex is a mapping of user ID to the *slack.User.
@@ -120,19 +117,8 @@ func (idx UserIndex) IsDeleted(id string) bool {
 	return thisUser.Deleted
 }
 
-// ChannelName return the "beautified" name of the channel.
-func (idx UserIndex) ChannelName(ch slack.Channel) (who string) {
-	t := ChannelType(ch)
-
-	switch t {
-	case CIM:
-		who = "@" + idx.Username(ch.User)
-	case CMPIM:
-		who = strings.ReplaceAll(ch.Purpose.Value, " messaging with", "")
-	case CPrivate:
-		who = "🔒 " + NVL(ch.NameNormalized, ch.Name)
-	default:
-		who = "#" + NVL(ch.NameNormalized, ch.Name)
-	}
-	return who + primitive.IfTrue(ch.IsArchived, " (archived)", "")
+// ChannelName return the "beautified" name of the channel, see
+// [NameResolver.ChannelName].
+func (idx UserIndex) ChannelName(ch slack.Channel) string {
+	return NewNameResolver(idx, "", nil).ChannelName(ch)
 }
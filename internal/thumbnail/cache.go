@@ -0,0 +1,154 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package thumbnail
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// maxConcurrent is the number of previews generated at the same time, as
+// each may take hundreds of megabytes of memory, see maxPixels.
+const maxConcurrent = 2
+
+// Cache generates the previews and keeps them in the cache directory, so
+// that they are generated once.  It is safe for concurrent use.
+type Cache struct {
+	dir  string
+	size int
+	sem  chan struct{} // limits the concurrent generation
+}
+
+// NewCache returns the new Cache that keeps the previews of the size in the
+// directory dir.  If dir is empty, the previews are not cached, and are
+// generated on each request.
+func NewCache(dir string, size int) *Cache {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &Cache{dir: dir, size: size, sem: make(chan struct{}, maxConcurrent)}
+}
+
+// Get returns the preview of the image name in fsys, and its content type.
+// The scope identifies the source of the file, i.e. the archive name, so that
+// the previews of the different archives do not collide.  The preview is
+// regenerated, if the image was modified.
+func (c *Cache) Get(scope string, fsys fs.FS, name string) ([]byte, string, error) {
+	fi, err := fs.Stat(fsys, name)
+	if err != nil {
+		return nil, "", err
+	}
+	key := c.key(scope, name, fi)
+	if data, ctype, ok := c.load(key); ok {
+		return data, ctype, nil
+	}
+	c.sem <- struct{}{}
+	defer func() { <-c.sem }()
+	// the preview could be generated while waiting.
+	if data, ctype, ok := c.load(key); ok {
+		return data, ctype, nil
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+	rs, ok := f.(io.ReadSeeker)
+	if !ok {
+		// i.e. the file in the ZIP archive.
+		b, err := io.ReadAll(f)
+		if err != nil {
+			return nil, "", err
+		}
+		rs = bytes.NewReader(b)
+	}
+	var buf bytes.Buffer
+	ctype, err := Generate(&buf, rs, c.size)
+	if err != nil {
+		return nil, "", err
+	}
+	// the preview is usable even if it can not be cached, it will be
+	// generated again next time.
+	_ = c.store(key, ctype, buf.Bytes())
+	return buf.Bytes(), ctype, nil
+}
+
+// key returns the cache key of the preview of the file.
+func (c *Cache) key(scope, name string, fi fs.FileInfo) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%d\x00%d\x00%d", scope, name, fi.Size(), fi.ModTime().UnixNano(), c.size)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+var extTypes = []struct{ ext, ctype string }{
+	{".jpg", TypeJPEG},
+	{".png", TypePNG},
+}
+
+func (c *Cache) path(key, ext string) string {
+	return filepath.Join(c.dir, key[:2], key+ext)
+}
+
+// load returns the cached preview.
+func (c *Cache) load(key string) ([]byte, string, bool) {
+	if c.dir == "" {
+		return nil, "", false
+	}
+	for _, et := range extTypes {
+		if data, err := os.ReadFile(c.path(key, et.ext)); err == nil {
+			return data, et.ctype, true
+		}
+	}
+	return nil, "", false
+}
+
+// store saves the preview to the cache.  The preview is written to the
+// temporary file first, so that the partial previews are never served.
+func (c *Cache) store(key, ctype string, data []byte) error {
+	if c.dir == "" {
+		return nil
+	}
+	ext := ""
+	for _, et := range extTypes {
+		if et.ctype == ctype {
+			ext = et.ext
+		}
+	}
+	pth := c.path(key, ext)
+	if err := os.MkdirAll(filepath.Dir(pth), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(pth), ".thumb-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), pth)
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package thumbnail generates the downscaled previews of the images, and
+// caches them on disk.  Only the image formats supported by the standard
+// library are handled, so that no external dependencies are required.
+package thumbnail
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+)
+
+const (
+	// DefaultSize is the default size of the longest side of the preview.
+	DefaultSize = 480
+	// maxPixels is the limit of the image size, larger images are not
+	// decoded to protect from decompression bombs.  The decoded image and
+	// its RGBA copy take up to 8 bytes per pixel, 256 MiB at the limit.
+	maxPixels = 32 << 20
+	// jpegQuality is the quality of the JPEG previews.
+	jpegQuality = 85
+)
+
+// Content types of the previews.
+const (
+	TypeJPEG = "image/jpeg"
+	TypePNG  = "image/png"
+)
+
+var (
+	// ErrUnsupported is returned if the image format is not supported.
+	ErrUnsupported = errors.New("unsupported image format")
+	// ErrTooLarge is returned if the image dimensions exceed the limit.
+	ErrTooLarge = errors.New("image is too large")
+)
+
+// Supported returns true if the preview can be generated for the file with
+// the mime type mimetype.  Animated GIFs are not supported, as the preview
+// would lose the animation.
+func Supported(mimetype string) bool {
+	switch strings.ToLower(mimetype) {
+	case "image/jpeg", "image/jpg", "image/pjpeg", "image/png":
+		return true
+	}
+	return false
+}
+
+// Generate writes the preview of the image read from r to w, and returns its
+// content type.  The image is downscaled to fit into the size×size square,
+// keeping the aspect ratio, smaller images are not enlarged.  Opaque images
+// are encoded as JPEG, the ones with transparency as PNG.
+func Generate(w io.Writer, r io.ReadSeeker, size int) (string, error) {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return "", ErrUnsupported
+		}
+		return "", err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return "", ErrUnsupported
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > maxPixels {
+		return "", fmt.Errorf("%w: %dx%d", ErrTooLarge, cfg.Width, cfg.Height)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return "", err
+	}
+	dst := downscale(src, size)
+	if dst.Opaque() {
+		return TypeJPEG, jpeg.Encode(w, dst, &jpeg.Options{Quality: jpegQuality})
+	}
+	return TypePNG, png.Encode(w, dst)
+}
+
+// fit returns the dimensions of the w×h rectangle, scaled down to fit into
+// the size×size square.
+func fit(w, h, size int) (int, int) {
+	if w <= size && h <= size {
+		return w, h
+	}
+	if w >= h {
+		return size, max(1, h*size/w)
+	}
+	return max(1, w*size/h), size
+}
+
+// downscale returns the image src, scaled down with the box filter to fit
+// into the size×size square.
+func downscale(src image.Image, size int) *image.RGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	// convert to RGBA first, the standard library has fast paths for the
+	// common image types.
+	rgba, ok := src.(*image.RGBA)
+	if !ok || b.Min != (image.Point{}) {
+		rgba = image.NewRGBA(image.Rect(0, 0, sw, sh))
+		draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	}
+
+	dw, dh := fit(sw, sh, size)
+	if dw == sw && dh == sh {
+		return rgba
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for dy := range dh {
+		sy0, sy1 := dy*sh/dh, max((dy+1)*sh/dh, dy*sh/dh+1)
+		for dx := range dw {
+			sx0, sx1 := dx*sw/dw, max((dx+1)*sw/dw, dx*sw/dw+1)
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				off := rgba.PixOffset(sx0, sy)
+				for sx := sx0; sx < sx1; sx++ {
+					p := rgba.Pix[off : off+4 : off+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					bl += uint64(p[2])
+					a += uint64(p[3])
+					n++
+					off += 4
+				}
+			}
+			off := dst.PixOffset(dx, dy)
+			dst.Pix[off+0] = uint8(r / n)
+			dst.Pix[off+1] = uint8(g / n)
+			dst.Pix[off+2] = uint8(bl / n)
+			dst.Pix[off+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
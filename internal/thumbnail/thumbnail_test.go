@@ -0,0 +1,203 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func testPNG(t *testing.T, w, h int, alpha uint8) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: alpha})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		size      int
+		wantType  string
+		wantW     int
+		wantH     int
+		wantErrIs error
+	}{
+		{"landscape", testPNG(t, 1000, 500, 255), 480, TypeJPEG, 480, 240, nil},
+		{"portrait", testPNG(t, 300, 600, 255), 100, TypeJPEG, 50, 100, nil},
+		{"transparent", testPNG(t, 600, 600, 100), 480, TypePNG, 480, 480, nil},
+		{"small image is not enlarged", testPNG(t, 40, 20, 255), 480, TypeJPEG, 40, 20, nil},
+		{"thin image", testPNG(t, 2000, 2, 255), 100, TypeJPEG, 100, 1, nil},
+		{"not an image", []byte("%PDF-1.4"), 480, "", 0, 0, ErrUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gotType, err := Generate(&buf, bytes.NewReader(tt.data), tt.size)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("Generate() error = %v, want %v", err, tt.wantErrIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Generate() unexpected error: %v", err)
+			}
+			if gotType != tt.wantType {
+				t.Errorf("Generate() type = %s, want %s", gotType, tt.wantType)
+			}
+			cfg, format, err := image.DecodeConfig(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if "image/"+format != tt.wantType {
+				t.Errorf("preview format = %s, want %s", format, tt.wantType)
+			}
+			if cfg.Width != tt.wantW || cfg.Height != tt.wantH {
+				t.Errorf("preview size = %dx%d, want %dx%d", cfg.Width, cfg.Height, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestSupported(t *testing.T) {
+	for mt, want := range map[string]bool{
+		"image/jpeg":      true,
+		"IMAGE/PNG":       true,
+		"image/gif":       false,
+		"application/pdf": false,
+		"":                false,
+	} {
+		if got := Supported(mt); got != want {
+			t.Errorf("Supported(%q) = %v, want %v", mt, got, want)
+		}
+	}
+}
+
+func TestCache_Get(t *testing.T) {
+	now := time.Now()
+	fsys := fstest.MapFS{
+		"F1/image.png": &fstest.MapFile{Data: testPNG(t, 800, 800, 255), ModTime: now},
+	}
+	dir := t.TempDir()
+	c := NewCache(dir, 100)
+
+	data, ctype, err := c.Get("archive", fsys, "F1/image.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctype != TypeJPEG || len(data) == 0 {
+		t.Fatalf("Get() = %d bytes of %s", len(data), ctype)
+	}
+	cached := cachedFiles(t, dir)
+	if len(cached) != 1 || !strings.HasSuffix(cached[0], ".jpg") {
+		t.Fatalf("cached previews = %v, want one JPEG", cached)
+	}
+
+	// the cached preview is served.
+	if err := os.WriteFile(cached[0], []byte("cached"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if data, _, err := c.Get("archive", fsys, "F1/image.png"); err != nil || string(data) != "cached" {
+		t.Errorf("Get() = %q, %v, want the cached preview", data, err)
+	}
+	// the other archive does not get the cached preview.
+	if data, _, err := c.Get("other", fsys, "F1/image.png"); err != nil || string(data) == "cached" {
+		t.Errorf("Get() = %q, %v, want the new preview", data, err)
+	}
+	// the modified image gets the new preview.
+	fsys["F1/image.png"].ModTime = now.Add(time.Second)
+	if data, _, err := c.Get("archive", fsys, "F1/image.png"); err != nil || string(data) == "cached" {
+		t.Errorf("Get() = %q, %v, want the new preview", data, err)
+	}
+	if _, _, err := c.Get("archive", fsys, "F1/missing.png"); err == nil {
+		t.Error("Get() expected an error for the missing file")
+	}
+}
+
+func TestCache_Get_concurrent(t *testing.T) {
+	fsys := fstest.MapFS{
+		"F1/image.png": &fstest.MapFile{Data: testPNG(t, 800, 800, 255), ModTime: time.Now()},
+	}
+	dir := t.TempDir()
+	c := NewCache(dir, 100)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*maxConcurrent+1)
+	for range cap(errs) {
+		wg.Go(func() {
+			_, _, err := c.Get("archive", fsys, "F1/image.png")
+			errs <- err
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(c.sem) != 0 {
+		t.Errorf("semaphore holds %d slots, want 0", len(c.sem))
+	}
+	if cached := cachedFiles(t, dir); len(cached) != 1 {
+		t.Errorf("cached previews = %v, want one", cached)
+	}
+}
+
+func TestCache_Get_noDir(t *testing.T) {
+	fsys := fstest.MapFS{"image.png": &fstest.MapFile{Data: testPNG(t, 10, 10, 255)}}
+	if _, ctype, err := NewCache("", 0).Get("archive", fsys, "image.png"); err != nil || ctype != TypeJPEG {
+		t.Errorf("Get() = %s, %v", ctype, err)
+	}
+}
+
+func cachedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var ff []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			ff = append(ff, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ff
+}
@@ -33,6 +33,7 @@ import (
 	st "github.com/rusq/slackdump/v4/internal/structures"
 	"github.com/rusq/slackdump/v4/internal/viewer/renderer"
 	"github.com/rusq/slackdump/v4/source"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 type aliasSourceStub struct {
@@ -46,6 +47,7 @@ type aliasSourceStub struct {
 	files   source.Storage
 	avatars source.Storage
 	wi      *slack.AuthTestResponse
+	members map[string][]types.MembershipEvent
 }
 
 func (s *aliasSourceStub) Name() string {
@@ -102,6 +104,13 @@ func (s *aliasSourceStub) ChannelInfo(_ context.Context, channelID string) (*sla
 	}
 	return &slack.Channel{}, nil
 }
+
+func (s *aliasSourceStub) ChannelMembership(_ context.Context, channelID string) ([]types.MembershipEvent, error) {
+	if ee, ok := s.members[channelID]; ok {
+		return ee, nil
+	}
+	return nil, source.ErrNotFound
+}
 func (s *aliasSourceStub) Files() source.Storage {
 	if s.files != nil {
 		return s.files
@@ -291,6 +300,34 @@ func TestChannelDisplayName(t *testing.T) {
 	}
 }
 
+func TestChannelDisplayName_dm(t *testing.T) {
+	um := st.NewUserIndex([]slack.User{{ID: "UME", Name: "me"}, {ID: "U1", Name: "alice"}})
+	v := &Viewer{
+		um:    um,
+		names: st.NewNameResolver(um, "UME", nil),
+		src:   &aliasSourceStub{aliases: map[string]string{"D2": "boss"}},
+	}
+	mpim := slack.Channel{
+		GroupConversation: slack.GroupConversation{
+			Conversation: slack.Conversation{ID: "G1", IsMpIM: true},
+			Name:         "mpdm-me--alice-1",
+			Members:      []string{"UME", "U1"},
+		},
+	}
+	if got := string(v.channelDisplayName(mpim)); got != "alice" {
+		t.Errorf("channelDisplayName() = %q, want %q", got, "alice")
+	}
+	// the alias is shown on top of the resolved name.
+	im := slack.Channel{
+		GroupConversation: slack.GroupConversation{
+			Conversation: slack.Conversation{ID: "D2", IsIM: true, User: "U1"},
+		},
+	}
+	if got := string(v.channelDisplayName(im)); got != "@<em>boss</em>" {
+		t.Errorf("channelDisplayName() = %q, want %q", got, "@<em>boss</em>")
+	}
+}
+
 func TestAliasPutHandler(t *testing.T) {
 	src := &aliasSourceStub{}
 	v := &Viewer{
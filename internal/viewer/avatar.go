@@ -0,0 +1,75 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package viewer
+
+import (
+	"io/fs"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/rusq/slack"
+
+	st "github.com/rusq/slackdump/v4/internal/structures"
+)
+
+// avatarHistoryStorage is the avatar storage that keeps the avatar history
+// of the users.
+type avatarHistoryStorage interface {
+	// AvatarAt should return the path of the avatar that the user had at the
+	// time t.
+	AvatarAt(userID string, t time.Time) (string, error)
+}
+
+// msgpic returns the userpic of the message sender, as it was at the time of
+// the message.  If the avatar history is not available, it falls back to
+// the current userpic.
+func (v *Viewer) msgpic(m slack.Message) string {
+	if m.User == "" {
+		return v.userpic(m.User)
+	}
+	as, ok := v.src.Avatars().(avatarHistoryStorage)
+	if !ok {
+		return v.userpic(m.User)
+	}
+	t, err := st.ParseSlackTS(m.Timestamp)
+	if err != nil {
+		return v.userpic(m.User)
+	}
+	pth, err := as.AvatarAt(m.User, t)
+	if err != nil {
+		return v.userpic(m.User)
+	}
+	return v.rts.Avatar(path.Dir(pth), path.Base(pth))
+}
+
+// avatarHandler serves the avatars from the avatar storage.  The user_id
+// is either the ID of the user, or the avatar store directory.
+func (v *Viewer) avatarHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("user_id")
+	filename := r.PathValue("filename")
+	if userID == "" || filename == "" || isInvalid(userID) || isInvalid(filename) {
+		http.NotFound(w, r)
+		return
+	}
+	fsys := v.src.Avatars().FS()
+	pth := path.Join(userID, filename)
+	if _, err := fs.Stat(fsys, pth); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFileFS(w, r, fsys, pth)
+}
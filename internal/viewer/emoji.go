@@ -0,0 +1,79 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package viewer
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+
+	"github.com/rusq/slackdump/v4/source"
+	"github.com/rusq/slackdump/v4/types"
+)
+
+// emojiIndex returns the map of custom emoji names to the image URLs, if the
+// source has any.  Aliases are resolved to the image of the original emoji.
+// If the emoji image was downloaded, the URL points to the local copy,
+// otherwise the Slack URL is used.
+func (v *Viewer) emojiIndex(ctx context.Context) map[string]string {
+	es, ok := v.src.(source.Emojier)
+	if !ok {
+		return nil
+	}
+	ee, err := es.Emojis(ctx)
+	if err != nil {
+		if !errors.Is(err, source.ErrNotFound) {
+			v.lg.WarnContext(ctx, "unable to read custom emoji", "error", err)
+		}
+		return nil
+	}
+	idx := types.Emojis(ee).IndexByName()
+	stg := es.EmojiStorage()
+	m := make(map[string]string, len(ee))
+	for name := range idx {
+		em, ok := types.ResolveEmoji(idx, name)
+		if !ok || em.URL == "" {
+			continue
+		}
+		if pth, err := stg.File(source.EmojiParams(em)); err == nil {
+			m[name] = v.rts.Emoji(pth)
+			continue
+		}
+		m[name] = em.URL
+	}
+	return m
+}
+
+// emojiHandler serves the custom emoji images from the emoji storage.
+func (v *Viewer) emojiHandler(w http.ResponseWriter, r *http.Request) {
+	filename := r.PathValue("filename")
+	if filename == "" || isInvalid(filename) {
+		http.NotFound(w, r)
+		return
+	}
+	es, ok := v.src.(source.Emojier)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	fsys := es.EmojiStorage().FS()
+	if _, err := fs.Stat(fsys, filename); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFileFS(w, r, fsys, filename)
+}
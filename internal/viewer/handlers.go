@@ -32,6 +32,7 @@ import (
 	"github.com/rusq/slack"
 
 	"github.com/rusq/slackdump/v4/internal/structures"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 // ── Render* methods ──────────────────────────────────────────────────────────
@@ -98,6 +99,7 @@ func (v *Viewer) RenderUser(ctx context.Context, userID string, w io.Writer) err
 	}
 	page := v.view()
 	page.User = u
+	page.UserHistory = v.userHistory(ctx, userID)
 	return v.tmpl.ExecuteTemplate(w, "index.html", page)
 }
 
@@ -123,6 +125,30 @@ func (v *Viewer) RenderCanvas(ctx context.Context, channelID string, w io.Writer
 	return v.tmpl.ExecuteTemplate(w, "index.html", page)
 }
 
+// RenderMembers renders the full channel membership tab page for channelID to
+// w.
+func (v *Viewer) RenderMembers(ctx context.Context, channelID string, w io.Writer) error {
+	ci, err := v.src.ChannelInfo(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	page := v.view()
+	if err := v.setConversation(&page, ci); err != nil {
+		return err
+	}
+	page.MembersActive = true
+	page.Membership = v.membership(ctx, channelID)
+
+	// fetch messages so the full page renders correctly on deep link.
+	itMsg, err := v.allMessagesOrEmpty(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	page.Messages = itMsg
+
+	return v.tmpl.ExecuteTemplate(w, "index.html", page)
+}
+
 // RenderCanvasContent writes the raw canvas HTML for channelID to w.
 func (v *Viewer) RenderCanvasContent(ctx context.Context, channelID string, w io.Writer) error {
 	ci, err := v.src.ChannelInfo(ctx, channelID)
@@ -239,7 +265,7 @@ func (v *Viewer) userHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	if isHXRequest(r) && v.rts.Interactive() {
-		if err := v.tmpl.ExecuteTemplate(w, "hx_user", userView{User: u, Interactive: true}); err != nil {
+		if err := v.tmpl.ExecuteTemplate(w, "hx_user", userView{User: u, Interactive: true, History: v.userHistory(ctx, uid)}); err != nil {
 			lg.ErrorContext(ctx, "ExecuteTemplate", "error", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
@@ -264,6 +290,18 @@ func (v *Viewer) canvasHandler(w http.ResponseWriter, r *http.Request, id string
 	}
 }
 
+func (v *Viewer) membersHandler(w http.ResponseWriter, r *http.Request, id string) {
+	if isHXRequest(r) {
+		v.membersPartial(w, r, id)
+		return
+	}
+	if err := v.RenderMembers(r.Context(), id, w); err != nil {
+		lg := v.lg.With("in", "membersHandler", "channel", id)
+		lg.ErrorContext(r.Context(), "RenderMembers", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 // canvasContentHandler streams the raw canvas HTML content for the given
 // channel directly, without requiring the caller to know the filename.
 func (v *Viewer) canvasContentHandler(w http.ResponseWriter, r *http.Request, id string) {
@@ -372,6 +410,30 @@ func (v *Viewer) canvasPartial(w http.ResponseWriter, r *http.Request, id string
 	}
 }
 
+func (v *Viewer) membersPartial(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+	lg := v.lg.With("in", "membersPartial", "channel", id)
+
+	ci, err := v.src.ChannelInfo(ctx, id)
+	if err != nil {
+		lg.ErrorContext(ctx, "ChannelInfo", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	page := v.view()
+	if err := v.setConversation(&page, ci); err != nil {
+		lg.ErrorContext(ctx, "setConversation", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	page.MembersActive = true
+	page.Membership = v.membership(ctx, id)
+	if err := v.tmpl.ExecuteTemplate(w, "hx_members", page); err != nil {
+		lg.ErrorContext(ctx, "ExecuteTemplate", "error", err, "template", "hx_members")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 // ── Remaining HTTP-only handlers ─────────────────────────────────────────────
 
 func (v *Viewer) fileHandler(w http.ResponseWriter, r *http.Request) {
@@ -557,11 +619,14 @@ type mainView struct {
 	ThreadID        string
 	Conversation    slack.Channel
 	User            *slack.User
-	Alias           string // conversation alias
+	UserHistory     []types.UserChange // profile changes of the User
+	Alias           string             // conversation alias
 	AliasError      string
-	CanAlias        bool // if true, alias can be set for the channel
-	CanvasActive    bool // true when the canvas tab is the active tab
-	CanvasAvailable bool // true when the canvas file exists in storage
+	CanAlias        bool                    // if true, alias can be set for the channel
+	CanvasActive    bool                    // true when the canvas tab is the active tab
+	CanvasAvailable bool                    // true when the canvas file exists in storage
+	MembersActive   bool                    // true when the members tab is the active tab
+	Membership      []types.MembershipEvent // membership timeline of the Conversation
 }
 
 type aliaser interface {
@@ -596,6 +661,35 @@ const (
 	aliasSet
 )
 
+// userHistory returns the profile changes of the user, if the source keeps
+// the user history.
+func (v *Viewer) userHistory(ctx context.Context, userID string) []types.UserChange {
+	uh, ok := v.src.(source.UserHistorian)
+	if !ok {
+		return nil
+	}
+	changes, err := uh.UserHistory(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, source.ErrNotFound) {
+			v.lg.WarnContext(ctx, "unable to get user history", "user_id", userID, "error", err)
+		}
+		return nil
+	}
+	return changes
+}
+
+// membership returns the membership timeline of the channel.
+func (v *Viewer) membership(ctx context.Context, channelID string) []types.MembershipEvent {
+	timeline, err := v.src.ChannelMembership(ctx, channelID)
+	if err != nil {
+		if !errors.Is(err, source.ErrNotFound) && !errors.Is(err, source.ErrNotSupported) {
+			v.lg.WarnContext(ctx, "unable to get channel membership", "channel_id", channelID, "error", err)
+		}
+		return nil
+	}
+	return timeline
+}
+
 func (v *Viewer) aliaser() (aliaser, bool) {
 	a, ok := v.src.(aliaser)
 	return a, ok
@@ -16,21 +16,31 @@
 package viewer
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"image"
+	"image/png"
 	"io/fs"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"path"
 	"strings"
 	"testing"
 	"testing/fstest"
+	"time"
 
 	"github.com/rusq/slack"
 
+	"github.com/rusq/slackdump/v4/internal/avatar"
+	"github.com/rusq/slackdump/v4/internal/chunk"
 	st "github.com/rusq/slackdump/v4/internal/structures"
+	"github.com/rusq/slackdump/v4/internal/thumbnail"
 	"github.com/rusq/slackdump/v4/internal/viewer/renderer"
 	"github.com/rusq/slackdump/v4/source"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 func Test_isInvalid(t *testing.T) {
@@ -169,6 +179,8 @@ func newHandlerTestViewer(src *aliasSourceStub) *Viewer {
 		lg:  slog.Default(),
 		r:   &renderer.Debug{},
 		rts: renderer.NewRoutes(renderer.ModeLive),
+
+		thumbs: thumbnail.NewCache("", 0),
 	}
 	initTemplates(v)
 	return v
@@ -320,6 +332,56 @@ func TestCanvasHandler_RendersHTMXPartial(t *testing.T) {
 	}
 }
 
+func TestMembersHandler_RendersHTMXPartial(t *testing.T) {
+	src := newViewerRouteSource()
+	src.members = map[string][]types.MembershipEvent{
+		"C1": {
+			{Time: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), UserID: "U1", Action: types.MemberJoined},
+			{Time: time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC), UserID: "U1", Action: types.MemberLeft, Inferred: true},
+		},
+	}
+	v := newHandlerTestViewer(src)
+	req := httptest.NewRequest(http.MethodGet, "/archives/C1/members", nil)
+	req.Header.Set("HX-Request", "true")
+	rr := httptest.NewRecorder()
+
+	v.membersHandler(rr, req, "C1")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("membersHandler() status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	body := rr.Body.String()
+	if strings.Contains(body, "<!DOCTYPE html>") {
+		t.Fatalf("membersHandler() HTMX response should not include full page HTML: %q", body)
+	}
+	if !strings.Contains(body, `id="tab-panel-members"`) || !strings.Contains(body, `aria-selected="true"
+        aria-controls="tab-panel-members"`) {
+		t.Fatalf("membersHandler() HTMX response should include the selected members panel: %q", body)
+	}
+	if strings.Count(body, `href="/team/U1"`) != 2 || !strings.Contains(body, "(inferred)") {
+		t.Fatalf("membersHandler() HTMX response should include the membership timeline: %q", body)
+	}
+}
+
+func TestMembersHandler_RendersFullPageWithoutMembership(t *testing.T) {
+	v := newHandlerTestViewer(newViewerRouteSource())
+	req := httptest.NewRequest(http.MethodGet, "/archives/C1/members", nil)
+	rr := httptest.NewRecorder()
+
+	v.membersHandler(rr, req, "C1")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("membersHandler() status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "<!DOCTYPE html>") || !strings.Contains(body, `id="channel-link-C1"`) {
+		t.Fatalf("membersHandler() should render full page HTML: %q", body)
+	}
+	if !strings.Contains(body, "No membership information") {
+		t.Fatalf("membersHandler() should explain missing membership: %q", body)
+	}
+}
+
 func TestCanvasContentHandler_ServesCanvasHTML(t *testing.T) {
 	v := newHandlerTestViewer(newViewerRouteSource())
 	req := httptest.NewRequest(http.MethodGet, "/archives/C1/canvas/content", nil)
@@ -405,6 +467,143 @@ func TestFileHandler_ReturnsNotFoundForMissingFile(t *testing.T) {
 	}
 }
 
+func TestThumbHandler(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 1000, 800))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	src := newViewerRouteSource()
+	files := src.files.(storageStub)
+	files.fsys.(fstest.MapFS)["F2/photo.png"] = &fstest.MapFile{Data: buf.Bytes()}
+	files.byName["F2/photo.png"] = "F2/photo.png"
+	v := newHandlerTestViewer(src)
+
+	tests := []struct {
+		name         string
+		id, filename string
+		wantCode     int
+		wantType     string
+		wantLocation string
+	}{
+		{"preview", "F2", "photo.png", http.StatusOK, thumbnail.TypeJPEG, ""},
+		{"not an image", "F1", "hello.txt", http.StatusFound, "", "/slackdump/file/F1/hello.txt"},
+		{"missing", "F1", "missing.png", http.StatusNotFound, "", ""},
+		{"invalid", "F1", "../photo.png", http.StatusNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/slackdump/thumb/x/y", nil)
+			req.SetPathValue("id", tt.id)
+			req.SetPathValue("filename", tt.filename)
+			rr := httptest.NewRecorder()
+
+			v.thumbHandler(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("thumbHandler() status = %d, want %d", rr.Code, tt.wantCode)
+			}
+			if tt.wantType != "" {
+				if got := rr.Header().Get("Content-Type"); got != tt.wantType {
+					t.Errorf("thumbHandler() content type = %q, want %q", got, tt.wantType)
+				}
+				cfg, _, err := image.DecodeConfig(rr.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if cfg.Width != thumbnail.DefaultSize {
+					t.Errorf("thumbHandler() preview width = %d, want %d", cfg.Width, thumbnail.DefaultSize)
+				}
+			}
+			if got := rr.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("thumbHandler() location = %q, want %q", got, tt.wantLocation)
+			}
+		})
+	}
+}
+
+func newAvatarHistorySource(t *testing.T) (*aliasSourceStub, string, string) {
+	t.Helper()
+	const (
+		oldURL = "https://avatars.slack-edge.com/2020/old_original.png"
+		newURL = "https://avatars.slack-edge.com/2024/new_original.png"
+	)
+	changed := time.Unix(1710000000, 0).UTC()
+	hist, err := json.Marshal([]avatar.Entry{
+		{Recorded: changed.Add(-24 * time.Hour), URL: oldURL, Path: avatar.StorePath(oldURL)},
+		{Recorded: changed, URL: newURL, Path: avatar.StorePath(newURL)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	avatars, err := source.NewAvatarStorage(fstest.MapFS{
+		path.Join(chunk.AvatarsDir, avatar.HistoryPath("U1")): &fstest.MapFile{Data: hist},
+		path.Join(chunk.AvatarsDir, avatar.StorePath(oldURL)): &fstest.MapFile{Data: []byte("old")},
+		path.Join(chunk.AvatarsDir, avatar.StorePath(newURL)): &fstest.MapFile{Data: []byte("new")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := newViewerRouteSource()
+	src.avatars = avatars
+	return src, avatar.StorePath(oldURL), avatar.StorePath(newURL)
+}
+
+func TestViewer_msgpic(t *testing.T) {
+	src, oldPath, newPath := newAvatarHistorySource(t)
+	v := newHandlerTestViewer(src)
+
+	tests := []struct {
+		name string
+		msg  slack.Message
+		want string
+	}{
+		{"before the change", slack.Message{Msg: slack.Msg{User: "U1", Timestamp: "1709990000.000001"}}, "/avatars/" + oldPath},
+		{"after the change", slack.Message{Msg: slack.Msg{User: "U1", Timestamp: "1710000001.000001"}}, "/avatars/" + newPath},
+		{"no history", slack.Message{Msg: slack.Msg{User: "U2", Timestamp: "1710000001.000001"}}, v.userpic("U2")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := v.msgpic(tt.msg); got != tt.want {
+				t.Errorf("msgpic() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAvatarHandler(t *testing.T) {
+	src, _, newPath := newAvatarHistorySource(t)
+	v := newHandlerTestViewer(src)
+
+	tests := []struct {
+		name             string
+		userID, filename string
+		wantCode         int
+		wantBody         string
+	}{
+		{"stored avatar", path.Dir(newPath), path.Base(newPath), http.StatusOK, "new"},
+		{"missing", "U1", "missing.png", http.StatusNotFound, ""},
+		{"invalid", "..", "history.json", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/avatars/x/y", nil)
+			req.SetPathValue("user_id", tt.userID)
+			req.SetPathValue("filename", tt.filename)
+			rr := httptest.NewRecorder()
+
+			v.avatarHandler(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("avatarHandler() status = %d, want %d", rr.Code, tt.wantCode)
+			}
+			if tt.wantBody != "" && rr.Body.String() != tt.wantBody {
+				t.Errorf("avatarHandler() body = %q, want %q", rr.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
 func TestRenderCanvasContent_MissingCanvasReturnsNotExist(t *testing.T) {
 	v := newHandlerTestViewer(&aliasSourceStub{
 		chs: []slack.Channel{{
@@ -0,0 +1,83 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package viewer
+
+import (
+	"context"
+	"io/fs"
+	"net/http"
+	"path"
+
+	"github.com/rusq/slackdump/v4/source"
+)
+
+// linkIndex returns the map of the link keys to the URLs of the archived
+// copies of the external links, if the source has any.
+func (v *Viewer) linkIndex(ctx context.Context) map[string]string {
+	ls, ok := v.src.(source.Linker)
+	if !ok || ls.LinkStorage().Type() == source.STnone {
+		return nil
+	}
+	fsys := ls.LinkStorage().FS()
+	dd, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		v.lg.WarnContext(ctx, "unable to read archived links", "error", err)
+		return nil
+	}
+	m := make(map[string]string, len(dd))
+	for _, d := range dd {
+		if !d.IsDir() {
+			continue
+		}
+		ff, err := fs.ReadDir(fsys, d.Name())
+		if err != nil {
+			continue
+		}
+		for _, f := range ff {
+			if f.Type().IsRegular() {
+				m[d.Name()] = v.rts.Link(d.Name(), f.Name())
+				break
+			}
+		}
+	}
+	return m
+}
+
+// linkHandler serves the archived copies of the external links from the
+// link storage.
+func (v *Viewer) linkHandler(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	filename := r.PathValue("filename")
+	if key == "" || filename == "" || isInvalid(key) || isInvalid(filename) {
+		http.NotFound(w, r)
+		return
+	}
+	ls, ok := v.src.(source.Linker)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	fsys := ls.LinkStorage().FS()
+	pth := path.Join(key, filename)
+	if _, err := fs.Stat(fsys, pth); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	// the archived page is served as is, it must not run any scripts in the
+	// context of the viewer.
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	http.ServeFileFS(w, r, fsys, pth)
+}
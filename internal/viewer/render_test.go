@@ -187,3 +187,37 @@ func TestRenderCanvas_StaticModePreservesSandbox(t *testing.T) {
 		t.Fatalf("RenderCanvas() static mode should not contain live attributes or scripts, got: %q", body)
 	}
 }
+
+func TestRenderChannel_CanvasesSection(t *testing.T) {
+	src := newViewerRouteSource()
+	src.chs = append(src.chs, slack.Channel{
+		GroupConversation: slack.GroupConversation{
+			Name:         "Roadmap",
+			Conversation: slack.Conversation{ID: "C06R4HA3ZS8"},
+		},
+		Properties: &slack.Properties{Canvas: slack.Canvas{FileId: "F06R4HA3ZS8"}},
+	})
+	v := &Viewer{
+		src: src,
+		ch:  initChannels(src.chs),
+		um:  st.NewUserIndex(src.users),
+		lg:  slog.Default(),
+		r:   &renderer.Debug{},
+		rts: renderer.NewRoutes(renderer.ModeStatic),
+	}
+	initTemplates(v)
+	if len(v.ch.Canvases) != 1 || len(v.ch.Public) != 1 {
+		t.Fatalf("initChannels() canvases = %d, public = %d, want 1 and 1", len(v.ch.Canvases), len(v.ch.Public))
+	}
+	var buf bytes.Buffer
+	if err := v.RenderChannel(context.Background(), "C1", &buf); err != nil {
+		t.Fatalf("RenderChannel() error = %v", err)
+	}
+	body := buf.String()
+	if !strings.Contains(body, "<p>Canvases</p>") {
+		t.Fatalf("RenderChannel() should include the canvases section, got: %q", body)
+	}
+	if !strings.Contains(body, `href="/archives/C06R4HA3ZS8/canvas/index.html"`) {
+		t.Fatalf("RenderChannel() canvas link should point to the canvas page, got: %q", body)
+	}
+}
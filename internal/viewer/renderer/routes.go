@@ -20,6 +20,7 @@ import (
 	"log/slog"
 	"net/url"
 	"path"
+	"slices"
 	"strings"
 
 	"github.com/rusq/slackdump/v4/internal/structures"
@@ -39,6 +40,7 @@ type Routes struct {
 	mode          Mode
 	workspaceHost string
 	liveHost      string
+	chanDir       func(id string) string // channel directory in static mode
 }
 
 type RouteOption func(*Routes)
@@ -60,6 +62,15 @@ func WithLiveHost(host string) RouteOption {
 	}
 }
 
+// WithChannelDir sets the function that returns the directory of the channel
+// pages in the static mode, by default it is the channel ID.  The directory
+// may contain slashes.
+func WithChannelDir(fn func(id string) string) RouteOption {
+	return func(r *Routes) {
+		r.chanDir = fn
+	}
+}
+
 func NewRoutes(mode Mode, opts ...RouteOption) *Routes {
 	r := &Routes{mode: mode}
 	for _, opt := range opts {
@@ -74,7 +85,7 @@ func (r *Routes) Interactive() bool {
 
 func (r *Routes) Channel(id string) string {
 	if r != nil && r.mode == ModeStatic {
-		return routePath("archives", id, "index.html")
+		return routePath(r.staticChannel(id, "index.html")...)
 	}
 	return routePath("archives", id)
 }
@@ -85,7 +96,7 @@ func (r *Routes) ChannelMessage(id, ts string) string {
 
 func (r *Routes) Thread(id, ts string) string {
 	if r != nil && r.mode == ModeStatic {
-		return routePath("archives", id, "threads", ts+".html")
+		return routePath(r.staticChannel(id, "threads", ts+".html")...)
 	}
 	return routePath("archives", id, ts)
 }
@@ -103,18 +114,25 @@ func (r *Routes) User(userID string) string {
 
 func (r *Routes) Canvas(id string) string {
 	if r != nil && r.mode == ModeStatic {
-		return routePath("archives", id, "canvas", "index.html")
+		return routePath(r.staticChannel(id, "canvas", "index.html")...)
 	}
 	return routePath("archives", id, "canvas")
 }
 
 func (r *Routes) CanvasContent(id string) string {
 	if r != nil && r.mode == ModeStatic {
-		return routePath("archives", id, "canvas", "content.html")
+		return routePath(r.staticChannel(id, "canvas", "content.html")...)
 	}
 	return routePath("archives", id, "canvas", "content")
 }
 
+func (r *Routes) Members(id string) string {
+	if r != nil && r.mode == ModeStatic {
+		return routePath(r.staticChannel(id, "members", "index.html")...)
+	}
+	return routePath("archives", id, "members")
+}
+
 func (r *Routes) File(id, filename string) string {
 	if r != nil && r.mode == ModeStatic {
 		return routePath("files", id, source.SanitizeFilename(filename))
@@ -122,6 +140,15 @@ func (r *Routes) File(id, filename string) string {
 	return routePath("slackdump", "file", id, filename)
 }
 
+// Thumb returns the route to the image preview of the file.  The static
+// output has no previews, so it returns the route to the file itself.
+func (r *Routes) Thumb(id, filename string) string {
+	if r != nil && r.mode == ModeStatic {
+		return r.File(id, filename)
+	}
+	return routePath("slackdump", "thumb", id, filename)
+}
+
 func (r *Routes) StaticAsset(name string) string {
 	return routePath("static", name)
 }
@@ -130,6 +157,36 @@ func (r *Routes) Avatar(userID, filename string) string {
 	return routePath("avatars", userID, filename)
 }
 
+func (r *Routes) Emoji(filename string) string {
+	return routePath("emoji", filename)
+}
+
+// Link returns the route to the archived copy of the external link, filename
+// is the name of the file within the link key directory.  In static mode,
+// the file is renamed, see [StaticLinkName].
+func (r *Routes) Link(key, filename string) string {
+	if r.mode == ModeStatic {
+		filename = StaticLinkName(filename)
+	}
+	return routePath("links", key, filename)
+}
+
+// renderedExts are the extensions of the files, that the browser renders as
+// documents, which can run scripts.
+var renderedExts = []string{".htm", ".html", ".shtml", ".svg", ".svgz", ".xht", ".xhtml", ".xml"}
+
+// StaticLinkName returns the name of the archived copy of the link in the
+// static output.  The archived pages are untrusted, and the static output
+// can't sandbox them with the Content-Security-Policy header, as the viewer
+// does, so the documents that can run scripts get the ".txt" extension, and
+// are shown as text.
+func StaticLinkName(filename string) string {
+	if slices.Contains(renderedExts, strings.ToLower(path.Ext(filename))) {
+		return filename + ".txt"
+	}
+	return filename
+}
+
 func (r *Routes) RewriteSlackURL(src string) string {
 	if r == nil || r.workspaceHost == "" {
 		return src
@@ -178,6 +235,16 @@ func (r *Routes) RewriteSlackURL(src string) string {
 	return src
 }
 
+// staticChannel returns the path parts of the channel page in the static
+// mode, rest is appended to the channel directory.
+func (r *Routes) staticChannel(id string, rest ...string) []string {
+	dir := id
+	if r.chanDir != nil {
+		dir = r.chanDir(id)
+	}
+	return append(append([]string{"archives"}, strings.Split(dir, "/")...), rest...)
+}
+
 func routePath(parts ...string) string {
 	escaped := make([]string, 0, len(parts)+1)
 	escaped = append(escaped, "")
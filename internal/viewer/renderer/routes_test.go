@@ -64,6 +64,32 @@ func TestRoutes_Avatar(t *testing.T) {
 	})
 }
 
+func TestRoutes_Emoji(t *testing.T) {
+	for _, mode := range []Mode{ModeLive, ModeStatic} {
+		r := NewRoutes(mode)
+		if got := r.Emoji("partyparrot.gif"); got != "/emoji/partyparrot.gif" {
+			t.Fatalf("Emoji() = %q, want %q", got, "/emoji/partyparrot.gif")
+		}
+	}
+}
+
+func TestRoutes_Link(t *testing.T) {
+	live := NewRoutes(ModeLive)
+	if got := live.Link("abc", "index.html"); got != "/links/abc/index.html" {
+		t.Fatalf("live Link() = %q", got)
+	}
+	static := NewRoutes(ModeStatic)
+	for name, want := range map[string]string{
+		"index.html": "/links/abc/index.html.txt",
+		"image.SVG":  "/links/abc/image.SVG.txt",
+		"report.pdf": "/links/abc/report.pdf",
+	} {
+		if got := static.Link("abc", name); got != want {
+			t.Errorf("static Link(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
 func TestRoutes_StaticPaths(t *testing.T) {
 	routes := NewRoutes(ModeStatic)
 
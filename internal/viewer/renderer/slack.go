@@ -20,14 +20,17 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
+	"html"
 	"html/template"
 	"log"
 	"log/slog"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/rusq/slack"
 
+	"github.com/rusq/slackdump/v4/internal/linkfetch"
 	"github.com/rusq/slackdump/v4/internal/osext"
 	"github.com/rusq/slackdump/v4/internal/viewer/renderer/functions"
 )
@@ -38,6 +41,9 @@ type Slack struct {
 	tmpl   *template.Template
 	uu     map[string]slack.User    // map of user id to user
 	cc     map[string]slack.Channel // map of channel id to channel
+	ee     map[string]string        // map of custom emoji name to image URL
+	ll     map[string]string        // map of link key to the archived copy URL
+	stored func(id, filename string) bool
 	routes *Routes
 }
 
@@ -55,6 +61,31 @@ func WithChannels(cc map[string]slack.Channel) SlackOption {
 	}
 }
 
+// WithEmojis sets the custom emoji index, mapping the emoji name to the URL
+// of its image.
+func WithEmojis(ee map[string]string) SlackOption {
+	return func(sm *Slack) {
+		sm.ee = ee
+	}
+}
+
+// WithLinks sets the archived links index, mapping the link key (see
+// [linkfetch.Key]) to the URL of the archived copy.
+func WithLinks(ll map[string]string) SlackOption {
+	return func(sm *Slack) {
+		sm.ll = ll
+	}
+}
+
+// WithStoredFiles sets the function that reports whether the file with the
+// id and filename is present in the archive.  It is used to find the
+// thumbnails recorded by Slack that were downloaded.
+func WithStoredFiles(fn func(id, filename string) bool) SlackOption {
+	return func(sm *Slack) {
+		sm.stored = fn
+	}
+}
+
 func WithReplaceURL(wspURL, localHost string) SlackOption {
 	return func(sm *Slack) {
 		if sm.routes == nil {
@@ -84,6 +115,9 @@ func NewSlack(tmpl *template.Template, opts ...SlackOption) *Slack {
 		"fileurl": func(id, filename string) string {
 			return s.routes.File(id, filename)
 		},
+		"archivedlink":  s.archivedLink,
+		"thumburl":      s.thumbURL,
+		"attachmenturl": linkfetch.AttachmentURL,
 		"rewriteurl": func(src string) string {
 			if s.routes == nil {
 				return src
@@ -97,15 +131,15 @@ func NewSlack(tmpl *template.Template, opts ...SlackOption) *Slack {
 	return s
 }
 
-func (*Slack) RenderText(ctx context.Context, s string) (v string) {
-	return parseSlackMd(s)
+func (s *Slack) RenderText(ctx context.Context, text string) (v string) {
+	return s.replaceEmoji(parseSlackMd(text))
 }
 
 func (s *Slack) Render(ctx context.Context, m *slack.Message) (v template.HTML) {
 	var buf strings.Builder
 
 	if len(m.Blocks.BlockSet) == 0 {
-		fmt.Fprint(&buf, parseSlackMd(m.Text))
+		fmt.Fprint(&buf, s.replaceEmoji(parseSlackMd(m.Text)))
 	} else {
 		s.renderBlocks(ctx, &buf, m.Timestamp, m.Blocks.BlockSet)
 	}
@@ -161,6 +195,42 @@ func (s *Slack) renderFiles(ctx context.Context, buf *strings.Builder, msgTS str
 	}
 }
 
+// archivedLink returns the URL of the archived copy of the link u, or an
+// empty string, if the link was not archived.
+func (s *Slack) archivedLink(u string) string {
+	if u == "" || len(s.ll) == 0 {
+		return ""
+	}
+	return s.ll[linkfetch.Key(u)]
+}
+
+var reEmojiCode = regexp.MustCompile(`:([a-z0-9_+-]+):`)
+
+// replaceEmoji replaces the custom emoji codes, i.e. ":partyparrot:", in the
+// HTML escaped text with the emoji images.  Unknown codes are left as is.
+func (s *Slack) replaceEmoji(text string) string {
+	if len(s.ee) == 0 {
+		return text
+	}
+	return reEmojiCode.ReplaceAllStringFunc(text, func(code string) string {
+		if img, ok := s.customEmoji(strings.Trim(code, ":")); ok {
+			return img
+		}
+		return code
+	})
+}
+
+// customEmoji returns the image element for the custom emoji name, if it is
+// known.
+func (s *Slack) customEmoji(name string) (string, bool) {
+	src, ok := s.ee[name]
+	if !ok {
+		return "", false
+	}
+	name = html.EscapeString(name)
+	return fmt.Sprintf(`<img class="%s" src="%s" alt=":%s:" title=":%s:">`, rtseTypeClass[slack.RTSEEmoji], html.EscapeString(src), name, name), true
+}
+
 func maybeprint(v any) {
 	if debug {
 		enc := json.NewEncoder(os.Stderr)
@@ -19,8 +19,45 @@ import (
 	"fmt"
 
 	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/internal/structures/files"
+	"github.com/rusq/slackdump/v4/internal/thumbnail"
 )
 
+// thumbURL returns the URL of the image preview of the file f.  It prefers
+// the thumbnail recorded by Slack, if it was downloaded, then the generated
+// preview, if the image format is supported, and falls back to the file
+// itself.
+func (s *Slack) thumbURL(f slack.File) string {
+	if !s.routes.Interactive() {
+		// static output contains only the files themselves.
+		return s.routes.File(f.ID, f.Name)
+	}
+	if name := s.storedThumb(f); name != "" {
+		return s.routes.File(f.ID, name)
+	}
+	if thumbnail.Supported(f.Mimetype) {
+		return s.routes.Thumb(f.ID, f.Name)
+	}
+	return s.routes.File(f.ID, f.Name)
+}
+
+// storedThumb returns the file name of the downloaded Slack thumbnail of the
+// file f, that is closest to the preview size, or an empty string, if none
+// were downloaded.
+func (s *Slack) storedThumb(f slack.File) string {
+	if s.stored == nil {
+		return ""
+	}
+	for _, u := range files.PreviewThumbs(&f) {
+		name := files.ThumbName(u)
+		if s.stored(f.ID, name) {
+			return name
+		}
+	}
+	return ""
+}
+
 func (s *Slack) mbtImage(ib slack.Block) (string, string, error) {
 	b, ok := ib.(*slack.ImageBlock)
 	if !ok {
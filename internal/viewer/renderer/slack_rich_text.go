@@ -222,7 +222,9 @@ func (s *Slack) rtseEmoji(ie slack.RichTextSectionElement) (string, string, erro
 	if !ok {
 		return "", "", NewErrIncorrectType(&slack.RichTextSectionEmojiElement{}, ie)
 	}
-	// TODO: resolve and render emoji.
+	if img, ok := s.customEmoji(e.Name); ok {
+		return img, "", nil
+	}
 	em := emj.Parse(fmt.Sprintf(":%s:", e.Name))
 	return applyStyle(em, e.Style), "", nil
 }
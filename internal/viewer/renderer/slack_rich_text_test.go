@@ -441,3 +441,45 @@ func TestMbtRichText(t *testing.T) {
 		}
 	})
 }
+
+func TestSlack_rtseEmoji(t *testing.T) {
+	ee := map[string]string{
+		"partyparrot": "/emoji/partyparrot.gif",
+	}
+	tests := []struct {
+		name string
+		s    *Slack
+		ie   slack.RichTextSectionElement
+		want string
+	}{
+		{
+			name: "standard emoji",
+			s:    &Slack{ee: ee},
+			ie:   slack.NewRichTextSectionEmojiElement("smile", 0, nil),
+			want: "😄",
+		},
+		{
+			name: "custom emoji",
+			s:    &Slack{ee: ee},
+			ie:   slack.NewRichTextSectionEmojiElement("partyparrot", 0, nil),
+			want: `<img class="slack-rich-text-section-emoji" src="/emoji/partyparrot.gif" alt=":partyparrot:" title=":partyparrot:">`,
+		},
+		{
+			name: "unknown custom emoji without index",
+			s:    &Slack{},
+			ie:   slack.NewRichTextSectionEmojiElement("partyparrot", 0, nil),
+			want: ":partyparrot:",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := tt.s.rtseEmoji(tt.ie)
+			if err != nil {
+				t.Fatalf("rtseEmoji() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("rtseEmoji() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
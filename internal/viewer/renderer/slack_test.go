@@ -27,6 +27,7 @@ import (
 	"github.com/rusq/slack"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/rusq/slackdump/v4/internal/linkfetch"
 	"github.com/rusq/slackdump/v4/internal/viewer/renderer/functions"
 )
 
@@ -34,7 +35,10 @@ var tmpl = template.Must(template.New("blocks").Funcs(functions.FuncMap).Funcs(t
 	"fileurl": func(id, filename string) string {
 		return NewRoutes(ModeLive).File(id, filename)
 	},
-	"rewriteurl": func(src string) string { return src },
+	"rewriteurl":    func(src string) string { return src },
+	"archivedlink":  func(string) string { return "" },
+	"thumburl":      func(f slack.File) string { return NewRoutes(ModeLive).File(f.ID, f.Name) },
+	"attachmenturl": linkfetch.AttachmentURL,
 }).ParseFS(templates, "templates/*.html"))
 
 func TestSlack_Render(t *testing.T) {
@@ -124,6 +128,81 @@ func TestSlack_Render_SanitizesStaticFileRoutes(t *testing.T) {
 	}
 }
 
+func TestSlack_Render_ArchivedLinks(t *testing.T) {
+	const (
+		doc  = "https://docs.google.com/document/d/1"
+		page = "https://example.com/article"
+	)
+	rts := NewRoutes(ModeStatic)
+	sm := NewSlack(template.Must(template.New("base").Parse("")), WithRoutes(rts), WithLinks(map[string]string{
+		linkfetch.Key(doc):  rts.Link(linkfetch.Key(doc), "report.pdf"),
+		linkfetch.Key(page): rts.Link(linkfetch.Key(page), "index.html"),
+	}))
+	got := string(sm.Render(t.Context(), &slack.Message{Msg: slack.Msg{
+		Files:       []slack.File{{ID: "F123", Name: "Report", Mode: "external", URLPrivate: doc}},
+		Attachments: []slack.Attachment{{FromURL: page, ImageURL: "https://example.com/image.png"}},
+	}}))
+	for _, want := range []string{
+		doc,
+		"/links/" + linkfetch.Key(doc) + "/report.pdf",
+		"/links/" + linkfetch.Key(page) + "/index.html",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() should contain %q, got %q", want, got)
+		}
+	}
+
+	sm = NewSlack(template.Must(template.New("base").Parse("")), WithRoutes(rts))
+	if got := string(sm.Render(t.Context(), &slack.Message{Msg: slack.Msg{
+		Attachments: []slack.Attachment{{FromURL: page, ImageURL: "https://example.com/image.png"}},
+	}})); strings.Contains(got, "archived-link") {
+		t.Errorf("Render() should not link to the missing archived copy, got %q", got)
+	}
+}
+
+func TestSlack_thumbURL(t *testing.T) {
+	stored := func(id, filename string) bool { return id == "F1" && filename == "photo_480.jpg" }
+	tests := []struct {
+		name string
+		mode Mode
+		f    slack.File
+		want string
+	}{
+		{
+			"downloaded slack thumbnail",
+			ModeLive,
+			slack.File{ID: "F1", Name: "photo.jpg", Mimetype: "image/jpeg", Thumb360: "https://files.slack.com/files-tmb/T1-F1-x/photo_360.jpg", Thumb480: "https://files.slack.com/files-tmb/T1-F1-x/photo_480.jpg"},
+			"/slackdump/file/F1/photo_480.jpg",
+		},
+		{
+			"generated preview",
+			ModeLive,
+			slack.File{ID: "F2", Name: "photo.jpg", Mimetype: "image/jpeg", Thumb480: "https://files.slack.com/files-tmb/T1-F2-x/photo_480.jpg"},
+			"/slackdump/thumb/F2/photo.jpg",
+		},
+		{
+			"unsupported format",
+			ModeLive,
+			slack.File{ID: "F3", Name: "anim.gif", Mimetype: "image/gif"},
+			"/slackdump/file/F3/anim.gif",
+		},
+		{
+			"static output",
+			ModeStatic,
+			slack.File{ID: "F1", Name: "photo.jpg", Mimetype: "image/jpeg", Thumb480: "https://files.slack.com/files-tmb/T1-F1-x/photo_480.jpg"},
+			"/files/F1/photo.jpg",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSlack(template.Must(template.New("base").Parse("")), WithRoutes(NewRoutes(tt.mode)), WithStoredFiles(stored))
+			if got := s.thumbURL(tt.f); got != tt.want {
+				t.Errorf("thumbURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func ungzip(t *testing.T, b []byte) string {
 	t.Helper()
 	gr, err := gzip.NewReader(bytes.NewReader(b))
@@ -168,3 +247,28 @@ func TestSlack_renderAttachment(t *testing.T) {
 		})
 	}
 }
+
+func TestSlack_replaceEmoji(t *testing.T) {
+	s := &Slack{ee: map[string]string{"partyparrot": "/emoji/partyparrot.gif"}}
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "custom emoji",
+			text: "hello :partyparrot:!",
+			want: `hello <img class="slack-rich-text-section-emoji" src="/emoji/partyparrot.gif" alt=":partyparrot:" title=":partyparrot:">!`,
+		},
+		{
+			name: "unknown emoji is left as is",
+			text: "hello :smile: at 10:30:00",
+			want: "hello :smile: at 10:30:00",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, s.replaceEmoji(tt.text))
+		})
+	}
+}
@@ -27,6 +27,7 @@ import (
 
 	st "github.com/rusq/slackdump/v4/internal/structures"
 	"github.com/rusq/slackdump/v4/source"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 //go:embed templates
@@ -44,6 +45,7 @@ func initTemplates(v *Viewer) {
 			"profileurl":       v.profileURL,
 			"canvasurl":        v.rts.Canvas,
 			"canvascontenturl": v.rts.CanvasContent,
+			"membersurl":       v.rts.Members,
 			"staticasset":      v.rts.StaticAsset,
 			"chlink": func(ch slack.Channel, interactive bool) channelLinkView {
 				return channelLinkView{Channel: ch, Interactive: interactive}
@@ -51,12 +53,16 @@ func initTemplates(v *Viewer) {
 			"userview": func(user *slack.User, interactive bool) userView {
 				return userView{User: user, Interactive: interactive}
 			},
+			"userhistoryview": func(user *slack.User, interactive bool, history []types.UserChange) userView {
+				return userView{User: user, Interactive: interactive, History: history}
+			},
 			"staticuserview":  v.staticUserView,
 			"is_app_msg":      isAppMsg,
 			"is_user_msg":     isUserMsg,
 			"displayname":     v.um.DisplayName,
 			"username":        v.username, // username returns the username for the message
 			"userpic":         v.userpic,  // userpic returns the userpic for the user
+			"msgpic":          v.msgpic,   // msgpic returns the userpic of the message sender at the time of the message
 			"time":            localtime,
 			"rendertext":      func(s string) string { return v.r.RenderText(context.Background(), s) },            // render message text
 			"render":          func(m slack.Message) template.HTML { return v.r.Render(context.Background(), &m) }, // render message
@@ -80,10 +86,15 @@ type userView struct {
 	Interactive bool
 	TargetID    string
 	CloseHref   string
+	History     []types.UserChange // profile changes, if available
 }
 
 func (v *Viewer) channelDisplayName(ch slack.Channel) template.HTML {
-	name := v.um.ChannelName(ch)
+	names := v.names
+	if names == nil {
+		names = st.NewNameResolver(v.um, "", nil)
+	}
+	name := names.ChannelName(ch)
 	alias, ok, err := v.alias(ch.ID)
 	if err != nil || !ok || alias == "" {
 		return template.HTML(template.HTMLEscapeString(name))
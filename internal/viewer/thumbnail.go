@@ -0,0 +1,65 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package viewer
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"strconv"
+)
+
+// isStored returns true if the file with id and filename is present in the
+// file storage of the source.
+func (v *Viewer) isStored(id, filename string) bool {
+	_, err := v.src.Files().File(id, filename)
+	return err == nil
+}
+
+// thumbHandler serves the preview of the image file.  If the preview can not
+// be generated, i.e. the image is corrupt or too large, it redirects to the
+// file itself.
+func (v *Viewer) thumbHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		id       = r.PathValue("id")
+		filename = r.PathValue("filename")
+		ctx      = r.Context()
+	)
+	if id == "" || filename == "" || isInvalid(filename) || isInvalid(id) {
+		http.NotFound(w, r)
+		return
+	}
+	lg := v.lg.With("in", "thumbHandler", "id", id, "filename", filename)
+	pth, err := v.src.Files().File(id, filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			http.NotFound(w, r)
+			return
+		}
+		lg.ErrorContext(ctx, "File", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, ctype, err := v.thumbs.Get(v.src.Name(), v.src.Files().FS(), pth)
+	if err != nil {
+		lg.DebugContext(ctx, "unable to generate the preview", "error", err)
+		http.Redirect(w, r, v.rts.File(id, filename), http.StatusFound)
+		return
+	}
+	w.Header().Set("Content-Type", ctype)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/rusq/slack"
 
 	st "github.com/rusq/slackdump/v4/internal/structures"
+	"github.com/rusq/slackdump/v4/internal/thumbnail"
 	"github.com/rusq/slackdump/v4/internal/viewer/renderer"
 	"github.com/rusq/slackdump/v4/source"
 )
@@ -65,6 +66,10 @@ type Viewer struct {
 	tmpl *template.Template
 	mode renderer.Mode
 	rts  *renderer.Routes
+	// thumbs generates and caches the image previews.
+	thumbs *thumbnail.Cache
+	// names resolves the DM and group DM names.
+	names *st.NameResolver
 
 	// handles
 	srv *http.Server
@@ -75,7 +80,9 @@ type Viewer struct {
 type Option func(*viewerOptions)
 
 type viewerOptions struct {
-	mode renderer.Mode
+	mode     renderer.Mode
+	thumbDir string
+	chanDir  func(id string) string
 }
 
 func WithMode(mode renderer.Mode) Option {
@@ -84,6 +91,22 @@ func WithMode(mode renderer.Mode) Option {
 	}
 }
 
+// WithThumbnailCache sets the directory where the generated image previews
+// are cached.  If not set, the previews are generated on each request.
+func WithThumbnailCache(dir string) Option {
+	return func(o *viewerOptions) {
+		o.thumbDir = dir
+	}
+}
+
+// WithChannelDir sets the function that returns the directory of the channel
+// pages in the static mode, see [renderer.WithChannelDir].
+func WithChannelDir(fn func(id string) string) Option {
+	return func(o *viewerOptions) {
+		o.chanDir = fn
+	}
+}
+
 const (
 	hour = 60 * time.Minute
 )
@@ -121,13 +144,23 @@ func New(ctx context.Context, addr string, r source.Sourcer, opts ...Option) (*V
 		um:   um,
 		lg:   slog.Default(),
 		mode: options.mode,
+
+		thumbs: thumbnail.NewCache(options.thumbDir, thumbnail.DefaultSize),
 	}
 	rtOpts := []renderer.RouteOption{}
 	if addr != "" {
 		rtOpts = append(rtOpts, renderer.WithLiveHost(normalise(addr)))
 	}
+	var me string
 	if wi, err := r.WorkspaceInfo(ctx); err == nil {
 		rtOpts = append(rtOpts, renderer.WithWorkspaceURL(wi.URL))
+		me = wi.UserID
+	}
+	// aliases are not given to the resolver, as they are applied on top of
+	// the name, and may change while the viewer is running.
+	v.names = st.NewNameResolver(um, me, nil)
+	if options.chanDir != nil {
+		rtOpts = append(rtOpts, renderer.WithChannelDir(options.chanDir))
 	}
 	v.rts = renderer.NewRoutes(options.mode, rtOpts...)
 	// postinit
@@ -138,6 +171,9 @@ func New(ctx context.Context, addr string, r source.Sourcer, opts ...Option) (*V
 			renderer.WithUsers(indexusers(uu)),
 			renderer.WithChannels(indexchannels(all)),
 			renderer.WithRoutes(v.rts),
+			renderer.WithEmojis(v.emojiIndex(ctx)),
+			renderer.WithLinks(v.linkIndex(ctx)),
+			renderer.WithStoredFiles(v.isStored),
 		}
 		v.r = renderer.NewSlack(
 			template.New("viewer-renderer"),
@@ -154,6 +190,7 @@ func New(ctx context.Context, addr string, r source.Sourcer, opts ...Option) (*V
 	mux.HandleFunc("GET /archives/{id}", v.newFileHandler(v.channelHandler))
 	mux.HandleFunc("GET /archives/{id}/canvas", v.newFileHandler(v.canvasHandler))
 	mux.HandleFunc("GET /archives/{id}/canvas/content", v.newFileHandler(v.canvasContentHandler))
+	mux.HandleFunc("GET /archives/{id}/members", v.newFileHandler(v.membersHandler))
 	// https: //ora600.slack.com/archives/DHMAB25DY/p1710063528879959
 	// https://ora600.slack.com/archives/CHY5HUESG/p1738580940349469?thread_ts=1737716342.919259&cid=CHY5HUESG
 	mux.HandleFunc("GET /archives/{id}/alias/", v.aliasHandler)
@@ -162,6 +199,10 @@ func New(ctx context.Context, addr string, r source.Sourcer, opts ...Option) (*V
 	mux.HandleFunc("GET /archives/{id}/{ts}", v.newFileHandler(v.postRedirectHandler))
 	mux.HandleFunc("GET /team/{user_id}", v.userHandler)
 	mux.Handle("GET /slackdump/file/{id}/{filename}", cacheMwareFunc(3*hour)(http.HandlerFunc(v.fileHandler)))
+	mux.Handle("GET /slackdump/thumb/{id}/{filename}", cacheMwareFunc(3*hour)(http.HandlerFunc(v.thumbHandler)))
+	mux.Handle("GET /avatars/{user_id}/{filename}", cacheMwareFunc(3*hour)(http.HandlerFunc(v.avatarHandler)))
+	mux.Handle("GET /emoji/{filename}", cacheMwareFunc(3*hour)(http.HandlerFunc(v.emojiHandler)))
+	mux.Handle("GET /links/{key}/{filename}", cacheMwareFunc(3*hour)(http.HandlerFunc(v.linkHandler)))
 	v.srv = &http.Server{
 		Addr:    addr,
 		Handler: middleware.Logger(mux),
@@ -213,10 +254,11 @@ func indexchannels(cc []slack.Channel) map[string]slack.Channel {
 }
 
 type channels struct {
-	Public  []slack.Channel
-	Private []slack.Channel
-	MPIM    []slack.Channel
-	DM      []slack.Channel
+	Public   []slack.Channel
+	Private  []slack.Channel
+	MPIM     []slack.Channel
+	DM       []slack.Channel
+	Canvases []slack.Channel // standalone canvas channels
 }
 
 func (c channels) find(id string) (slack.Channel, bool) {
@@ -225,6 +267,7 @@ func (c channels) find(id string) (slack.Channel, bool) {
 		c.Private,
 		c.MPIM,
 		c.DM,
+		c.Canvases,
 	} {
 		for _, ch := range chset {
 			if ch.ID == id {
@@ -238,6 +281,10 @@ func (c channels) find(id string) (slack.Channel, bool) {
 func initChannels(c []slack.Channel) channels {
 	var cc channels
 	for _, ch := range c {
+		if st.IsCanvas(ch) {
+			cc.Canvases = append(cc.Canvases, ch)
+			continue
+		}
 		t := st.ChannelType(ch)
 		switch t {
 		case st.CIM:
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/rusq/slackdump/v4/processor (interfaces: Conversations,Users,Channels,ChannelInformer,Filer,WorkspaceInfo,MessageSearcher,FileSearcher,Searcher,Avatars)
+// Source: github.com/rusq/slackdump/v4/processor (interfaces: Conversations,Users,Channels,ChannelInformer,Filer,WorkspaceInfo,MessageSearcher,FileSearcher,Searcher,Avatars,Emojis,EmojiDownloader)
 //
 // Generated by this command:
 //
-//	mockgen -destination ../mocks/mock_processor/mock_processor.go github.com/rusq/slackdump/v4/processor Conversations,Users,Channels,ChannelInformer,Filer,WorkspaceInfo,MessageSearcher,FileSearcher,Searcher,Avatars
+//	mockgen -destination ../mocks/mock_processor/mock_processor.go github.com/rusq/slackdump/v4/processor Conversations,Users,Channels,ChannelInformer,Filer,WorkspaceInfo,MessageSearcher,FileSearcher,Searcher,Avatars,Emojis,EmojiDownloader
 //
 
 // Package mock_processor is a generated GoMock package.
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	slack "github.com/rusq/slack"
+	types "github.com/rusq/slackdump/v4/types"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -634,3 +635,93 @@ func (mr *MockAvatarsMockRecorder) Users(ctx, users any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Users", reflect.TypeOf((*MockAvatars)(nil).Users), ctx, users)
 }
+
+// MockEmojis is a mock of Emojis interface.
+type MockEmojis struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmojisMockRecorder
+	isgomock struct{}
+}
+
+// MockEmojisMockRecorder is the mock recorder for MockEmojis.
+type MockEmojisMockRecorder struct {
+	mock *MockEmojis
+}
+
+// NewMockEmojis creates a new mock instance.
+func NewMockEmojis(ctrl *gomock.Controller) *MockEmojis {
+	mock := &MockEmojis{ctrl: ctrl}
+	mock.recorder = &MockEmojisMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmojis) EXPECT() *MockEmojisMockRecorder {
+	return m.recorder
+}
+
+// Emojis mocks base method.
+func (m *MockEmojis) Emojis(ctx context.Context, emojis []types.Emoji) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Emojis", ctx, emojis)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Emojis indicates an expected call of Emojis.
+func (mr *MockEmojisMockRecorder) Emojis(ctx, emojis any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Emojis", reflect.TypeOf((*MockEmojis)(nil).Emojis), ctx, emojis)
+}
+
+// MockEmojiDownloader is a mock of EmojiDownloader interface.
+type MockEmojiDownloader struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmojiDownloaderMockRecorder
+	isgomock struct{}
+}
+
+// MockEmojiDownloaderMockRecorder is the mock recorder for MockEmojiDownloader.
+type MockEmojiDownloaderMockRecorder struct {
+	mock *MockEmojiDownloader
+}
+
+// NewMockEmojiDownloader creates a new mock instance.
+func NewMockEmojiDownloader(ctrl *gomock.Controller) *MockEmojiDownloader {
+	mock := &MockEmojiDownloader{ctrl: ctrl}
+	mock.recorder = &MockEmojiDownloaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmojiDownloader) EXPECT() *MockEmojiDownloaderMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockEmojiDownloader) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockEmojiDownloaderMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockEmojiDownloader)(nil).Close))
+}
+
+// Emojis mocks base method.
+func (m *MockEmojiDownloader) Emojis(ctx context.Context, emojis []types.Emoji) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Emojis", ctx, emojis)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Emojis indicates an expected call of Emojis.
+func (mr *MockEmojiDownloaderMockRecorder) Emojis(ctx, emojis any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Emojis", reflect.TypeOf((*MockEmojiDownloader)(nil).Emojis), ctx, emojis)
+}
@@ -19,6 +19,8 @@ import (
 	"context"
 
 	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/types"
 )
 
 type NopFiler struct{}
@@ -33,6 +35,11 @@ type NopAvatars struct{}
 func (n *NopAvatars) Users(ctx context.Context, users []slack.User) error { return nil }
 func (n *NopAvatars) Close() error                                        { return nil }
 
+type NopEmojis struct{}
+
+func (n *NopEmojis) Emojis(ctx context.Context, emojis []types.Emoji) error { return nil }
+func (n *NopEmojis) Close() error                                           { return nil }
+
 type NopChannels struct{}
 
 func (NopChannels) Channels(ctx context.Context, ch []slack.Channel) error {
@@ -21,11 +21,13 @@ import (
 	"io"
 
 	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/types"
 )
 
 // Conversations is the interface for conversation fetching with files.
 //
-//go:generate mockgen -destination ../mocks/mock_processor/mock_processor.go github.com/rusq/slackdump/v4/processor Conversations,Users,Channels,ChannelInformer,Filer,WorkspaceInfo,MessageSearcher,FileSearcher,Searcher,Avatars
+//go:generate mockgen -destination ../mocks/mock_processor/mock_processor.go github.com/rusq/slackdump/v4/processor Conversations,Users,Channels,ChannelInformer,Filer,WorkspaceInfo,MessageSearcher,FileSearcher,Searcher,Avatars,Emojis,EmojiDownloader
 type Conversations interface {
 	Messenger
 	Filer
@@ -99,6 +101,25 @@ type Avatars interface {
 	io.Closer
 }
 
+// Emojis is the interface for the custom emoji processor.
+type Emojis interface {
+	// Emojis is called for each chunk of custom emoji that is retrieved.
+	Emojis(ctx context.Context, emojis []types.Emoji) error
+}
+
+// EmojiDownloader is the interface for downloading emoji images.
+type EmojiDownloader interface {
+	Emojis
+	io.Closer
+}
+
+// LinkFetcher is the interface for archiving the external links found in
+// messages.
+type LinkFetcher interface {
+	Messenger
+	io.Closer
+}
+
 // JointChannels is a processor that joins multiple Channels processors into
 // one.
 type JointChannels struct {
@@ -147,6 +168,29 @@ func (u *JointUsers) Close() error {
 	return closeall(u.pp)
 }
 
+// JointEmojis is a processor that joins multiple Emojis processors.
+type JointEmojis struct {
+	pp []Emojis
+}
+
+// JoinEmojis joins multiple Emojis processors into one.
+func JoinEmojis(procs ...Emojis) *JointEmojis {
+	return &JointEmojis{pp: procs}
+}
+
+func (e *JointEmojis) Emojis(ctx context.Context, emojis []types.Emoji) error {
+	for _, p := range e.pp {
+		if err := p.Emojis(ctx, emojis); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *JointEmojis) Close() error {
+	return closeall(e.pp)
+}
+
 type JointMessengers struct {
 	pp []Messenger
 }
@@ -115,6 +115,15 @@ func WithSlackClient(cl client.Slack) Option {
 	}
 }
 
+// WithClientOptions sets the additional options for the Slack client, that is
+// initialised by the session.  It has no effect, if the client is set with
+// WithSlackClient.
+func WithClientOptions(opts ...client.Option) Option {
+	return func(s *Session) {
+		s.cfg.clientOpts = append(s.cfg.clientOpts, opts...)
+	}
+}
+
 func WithForceEnterprise(b bool) Option {
 	return func(s *Session) {
 		s.cfg.forceEnterprise = b
@@ -174,7 +183,8 @@ func (s *Session) initWorkspaceInfo(ctx context.Context, cl client.Slack) error
 // WithClient option, it will not override it.
 func (s *Session) initClient(ctx context.Context, prov auth.Provider, forceEdge bool) error {
 	if s.client == nil {
-		cl, err := client.New(ctx, prov, client.WithEnterprise(forceEdge))
+		opts := append([]client.Option{client.WithEnterprise(forceEdge)}, s.cfg.clientOpts...)
+		cl, err := client.New(ctx, prov, opts...)
 		if err != nil {
 			return err
 		}
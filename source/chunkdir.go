@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"io/fs"
 	"iter"
-	"os"
 	"path/filepath"
 	"time"
 
@@ -29,6 +28,7 @@ import (
 
 	"github.com/rusq/slackdump/v4/internal/chunk"
 	"github.com/rusq/slackdump/v4/internal/structures"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 // ChunkDir is the chunk directory source.
@@ -40,22 +40,24 @@ type ChunkDir struct {
 	fast    bool
 	files   Storage
 	avatars Storage
+	emojis  Storage
+	links   Storage
+	// cleanup is called on Close, it removes the local copy of the remote
+	// chunk directory.
+	cleanup func() error
 }
 
+var (
+	_ Emojier = (*ChunkDir)(nil)
+	_ Linker  = (*ChunkDir)(nil)
+)
+
 // OpenChunkDir creates a new ChurkDir source.  It expects the attachments to be
 // in the mattermost storage format.  If the attachments are not in the
 // mattermost storage format, it will assume they were not downloaded.
 func OpenChunkDir(d *chunk.Directory, fast bool) *ChunkDir {
-	rootFS := os.DirFS(d.Name())
-	var stFile Storage = NoStorage{}
-	if fst, err := OpenMattermostStorage(rootFS); err == nil {
-		stFile = fst
-	}
-	var stAvatars Storage = NoStorage{}
-	if ast, err := NewAvatarStorage(rootFS); err == nil {
-		stAvatars = ast
-	}
-	return &ChunkDir{d: d, files: stFile, avatars: stAvatars, fast: fast}
+	stFile, stAvatars, stEmojis, stLinks := openStorages(d.FS(), d.Name(), "")
+	return &ChunkDir{d: d, files: stFile, avatars: stAvatars, emojis: stEmojis, links: stLinks, fast: fast}
 }
 
 // AllMessages returns all messages for the channel.  Current restriction -
@@ -139,6 +141,12 @@ func (c *ChunkDir) ChannelInfo(_ context.Context, channelID string) (*slack.Chan
 	return nil, lastErr
 }
 
+// ChannelMembership returns the membership timeline of the channel, derived
+// from the join and leave messages.
+func (c *ChunkDir) ChannelMembership(ctx context.Context, channelID string) ([]types.MembershipEvent, error) {
+	return messageMembership(ctx, c, channelID)
+}
+
 func (c *ChunkDir) channelInfo(fileID chunk.FileID) (*slack.Channel, error) {
 	f, err := c.d.Open(fileID)
 	if err != nil {
@@ -173,7 +181,11 @@ func (c *ChunkDir) Users(context.Context) ([]slack.User, error) {
 }
 
 func (c *ChunkDir) Close() error {
-	return c.d.Close()
+	err := c.d.Close()
+	if c.cleanup != nil {
+		err = errors.Join(err, c.cleanup())
+	}
+	return err
 }
 
 var ErrUnknownLinkType = errors.New("unknown link type")
@@ -208,6 +220,33 @@ func (c *ChunkDir) Avatars() Storage {
 	return c.avatars
 }
 
+func (c *ChunkDir) EmojiStorage() Storage {
+	return c.emojis
+}
+
+func (c *ChunkDir) LinkStorage() Storage {
+	if c.links == nil {
+		return NoStorage{}
+	}
+	return c.links
+}
+
+// Emojis returns the custom emoji recorded in the chunk directory.  It
+// returns [ErrNotFound] if the emoji were not recorded.
+func (c *ChunkDir) Emojis(context.Context) ([]types.Emoji, error) {
+	ee, err := c.d.Emojis()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, chunk.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if len(ee) == 0 {
+		return nil, ErrNotFound
+	}
+	return ee, nil
+}
+
 func (c *ChunkDir) Sorted(ctx context.Context, id string, desc bool, cb func(ts time.Time, msg *slack.Message) error) error {
 	if err := c.d.Sorted(ctx, id, desc, cb); err != nil {
 		if errors.Is(err, chunk.ErrNoData) || errors.Is(err, chunk.ErrNotFound) {
@@ -19,12 +19,16 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
 	"github.com/rusq/slack"
 
 	"github.com/rusq/slackdump/v4/internal/chunk/backend/dbase"
+	"github.com/rusq/slackdump/v4/internal/seal"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 // DefaultDBFile is the default name for the sqlite database.
@@ -38,10 +42,22 @@ type Database struct {
 	name    string
 	files   Storage
 	avatars Storage
+	emojis  Storage
+	links   Storage
+	sealed  bool
 	*dbase.Source
+	// cleanup is called on Close, it removes the database extracted from
+	// the ZIP file or downloaded from S3.
+	cleanup func() error
 }
 
-var _ Sourcer = (*Database)(nil)
+var (
+	_ Sourcer       = (*Database)(nil)
+	_ Emojier       = (*Database)(nil)
+	_ Linker        = (*Database)(nil)
+	_ UserHistorian = (*Database)(nil)
+	_ Sealer        = (*Database)(nil)
+)
 
 // dbOpenParams holds the resolved paths and storages for opening a database.
 type dbOpenParams struct {
@@ -49,33 +65,65 @@ type dbOpenParams struct {
 	name    string
 	files   Storage
 	avatars Storage
+	emojis  Storage
+	links   Storage
+	// secret is set if the database is sealed, such database is decrypted
+	// as it is read, see [openSealed].
+	secret *seal.Secret
 }
 
 // resolveDBPath resolves the database file, name, and optional storages for
 // the given path, which may be either a direct database file or a directory.
-func resolveDBPath(path string) (dbOpenParams, error) {
-	p := dbOpenParams{files: NoStorage{}, avatars: NoStorage{}}
+// If the database is sealed (see package seal), the secret is taken from the
+// context, and the storages decrypt files transparently.  Only database
+// archives can be sealed, chunk directories are not supported.
+func resolveDBPath(ctx context.Context, path string) (dbOpenParams, error) {
+	p := dbOpenParams{files: NoStorage{}, avatars: NoStorage{}, emojis: NoStorage{}, links: NoStorage{}}
 	fi, err := os.Stat(path)
 	if err != nil {
 		return p, err
 	}
+	dbfile := path
+	if fi.IsDir() {
+		dbfile = filepath.Join(path, DefaultDBFile)
+	}
+	if seal.IsPending(filepath.Dir(dbfile)) {
+		return p, fmt.Errorf("%s: %w", path, seal.ErrPending)
+	}
+	if sealed, err := seal.IsSealedFile(dbfile); err == nil && sealed {
+		if p.secret, err = seal.SecretFromContext(ctx); err != nil {
+			return p, err
+		}
+	}
+	p.dbfile = dbfile
 	if !fi.IsDir() {
-		p.dbfile = path
 		p.name = filepath.Dir(path)
 	} else {
-		p.dbfile = filepath.Join(path, DefaultDBFile)
-		rootFS := os.DirFS(path)
-		if st, err := OpenMattermostStorage(rootFS); err == nil {
-			p.files = st
-		}
-		if st, err := NewAvatarStorage(os.DirFS(path)); err == nil {
-			p.avatars = st
+		var rootFS fs.FS = os.DirFS(path)
+		if p.secret != nil {
+			rootFS = seal.FS(rootFS, p.secret)
 		}
+		p.files, p.avatars, p.emojis, p.links = openStorages(rootFS, path, fileStoreFromContext(ctx))
 		p.name = path
 	}
 	return p, nil
 }
 
+// openSealed opens the sealed database file read-only.  The pages are
+// decrypted as SQLite reads them, so that the plaintext is never written to
+// disk.
+func openSealed(ctx context.Context, dbfile string, secret *seal.Secret) (*dbase.Source, error) {
+	return openFSFn(ctx, seal.FS(os.DirFS(filepath.Dir(dbfile)), secret), filepath.Base(dbfile))
+}
+
+// open opens the database read-only.
+func (p dbOpenParams) open(ctx context.Context) (*dbase.Source, error) {
+	if p.secret != nil {
+		return openSealed(ctx, p.dbfile, p.secret)
+	}
+	return openFn(ctx, p.dbfile)
+}
+
 // OpenDatabase attempts to open the database at given path for reading.
 // It supports both types - when database file is given directly, and when
 // the path is a directory containing the "slackdump.sqlite" file.  In the
@@ -83,18 +131,23 @@ func resolveDBPath(path string) (dbOpenParams, error) {
 // no storage is found, it will return a special [NoStorage] type, which
 // returns [fs.ErrNotExist] for all file operations.
 //
+// The path may also be a ZIP file with the database, see [openZipDatabase].
+//
 // The returned [Database] does not support alias editing.  Use
 // [OpenDatabaseRW] when alias write capability is needed (e.g. the viewer).
 func OpenDatabase(ctx context.Context, path string) (*Database, error) {
-	p, err := resolveDBPath(path)
+	if isZip(path) {
+		return openZipDatabase(ctx, path)
+	}
+	p, err := resolveDBPath(ctx, path)
 	if err != nil {
 		return nil, err
 	}
-	s, err := dbase.Open(ctx, p.dbfile)
+	s, err := p.open(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return &Database{name: p.name, Source: s, files: p.files, avatars: p.avatars}, nil
+	return &Database{name: p.name, Source: s, files: p.files, avatars: p.avatars, emojis: p.emojis, links: p.links, sealed: p.secret != nil}, nil
 }
 
 // RWDatabase is a [Database] that also supports alias write operations.
@@ -108,25 +161,33 @@ type RWDatabase struct {
 func (d *RWDatabase) SetAlias(id, alias string) error { return d.rw.SetAlias(id, alias) }
 func (d *RWDatabase) DeleteAlias(id string) error     { return d.rw.DeleteAlias(id) }
 
-// openRWFn and openFn are the functions used by OpenDatabaseRW to open the
+// openRWFn, openFn and openFSFn are the functions used to open the
 // underlying database.  They are package-level variables so that tests can
 // replace them to simulate failure scenarios without requiring filesystem
 // tricks (e.g. read-only mounts) that would also block the fallback path.
 var (
 	openRWFn = dbase.OpenRW
 	openFn   = dbase.Open
+	openFSFn = dbase.OpenFS
 )
 
 // OpenDatabaseRW attempts to open the database at the given path for reading
 // and writing, returning an [*RWDatabase] that satisfies the viewer Aliaser
 // interface.  If the database file is not writable (e.g. a read-only
 // filesystem or insufficient permissions), it transparently falls back to a
-// read-only [*Database].
+// read-only [*Database].  Sealed databases are always opened read-only.
 func OpenDatabaseRW(ctx context.Context, path string) (SourceResumeCloser, error) {
-	p, err := resolveDBPath(path)
+	p, err := resolveDBPath(ctx, path)
 	if err != nil {
 		return nil, err
 	}
+	if p.secret != nil {
+		s, err := openSealed(ctx, p.dbfile, p.secret)
+		if err != nil {
+			return nil, err
+		}
+		return &Database{name: p.name, Source: s, files: p.files, avatars: p.avatars, emojis: p.emojis, links: p.links, sealed: true}, nil
+	}
 	rw, err := openRWFn(ctx, p.dbfile)
 	if err != nil {
 		// Cannot open rw — fall back to ro (read-only filesystem, missing
@@ -135,18 +196,33 @@ func OpenDatabaseRW(ctx context.Context, path string) (SourceResumeCloser, error
 		if err2 != nil {
 			return nil, err2
 		}
-		return &Database{name: p.name, Source: s, files: p.files, avatars: p.avatars}, nil
+		return &Database{name: p.name, Source: s, files: p.files, avatars: p.avatars, emojis: p.emojis, links: p.links}, nil
 	}
-	db := &Database{name: p.name, Source: rw.Source, files: p.files, avatars: p.avatars}
+	db := &Database{name: p.name, Source: rw.Source, files: p.files, avatars: p.avatars, emojis: p.emojis, links: p.links}
 	return &RWDatabase{Database: db, rw: rw}, nil
 }
 
 // DatabaseWithSource returns a new database source with the given database
-// processor source.  It will not have any files or avatars storage.  In most
+// processor source.  It will not have any files, avatars or emoji storage.  In most
 // cases you should use [OpenDatabase] instead, unless you know what you are
 // doing.
 func DatabaseWithSource(source *dbase.Source) *Database {
-	return &Database{name: "dbase", Source: source, files: NoStorage{}, avatars: NoStorage{}}
+	return &Database{name: "dbase", Source: source, files: NoStorage{}, avatars: NoStorage{}, emojis: NoStorage{}}
+}
+
+// Close closes the database, and removes the extracted or downloaded copy
+// of the database, if any.
+func (d *Database) Close() error {
+	err := d.Source.Close()
+	if d.cleanup != nil {
+		err = errors.Join(err, d.cleanup())
+	}
+	return err
+}
+
+// Sealed returns true if the database is encrypted.
+func (d *Database) Sealed() bool {
+	return d.sealed
 }
 
 func (d *Database) Name() string {
@@ -165,6 +241,61 @@ func (d *Database) Avatars() Storage {
 	return d.avatars
 }
 
+func (d *Database) EmojiStorage() Storage {
+	return d.emojis
+}
+
+func (d *Database) LinkStorage() Storage {
+	if d.links == nil {
+		return NoStorage{}
+	}
+	return d.links
+}
+
+// Emojis returns the custom emoji recorded in the database.  It returns
+// [ErrNotFound] if there are none.
+func (d *Database) Emojis(ctx context.Context) ([]types.Emoji, error) {
+	ee, err := d.Source.Emojis(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(ee) == 0 {
+		return nil, ErrNotFound
+	}
+	return ee, nil
+}
+
+// UserHistory returns the changes of the user profile across the archive
+// sessions.  It returns [ErrNotFound] if the user is not in the archive.
+func (d *Database) UserHistory(ctx context.Context, userID string) ([]types.UserChange, error) {
+	ss, err := d.Source.UserSnapshots(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ss) == 0 {
+		return nil, ErrNotFound
+	}
+	return types.UserChanges(ss), nil
+}
+
+// ChannelMembership returns the membership timeline of the channel, derived
+// from the join and leave messages and the channel members recorded by each
+// session.
+func (d *Database) ChannelMembership(ctx context.Context, channelID string) ([]types.MembershipEvent, error) {
+	ee, err := messageMembership(ctx, d, channelID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	ss, err := d.Source.MemberSnapshots(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ee) == 0 && len(ss) == 0 {
+		return nil, ErrNotFound
+	}
+	return types.MergeMembership(ee, ss), nil
+}
+
 func (d *Database) Channels(ctx context.Context) ([]slack.Channel, error) {
 	chns, err := d.Source.Channels(ctx)
 	if err != nil {
@@ -19,10 +19,12 @@ import (
 	"context"
 	"errors"
 	"io"
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/rusq/slackdump/v4/internal/chunk/backend/dbase"
+	"github.com/rusq/slackdump/v4/internal/seal"
 )
 
 func TestDatabase_Name(t *testing.T) {
@@ -102,7 +104,7 @@ type aliasWriter interface {
 }
 
 func TestOpenDatabaseRW_writable(t *testing.T) {
-	dbpath := filepath.Join(fixturesDir, "source_database.db")
+	dbpath := copyFixture(t, "source_database.db")
 	got, err := OpenDatabaseRW(t.Context(), dbpath)
 	if err != nil {
 		t.Fatalf("OpenDatabaseRW() error = %v", err)
@@ -125,7 +127,7 @@ func TestOpenDatabaseRW_fallback(t *testing.T) {
 		return nil, errors.New("simulated rw open failure")
 	}
 
-	dbpath := filepath.Join(fixturesDir, "source_database.db")
+	dbpath := copyFixture(t, "source_database.db")
 	got, err := OpenDatabaseRW(t.Context(), dbpath)
 	if err != nil {
 		t.Fatalf("OpenDatabaseRW() fallback error = %v", err)
@@ -139,3 +141,56 @@ func TestOpenDatabaseRW_fallback(t *testing.T) {
 		t.Error("OpenDatabaseRW() fallback result unexpectedly implements aliasWriter (Aliaser)")
 	}
 }
+
+func TestOpenDatabaseRW_sealed(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(fixturesDir, "source_database.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	dbpath := filepath.Join(dir, DefaultDBFile)
+	if err := os.WriteFile(dbpath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	secret, err := seal.NewSecret([]byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := seal.SealDir(dir, secret); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenDatabaseRW(t.Context(), dir); !errors.Is(err, seal.ErrNoSecret) {
+		t.Fatalf("OpenDatabaseRW() without secret error = %v, want %v", err, seal.ErrNoSecret)
+	}
+
+	got, err := OpenDatabaseRW(seal.WithSecret(t.Context(), secret), dir)
+	if err != nil {
+		t.Fatalf("OpenDatabaseRW() error = %v", err)
+	}
+	db, ok := got.(*Database)
+	if !ok {
+		t.Fatalf("OpenDatabaseRW() = %T, want read-only *Database", got)
+	}
+	if !db.Sealed() {
+		t.Error("Sealed() = false, want true")
+	}
+	if _, err := db.Channels(t.Context()); err != nil && !errors.Is(err, ErrNotFound) {
+		t.Errorf("Channels() error = %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	// the database is read in place, nothing is written next to it.
+	if ee, _ := os.ReadDir(dir); len(ee) != 1 {
+		t.Errorf("unexpected files in the archive directory: %v", ee)
+	}
+
+	// interrupted resume leaves the marker, such archive is not opened.
+	if err := seal.MarkPending(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenDatabaseRW(seal.WithSecret(t.Context(), secret), dir); !errors.Is(err, seal.ErrPending) {
+		t.Errorf("OpenDatabaseRW() pending error = %v, want %v", err, seal.ErrPending)
+	}
+}
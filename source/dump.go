@@ -26,6 +26,7 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/rusq/slack"
@@ -113,6 +114,10 @@ func (d Dump) Channels(context.Context) ([]slack.Channel, error) {
 			GroupConversation: slack.GroupConversation{
 				Conversation: slack.Conversation{
 					ID: c.ID,
+					// dump files do not have the conversation type, so it is
+					// guessed from the ID and the name.
+					IsIM:   strings.HasPrefix(c.ID, "D"),
+					IsMpIM: strings.HasPrefix(c.Name, "mpdm-"),
 				},
 				Name: structures.NVL(c.Name, c.ID), // dump files do not have channel names for private conversations.
 			},
@@ -253,6 +258,12 @@ func (d Dump) ChannelInfo(_ context.Context, channelID string) (*slack.Channel,
 	return nil, fs.ErrNotExist
 }
 
+// ChannelMembership returns the membership timeline of the channel, derived
+// from the join and leave messages.
+func (d Dump) ChannelMembership(ctx context.Context, channelID string) ([]types.MembershipEvent, error) {
+	return messageMembership(ctx, &d, channelID)
+}
+
 func (d Dump) Close() error {
 	return nil
 }
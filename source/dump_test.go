@@ -123,6 +123,43 @@ func TestDump_Channels(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "guesses DMs and group DMs",
+			fields: fields{
+				fs: fstest.MapFS{
+					"D12345678.json": &fstest.MapFile{
+						Data: []byte(`{"channel_id":"D12345678","name":"alice"}`),
+					},
+					"G12345678.json": &fstest.MapFile{
+						Data: []byte(`{"channel_id":"G12345678","name":"mpdm-alice--bob-1"}`),
+					},
+				},
+			},
+			args: args{
+				in0: t.Context(),
+			},
+			want: []slack.Channel{
+				{
+					GroupConversation: slack.GroupConversation{
+						Conversation: slack.Conversation{
+							ID:   "D12345678",
+							IsIM: true,
+						},
+						Name: "alice",
+					},
+				},
+				{
+					GroupConversation: slack.GroupConversation{
+						Conversation: slack.Conversation{
+							ID:     "G12345678",
+							IsMpIM: true,
+						},
+						Name: "mpdm-alice--bob-1",
+					},
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -25,6 +25,7 @@ import (
 	"log/slog"
 	"path"
 	"runtime/trace"
+	"slices"
 	"time"
 
 	"github.com/rusq/slack"
@@ -32,6 +33,7 @@ import (
 	"github.com/rusq/slackdump/v4/export"
 	"github.com/rusq/slackdump/v4/internal/chunk"
 	"github.com/rusq/slackdump/v4/internal/structures"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 // Export implements viewer.Sourcer for the zip file Slack export format.
@@ -43,9 +45,21 @@ type Export struct {
 	idx       structures.ExportIndex
 	files     Storage
 	avatars   Storage
+	emojis    Storage
 	cache     *threadCache
 }
 
+var _ Emojier = (*Export)(nil)
+
+// ExportEmojiFile is the name of the file in the root of the export, that
+// contains the custom emoji, if they were recorded.
+const ExportEmojiFile = "emoji.json"
+
+// ExportDirsFile is the name of the file in the root of the export, that maps
+// the channel IDs to the channel directory names, if the directories are not
+// named after the channels.
+const ExportDirsFile = "__dirs.json"
+
 const cacheSz = 1 << 20
 
 // OpenExport opens a Slack export with the given name from the filesystem
@@ -64,14 +78,22 @@ func OpenExport(fsys fs.FS, name string) (*Export, error) {
 		chanNames: make(map[string]string, len(chans)),
 		files:     NoStorage{},
 		avatars:   NoStorage{},
+		emojis:    NoStorage{},
 		cache:     newThreadCache(cacheSz),
 	}
 	// initialise channels for quick lookup
 	for _, ch := range z.channels {
 		z.chanNames[ch.ID] = structures.NVL(ch.Name, ch.ID)
 	}
+	if dirs, err := unmarshalOne[map[string]string](fsys, ExportDirsFile); err == nil {
+		for id, dir := range dirs {
+			if _, ok := z.chanNames[id]; ok {
+				z.chanNames[id] = dir
+			}
+		}
+	}
 	// determine files path
-	fst, err := loadStorage(fsys)
+	fst, err := loadStorage(fsys, name)
 	if err != nil {
 		return nil, err
 	}
@@ -79,13 +101,19 @@ func OpenExport(fsys fs.FS, name string) (*Export, error) {
 	if fst, err := NewAvatarStorage(fsys); err == nil {
 		z.avatars = fst
 	}
+	if est, err := NewEmojiStorage(fsys); err == nil {
+		z.emojis = est
+	}
 
 	return z, nil
 }
 
 // loadStorage determines the type of the file storage used and initialises
-// appropriate Storage implementation.
-func loadStorage(fsys fs.FS) (Storage, error) {
+// appropriate Storage implementation.  name is the path of the export.
+func loadStorage(fsys fs.FS, name string) (Storage, error) {
+	if st, err := OpenCASStorage(fsys, name, ""); err == nil {
+		return st, nil
+	}
 	if _, err := fs.Stat(fsys, chunk.UploadsDir); err == nil {
 		return OpenMattermostStorage(fsys)
 	}
@@ -237,8 +265,12 @@ func (w *fullScanIter) Iter(yield func(slack.Message, error) bool) {
 }
 
 // walkDir walks through the directory with given name on the filesystem fsys,
-// calling the callback function cb for every JSON file it encounters.
+// calling the callback function cb for every JSON file it encounters.  Files
+// are visited in chronological order of their periods, whatever the
+// [ExportPeriod] of the export is, files with unrecognised names are visited
+// last.
 func walkDir(fsys fs.FS, dirName string, cb func(file string) error) error {
+	var files []string
 	err := fs.WalkDir(fsys, dirName, func(file string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -249,9 +281,39 @@ func walkDir(fsys fs.FS, dirName string, cb func(file string) error) error {
 		if path.Ext(file) != ".json" {
 			return nil
 		}
-		return cb(file)
+		files = append(files, file)
+		return nil
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	slices.SortStableFunc(files, compareExportFiles)
+	for _, file := range files {
+		if err := cb(file); err != nil {
+			if errors.Is(err, fs.SkipAll) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// compareExportFiles compares the message files by the start of their
+// periods.
+func compareExportFiles(a, b string) int {
+	ta, oka := parseExportFilename(a)
+	tb, okb := parseExportFilename(b)
+	switch {
+	case oka && okb:
+		return ta.Compare(tb)
+	case oka:
+		return -1
+	case okb:
+		return 1
+	default:
+		return 0 // keep the lexical order
+	}
 }
 
 // fileListIter is meant to reduce the scope of iteration to the given file
@@ -361,6 +423,12 @@ func (e *Export) ChannelInfo(ctx context.Context, channelID string) (*slack.Chan
 	return nil, fmt.Errorf("%s: %s", "channel not found", channelID)
 }
 
+// ChannelMembership returns the membership timeline of the channel, derived
+// from the join and leave messages.
+func (e *Export) ChannelMembership(ctx context.Context, channelID string) ([]types.MembershipEvent, error) {
+	return messageMembership(ctx, e, channelID)
+}
+
 func (e *Export) Latest(ctx context.Context) (map[structures.SlackLink]time.Time, error) {
 	// there will be no resume on export.
 	return nil, ErrNotSupported
@@ -380,6 +448,30 @@ func (e *Export) Avatars() Storage {
 	return e.avatars
 }
 
+func (e *Export) EmojiStorage() Storage {
+	return e.emojis
+}
+
+// Emojis returns the custom emoji from the emoji file of the export.  It
+// returns [ErrNotFound] if the file does not exist.
+func (e *Export) Emojis(context.Context) ([]types.Emoji, error) {
+	data, err := fs.ReadFile(e.fs, ExportEmojiFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	var ee []types.Emoji
+	if err := json.Unmarshal(data, &ee); err != nil {
+		return nil, fmt.Errorf("%s: %w", ExportEmojiFile, err)
+	}
+	if len(ee) == 0 {
+		return nil, ErrNotFound
+	}
+	return ee, nil
+}
+
 func (e *Export) Sorted(ctx context.Context, channelID string, desc bool, cb func(ts time.Time, msg *slack.Message) error) error {
 	// doesn't matter, this method is used only in export conversion, and as
 	// this is export it should never be called, just like your ex.
@@ -0,0 +1,118 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package source
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportPeriod is the period of messages that are written into a single
+// message file of the channel directory in the Slack export format.
+type ExportPeriod string
+
+const (
+	// PeriodDay is the Slack export default, one file per day, i.e.
+	// "2024-01-31.json".
+	PeriodDay ExportPeriod = "day"
+	// PeriodWeek is one file per ISO week, i.e. "2024-W05.json".
+	PeriodWeek ExportPeriod = "week"
+	// PeriodMonth is one file per month, i.e. "2024-01.json".
+	PeriodMonth ExportPeriod = "month"
+	// PeriodYear is one file per year, i.e. "2024.json".
+	PeriodYear ExportPeriod = "year"
+	// PeriodSingle is one file per channel, see [ExportSingleFile].
+	PeriodSingle ExportPeriod = "single"
+)
+
+// ExportSingleFile is the name of the message file in the channel directory,
+// when all messages of the channel are in a single file.
+const ExportSingleFile = "messages.json"
+
+func (p *ExportPeriod) Set(v string) error {
+	switch ExportPeriod(strings.ToLower(v)) {
+	case PeriodDay, PeriodWeek, PeriodMonth, PeriodYear, PeriodSingle:
+		*p = ExportPeriod(strings.ToLower(v))
+		return nil
+	default:
+		return fmt.Errorf("unknown export period: %s", v)
+	}
+}
+
+func (p ExportPeriod) String() string {
+	if p == "" {
+		return string(PeriodDay)
+	}
+	return string(p)
+}
+
+// Filename returns the name of the message file for the message posted at
+// the time t.  The caller is responsible for converting t to the desired
+// location.
+func (p ExportPeriod) Filename(t time.Time) string {
+	switch p {
+	case PeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d.json", year, week)
+	case PeriodMonth:
+		return t.Format("2006-01") + ".json"
+	case PeriodYear:
+		return t.Format("2006") + ".json"
+	case PeriodSingle:
+		return ExportSingleFile
+	default:
+		return t.Format("2006-01-02") + ".json"
+	}
+}
+
+// parseExportFilename returns the start of the period for the message file
+// with the given name, produced by [ExportPeriod.Filename].  It returns false
+// if the name is not recognised.  The single file sorts before all others.
+func parseExportFilename(name string) (time.Time, bool) {
+	base := path.Base(name)
+	if base == ExportSingleFile {
+		return time.Time{}, true
+	}
+	base = strings.TrimSuffix(base, ".json")
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+		if len(base) != len(layout) {
+			continue
+		}
+		if t, err := time.Parse(layout, base); err == nil {
+			return t, true
+		}
+	}
+	// ISO week
+	year, week, ok := strings.Cut(base, "-W")
+	if !ok || len(year) != 4 || len(week) != 2 {
+		return time.Time{}, false
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return time.Time{}, false
+	}
+	w, err := strconv.Atoi(week)
+	if err != nil || w < 1 || w > 53 {
+		return time.Time{}, false
+	}
+	// January 4th is always in the first ISO week.
+	jan4 := time.Date(y, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	return monday.AddDate(0, 0, (w-1)*7), true
+}
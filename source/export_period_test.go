@@ -0,0 +1,93 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package source
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestExportPeriod_Filename(t *testing.T) {
+	ts := time.Date(2024, time.December, 31, 13, 0, 0, 0, time.UTC)
+	tests := []struct {
+		period ExportPeriod
+		want   string
+	}{
+		{"", "2024-12-31.json"},
+		{PeriodDay, "2024-12-31.json"},
+		{PeriodWeek, "2025-W01.json"}, // ISO week of the next year
+		{PeriodMonth, "2024-12.json"},
+		{PeriodYear, "2024.json"},
+		{PeriodSingle, ExportSingleFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period.String(), func(t *testing.T) {
+			if got := tt.period.Filename(ts); got != tt.want {
+				t.Errorf("ExportPeriod.Filename() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportPeriod_Set(t *testing.T) {
+	var p ExportPeriod
+	if err := p.Set("Month"); err != nil {
+		t.Fatal(err)
+	}
+	if p != PeriodMonth {
+		t.Errorf("ExportPeriod.Set() = %v, want %v", p, PeriodMonth)
+	}
+	if err := p.Set("fortnight"); err == nil {
+		t.Error("ExportPeriod.Set() expected an error")
+	}
+}
+
+func Test_parseExportFilename(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   time.Time
+		wantOk bool
+	}{
+		{"general/2024-01-31.json", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), true},
+		{"general/2024-01.json", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"general/2024.json", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"general/2024-W05.json", time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC), true},
+		{"general/2025-W01.json", time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), true},
+		{"general/" + ExportSingleFile, time.Time{}, true},
+		{"general/2024-W99.json", time.Time{}, false},
+		{"general/notes.json", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseExportFilename(tt.name)
+			if ok != tt.wantOk || !got.Equal(tt.want) {
+				t.Errorf("parseExportFilename() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_compareExportFiles(t *testing.T) {
+	// mixed periods are ordered by their start, unknown files go last.
+	files := []string{"notes.json", "2024-12-31.json", "2025-W01.json", "2024-12.json"}
+	want := []string{"2024-12.json", "2025-W01.json", "2024-12-31.json", "notes.json"}
+	got := slices.Clone(files)
+	slices.SortStableFunc(got, compareExportFiles)
+	if !slices.Equal(got, want) {
+		t.Errorf("sorted = %v, want %v", got, want)
+	}
+}
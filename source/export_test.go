@@ -18,6 +18,7 @@ package source
 import (
 	"archive/zip"
 	"context"
+	"errors"
 	"io/fs"
 	"os"
 	"path"
@@ -32,6 +33,7 @@ import (
 	"github.com/rusq/slackdump/v4/internal/chunk"
 	"github.com/rusq/slackdump/v4/internal/fixtures"
 	"github.com/rusq/slackdump/v4/internal/structures"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 var testZipFile = filepath.Join("..", "..", "..", "tmp", "realexport.zip")
@@ -257,6 +259,48 @@ func TestExport_WorkspaceInfo(t *testing.T) {
 	}
 }
 
+func TestExport_Emojis(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fs.FS
+		want    []types.Emoji
+		wantErr error
+	}{
+		{
+			name: "ok",
+			fsys: fstest.MapFS{
+				ExportEmojiFile: &fstest.MapFile{Data: []byte(`[{"name":"partyparrot","url":"https://example.com/partyparrot.gif"}]`)},
+			},
+			want: []types.Emoji{{Name: "partyparrot", URL: "https://example.com/partyparrot.gif"}},
+		},
+		{
+			name:    "no emoji file",
+			fsys:    fstest.MapFS{},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "empty",
+			fsys: fstest.MapFS{
+				ExportEmojiFile: &fstest.MapFile{Data: []byte(`[]`)},
+			},
+			wantErr: ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Export{fs: tt.fsys}
+			got, err := e.Emojis(t.Context())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Export.Emojis() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Export.Emojis() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_loadStorage(t *testing.T) {
 	mattermostFS := fstest.MapFS{
 		path.Join(chunk.UploadsDir, "F123456", "somefile.txt"): {
@@ -314,7 +358,7 @@ func Test_loadStorage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := loadStorage(tt.args.fsys)
+			got, err := loadStorage(tt.args.fsys, "")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("loadStorage() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -501,3 +545,16 @@ func TestExport_nameByID(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenExport_dirs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"channels.json": &fstest.MapFile{Data: []byte(`[{"id":"C1","name":"general"},{"id":"C2","name":"random"}]`)},
+		ExportDirsFile:  &fstest.MapFile{Data: []byte(`{"C1":"public_channel/general","C9":"unknown"}`)},
+	}
+	e, err := OpenExport(fsys, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"C1": "public_channel/general", "C2": "random"}
+	assert.Equal(t, want, e.chanNames)
+}
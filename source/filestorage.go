@@ -16,6 +16,7 @@
 package source
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -23,10 +24,16 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/rusq/slack"
 
+	"github.com/rusq/slackdump/v4/internal/avatar"
+	"github.com/rusq/slackdump/v4/internal/cas"
 	"github.com/rusq/slackdump/v4/internal/chunk"
+	"github.com/rusq/slackdump/v4/internal/linkfetch"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 const (
@@ -94,7 +101,16 @@ func MattermostFilepathWithDir(dir string) func(*slack.Channel, *slack.File) str
 // StdFilepath returns the path to the file within the "attachments"
 // directory.
 func StdFilepath(ci *slack.Channel, f *slack.File) string {
-	return path.Join(ExportChanName(ci), "attachments", fmt.Sprintf("%s-%s", f.ID, SanitizeFilename(f.Name)))
+	return StdFilepathWithDir(ExportChanName)(ci, f)
+}
+
+// StdFilepathWithDir returns the function that returns the path to the file
+// within the "attachments" directory of the channel directory, given by the
+// dir function.
+func StdFilepathWithDir(dir func(*slack.Channel) string) func(*slack.Channel, *slack.File) string {
+	return func(ci *slack.Channel, f *slack.File) string {
+		return path.Join(dir(ci), attachmentDir, fmt.Sprintf("%s-%s", f.ID, SanitizeFilename(f.Name)))
+	}
 }
 
 // DumpFilepath returns the path to the file within the channel directory.
@@ -170,6 +186,68 @@ func (r *STMattermost) FilePath(_ *slack.Channel, f *slack.File) string {
 	return MattermostFilepath(nil, f)
 }
 
+// STCAS is the Storage for the archives that keep files in the
+// content-addressed file store.  The archive has the manifest that maps the
+// mattermost file paths to the files in the store, shared with the other
+// archives, and the Storage is the filesystem of the __uploads directory, as
+// if the files were stored in the archive.
+type STCAS struct {
+	STMattermost
+}
+
+// OpenCASStorage returns the resolver for the archive with the
+// content-addressed file store.  rootfs is the root filesystem of the
+// archive, and dir is the archive path, that is used to locate the store, if
+// the manifest references it with the relative path.  If storeDir is not
+// empty, it overrides the store referenced by the manifest.
+func OpenCASStorage(rootfs fs.FS, dir string, storeDir string) (*STCAS, error) {
+	cfs, err := cas.Open(rootfs, dir, storeDir)
+	if err != nil {
+		return nil, err
+	}
+	fsys, err := fs.Sub(cfs, chunk.UploadsDir)
+	if err != nil {
+		return nil, err
+	}
+	return &STCAS{STMattermost{fs: fsys}}, nil
+}
+
+func (r *STCAS) Type() StorageType {
+	return STcas
+}
+
+// openFileStorage opens the file storage of the archive in rootfs: the
+// content-addressed file store, if the archive has the manifest, or the
+// mattermost storage otherwise.  See [OpenCASStorage] for dir and storeDir.
+func openFileStorage(rootfs fs.FS, dir string, storeDir string) (Storage, error) {
+	if st, err := OpenCASStorage(rootfs, dir, storeDir); err == nil {
+		return st, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return OpenMattermostStorage(rootfs)
+}
+
+// openStorages opens the file, avatar and emoji storages of the archive in
+// rootfs.  Storages that do not exist are returned as [NoStorage].  See
+// [OpenCASStorage] for dir and storeDir.
+func openStorages(rootfs fs.FS, dir string, storeDir string) (files, avatars, emojis, links Storage) {
+	files, avatars, emojis, links = NoStorage{}, NoStorage{}, NoStorage{}, NoStorage{}
+	if st, err := openFileStorage(rootfs, dir, storeDir); err == nil {
+		files = st
+	}
+	if st, err := NewAvatarStorage(rootfs); err == nil {
+		avatars = st
+	}
+	if st, err := NewEmojiStorage(rootfs); err == nil {
+		emojis = st
+	}
+	if st, err := NewLinkStorage(rootfs); err == nil {
+		links = st
+	}
+	return files, avatars, emojis, links
+}
+
 // STStandard is the Storage for the standard export format.  Files are
 // stored in the "attachments" subdirectories, and the Storage is the
 // filesystem of the export.
@@ -418,6 +496,9 @@ func (r *STDump) FileByID(id string) (string, error) {
 
 type AvatarStorage struct {
 	fs fs.FS
+
+	mu   sync.Mutex
+	hist map[string][]avatar.Entry // cached avatar histories
 }
 
 func NewAvatarStorage(fsys fs.FS) (*AvatarStorage, error) {
@@ -450,13 +531,54 @@ func AvatarParams(u *slack.User) (userID string, filename string) {
 	return u.ID, path.Base(u.Profile.ImageOriginal)
 }
 
+// File returns the path of the avatar of the user with the given base name
+// of the image_original URL.  It looks up the avatar stored by the previous
+// versions first, and then the avatar store, see [avatar].
 func (r *AvatarStorage) File(userID string, imageOriginalBase string) (string, error) {
 	pth := path.Join(userID, imageOriginalBase)
 	_, err := fs.Stat(r.fs, pth)
-	if err != nil {
+	if err == nil {
+		return pth, nil
+	}
+	for _, e := range r.history(userID) {
+		if path.Base(e.URL) != imageOriginalBase {
+			continue
+		}
+		if _, serr := fs.Stat(r.fs, e.Path); serr == nil {
+			return e.Path, nil
+		}
+	}
+	return "", err
+}
+
+// AvatarAt returns the path of the avatar that the user had at the time t.
+// It returns fs.ErrNotExist if there's no avatar history for the user, or
+// the avatar image is missing.
+func (r *AvatarStorage) AvatarAt(userID string, t time.Time) (string, error) {
+	e, ok := avatar.At(r.history(userID), t)
+	if !ok {
+		return "", fs.ErrNotExist
+	}
+	if _, err := fs.Stat(r.fs, e.Path); err != nil {
 		return "", err
 	}
-	return pth, nil
+	return e.Path, nil
+}
+
+// history returns the avatar history of the user, it is read once and then
+// cached.
+func (r *AvatarStorage) history(userID string) []avatar.Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if hh, ok := r.hist[userID]; ok {
+		return hh
+	}
+	hh, _ := avatar.ReadHistory(r.fs, userID) // missing history is not an error
+	if r.hist == nil {
+		r.hist = make(map[string][]avatar.Entry)
+	}
+	r.hist[userID] = hh
+	return hh
 }
 
 // FileByID is not meaningful for AvatarStorage; it always returns fs.ErrNotExist.
@@ -468,3 +590,109 @@ func (r *AvatarStorage) FileByID(id string) (string, error) {
 func (r *AvatarStorage) FilePath(_ *slack.Channel, _ *slack.File) string {
 	return ""
 }
+
+// EmojiStorage is the storage for the custom emoji images, that are kept in
+// the "__emoji" directory.
+type EmojiStorage struct {
+	fs fs.FS
+}
+
+func NewEmojiStorage(fsys fs.FS) (*EmojiStorage, error) {
+	if _, err := fs.Stat(fsys, chunk.EmojiDir); err != nil {
+		return nil, err
+	}
+	subfs, err := fs.Sub(fsys, chunk.EmojiDir)
+	if err != nil {
+		return nil, err
+	}
+	return &EmojiStorage{fs: subfs}, nil
+}
+
+func (r *EmojiStorage) FS() fs.FS {
+	return r.fs
+}
+
+func (r *EmojiStorage) Type() StorageType {
+	return STEmoji
+}
+
+// EmojiParams is a convenience function that returns the emoji name and the
+// base name of the emoji URL to be passed to EmojiStorage.File function.
+func EmojiParams(em *types.Emoji) (name string, filename string) {
+	return em.Name, path.Base(em.URL)
+}
+
+// File returns the path of the image for the emoji name.  Only the extension
+// of the urlBase is used.
+func (r *EmojiStorage) File(name string, urlBase string) (string, error) {
+	pth := name + path.Ext(urlBase)
+	if _, err := fs.Stat(r.fs, pth); err != nil {
+		return "", err
+	}
+	return pth, nil
+}
+
+// FilePath is unused on EmojiStorage.
+func (r *EmojiStorage) FilePath(_ *slack.Channel, _ *slack.File) string {
+	return ""
+}
+
+// LinkStorage is the storage for the archived copies of the external links,
+// that are kept in the "__links" directory, see package linkfetch.
+type LinkStorage struct {
+	fs fs.FS
+}
+
+func NewLinkStorage(fsys fs.FS) (*LinkStorage, error) {
+	if _, err := fs.Stat(fsys, chunk.LinksDir); err != nil {
+		return nil, err
+	}
+	subfs, err := fs.Sub(fsys, chunk.LinksDir)
+	if err != nil {
+		return nil, err
+	}
+	return &LinkStorage{fs: subfs}, nil
+}
+
+func (r *LinkStorage) FS() fs.FS {
+	return r.fs
+}
+
+func (r *LinkStorage) Type() StorageType {
+	return STLink
+}
+
+// File returns the path of the archived copy of the link url.  The name is
+// ignored, as the file name is known only after the link is fetched.
+func (r *LinkStorage) File(url string, _ string) (string, error) {
+	key := linkfetch.Key(url)
+	dd, err := fs.ReadDir(r.fs, key)
+	if err != nil {
+		return "", err
+	}
+	for _, d := range dd {
+		if d.Type().IsRegular() {
+			return path.Join(key, d.Name()), nil
+		}
+	}
+	return "", fs.ErrNotExist
+}
+
+// FilePath is unused on LinkStorage.
+func (r *LinkStorage) FilePath(_ *slack.Channel, _ *slack.File) string {
+	return ""
+}
+
+type fileStoreCtxKey struct{}
+
+// WithFileStore returns the context with the content-addressed file store
+// directory, that is used instead of the store referenced by the archive
+// manifest, i.e. if the store was moved.
+func WithFileStore(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, fileStoreCtxKey{}, dir)
+}
+
+func fileStoreFromContext(ctx context.Context) string {
+	dir, _ := ctx.Value(fileStoreCtxKey{}).(string)
+	return dir
+}
@@ -16,13 +16,22 @@
 package source
 
 import (
+	"encoding/json"
+	"errors"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"testing"
 	"testing/fstest"
+	"time"
 
+	"github.com/rusq/fsadapter"
+
+	"github.com/rusq/slackdump/v4/internal/avatar"
+	"github.com/rusq/slackdump/v4/internal/cas"
 	"github.com/rusq/slackdump/v4/internal/chunk"
+	"github.com/rusq/slackdump/v4/internal/linkfetch"
 )
 
 func Test_fstMattermost_File(t *testing.T) {
@@ -99,6 +108,140 @@ func Test_fstMattermost_File(t *testing.T) {
 	}
 }
 
+func TestOpenCASStorage(t *testing.T) {
+	dir := t.TempDir()
+	w, err := cas.NewWriter(fsadapter.NewDirectory(dir), dir, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteFile(filepath.Join(chunk.UploadsDir, "file_id1", "filename.ext"), []byte("file contents"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := openFileStorage(os.DirFS(dir), dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Type() != STcas {
+		t.Fatalf("openFileStorage() type = %s, want %s", st.Type(), STcas)
+	}
+	got, err := st.File("file_id1", "filename.ext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "file_id1/filename.ext"; got != want {
+		t.Errorf("STCAS.File() = %v, want %v", got, want)
+	}
+	data, err := fs.ReadFile(st.FS(), got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "file contents" {
+		t.Errorf("file contents = %q", data)
+	}
+	if _, err := st.File("file_id1", "nonexistent.ext"); err == nil {
+		t.Error("STCAS.File() expected error for nonexistent file")
+	}
+}
+
+func TestLinkStorage_File(t *testing.T) {
+	const u = "https://docs.google.com/document/d/1"
+	key := linkfetch.Key(u)
+	fsys := fstest.MapFS{
+		path.Join(chunk.LinksDir, key, "index.html"): &fstest.MapFile{Data: []byte("<html></html>")},
+	}
+	_, _, _, links := openStorages(fsys, "", "")
+	if links.Type() != STLink {
+		t.Fatalf("openStorages() links type = %s, want %s", links.Type(), STLink)
+	}
+	got, err := links.File(u, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := key + "/index.html"; got != want {
+		t.Errorf("LinkStorage.File() = %v, want %v", got, want)
+	}
+	if _, err := links.File("https://example.com/", ""); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LinkStorage.File() error = %v, want fs.ErrNotExist", err)
+	}
+
+	_, _, _, links = openStorages(fstest.MapFS{}, "", "")
+	if links.Type() != STnone {
+		t.Errorf("openStorages() links type = %s, want %s", links.Type(), STnone)
+	}
+}
+
+func TestAvatarStorage_History(t *testing.T) {
+	const (
+		oldURL = "https://avatars.slack-edge.com/2020-01-01/1_original.png"
+		newURL = "https://avatars.slack-edge.com/2024-01-01/2_original.jpg"
+	)
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	hist, err := json.Marshal([]avatar.Entry{
+		{Recorded: t0, URL: oldURL, Path: avatar.StorePath(oldURL)},
+		{Recorded: t0.Add(24 * time.Hour), URL: newURL, Path: avatar.StorePath(newURL)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{
+		path.Join(chunk.AvatarsDir, avatar.HistoryPath("U1")):    &fstest.MapFile{Data: hist},
+		path.Join(chunk.AvatarsDir, avatar.StorePath(oldURL)):    &fstest.MapFile{Data: []byte("old")},
+		path.Join(chunk.AvatarsDir, avatar.StorePath(newURL)):    &fstest.MapFile{Data: []byte("new")},
+		path.Join(chunk.AvatarsDir, "U2", "legacy_original.png"): &fstest.MapFile{Data: []byte("legacy")},
+	}
+	st, err := NewAvatarStorage(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("file from the store", func(t *testing.T) {
+		got, err := st.File("U1", path.Base(oldURL))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := avatar.StorePath(oldURL); got != want {
+			t.Errorf("File() = %v, want %v", got, want)
+		}
+	})
+	t.Run("legacy file", func(t *testing.T) {
+		got, err := st.File("U2", "legacy_original.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "U2/legacy_original.png"; got != want {
+			t.Errorf("File() = %v, want %v", got, want)
+		}
+	})
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := st.File("U1", "missing.png"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("File() error = %v, want fs.ErrNotExist", err)
+		}
+	})
+	t.Run("avatar at", func(t *testing.T) {
+		tests := []struct {
+			t    time.Time
+			want string
+		}{
+			{t0.Add(-time.Hour), avatar.StorePath(oldURL)},
+			{t0.Add(time.Hour), avatar.StorePath(oldURL)},
+			{t0.Add(48 * time.Hour), avatar.StorePath(newURL)},
+		}
+		for _, tt := range tests {
+			got, err := st.AvatarAt("U1", tt.t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("AvatarAt(%s) = %v, want %v", tt.t, got, tt.want)
+			}
+		}
+		if _, err := st.AvatarAt("U2", t0); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("AvatarAt() error = %v, want fs.ErrNotExist", err)
+		}
+	})
+}
+
 func Test_fstStandard_File(t *testing.T) {
 	type fields struct {
 		fs  fs.FS
@@ -19,6 +19,7 @@ import (
 	slack "github.com/rusq/slack"
 	structures "github.com/rusq/slackdump/v4/internal/structures"
 	source "github.com/rusq/slackdump/v4/source"
+	types "github.com/rusq/slackdump/v4/types"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -105,6 +106,21 @@ func (mr *MockSourcerMockRecorder) ChannelInfo(ctx, channelID any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChannelInfo", reflect.TypeOf((*MockSourcer)(nil).ChannelInfo), ctx, channelID)
 }
 
+// ChannelMembership mocks base method.
+func (m *MockSourcer) ChannelMembership(ctx context.Context, channelID string) ([]types.MembershipEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChannelMembership", ctx, channelID)
+	ret0, _ := ret[0].([]types.MembershipEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChannelMembership indicates an expected call of ChannelMembership.
+func (mr *MockSourcerMockRecorder) ChannelMembership(ctx, channelID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChannelMembership", reflect.TypeOf((*MockSourcer)(nil).ChannelMembership), ctx, channelID)
+}
+
 // Channels mocks base method.
 func (m *MockSourcer) Channels(ctx context.Context) ([]slack.Channel, error) {
 	m.ctrl.T.Helper()
@@ -409,6 +425,21 @@ func (mr *MockSourceResumeCloserMockRecorder) ChannelInfo(ctx, channelID any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChannelInfo", reflect.TypeOf((*MockSourceResumeCloser)(nil).ChannelInfo), ctx, channelID)
 }
 
+// ChannelMembership mocks base method.
+func (m *MockSourceResumeCloser) ChannelMembership(ctx context.Context, channelID string) ([]types.MembershipEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChannelMembership", ctx, channelID)
+	ret0, _ := ret[0].([]types.MembershipEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChannelMembership indicates an expected call of ChannelMembership.
+func (mr *MockSourceResumeCloserMockRecorder) ChannelMembership(ctx, channelID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChannelMembership", reflect.TypeOf((*MockSourceResumeCloser)(nil).ChannelMembership), ctx, channelID)
+}
+
 // Channels mocks base method.
 func (m *MockSourceResumeCloser) Channels(ctx context.Context) ([]slack.Channel, error) {
 	m.ctrl.T.Helper()
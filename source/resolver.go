@@ -0,0 +1,44 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package source
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rusq/slackdump/v4/internal/structures"
+)
+
+// NameResolver returns the resolver of the human-readable conversation names
+// for the source, see [structures.NameResolver].  It uses the users of the
+// source, the current user from the workspace information, and the aliases,
+// if the source implements [Aliaser].
+func NameResolver(ctx context.Context, src Sourcer) (*structures.NameResolver, error) {
+	users, err := src.Users(ctx)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	var me string
+	if wi, err := src.WorkspaceInfo(ctx); err == nil && wi != nil {
+		me = wi.UserID
+	}
+	var aliases map[string]string
+	if a, ok := src.(Aliaser); ok {
+		// older databases may not have the aliases, this is not an error.
+		aliases, _ = a.Aliases()
+	}
+	return structures.NewNameResolver(structures.NewUserIndex(users), me, aliases), nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rusq/slackdump/v4/internal/chunk"
+	"github.com/rusq/slackdump/v4/internal/s3"
+	"github.com/rusq/slackdump/v4/internal/seal"
+)
+
+// loadS3 loads the archive from the S3 URL src.  Export and dump archives
+// are read from the bucket directly.  Chunk files and the database must be
+// local, so they are downloaded to the temporary directory, that is removed
+// on Close, while the files, avatars and emoji are read from the bucket.
+func loadS3(ctx context.Context, src string) (SourceResumeCloser, error) {
+	sfs, err := s3.Open(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := sfs.Stat("."); err != nil {
+		return nil, err
+	}
+	st := contentType(sfs, FDirectory)
+	switch {
+	case st.Has(FChunk):
+		return openS3ChunkDir(ctx, sfs)
+	case st.Has(FExport):
+		return OpenExport(sfs, src)
+	case st.Has(FDump):
+		return OpenDump(ctx, sfs, src)
+	case st.Has(FDatabase):
+		return openS3Database(ctx, sfs, src)
+	default:
+		return nil, fmt.Errorf("unsupported source type: %s", src)
+	}
+}
+
+// isStorageDir returns true for the directories of the file, avatar, emoji
+// and link storages, and for the hidden directories, such as partial
+// downloads.
+func isStorageDir(name string) bool {
+	switch name {
+	case chunk.UploadsDir, chunk.AvatarsDir, chunk.EmojiDir, chunk.LinksDir:
+		return true
+	}
+	return strings.HasPrefix(name, ".")
+}
+
+func openS3ChunkDir(ctx context.Context, sfs *s3.FS) (*ChunkDir, error) {
+	tmpdir, err := os.MkdirTemp("", "slackdump-s3-*")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() error { return os.RemoveAll(tmpdir) }
+	if err := s3.Download(ctx, tmpdir, sfs, isStorageDir); err != nil {
+		return nil, errors.Join(err, cleanup())
+	}
+	d, err := chunk.OpenDir(tmpdir)
+	if err != nil {
+		return nil, errors.Join(err, cleanup())
+	}
+	files, avatars, emojis, links := openStorages(sfs, "", fileStoreFromContext(ctx))
+	return &ChunkDir{d: d, fast: true, files: files, avatars: avatars, emojis: emojis, links: links, cleanup: cleanup}, nil
+}
+
+// openS3Database opens the copy of the database.  It is opened read-only,
+// as the changes to the copy would be lost.
+func openS3Database(ctx context.Context, sfs *s3.FS, src string) (*Database, error) {
+	tmpdir, err := os.MkdirTemp("", "slackdump-s3-*")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() error { return os.RemoveAll(tmpdir) }
+	dbfile := filepath.Join(tmpdir, DefaultDBFile)
+	if err := downloadFile(dbfile, sfs, DefaultDBFile); err != nil {
+		return nil, errors.Join(err, cleanup())
+	}
+	p, err := resolveDBPath(ctx, dbfile)
+	if err != nil {
+		return nil, errors.Join(err, cleanup())
+	}
+	var rootFS fs.FS = sfs
+	if p.secret != nil {
+		rootFS = seal.FS(rootFS, p.secret)
+	}
+	s, err := p.open(ctx)
+	if err != nil {
+		return nil, errors.Join(err, cleanup())
+	}
+	files, avatars, emojis, links := openStorages(rootFS, "", fileStoreFromContext(ctx))
+	return &Database{
+		name:    src,
+		Source:  s,
+		files:   files,
+		avatars: avatars,
+		emojis:  emojis,
+		links:   links,
+		sealed:  p.secret != nil,
+		cleanup: cleanup,
+	}, nil
+}
+
+func downloadFile(dst string, fsys fs.FS, name string) error {
+	r, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusq/slackdump/v4/internal/s3"
+	"github.com/rusq/slackdump/v4/internal/s3/s3test"
+)
+
+func TestLoad_s3(t *testing.T) {
+	srv := s3test.NewServer(t)
+	srv.Setenv(t)
+	c, err := s3.New(srv.Config())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		fixture string
+		want    Sourcer
+		wantT   Flags
+	}{
+		{"chunk", "source_archive", &ChunkDir{}, FDirectory | FChunk},
+		{"export", "source_export_dir", &Export{}, FDirectory | FExport},
+		{"dump", "source_dump_dir", &Dump{}, FDirectory | FDump},
+		{"database", "source_database", &Database{}, FDirectory | FDatabase},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := s3.NewFS(t.Context(), c, "bucket", tt.name)
+			require.NoError(t, s3.Upload(t.Context(), dst, filepath.Join(fixturesDir, tt.fixture), nil))
+			src := "s3://bucket/" + tt.name
+
+			typ, err := Type(src)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantT, typ)
+
+			got, err := Load(t.Context(), src)
+			require.NoError(t, err)
+			defer got.Close()
+			assert.Equal(t, reflect.TypeOf(tt.want), reflect.TypeOf(got))
+			if _, err := got.Channels(t.Context()); err != nil {
+				assert.ErrorIs(t, err, ErrNotFound) // the database fixture has no channels
+			}
+		})
+	}
+	t.Run("missing", func(t *testing.T) {
+		_, err := Load(t.Context(), "s3://bucket/missing")
+		assert.Error(t, err)
+	})
+}
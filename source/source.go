@@ -34,7 +34,9 @@ import (
 
 	"github.com/rusq/slackdump/v4/internal/chunk"
 	"github.com/rusq/slackdump/v4/internal/primitive"
+	"github.com/rusq/slackdump/v4/internal/s3"
 	"github.com/rusq/slackdump/v4/internal/structures"
+	"github.com/rusq/slackdump/v4/types"
 )
 
 // Sourcer is an interface for retrieving data from different sources. If any
@@ -72,6 +74,10 @@ type Sourcer interface {
 	// ChannelInfo should return the channel information for the given channel
 	// id.
 	ChannelInfo(ctx context.Context, channelID string) (*slack.Channel, error)
+	// ChannelMembership should return the membership timeline (who joined or
+	// left, and when) of the channel, ordered chronologically.  If there's no
+	// membership information for the channel, it should return ErrNotFound.
+	ChannelMembership(ctx context.Context, channelID string) ([]types.MembershipEvent, error)
 	// Files should return file [Storage].
 	Files() Storage
 	// Avatars should return the avatar [Storage].
@@ -80,6 +86,48 @@ type Sourcer interface {
 	WorkspaceInfo(ctx context.Context) (*slack.AuthTestResponse, error)
 }
 
+// Emojier is the interface implemented by sources that may contain the
+// custom workspace emoji.
+type Emojier interface {
+	// Emojis should return all custom emoji.  If there are no emoji in the
+	// source, it should return ErrNotFound.
+	Emojis(ctx context.Context) ([]types.Emoji, error)
+	// EmojiStorage should return the emoji image [Storage].
+	EmojiStorage() Storage
+}
+
+// Linker is the interface implemented by sources that may contain the
+// archived copies of the external links, see package linkfetch.
+type Linker interface {
+	// LinkStorage should return the archived links [Storage].
+	LinkStorage() Storage
+}
+
+// Sealer is the interface implemented by sources that may be encrypted, see
+// package seal.
+type Sealer interface {
+	// Sealed should return true if the source is encrypted.  The data read
+	// from such source should not be written to disk in plaintext.
+	Sealed() bool
+}
+
+// Aliaser is the interface implemented by sources that keep the
+// conversation aliases.
+type Aliaser interface {
+	// Aliases should return the map of the conversation IDs to their
+	// aliases.
+	Aliases() (map[string]string, error)
+}
+
+// UserHistorian is the interface that should be implemented by sources that
+// keep the user profiles recorded by each session.
+type UserHistorian interface {
+	// UserHistory should return the changes of the user profile, ordered
+	// chronologically.  If the user is not in the source, it should return
+	// ErrNotFound.
+	UserHistory(ctx context.Context, userID string) ([]types.UserChange, error)
+}
+
 type Resumer interface {
 	// Latest should return the latest timestamps of all channels and threads.
 	Latest(ctx context.Context) (map[structures.SlackLink]time.Time, error)
@@ -162,6 +210,10 @@ var (
 // source type is not supported or if the source is not found.
 func Load(ctx context.Context, src string) (SourceResumeCloser, error) {
 	lg := slog.With("source", src)
+	if s3.IsURL(src) {
+		lg.DebugContext(ctx, "loading from S3")
+		return loadS3(ctx, src)
+	}
 	st, err := Type(src)
 	if err != nil {
 		return nil, err
@@ -170,6 +222,9 @@ func Load(ctx context.Context, src string) (SourceResumeCloser, error) {
 		return nil, fmt.Errorf("unsupported source type: %s", src)
 	}
 	switch {
+	case st.Has(FChunk | FZip):
+		lg.DebugContext(ctx, "loading chunk zip")
+		return openZipChunkDir(src)
 	case st.Has(FChunk | FDirectory):
 		lg.DebugContext(ctx, "loading chunk directory")
 		dir, err := chunk.OpenDir(src)
@@ -197,6 +252,9 @@ func Load(ctx context.Context, src string) (SourceResumeCloser, error) {
 	case st.Has(FDump | FDirectory):
 		lg.DebugContext(ctx, "loading dump directory")
 		return OpenDump(ctx, os.DirFS(src), src)
+	case st.Has(FDatabase | FZip):
+		lg.DebugContext(ctx, "loading database zip")
+		return OpenDatabase(ctx, src)
 	case st.Has(FDatabase):
 		lg.DebugContext(ctx, "loading database")
 		return OpenDatabaseRW(ctx, src)
@@ -205,7 +263,20 @@ func Load(ctx context.Context, src string) (SourceResumeCloser, error) {
 	}
 }
 
+// Type returns the type of the source src, which may be a directory, a ZIP
+// file, a database file or the S3 URL ("s3://bucket/prefix").  The objects
+// under the S3 prefix are treated as a directory.
 func Type(src string) (Flags, error) {
+	if s3.IsURL(src) {
+		sfs, err := s3.Open(context.Background(), src)
+		if err != nil {
+			return FUnknown, err
+		}
+		if _, err := sfs.Stat("."); err != nil {
+			return FUnknown, err
+		}
+		return contentType(sfs, FDirectory), nil
+	}
 	fi, err := os.Stat(src)
 	if err != nil {
 		return FUnknown, err
@@ -222,7 +293,7 @@ func srcType(src string, fi fs.FileInfo) Flags {
 		fsys = os.DirFS(src)
 		flags |= FDirectory
 	} else if fi.Mode().IsRegular() {
-		if strings.ToLower(path.Ext(src)) == ".zip" {
+		if isZip(src) {
 			f, err := zip.OpenReader(src)
 			if err != nil {
 				return FUnknown
@@ -240,20 +311,19 @@ func srcType(src string, fi fs.FileInfo) Flags {
 		return FUnknown
 	}
 
-	// main content
+	return contentType(fsys, flags)
+}
+
+// contentType detects the main content of the container fsys, flags are the
+// container flags.
+func contentType(fsys fs.FS, flags Flags) Flags {
 	if ff, err := fs.Glob(fsys, "[CDG]*.json"); err == nil && len(ff) > 0 {
 		return flags | FDump
 	}
 
 	if _, err := fs.Stat(fsys, "workspace.json.gz"); err == nil {
-		if flags&FZip != 0 {
-			return FUnknown // compressed chunk directories are not supported
-		}
 		return flags | FChunk
 	} else if files, err := fs.Glob(fsys, "*.json.gz"); err == nil && len(files) > 0 {
-		if flags&FZip != 0 {
-			return FUnknown
-		}
 		return flags | FChunk
 	}
 	if _, err := fs.Stat(fsys, "channels.json"); err == nil {
@@ -266,6 +336,28 @@ func srcType(src string, fi fs.FileInfo) Flags {
 	return FUnknown
 }
 
+// messageMembership returns the membership timeline of the channel, derived
+// from the join and leave messages.
+func messageMembership(ctx context.Context, src Sourcer, channelID string) ([]types.MembershipEvent, error) {
+	it, err := src.AllMessages(ctx, channelID)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	var ee []types.MembershipEvent
+	for m, err := range it {
+		if err != nil {
+			return nil, err
+		}
+		if ev, ok := types.MembershipFromMessage(&m); ok {
+			ee = append(ee, ev)
+		}
+	}
+	return types.MergeMembership(ee, nil), nil
+}
+
 func unmarshalOne[T any](fsys fs.FS, name string) (T, error) {
 	var v T
 	f, err := fsys.Open(name)
@@ -18,6 +18,7 @@ package source
 import (
 	"context"
 	"io/fs"
+	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
@@ -28,6 +29,33 @@ import (
 
 var fixturesDir = filepath.Join("..", "internal", "fixtures", "assets")
 
+// copyFixture copies the fixture file or directory to a temporary directory
+// and returns the path of the copy.  The database fixtures must be copied,
+// as opening the database migrates it to the latest schema.
+func copyFixture(t *testing.T, name string) string {
+	t.Helper()
+	src := filepath.Join(fixturesDir, name)
+	dst := filepath.Join(t.TempDir(), name)
+	fi, err := os.Stat(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.IsDir() {
+		if err := os.CopyFS(dst, os.DirFS(src)); err != nil {
+			t.Fatal(err)
+		}
+		return dst
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dst
+}
+
 func TestLoad(t *testing.T) {
 	type args struct {
 		ctx context.Context
@@ -77,13 +105,13 @@ func TestLoad(t *testing.T) {
 		},
 		{
 			"database directory",
-			args{t.Context(), filepath.Join(fixturesDir, "source_database")},
+			args{t.Context(), copyFixture(t, "source_database")},
 			&RWDatabase{},
 			false,
 		},
 		{
 			"database file",
-			args{t.Context(), filepath.Join(fixturesDir, "source_database.db")},
+			args{t.Context(), copyFixture(t, "source_database.db")},
 			&RWDatabase{},
 			false,
 		},
@@ -37,6 +37,13 @@ const (
 	STdump
 	// STAvatar is the storage type for the avatar storage.
 	STAvatar
+	// STEmoji is the storage type for the custom emoji storage.
+	STEmoji
+	// STcas is the storage type for the content-addressed file store,
+	// shared between archives.
+	STcas
+	// STLink is the storage type for the archived external links.
+	STLink
 )
 
 // Set translates the string value into the ExportType, satisfies flag.Value
@@ -62,11 +69,22 @@ func (e *StorageType) Func() (pathFn func(*slack.Channel, *slack.File) string, o
 	return
 }
 
+// FuncWithDir is the same as [StorageType.Func], but the storage types, that
+// keep the files in the channel directories, use the dir function to get the
+// channel directory name.
+func (e *StorageType) FuncWithDir(dir func(*slack.Channel) string) (pathFn func(*slack.Channel, *slack.File) string, ok bool) {
+	if *e == STstandard {
+		return StdFilepathWithDir(dir), true
+	}
+	return e.Func()
+}
+
 // storageTypeFuncs is a map of storage types to functions that return the
 // file path for the given channel and file.
 var storageTypeFuncs = map[StorageType]func(_ *slack.Channel, f *slack.File) string{
 	STmattermost: MattermostFilepath,
 	STstandard:   StdFilepath,
 	STdump:       DumpFilepath,
+	STcas:        MattermostFilepath,
 	STnone:       func(*slack.Channel, *slack.File) string { return "" },
 }
@@ -13,11 +13,14 @@ func _() {
 	_ = x[STmattermost-2]
 	_ = x[STdump-3]
 	_ = x[STAvatar-4]
+	_ = x[STEmoji-5]
+	_ = x[STcas-6]
+	_ = x[STLink-7]
 }
 
-const _StorageType_name = "nonestandardmattermostdumpAvatar"
+const _StorageType_name = "nonestandardmattermostdumpAvatarEmojicasLink"
 
-var _StorageType_index = [...]uint8{0, 4, 12, 22, 26, 32}
+var _StorageType_index = [...]uint8{0, 4, 12, 22, 26, 32, 37, 40, 44}
 
 func (i StorageType) String() string {
 	idx := int(i) - 0
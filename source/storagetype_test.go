@@ -18,6 +18,7 @@ package source
 import (
 	"testing"
 
+	"github.com/rusq/slack"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,3 +67,20 @@ func TestStorageType_Set(t *testing.T) {
 		})
 	}
 }
+
+func TestStorageType_FuncWithDir(t *testing.T) {
+	ch := &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "D1", IsIM: true}}}
+	f := &slack.File{ID: "F1", Name: "a.txt"}
+	dir := func(*slack.Channel) string { return "im/alice" }
+
+	st := STstandard
+	fn, ok := st.FuncWithDir(dir)
+	assert.True(t, ok)
+	assert.Equal(t, "im/alice/attachments/F1-a.txt", fn(ch, f))
+
+	// the other layouts do not depend on the channel directory.
+	st = STmattermost
+	fn, ok = st.FuncWithDir(dir)
+	assert.True(t, ok)
+	assert.Equal(t, "__uploads/F1/a.txt", fn(ch, f))
+}
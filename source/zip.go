@@ -0,0 +1,252 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package source
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rusq/slackdump/v4/internal/chunk"
+	"github.com/rusq/slackdump/v4/internal/seal"
+)
+
+type zipCacheCtxKey struct{}
+
+// WithZipCache returns the context with the ZIP cache directory.  The
+// databases opened from ZIP files are extracted to the ZIP cache directory,
+// and are reused on the next open of the same ZIP file.  Databases that were
+// not used for a week are removed.  If the context has no ZIP cache
+// directory, the database is extracted to the temporary directory, that is
+// removed on Close.
+func WithZipCache(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, zipCacheCtxKey{}, dir)
+}
+
+func zipCacheFromContext(ctx context.Context) string {
+	dir, _ := ctx.Value(zipCacheCtxKey{}).(string)
+	return dir
+}
+
+// isZip returns true if the name has the ZIP extension.
+func isZip(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".zip")
+}
+
+// openZipChunkDir opens the chunk directory in the ZIP file src.  The chunk
+// files and attachments are read from the ZIP file as they are needed.
+func openZipChunkDir(src string) (*ChunkDir, error) {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return nil, err
+	}
+	d, err := chunk.OpenDirFS(zr, src)
+	if err != nil {
+		return nil, errors.Join(err, zr.Close())
+	}
+	cd := OpenChunkDir(d, true)
+	cd.cleanup = zr.Close
+	return cd, nil
+}
+
+// openZipDatabase opens the database in the ZIP file src.  SQLite needs the
+// database on disk, so it is extracted to the temporary directory, or to the
+// ZIP cache directory (see [WithZipCache]), while the files, avatars and
+// emoji are read from the ZIP file as they are needed.  The database is opened read-only.
+func openZipDatabase(ctx context.Context, src string) (*Database, error) {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return nil, err
+	}
+	dbfile, rmdb, err := extractDB(ctx, &zr.Reader)
+	if err != nil {
+		return nil, errors.Join(err, zr.Close())
+	}
+	cleanup := func() error { return errors.Join(rmdb(), zr.Close()) }
+
+	p, err := resolveDBPath(ctx, dbfile)
+	if err != nil {
+		return nil, errors.Join(err, cleanup())
+	}
+	var rootFS fs.FS = zr
+	if p.secret != nil {
+		rootFS = seal.FS(rootFS, p.secret)
+	}
+	s, err := p.open(ctx)
+	if err != nil {
+		return nil, errors.Join(err, cleanup())
+	}
+	files, avatars, emojis, links := openStorages(rootFS, strings.TrimSuffix(src, filepath.Ext(src)), fileStoreFromContext(ctx))
+	return &Database{
+		name:    src,
+		Source:  s,
+		files:   files,
+		avatars: avatars,
+		emojis:  emojis,
+		links:   links,
+		sealed:  p.secret != nil,
+		cleanup: cleanup,
+	}, nil
+}
+
+// zipCacheMaxAge is the time after which the database, that was not used, is
+// removed from the ZIP cache.
+const zipCacheMaxAge = 7 * 24 * time.Hour
+
+// extractDB extracts the database from the ZIP file.  If the context has the
+// ZIP cache directory, the database is extracted to the subdirectory, named
+// after the SHA-256 hash of the database, unless it was extracted before.
+// Otherwise, it is extracted to the temporary directory, that is removed by
+// cleanup.
+func extractDB(ctx context.Context, zr *zip.Reader) (dbfile string, cleanup func() error, err error) {
+	var zf *zip.File
+	for _, f := range zr.File {
+		if f.Name == DefaultDBFile {
+			zf = f
+			break
+		}
+	}
+	if zf == nil {
+		return "", nil, fmt.Errorf("%s: %w", DefaultDBFile, fs.ErrNotExist)
+	}
+
+	cacheDir := zipCacheFromContext(ctx)
+	if cacheDir == "" {
+		tmpdir, err := os.MkdirTemp("", "slackdump-zip-*")
+		if err != nil {
+			return "", nil, err
+		}
+		cleanup := func() error { return os.RemoveAll(tmpdir) }
+		dbfile := filepath.Join(tmpdir, DefaultDBFile)
+		if _, err := extractFile(dbfile, zf); err != nil {
+			return "", nil, errors.Join(err, cleanup())
+		}
+		return dbfile, cleanup, nil
+	}
+
+	noop := func() error { return nil }
+	sum, err := hashFile(zf)
+	if err != nil {
+		return "", nil, err
+	}
+	dir := filepath.Join(cacheDir, sum)
+	dbfile = filepath.Join(dir, DefaultDBFile)
+	if _, err := os.Stat(dbfile); err == nil {
+		// the modification time is the last use time for eviction.
+		now := time.Now()
+		if err := os.Chtimes(dbfile, now, now); err != nil {
+			return "", nil, err
+		}
+		return dbfile, noop, nil
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", nil, err
+	}
+	// extract to the temporary file first, so that the interrupted
+	// extraction is not mistaken for the cached database.
+	tmp := dbfile + ".tmp"
+	got, err := extractFile(tmp, zf)
+	if err != nil {
+		return "", nil, errors.Join(err, os.Remove(tmp))
+	}
+	if got != sum {
+		// the ZIP file was changed in the meantime.
+		return "", nil, errors.Join(fmt.Errorf("%s: contents changed while extracting", DefaultDBFile), os.Remove(tmp))
+	}
+	if err := os.Rename(tmp, dbfile); err != nil {
+		return "", nil, err
+	}
+	if err := PruneZipCache(cacheDir, zipCacheMaxAge); err != nil {
+		slog.WarnContext(ctx, "unable to prune the ZIP cache", "dir", cacheDir, "error", err)
+	}
+	return dbfile, noop, nil
+}
+
+// PruneZipCache removes the databases that were not used for longer than
+// maxAge from the ZIP cache directory dir.
+func PruneZipCache(dir string, maxAge time.Duration) error {
+	ee, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var errs error
+	for _, e := range ee {
+		if !e.IsDir() {
+			continue
+		}
+		dbfile := filepath.Join(dir, e.Name(), DefaultDBFile)
+		fi, err := os.Stat(dbfile)
+		if err != nil {
+			// may be being extracted by another process.
+			fi, err = os.Stat(dbfile + ".tmp")
+		}
+		if err == nil && time.Since(fi.ModTime()) < maxAge {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// hashFile returns the hex-encoded SHA-256 hash of the ZIP file entry zf.
+func hashFile(zf *zip.File) (string, error) {
+	r, err := zf.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// extractFile extracts the ZIP file entry zf to the file dst, and returns the
+// hex-encoded SHA-256 hash of its contents.  The checksum of the entry is
+// verified by the zip reader.
+func extractFile(dst string, zf *zip.File) (string, error) {
+	r, err := zf.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), r); err != nil {
+		f.Close()
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), f.Close()
+}
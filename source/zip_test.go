@@ -0,0 +1,124 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package source
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zipDir packs the directory into the ZIP file in the temporary directory.
+func zipDir(t *testing.T, dir string) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), filepath.Base(dir)+".zip")
+	f, err := os.Create(name)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	require.NoError(t, zw.AddFS(os.DirFS(dir)))
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+	return name
+}
+
+func TestLoad_zip(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		want    Sourcer
+		wantT   Flags
+	}{
+		{"chunk", "source_archive", &ChunkDir{}, FZip | FChunk},
+		{"database", "source_database", &Database{}, FZip | FDatabase},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := zipDir(t, filepath.Join(fixturesDir, tt.fixture))
+
+			typ, err := Type(src)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantT, typ)
+
+			got, err := Load(t.Context(), src)
+			require.NoError(t, err)
+			defer got.Close()
+			assert.Equal(t, reflect.TypeOf(tt.want), reflect.TypeOf(got))
+			assert.Equal(t, src, got.Name())
+			if _, err := got.Channels(t.Context()); err != nil {
+				assert.ErrorIs(t, err, ErrNotFound) // the database fixture has no channels
+			}
+		})
+	}
+}
+
+func TestOpenDatabase_zipCache(t *testing.T) {
+	src := zipDir(t, filepath.Join(fixturesDir, "source_database"))
+	cacheDir := t.TempDir()
+	ctx := WithZipCache(t.Context(), cacheDir)
+
+	db, err := OpenDatabase(ctx, src)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	cached, err := filepath.Glob(filepath.Join(cacheDir, "*", DefaultDBFile))
+	require.NoError(t, err)
+	require.Len(t, cached, 1, "database is not cached")
+	assert.Len(t, filepath.Base(filepath.Dir(cached[0])), 64, "cache key is the SHA-256 hash")
+	fi, err := os.Stat(cached[0])
+	require.NoError(t, err)
+
+	// second open reuses the cached database
+	db, err = OpenDatabase(ctx, src)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	fi2, err := os.Stat(cached[0])
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(fi, fi2))
+
+	t.Run("no cache directory", func(t *testing.T) {
+		tmp := t.TempDir()
+		t.Setenv("TMPDIR", tmp)
+		db, err := OpenDatabase(t.Context(), src)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+		ee, err := os.ReadDir(tmp)
+		require.NoError(t, err)
+		assert.Empty(t, ee, "extracted database is not removed")
+	})
+}
+
+func TestPruneZipCache(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"fresh", "stale"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, name), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name, DefaultDBFile), nil, 0o600))
+	}
+	old := time.Now().Add(-2 * zipCacheMaxAge)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "stale", DefaultDBFile), old, old))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "interrupted"), 0o700))
+
+	require.NoError(t, PruneZipCache(dir, zipCacheMaxAge))
+	assert.DirExists(t, filepath.Join(dir, "fresh"))
+	assert.NoDirExists(t, filepath.Join(dir, "stale"))
+	assert.NoDirExists(t, filepath.Join(dir, "interrupted"))
+
+	assert.NoError(t, PruneZipCache(filepath.Join(dir, "missing"), zipCacheMaxAge))
+}
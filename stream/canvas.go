@@ -0,0 +1,148 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package stream
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/trace"
+
+	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/internal/network"
+	"github.com/rusq/slackdump/v4/internal/structures"
+	"github.com/rusq/slackdump/v4/processor"
+)
+
+const (
+	// canvasFileType is the files.list type filter for canvases.
+	canvasFileType = "canvas"
+	// canvasPageSize is the number of files requested per files.list call.
+	canvasPageSize = 100
+)
+
+// Canvases discovers all canvases that the current user can access, including
+// standalone canvases and canvases shared in DMs, and passes each of them to
+// the processor as a canvas channel:  the channel information, the canvas file
+// and the comment threads.  Errors fetching an individual canvas are logged and
+// the canvas is skipped.
+//
+// Slack API does not expose the canvas edit history, only the current version
+// of the canvas is recorded.
+func (cs *Stream) Canvases(ctx context.Context, proc processor.Conversations) error {
+	ctx, task := trace.NewTask(ctx, "Canvases")
+	defer task.End()
+
+	lg := slog.With("in", "Canvases")
+	for page := 1; ; page++ {
+		var (
+			ff     []slack.File
+			paging *slack.Paging
+		)
+		if err := network.WithRetry(ctx, cs.limits.files, cs.limits.tier.Tier3.Retries, func(ctx context.Context) error {
+			var err error
+			ff, paging, err = cs.client.GetFilesContext(ctx, slack.GetFilesParameters{
+				Types: canvasFileType,
+				Count: canvasPageSize,
+				Page:  page,
+			})
+			return err
+		}); err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+		for _, f := range ff {
+			if !isCanvasFile(f) {
+				continue
+			}
+			if err := cs.canvasChannel(ctx, proc, f); err != nil {
+				if ctx.Err() != nil {
+					return context.Cause(ctx)
+				}
+				lg.WarnContext(ctx, "canvas error, skipping", "file_id", f.ID, "error", err)
+			}
+		}
+		if paging == nil || paging.Page >= paging.Pages {
+			break
+		}
+	}
+	return nil
+}
+
+// isCanvasFile returns true if the file is a canvas.  Older canvases are
+// reported with the "quip" file type.
+func isCanvasFile(f slack.File) bool {
+	return f.Filetype == "canvas" || f.Filetype == "quip" || f.Mode == "canvas" || f.Mode == "quip"
+}
+
+// canvasChannelInfo returns the canvas channel for the canvas file f.
+func canvasChannelInfo(f slack.File) *slack.Channel {
+	ch := structures.ChannelFromID(structures.CanvasChannelID(f.ID))
+	ch.Name = f.Title
+	if ch.Name == "" {
+		ch.Name = f.Name
+	}
+	ch.Created = f.Created
+	ch.Creator = f.User
+	ch.IsPrivate = !f.IsPublic
+	ch.Properties = &slack.Properties{
+		Canvas: slack.Canvas{FileId: f.ID},
+	}
+	return ch
+}
+
+// canvasChannel processes a single canvas file f:  it records the canvas
+// channel information, the canvas file and the comment threads.
+func (cs *Stream) canvasChannel(ctx context.Context, proc processor.Conversations, f slack.File) error {
+	channel := canvasChannelInfo(f)
+	if channel.ID == "" {
+		return fmt.Errorf("canvas: invalid file ID %q", f.ID)
+	}
+	if err := proc.ChannelInfo(ctx, channel, ""); err != nil {
+		return fmt.Errorf("canvas: %s: %w", f.ID, err)
+	}
+	if err := proc.Files(ctx, channel, slack.Message{}, []slack.File{f}); err != nil {
+		return fmt.Errorf("canvas: %s: %w", f.ID, err)
+	}
+
+	// comments are stored in the canvas channel.
+	req := request{sl: &structures.SlackLink{Channel: channel.ID}}
+	err := cs.channel(ctx, req, func(mm []slack.Message, isLast bool) error {
+		threadC := make(chan request, len(mm))
+		_, err := cs.procChanMsg(ctx, proc, threadC, channel, isLast, mm)
+		close(threadC)
+		if err != nil {
+			return err
+		}
+		for tr := range threadC {
+			if err := cs.thread(ctx, tr, func(msgs []slack.Message, isLast bool) error {
+				return procThreadMsg(ctx, proc, channel, tr.sl.ThreadTS, false, isLast, msgs)
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := isNonCriticalErr(err); ok {
+			// canvas has no comment channel, or it is not accessible.
+			slog.DebugContext(ctx, "canvas comments not available", "file_id", f.ID, "error", err)
+			return nil
+		}
+		return fmt.Errorf("canvas: %s: comments: %w", f.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package stream
+
+import (
+	"testing"
+
+	"github.com/rusq/slack"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/rusq/slackdump/v4/internal/client/mock_client"
+	"github.com/rusq/slackdump/v4/internal/network"
+	"github.com/rusq/slackdump/v4/mocks/mock_processor"
+)
+
+func TestStream_Canvases(t *testing.T) {
+	var (
+		canvas = slack.File{ID: "F06R4HA3ZS8", Title: "Roadmap", Filetype: "quip", User: "U1"}
+		other  = slack.File{ID: "F0000000001", Filetype: "png"}
+		root   = slack.Message{Msg: slack.Msg{Timestamp: "1710000000.000100", ThreadTimestamp: "1710000000.000100", ReplyCount: 1, SubType: "document_comment_root"}}
+		reply  = slack.Message{Msg: slack.Msg{Timestamp: "1710000001.000100", ThreadTimestamp: "1710000000.000100", Text: "LGTM"}}
+	)
+	t.Run("records canvas, file and comments", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := mock_client.NewMockSlack(ctrl)
+		mp := mock_processor.NewMockConversations(ctrl)
+
+		ms.EXPECT().GetFilesContext(gomock.Any(), slack.GetFilesParameters{Types: canvasFileType, Count: canvasPageSize, Page: 1}).
+			Return([]slack.File{canvas, other}, &slack.Paging{Page: 1, Pages: 1}, nil)
+		mp.EXPECT().ChannelInfo(gomock.Any(), gomock.Any(), "").DoAndReturn(func(_ any, ci *slack.Channel, _ string) error {
+			assert.Equal(t, "C06R4HA3ZS8", ci.ID)
+			assert.Equal(t, "Roadmap", ci.Name)
+			assert.Equal(t, "F06R4HA3ZS8", ci.Properties.Canvas.FileId)
+			return nil
+		})
+		mp.EXPECT().Files(gomock.Any(), gomock.Any(), slack.Message{}, []slack.File{canvas}).Return(nil)
+		ms.EXPECT().GetConversationHistoryContext(gomock.Any(), gomock.Any()).
+			Return(&slack.GetConversationHistoryResponse{SlackResponse: slack.SlackResponse{Ok: true}, Messages: []slack.Message{root}}, nil)
+		mp.EXPECT().Messages(gomock.Any(), "C06R4HA3ZS8", 1, true, []slack.Message{root}).Return(nil)
+		ms.EXPECT().GetConversationRepliesContext(gomock.Any(), gomock.Any()).
+			Return([]slack.Message{root, reply}, false, "", nil)
+		mp.EXPECT().ThreadMessages(gomock.Any(), "C06R4HA3ZS8", root, false, true, []slack.Message{root, reply}).Return(nil)
+
+		cs := New(ms, network.NoLimits)
+		if err := cs.Canvases(t.Context(), mp); err != nil {
+			t.Fatalf("Stream.Canvases() error = %v", err)
+		}
+	})
+	t.Run("canvas without comment channel", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := mock_client.NewMockSlack(ctrl)
+		mp := mock_processor.NewMockConversations(ctrl)
+
+		ms.EXPECT().GetFilesContext(gomock.Any(), gomock.Any()).
+			Return([]slack.File{canvas}, &slack.Paging{Page: 1, Pages: 1}, nil)
+		mp.EXPECT().ChannelInfo(gomock.Any(), gomock.Any(), "").Return(nil)
+		mp.EXPECT().Files(gomock.Any(), gomock.Any(), slack.Message{}, []slack.File{canvas}).Return(nil)
+		ms.EXPECT().GetConversationHistoryContext(gomock.Any(), gomock.Any()).
+			Return(nil, slack.SlackErrorResponse{Err: "channel_not_found"})
+
+		cs := New(ms, network.NoLimits)
+		if err := cs.Canvases(t.Context(), mp); err != nil {
+			t.Fatalf("Stream.Canvases() error = %v", err)
+		}
+	})
+}
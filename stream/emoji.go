@@ -0,0 +1,90 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"runtime/trace"
+	"sort"
+
+	"github.com/rusq/slackdump/v4/internal/client"
+	"github.com/rusq/slackdump/v4/internal/edge"
+	"github.com/rusq/slackdump/v4/internal/network"
+	"github.com/rusq/slackdump/v4/processor"
+	"github.com/rusq/slackdump/v4/types"
+)
+
+// emojiLister is a narrow interface satisfied by *client.Client, it returns
+// the custom emoji with the uploader details when an edge connection is
+// available.
+type emojiLister interface {
+	AdminEmojiList(ctx context.Context) iter.Seq2[edge.EmojiResult, error]
+}
+
+// Emojis fetches the custom workspace emoji and passes them to the processor.
+// If the client supports the edge API, the emoji are fetched with
+// emoji.adminList, which returns the uploader details, otherwise it falls back
+// to emoji.list, which returns only names and URLs.
+func (cs *Stream) Emojis(ctx context.Context, proc processor.Emojis) error {
+	ctx, task := trace.NewTask(ctx, "Emojis")
+	defer task.End()
+
+	if el, ok := cs.client.(emojiLister); ok {
+		err := cs.adminEmojis(ctx, proc, el)
+		if !errors.Is(err, client.ErrOpNotSupported) {
+			return err
+		}
+	}
+
+	var em map[string]string
+	if err := network.WithRetry(ctx, cs.limits.emojis, cs.limits.tier.Tier2.Retries, func(ctx context.Context) error {
+		var err error
+		em, err = cs.client.GetEmojiContext(ctx)
+		return err
+	}); err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+	if len(em) == 0 {
+		return nil
+	}
+	ee := make([]types.Emoji, 0, len(em))
+	for name, uri := range em {
+		ee = append(ee, types.LegacyEmoji(name, uri))
+	}
+	sort.Slice(ee, func(i, j int) bool { return ee[i].Name < ee[j].Name })
+	return proc.Emojis(ctx, ee)
+}
+
+// adminEmojis fetches the emoji using the edge API.  It returns
+// [client.ErrOpNotSupported] if the edge API is not available, and no emoji
+// were processed.
+func (cs *Stream) adminEmojis(ctx context.Context, proc processor.Emojis, el emojiLister) error {
+	for res, err := range el.AdminEmojiList(ctx) {
+		if err != nil {
+			return err
+		}
+		if len(res.Emoji) == 0 {
+			continue
+		}
+		if err := proc.Emojis(ctx, res.Emoji); err != nil {
+			return err
+		}
+	}
+	return nil
+}
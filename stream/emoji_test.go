@@ -0,0 +1,126 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/rusq/slackdump/v4/internal/client"
+	"github.com/rusq/slackdump/v4/internal/client/mock_client"
+	"github.com/rusq/slackdump/v4/internal/edge"
+	"github.com/rusq/slackdump/v4/internal/network"
+	"github.com/rusq/slackdump/v4/mocks/mock_processor"
+	"github.com/rusq/slackdump/v4/types"
+)
+
+// edgeSlack is the mock client with the edge emoji lister.
+type edgeSlack struct {
+	*mock_client.MockSlack
+	results []edge.EmojiResult
+	err     error
+}
+
+func (e *edgeSlack) AdminEmojiList(context.Context) iter.Seq2[edge.EmojiResult, error] {
+	return func(yield func(edge.EmojiResult, error) bool) {
+		if e.err != nil {
+			yield(edge.EmojiResult{}, e.err)
+			return
+		}
+		for _, r := range e.results {
+			if !yield(r, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestStream_Emojis(t *testing.T) {
+	testlimits := rateLimits{
+		emojis: network.NewLimiter(network.NoTier, 100, 100),
+		tier:   network.DefLimits,
+	}
+	t.Run("emoji.list", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := mock_client.NewMockSlack(ctrl)
+		mp := mock_processor.NewMockEmojis(ctrl)
+
+		ms.EXPECT().GetEmojiContext(gomock.Any()).Return(map[string]string{
+			"parrot":      "alias:partyparrot",
+			"partyparrot": "https://example.com/partyparrot.gif",
+		}, nil)
+		mp.EXPECT().Emojis(gomock.Any(), []types.Emoji{
+			{Name: "parrot", URL: "alias:partyparrot", IsAlias: 1, AliasFor: "partyparrot"},
+			{Name: "partyparrot", URL: "https://example.com/partyparrot.gif"},
+		}).Return(nil)
+
+		cs := &Stream{client: ms, limits: testlimits}
+		if err := cs.Emojis(t.Context(), mp); err != nil {
+			t.Fatalf("Stream.Emojis() error = %v", err)
+		}
+	})
+	t.Run("edge", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := &edgeSlack{
+			MockSlack: mock_client.NewMockSlack(ctrl),
+			results: []edge.EmojiResult{
+				{Emoji: []edge.Emoji{{Name: "partyparrot", URL: "https://example.com/partyparrot.gif", UserID: "U1"}}},
+				{},
+			},
+		}
+		mp := mock_processor.NewMockEmojis(ctrl)
+		mp.EXPECT().Emojis(gomock.Any(), []types.Emoji{
+			{Name: "partyparrot", URL: "https://example.com/partyparrot.gif", UserID: "U1"},
+		}).Return(nil)
+
+		cs := &Stream{client: ms, limits: testlimits}
+		if err := cs.Emojis(t.Context(), mp); err != nil {
+			t.Fatalf("Stream.Emojis() error = %v", err)
+		}
+	})
+	t.Run("edge not supported falls back to emoji.list", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ms := &edgeSlack{
+			MockSlack: mock_client.NewMockSlack(ctrl),
+			err:       client.ErrOpNotSupported,
+		}
+		mp := mock_processor.NewMockEmojis(ctrl)
+		ms.EXPECT().GetEmojiContext(gomock.Any()).Return(map[string]string{}, nil)
+
+		cs := &Stream{client: ms, limits: testlimits}
+		if err := cs.Emojis(t.Context(), mp); err != nil {
+			t.Fatalf("Stream.Emojis() error = %v", err)
+		}
+	})
+	t.Run("edge error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		errEdge := errors.New("edge error")
+		ms := &edgeSlack{
+			MockSlack: mock_client.NewMockSlack(ctrl),
+			err:       errEdge,
+		}
+		mp := mock_processor.NewMockEmojis(ctrl)
+
+		cs := &Stream{client: ms, limits: testlimits}
+		if err := cs.Emojis(t.Context(), mp); !errors.Is(err, errEdge) {
+			t.Fatalf("Stream.Emojis() error = %v, want %v", err, errEdge)
+		}
+	})
+}
@@ -25,7 +25,6 @@ import (
 	"time"
 
 	"github.com/rusq/slack"
-	"golang.org/x/time/rate"
 
 	"github.com/rusq/slackdump/v4/internal/client"
 	"github.com/rusq/slackdump/v4/internal/network"
@@ -60,6 +59,7 @@ type Stream struct {
 	failChnlNotFnd bool // if true, will fail if channel not found
 	resultFn       []func(sr Result) error
 	skipThread     func(ctx context.Context, channelID, threadTS string, replyCount int) bool
+	adaptive       *network.Adaptive
 }
 
 // ResultType helps to identify the type of the result, so that the callback
@@ -113,23 +113,35 @@ func (r Result) String() string {
 
 // rateLimits contains the rate limiters for the different tiers.
 type rateLimits struct {
-	channels    *rate.Limiter
-	threads     *rate.Limiter
-	users       *rate.Limiter
-	userinfo    *rate.Limiter
-	searchmsg   *rate.Limiter
-	searchfiles *rate.Limiter
+	channels    network.Limiter
+	threads     network.Limiter
+	users       network.Limiter
+	userinfo    network.Limiter
+	searchmsg   network.Limiter
+	searchfiles network.Limiter
+	emojis      network.Limiter
+	files       network.Limiter
 	tier        network.Limits
 }
 
-func limits(l network.Limits) rateLimits {
+// limits creates the rate limiters for the given limits.  If the adaptive
+// controller a is not nil, the limiters are adaptive.
+func limits(l network.Limits, a *network.Adaptive) rateLimits {
+	newLimiter := func(t network.Tier, tl network.TierLimit) network.Limiter {
+		if a != nil {
+			return a.Limiter(t, tl.Burst, int(tl.Boost))
+		}
+		return network.NewLimiter(t, tl.Burst, int(tl.Boost))
+	}
 	return rateLimits{
-		channels:    network.NewLimiter(network.Tier3, l.Tier3.Burst, int(l.Tier3.Boost)),
-		threads:     network.NewLimiter(network.Tier3, l.Tier3.Burst, int(l.Tier3.Boost)),
-		users:       network.NewLimiter(network.Tier2, l.Tier2.Burst, int(l.Tier2.Boost)),
-		userinfo:    network.NewLimiter(network.Tier4, l.Tier4.Burst, int(l.Tier4.Boost)),
-		searchmsg:   network.NewLimiter(network.Tier2, l.Tier2.Burst, int(l.Tier2.Boost)),
-		searchfiles: network.NewLimiter(network.Tier2, l.Tier2.Burst, int(l.Tier2.Boost)),
+		channels:    newLimiter(network.Tier3, l.Tier3),
+		threads:     newLimiter(network.Tier3, l.Tier3),
+		users:       newLimiter(network.Tier2, l.Tier2),
+		userinfo:    newLimiter(network.Tier4, l.Tier4),
+		searchmsg:   newLimiter(network.Tier2, l.Tier2),
+		searchfiles: newLimiter(network.Tier2, l.Tier2),
+		emojis:      newLimiter(network.Tier2, l.Tier2),
+		files:       newLimiter(network.Tier3, l.Tier3),
 		tier:        l,
 	}
 }
@@ -190,12 +202,21 @@ func OptSkipThreadFunc(fn func(ctx context.Context, channelID, threadTS string,
 	}
 }
 
+// OptAdaptive enables the adaptive rate limiting.  The rate of the API tiers
+// is lowered when Slack responds with "429 Too Many Requests", and is slowly
+// increased back to the configured limits.  The controller can be shared
+// between several streams.
+func OptAdaptive(a *network.Adaptive) Option {
+	return func(cs *Stream) {
+		cs.adaptive = a
+	}
+}
+
 // New creates a new Stream instance that allows to stream different slack
 // entities.
 func New(cl client.Slack, l network.Limits, opts ...Option) *Stream {
 	cs := &Stream{
 		client:    cl,
-		limits:    limits(l),
 		chanCache: new(chanCache),
 		userCache: new(userCache),
 		inclusive: true,
@@ -203,6 +224,7 @@ func New(cl client.Slack, l network.Limits, opts ...Option) *Stream {
 	for _, opt := range opts {
 		opt(cs)
 	}
+	cs.limits = limits(l, cs.adaptive)
 	if cs.oldest.After(cs.latest) {
 		cs.oldest, cs.latest = cs.latest, cs.oldest
 	}
@@ -230,6 +252,10 @@ func (cs *Stream) Users(ctx context.Context, proc processor.Users, opt ...slack.
 	ctx, task := trace.NewTask(ctx, "Users")
 	defer task.End()
 
+	if up, ok := cs.client.(usersPager); ok {
+		return cs.usersPaged(ctx, proc, up, opt...)
+	}
+
 	p := cs.client.GetUsersPaginated(opt...)
 	var apiErr error
 	for apiErr == nil {
@@ -248,6 +274,38 @@ func (cs *Stream) Users(ctx context.Context, proc processor.Users, opt ...slack.
 	return p.Failure(errors.Unwrap(apiErr))
 }
 
+// usersPager is a narrow interface satisfied by *client.Pool, that fetches
+// each page of users with the client chosen for it.
+type usersPager interface {
+	GetUsersPage(ctx context.Context, cursor string, options ...slack.GetUsersOption) (users []slack.User, nextCursor string, err error)
+}
+
+// usersPaged processes all users in the workspace fetching them page by page
+// with up.
+func (cs *Stream) usersPaged(ctx context.Context, proc processor.Users, up usersPager, opt ...slack.GetUsersOption) error {
+	var cursor string
+	for {
+		var (
+			users []slack.User
+			next  string
+		)
+		if err := network.WithRetry(ctx, cs.limits.users, cs.limits.tier.Tier2.Retries, func(ctx context.Context) error {
+			var err error
+			users, next, err = up.GetUsersPage(ctx, cursor, opt...)
+			return err
+		}); err != nil {
+			return err
+		}
+		if err := proc.Users(ctx, users); err != nil {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+		cursor = next
+	}
+}
+
 var (
 	ErrOpNotSupported = errors.New("client doesn't support this operation")
 )
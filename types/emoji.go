@@ -0,0 +1,82 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package types
+
+import "strings"
+
+// Emoji represents a custom workspace emoji.
+type Emoji struct {
+	Name            string   `json:"name"`
+	IsAlias         int      `json:"is_alias,omitempty"`
+	AliasFor        string   `json:"alias_for,omitempty"`
+	URL             string   `json:"url"`
+	TeamID          string   `json:"team_id,omitempty"`
+	UserID          string   `json:"user_id,omitempty"`
+	Created         int64    `json:"created,omitempty"`
+	IsBad           bool     `json:"is_bad,omitempty"`
+	UserDisplayName string   `json:"user_display_name,omitempty"`
+	AvatarHash      string   `json:"avatar_hash,omitempty"`
+	CanDelete       bool     `json:"can_delete,omitempty"`
+	Synonyms        []string `json:"synonyms,omitempty"`
+}
+
+// LegacyEmoji returns the Emoji for the name and URL as returned by the
+// emoji.list API, where the URL of an alias has the "alias:" prefix.
+func LegacyEmoji(name, url string) Emoji {
+	target, isAlias := strings.CutPrefix(url, aliasPrefix)
+	if !isAlias {
+		return Emoji{Name: name, URL: url}
+	}
+	return Emoji{Name: name, URL: url, IsAlias: 1, AliasFor: target}
+}
+
+const aliasPrefix = "alias:"
+
+// Emojis is a slice of custom emoji.
+type Emojis []Emoji
+
+// IndexByName returns the emoji indexed by name.
+func (ee Emojis) IndexByName() map[string]*Emoji {
+	idx := make(map[string]*Emoji, len(ee))
+	for i := range ee {
+		idx[ee[i].Name] = &ee[i]
+	}
+	return idx
+}
+
+// Resolve returns the emoji with the name, following aliases.  It
+// returns false if the emoji is not known or the alias chain is broken.
+func (ee Emojis) Resolve(name string) (*Emoji, bool) {
+	return ResolveEmoji(ee.IndexByName(), name)
+}
+
+// ResolveEmoji follows the alias chain of the emoji name in idx and returns
+// the target emoji.
+func ResolveEmoji(idx map[string]*Emoji, name string) (*Emoji, bool) {
+	// aliases can't point to aliases in Slack, but we guard against loops
+	// anyway, in case the data is malformed.
+	for range 8 {
+		em, ok := idx[name]
+		if !ok {
+			return nil, false
+		}
+		if em.IsAlias == 0 || em.AliasFor == "" {
+			return em, true
+		}
+		name = em.AliasFor
+	}
+	return nil, false
+}
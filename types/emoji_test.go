@@ -0,0 +1,80 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegacyEmoji(t *testing.T) {
+	tests := []struct {
+		name    string
+		argName string
+		argURL  string
+		want    Emoji
+	}{
+		{
+			name:    "emoji",
+			argName: "partyparrot",
+			argURL:  "https://emoji.slack-edge.com/T1/partyparrot/abc.gif",
+			want:    Emoji{Name: "partyparrot", URL: "https://emoji.slack-edge.com/T1/partyparrot/abc.gif"},
+		},
+		{
+			name:    "alias",
+			argName: "parrot",
+			argURL:  "alias:partyparrot",
+			want:    Emoji{Name: "parrot", URL: "alias:partyparrot", IsAlias: 1, AliasFor: "partyparrot"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, LegacyEmoji(tt.argName, tt.argURL))
+		})
+	}
+}
+
+func TestEmojis_Resolve(t *testing.T) {
+	ee := Emojis{
+		{Name: "partyparrot", URL: "https://example.com/partyparrot.gif"},
+		{Name: "parrot", IsAlias: 1, AliasFor: "partyparrot"},
+		{Name: "broken", IsAlias: 1, AliasFor: "missing"},
+		{Name: "loop1", IsAlias: 1, AliasFor: "loop2"},
+		{Name: "loop2", IsAlias: 1, AliasFor: "loop1"},
+	}
+	tests := []struct {
+		name     string
+		emoji    string
+		wantName string
+		wantOK   bool
+	}{
+		{"emoji", "partyparrot", "partyparrot", true},
+		{"alias", "parrot", "partyparrot", true},
+		{"unknown", "nope", "", false},
+		{"broken alias", "broken", "", false},
+		{"alias loop", "loop1", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ee.Resolve(tt.emoji)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.wantName, got.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/rusq/slack"
+
+	"github.com/rusq/slackdump/v4/internal/structures"
+)
+
+// MembershipAction is the kind of the channel membership change.
+type MembershipAction string
+
+const (
+	MemberJoined MembershipAction = "joined"
+	MemberLeft   MembershipAction = "left"
+)
+
+// MembershipEvent is a change of the channel membership.
+type MembershipEvent struct {
+	Time   time.Time        `json:"time"`
+	UserID string           `json:"user_id"`
+	Action MembershipAction `json:"action"`
+	// Inferred is set if the event was derived from the member list
+	// snapshots, and not from a join or leave message.  In this case, Time is
+	// the time of the snapshot that observed the change first, and the actual
+	// change happened at some point before it.
+	Inferred bool `json:"inferred,omitempty"`
+}
+
+// MemberSnapshot is the list of channel members, as recorded by one of the
+// archive sessions.
+type MemberSnapshot struct {
+	Recorded time.Time
+	Members  []string
+}
+
+// MembershipFromMessage returns the membership event for the channel or group
+// join and leave messages.  For all other messages, it returns false.
+func MembershipFromMessage(m *slack.Message) (MembershipEvent, bool) {
+	var action MembershipAction
+	switch m.SubType {
+	case slack.MsgSubTypeChannelJoin, slack.MsgSubTypeGroupJoin:
+		action = MemberJoined
+	case slack.MsgSubTypeChannelLeave, slack.MsgSubTypeGroupLeave:
+		action = MemberLeft
+	default:
+		return MembershipEvent{}, false
+	}
+	if m.User == "" {
+		return MembershipEvent{}, false
+	}
+	ts, err := structures.ParseSlackTS(m.Timestamp)
+	if err != nil {
+		return MembershipEvent{}, false
+	}
+	return MembershipEvent{Time: ts, UserID: m.User, Action: action}, true
+}
+
+// MergeMembership returns the chronological membership timeline built from the
+// events derived from join and leave messages, and the member list snapshots.
+// Any difference between the membership state, reconstructed from events, and
+// the snapshot, is recorded as an inferred event at the time of the snapshot.
+func MergeMembership(events []MembershipEvent, snapshots []MemberSnapshot) []MembershipEvent {
+	events = slices.Clone(events)
+	sortMembership(events)
+	snapshots = slices.Clone(snapshots)
+	slices.SortStableFunc(snapshots, func(a, b MemberSnapshot) int {
+		return a.Recorded.Compare(b.Recorded)
+	})
+
+	var (
+		result  = make([]MembershipEvent, 0, len(events))
+		members = make(map[string]bool)
+		i       int
+	)
+	for _, sn := range snapshots {
+		for ; i < len(events) && !events[i].Time.After(sn.Recorded); i++ {
+			result = append(result, events[i])
+			members[events[i].UserID] = events[i].Action == MemberJoined
+		}
+		present := make(map[string]bool, len(sn.Members))
+		for _, id := range sn.Members {
+			present[id] = true
+		}
+		var inferred []MembershipEvent
+		for _, id := range sn.Members {
+			if !members[id] {
+				inferred = append(inferred, MembershipEvent{Time: sn.Recorded, UserID: id, Action: MemberJoined, Inferred: true})
+				members[id] = true
+			}
+		}
+		for id, isMember := range members {
+			if isMember && !present[id] {
+				inferred = append(inferred, MembershipEvent{Time: sn.Recorded, UserID: id, Action: MemberLeft, Inferred: true})
+				members[id] = false
+			}
+		}
+		sortMembership(inferred)
+		result = append(result, inferred...)
+	}
+	return append(result, events[i:]...)
+}
+
+func sortMembership(ee []MembershipEvent) {
+	slices.SortStableFunc(ee, func(a, b MembershipEvent) int {
+		if c := a.Time.Compare(b.Time); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+}
+
+// MembersAt returns the sorted IDs of the channel members at time t, according
+// to the chronological membership timeline.
+func MembersAt(timeline []MembershipEvent, t time.Time) []string {
+	members := make(map[string]bool)
+	for _, ev := range timeline {
+		if ev.Time.After(t) {
+			break
+		}
+		members[ev.UserID] = ev.Action == MemberJoined
+	}
+	var ids []string
+	for id, isMember := range members {
+		if isMember {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
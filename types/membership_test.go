@@ -0,0 +1,88 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rusq/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMembershipFromMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		m      slack.Message
+		want   MembershipEvent
+		wantOk bool
+	}{
+		{
+			name:   "channel join",
+			m:      slack.Message{Msg: slack.Msg{SubType: slack.MsgSubTypeChannelJoin, User: "U1", Timestamp: "1704103200.000100"}},
+			want:   MembershipEvent{Time: time.Unix(1704103200, 100000), UserID: "U1", Action: MemberJoined},
+			wantOk: true,
+		},
+		{
+			name:   "group leave",
+			m:      slack.Message{Msg: slack.Msg{SubType: slack.MsgSubTypeGroupLeave, User: "U1", Timestamp: "1704103200.000100"}},
+			want:   MembershipEvent{Time: time.Unix(1704103200, 100000), UserID: "U1", Action: MemberLeft},
+			wantOk: true,
+		},
+		{
+			name: "ordinary message",
+			m:    slack.Message{Msg: slack.Msg{User: "U1", Timestamp: "1704103200.000100"}},
+		},
+		{
+			name: "invalid timestamp",
+			m:    slack.Message{Msg: slack.Msg{SubType: slack.MsgSubTypeChannelJoin, User: "U1", Timestamp: "x"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := MembershipFromMessage(&tt.m)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.True(t, tt.want.Time.Equal(got.Time))
+			got.Time = tt.want.Time
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMergeMembership(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	events := []MembershipEvent{
+		{Time: day(5), UserID: "U3", Action: MemberJoined},
+		{Time: day(2), UserID: "U2", Action: MemberJoined},
+	}
+	snapshots := []MemberSnapshot{
+		{Recorded: day(3), Members: []string{"U1", "U2"}},
+		{Recorded: day(10), Members: []string{"U2", "U3"}},
+	}
+	want := []MembershipEvent{
+		{Time: day(2), UserID: "U2", Action: MemberJoined},
+		{Time: day(3), UserID: "U1", Action: MemberJoined, Inferred: true},
+		{Time: day(5), UserID: "U3", Action: MemberJoined},
+		{Time: day(10), UserID: "U1", Action: MemberLeft, Inferred: true},
+	}
+	got := MergeMembership(events, snapshots)
+	assert.Equal(t, want, got)
+	assert.Equal(t, events[0].UserID, "U3", "input must not be modified")
+
+	assert.Equal(t, []string{"U1", "U2"}, MembersAt(got, day(4)))
+	assert.Equal(t, []string{"U2", "U3"}, MembersAt(got, day(10)))
+	assert.Empty(t, MembersAt(got, day(1)))
+}
@@ -16,6 +16,9 @@
 package types
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/rusq/slack"
 
 	"github.com/rusq/slackdump/v4/internal/structures"
@@ -37,3 +40,60 @@ func (us Users) UserIDs() []string {
 	}
 	return ids
 }
+
+// UserSnapshot is the state of the user, as recorded by one of the archive
+// sessions.
+type UserSnapshot struct {
+	// SessionID is the ID of the session that recorded the user.
+	SessionID int64
+	// Recorded is the time when the user was recorded.
+	Recorded time.Time
+	User     slack.User
+}
+
+// UserChange is a change of the user profile field between two consecutive
+// snapshots of the user.
+type UserChange struct {
+	// SessionID is the ID of the session that recorded the change.
+	SessionID int64     `json:"session_id"`
+	Recorded  time.Time `json:"recorded"`
+	Field     string    `json:"field"`
+	Old       string    `json:"old"`
+	New       string    `json:"new"`
+}
+
+// userFields are the tracked user profile fields.
+var userFields = []struct {
+	name  string
+	value func(u *slack.User) string
+}{
+	{"username", func(u *slack.User) string { return u.Name }},
+	{"real_name", func(u *slack.User) string { return u.Profile.RealName }},
+	{"display_name", func(u *slack.User) string { return u.Profile.DisplayName }},
+	{"title", func(u *slack.User) string { return u.Profile.Title }},
+	{"status_text", func(u *slack.User) string { return u.Profile.StatusText }},
+	{"status_emoji", func(u *slack.User) string { return u.Profile.StatusEmoji }},
+	{"deleted", func(u *slack.User) string { return strconv.FormatBool(u.Deleted) }},
+}
+
+// UserChanges returns the changes of the tracked profile fields between the
+// consecutive snapshots, which must be ordered chronologically.  The first
+// snapshot is the baseline, and does not produce any changes.
+func UserChanges(snapshots []UserSnapshot) []UserChange {
+	var changes []UserChange
+	for i := 1; i < len(snapshots); i++ {
+		prev, cur := &snapshots[i-1].User, &snapshots[i].User
+		for _, f := range userFields {
+			if o, n := f.value(prev), f.value(cur); o != n {
+				changes = append(changes, UserChange{
+					SessionID: snapshots[i].SessionID,
+					Recorded:  snapshots[i].Recorded,
+					Field:     f.name,
+					Old:       o,
+					New:       n,
+				})
+			}
+		}
+	}
+	return changes
+}
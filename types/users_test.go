@@ -0,0 +1,43 @@
+// Copyright (c) 2021-2026 Rustam Gilyazov and Contributors.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rusq/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserChanges(t *testing.T) {
+	t1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	t3 := t2.Add(24 * time.Hour)
+	snapshots := []UserSnapshot{
+		{SessionID: 1, Recorded: t1, User: slack.User{ID: "U1", Name: "bob", Profile: slack.UserProfile{Title: "intern"}}},
+		{SessionID: 2, Recorded: t2, User: slack.User{ID: "U1", Name: "bob", Profile: slack.UserProfile{Title: "engineer", StatusEmoji: ":palm_tree:"}}},
+		{SessionID: 3, Recorded: t3, User: slack.User{ID: "U1", Name: "bob", Deleted: true, Profile: slack.UserProfile{Title: "engineer", StatusEmoji: ":palm_tree:"}}},
+	}
+	want := []UserChange{
+		{SessionID: 2, Recorded: t2, Field: "title", Old: "intern", New: "engineer"},
+		{SessionID: 2, Recorded: t2, Field: "status_emoji", Old: "", New: ":palm_tree:"},
+		{SessionID: 3, Recorded: t3, Field: "deleted", Old: "false", New: "true"},
+	}
+	assert.Equal(t, want, UserChanges(snapshots))
+	assert.Empty(t, UserChanges(snapshots[:1]))
+	assert.Empty(t, UserChanges(nil))
+}